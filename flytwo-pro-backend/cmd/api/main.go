@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/gob"
 	"fmt"
 	"gobid/internal/api"
 	"gobid/internal/cache"
 	"gobid/internal/logger"
+	"gobid/internal/mailer"
+	"gobid/internal/oidc"
+	"gobid/internal/ratelimit"
 	"gobid/internal/services"
 	"net/http"
 	"os"
@@ -40,9 +44,15 @@ import (
 // @host      localhost:3080
 // @BasePath  /api/v1
 
-// @securityDefinitions.apikey ApiKeyAuth
+// @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @description "Bearer " followed by an access token from POST /auth/token. Browsers use the session cookie set at login instead. Served as an http bearer scheme in the OpenAPI 3 document.
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created at POST /users/api-keys; accepted only by endpoints of its scopes
 
 func main() {
 	gob.Register(uuid.UUID{})
@@ -78,11 +88,13 @@ func main() {
 
 	logger.Log.Info("Successfully connected to database")
 
-	// Cache configuration (L1 Ristretto, optional L2 Redis)
+	// Cache configuration (L1 Ristretto, optional L2 Redis or Postgres)
 	cacheTTLSeconds, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_TTL_SECONDS"))
 	if cacheTTLSeconds == 0 {
 		cacheTTLSeconds = 300
 	}
+	// Stale-while-revalidate window; 0 disables it.
+	cacheStaleSeconds, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_STALE_SECONDS"))
 	l1MaxCost, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_L1_MAX_COST"))
 	if l1MaxCost == 0 {
 		l1MaxCost = 10000
@@ -93,21 +105,62 @@ func main() {
 		L1MaxCost:     int64(l1MaxCost),
 		L1BufferItems: 64,
 		TTL:           time.Duration(cacheTTLSeconds) * time.Second,
+		StaleTTL:      time.Duration(cacheStaleSeconds) * time.Second,
 		RedisAddr:     os.Getenv("GOBID_REDIS_ADDR"),
 		RedisPassword: os.Getenv("GOBID_REDIS_PASSWORD"),
 		EnableL2:      os.Getenv("GOBID_REDIS_ADDR") != "",
 		Logger:        logger.Log,
+
+		InvalidationChannel: os.Getenv("GOBID_CACHE_INVALIDATION_CHANNEL"),
 	}
 	if dbStr := os.Getenv("GOBID_REDIS_DB"); dbStr != "" {
 		if v, err := strconv.Atoi(dbStr); err == nil {
 			cacheCfg.RedisDB = v
 		}
 	}
+	// L2 backend: redis (used when GOBID_REDIS_ADDR is set), postgres or none
+	switch l2 := os.Getenv("GOBID_CACHE_L2"); l2 {
+	case "", "redis":
+	case "postgres":
+		cleanupSeconds, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_PG_CLEANUP_SECONDS"))
+		cacheCfg.Store = cache.NewPostgresStore(pool, cache.PostgresConfig{
+			CleanupInterval: time.Duration(cleanupSeconds) * time.Second,
+			Logger:          logger.Log,
+		})
+	case "none":
+		cacheCfg.EnableL2 = false
+	default:
+		logger.Log.Warn("Invalid GOBID_CACHE_L2; using redis", zap.String("value", l2))
+	}
+	// Encoding: json (default) or msgpack, optionally compressed above a size threshold
+	if codec, err := cache.CodecByName(os.Getenv("GOBID_CACHE_CODEC")); err != nil {
+		logger.Log.Warn("Invalid GOBID_CACHE_CODEC; using json", zap.Error(err))
+	} else {
+		cacheCfg.Codec = codec
+	}
+	cacheCfg.Compression = os.Getenv("GOBID_CACHE_COMPRESSION")
+	cacheCfg.CompressMinBytes, _ = strconv.Atoi(os.Getenv("GOBID_CACHE_COMPRESS_MIN_BYTES"))
+	// Per-prefix TTLs ("prefix=seconds,..."); stats change only on import
+	cacheTTLs := os.Getenv("GOBID_CACHE_TTLS")
+	if cacheTTLs == "" {
+		cacheTTLs = "catalog:stats=3600"
+	}
+	if ttls, err := cache.ParsePrefixTTLs(cacheTTLs); err != nil {
+		logger.Log.Warn("Invalid GOBID_CACHE_TTLS; using GOBID_CACHE_TTL_SECONDS for every key", zap.Error(err))
+	} else {
+		cacheCfg.PrefixTTLs = ttls
+	}
+	// Empty search results are kept for a shorter time; 0 disables it
+	cacheCfg.NegativeTTL = 30 * time.Second
+	if v, err := strconv.Atoi(os.Getenv("GOBID_CACHE_NEGATIVE_TTL_SECONDS")); err == nil && v >= 0 {
+		cacheCfg.NegativeTTL = time.Duration(v) * time.Second
+	}
 	appCache, err := cache.New(cacheCfg)
 	if err != nil {
 		logger.Log.Warn("Failed to initialize cache; continuing without cache", zap.Error(err))
 		appCache = nil
 	}
+	defer appCache.Close()
 
 	s := scs.New()
 	s.Store = pgxstore.New(pool)
@@ -115,12 +168,241 @@ func main() {
 	s.Cookie.HttpOnly = true
 	s.Cookie.SameSite = http.SameSiteLaxMode
 	userService := services.NewUserService(pool)
-	catalogService := services.NewCatalogImportService(pool, appCache)
+
+	// Roles; GOBID_BOOTSTRAP_ADMIN_EMAIL becomes admin while there is none
+	roleService := services.NewRoleService(pool, os.Getenv("GOBID_BOOTSTRAP_ADMIN_EMAIL"))
+	if granted, err := roleService.BootstrapAdmin(ctx); err != nil {
+		logger.Log.Error("Failed to bootstrap admin", zap.Error(err))
+	} else if granted {
+		logger.Log.Info("Bootstrap admin granted", zap.String("email", os.Getenv("GOBID_BOOTSTRAP_ADMIN_EMAIL")))
+	}
+
+	// Search analytics (async search log, retention in days; 0 keeps everything)
+	retentionDays := 90
+	if v, err := strconv.Atoi(os.Getenv("GOBID_SEARCH_LOG_RETENTION_DAYS")); err == nil && v >= 0 {
+		retentionDays = v
+	}
+	searchAnalytics := services.NewSearchAnalyticsService(pool, services.SearchAnalyticsConfig{
+		Retention: time.Duration(retentionDays) * 24 * time.Hour,
+	})
+	searchAnalytics.Start(ctx)
+	defer searchAnalytics.Close()
+
+	synonymService := services.NewSearchSynonymService(pool)
+	catalogService := services.NewCatalogImportService(pool, appCache, &synonymService)
+	savedSearchService := services.NewSavedSearchService(pool, &synonymService)
+
+	// Cache warm-up after startup and after imports: configured searches
+	// ("catalog:query;...") plus the most frequent ones of the search log
+	var cacheWarmup services.CacheWarmupServiceInterface
+	if appCache != nil {
+		warmupQueries, err := services.ParseWarmupQueries(os.Getenv("GOBID_CACHE_WARMUP_QUERIES"))
+		if err != nil {
+			logger.Log.Warn("Invalid GOBID_CACHE_WARMUP_QUERIES; warming only popular searches", zap.Error(err))
+		}
+		warmupTop := 20
+		if v, err := strconv.Atoi(os.Getenv("GOBID_CACHE_WARMUP_TOP")); err == nil && v >= 0 {
+			warmupTop = v
+		}
+		warmupDays, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_WARMUP_DAYS"))
+		warmupConcurrency, _ := strconv.Atoi(os.Getenv("GOBID_CACHE_WARMUP_CONCURRENCY"))
+		warmupService := services.NewCacheWarmupService(&catalogService, &searchAnalytics, services.CacheWarmupConfig{
+			Queries:     warmupQueries,
+			TopQueries:  int32(warmupTop),
+			Window:      time.Duration(warmupDays) * 24 * time.Hour,
+			Concurrency: warmupConcurrency,
+		})
+		warmupService.Start(ctx)
+		defer warmupService.Close()
+		warmupService.Trigger("startup")
+		cacheWarmup = &warmupService
+	}
+	itemListService := services.NewItemListService(pool)
+	organizationService := services.NewOrganizationService(pool)
+
+	// Bearer tokens for the mobile app; without GOBID_JWT_SECRET a random key
+	// is used, so tokens break on restart and across instances
+	jwtSecret := []byte(os.Getenv("GOBID_JWT_SECRET"))
+	if len(jwtSecret) == 0 {
+		logger.Log.Warn("GOBID_JWT_SECRET not set; using a random key, issued tokens will not survive a restart")
+		jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(jwtSecret); err != nil {
+			logger.Log.Fatal("Failed to generate JWT secret", zap.Error(err))
+		}
+	}
+	accessTTLMinutes, _ := strconv.Atoi(os.Getenv("GOBID_JWT_ACCESS_TTL_MINUTES"))
+	refreshTTLDays, _ := strconv.Atoi(os.Getenv("GOBID_JWT_REFRESH_TTL_DAYS"))
+	tokenService := services.NewTokenService(pool, services.TokenConfig{
+		Secret:     jwtSecret,
+		AccessTTL:  time.Duration(accessTTLMinutes) * time.Minute,
+		RefreshTTL: time.Duration(refreshTTLDays) * 24 * time.Hour,
+	})
+
+	// Outgoing email: SMTP when GOBID_SMTP_HOST is set, otherwise .eml files
+	// in GOBID_MAIL_DIR (or only the log) for development
+	var appMailer mailer.Mailer
+	if host := os.Getenv("GOBID_SMTP_HOST"); host != "" {
+		smtpPort, _ := strconv.Atoi(os.Getenv("GOBID_SMTP_PORT"))
+		smtpMailer, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     host,
+			Port:     smtpPort,
+			Username: os.Getenv("GOBID_SMTP_USERNAME"),
+			Password: os.Getenv("GOBID_SMTP_PASSWORD"),
+			From:     os.Getenv("GOBID_MAIL_FROM"),
+		})
+		if err != nil {
+			logger.Log.Fatal("Invalid SMTP configuration", zap.Error(err))
+		}
+		appMailer = smtpMailer
+	} else {
+		fileMailer, err := mailer.NewFileMailer(os.Getenv("GOBID_MAIL_DIR"), os.Getenv("GOBID_MAIL_FROM"), logger.Log)
+		if err != nil {
+			logger.Log.Fatal("Failed to set up file mailer", zap.Error(err))
+		}
+		appMailer = fileMailer
+	}
+
+	// Login and password reset limits: counters in Redis when configured, so
+	// every instance shares them, in memory otherwise. They get a DB of their
+	// own (GOBID_LOGIN_LIMIT_REDIS_DB, default 1) apart from the cache.
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if redisAddr := os.Getenv("GOBID_REDIS_ADDR"); redisAddr != "" {
+		loginRedisDB := 1
+		if dbStr := os.Getenv("GOBID_LOGIN_LIMIT_REDIS_DB"); dbStr != "" {
+			if v, err := strconv.Atoi(dbStr); err == nil {
+				loginRedisDB = v
+			}
+		}
+		if cacheCfg.EnableL2 && cacheCfg.Store == nil && loginRedisDB == cacheCfg.RedisDB {
+			logger.Log.Fatal("GOBID_LOGIN_LIMIT_REDIS_DB must differ from GOBID_REDIS_DB used by the cache",
+				zap.Int("db", loginRedisDB))
+		}
+		redisStore := ratelimit.NewRedisStore(ratelimit.RedisConfig{
+			Addr:     redisAddr,
+			Password: os.Getenv("GOBID_REDIS_PASSWORD"),
+			DB:       loginRedisDB,
+		})
+		defer redisStore.Close()
+		if err := redisStore.Ping(ctx); err != nil {
+			logger.Log.Warn("Redis unreachable for rate limits; attempts are not limited until it responds", zap.Error(err))
+		}
+		limitStore = redisStore
+	}
+	resetTTLMinutes, _ := strconv.Atoi(os.Getenv("GOBID_PASSWORD_RESET_TTL_MINUTES"))
+	resetMaxPerEmail, _ := strconv.Atoi(os.Getenv("GOBID_PASSWORD_RESET_MAX_PER_EMAIL"))
+	resetMaxPerIP, _ := strconv.Atoi(os.Getenv("GOBID_PASSWORD_RESET_MAX_PER_IP"))
+	resetMaxAttemptsPerIP, _ := strconv.Atoi(os.Getenv("GOBID_PASSWORD_RESET_MAX_ATTEMPTS_PER_IP"))
+	passwordResetService := services.NewPasswordResetService(pool, appMailer, limitStore, services.PasswordResetConfig{
+		TTL:              time.Duration(resetTTLMinutes) * time.Minute,
+		ResetURL:         os.Getenv("GOBID_PASSWORD_RESET_URL"),
+		MaxPerEmail:      int64(resetMaxPerEmail),
+		MaxPerIP:         int64(resetMaxPerIP),
+		MaxAttemptsPerIP: int64(resetMaxAttemptsPerIP),
+	})
+	passwordResetService.Start(ctx)
+	defer passwordResetService.Close()
+
+	verifyTTLHours, _ := strconv.Atoi(os.Getenv("GOBID_EMAIL_VERIFY_TTL_HOURS"))
+	verifyResendSeconds, _ := strconv.Atoi(os.Getenv("GOBID_EMAIL_VERIFY_RESEND_SECONDS"))
+	verifyMaxPerHour, _ := strconv.Atoi(os.Getenv("GOBID_EMAIL_VERIFY_MAX_PER_HOUR"))
+	emailVerificationService := services.NewEmailVerificationService(pool, appMailer, services.EmailVerificationConfig{
+		TTL:            time.Duration(verifyTTLHours) * time.Hour,
+		VerifyURL:      os.Getenv("GOBID_EMAIL_VERIFY_URL"),
+		ResendInterval: time.Duration(verifyResendSeconds) * time.Second,
+		MaxPerHour:     int64(verifyMaxPerHour),
+	})
+	defer emailVerificationService.Close()
+
+	loginFreeAttempts, _ := strconv.Atoi(os.Getenv("GOBID_LOGIN_FREE_ATTEMPTS"))
+	loginMaxFailures, _ := strconv.Atoi(os.Getenv("GOBID_LOGIN_MAX_FAILURES"))
+	loginIPFreeAttempts, _ := strconv.Atoi(os.Getenv("GOBID_LOGIN_IP_FREE_ATTEMPTS"))
+	loginIPMaxFailures, _ := strconv.Atoi(os.Getenv("GOBID_LOGIN_IP_MAX_FAILURES"))
+	loginLockoutMinutes, _ := strconv.Atoi(os.Getenv("GOBID_LOGIN_LOCKOUT_MINUTES"))
+	loginProtectionService := services.NewLoginProtectionService(pool, limitStore, services.LoginProtectionConfig{
+		Account: services.LoginLimit{
+			FreeAttempts: int64(loginFreeAttempts),
+			MaxFailures:  int64(loginMaxFailures),
+			Lockout:      time.Duration(loginLockoutMinutes) * time.Minute,
+		},
+		IP: services.LoginLimit{
+			FreeAttempts: int64(loginIPFreeAttempts),
+			MaxFailures:  int64(loginIPMaxFailures),
+			Lockout:      time.Duration(loginLockoutMinutes) * time.Minute,
+		},
+	})
+	logger.Log.Info("Login protection enabled", zap.String("store", limitStore.Name()))
+
+	// TOTP secrets are encrypted with a key of their own: losing or rotating
+	// it locks every enrolled user out, so it is required and never taken
+	// from the JWT key, which may be random or rotated
+	totpKey := []byte(os.Getenv("GOBID_TOTP_KEY"))
+	if len(totpKey) == 0 {
+		logger.Log.Fatal("GOBID_TOTP_KEY is required to store two-factor secrets")
+	}
+	if string(totpKey) == os.Getenv("GOBID_JWT_SECRET") {
+		logger.Log.Fatal("GOBID_TOTP_KEY must differ from GOBID_JWT_SECRET")
+	}
+	twoFactorService, err := services.NewTwoFactorService(pool, services.TwoFactorConfig{
+		Issuer:        os.Getenv("GOBID_TOTP_ISSUER"),
+		EncryptionKey: totpKey,
+	})
+	if err != nil {
+		logger.Log.Fatal("Failed to set up two-factor authentication", zap.Error(err))
+	}
+
+	apiKeyService := services.NewApiKeyService(pool)
+	userSessionService := services.NewUserSessionService(pool)
+
+	// LGPD account deletions (grace period in days before the account is deleted)
+	deletionGraceDays := 30
+	if v, err := strconv.Atoi(os.Getenv("GOBID_ACCOUNT_DELETION_GRACE_DAYS")); err == nil && v >= 0 {
+		deletionGraceDays = v
+	}
+	privacyService := services.NewPrivacyService(pool, services.PrivacyConfig{
+		GracePeriod: time.Duration(deletionGraceDays) * 24 * time.Hour,
+	})
+	privacyService.Start(ctx)
+	defer privacyService.Close()
+
+	// OpenID Connect login (GOBID_OIDC_PROVIDERS and GOBID_OIDC_<NAME>_*)
+	oidcConfigs, err := oidc.LoadConfigs(os.Getenv)
+	if err != nil {
+		logger.Log.Fatal("Invalid OpenID Connect configuration", zap.Error(err))
+	}
+	oidcProviders := make([]*oidc.Provider, len(oidcConfigs))
+	for i, cfg := range oidcConfigs {
+		oidcProviders[i] = oidc.NewProvider(cfg, nil)
+		logger.Log.Info("OpenID Connect provider enabled",
+			zap.String("provider", cfg.Name),
+			zap.String("issuer", cfg.Issuer))
+	}
+	identityService := services.NewIdentityService(pool)
+
 	api := api.Api{
-		Router:         chi.NewMux(),
-		UserService:    &userService,
-		CatalogService: &catalogService,
-		Sessions:       s,
+		Router:          chi.NewMux(),
+		UserService:     &userService,
+		CatalogService:  &catalogService,
+		SynonymService:  &synonymService,
+		SearchAnalytics: &searchAnalytics,
+		SavedSearches:   &savedSearchService,
+		ItemLists:       &itemListService,
+		Cache:           appCache,
+		CacheWarmup:     cacheWarmup,
+		Tokens:          &tokenService,
+		Roles:           &roleService,
+		PasswordReset:   &passwordResetService,
+		EmailVerifier:   &emailVerificationService,
+		LoginProtection: &loginProtectionService,
+		TwoFactor:       &twoFactorService,
+		ApiKeys:         &apiKeyService,
+		UserSessions:    &userSessionService,
+		Privacy:         &privacyService,
+		Identities:      &identityService,
+		Organizations:   &organizationService,
+		OIDC:            oidc.NewRegistry(oidcProviders...),
+		Sessions:        s,
+		TrustProxy:      os.Getenv("GOBID_TRUST_PROXY") == "true",
+		OIDCFrontendURL: os.Getenv("GOBID_OIDC_FRONTEND_URL"),
 		WsUpgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true
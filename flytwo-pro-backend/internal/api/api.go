@@ -1,6 +1,7 @@
 package api
 
 import (
+	"gobid/internal/oidc"
 	"gobid/internal/services"
 
 	"github.com/alexedwards/scs/v2"
@@ -9,9 +10,36 @@ import (
 )
 
 type Api struct {
-	Router         *chi.Mux
-	UserService    services.UserServiceInterface
-	CatalogService services.CatalogImportServiceInterface
-	Sessions       *scs.SessionManager
-	WsUpgrader     websocket.Upgrader
+	Router          *chi.Mux
+	UserService     services.UserServiceInterface
+	CatalogService  services.CatalogImportServiceInterface
+	SynonymService  services.SearchSynonymServiceInterface
+	SearchAnalytics services.SearchAnalyticsServiceInterface
+	SavedSearches   services.SavedSearchServiceInterface
+	ItemLists       services.ItemListServiceInterface
+	Cache           services.CacheInspectorInterface
+	CacheWarmup     services.CacheWarmupServiceInterface
+	Tokens          services.TokenServiceInterface
+	Roles           services.RoleServiceInterface
+	PasswordReset   services.PasswordResetServiceInterface
+	EmailVerifier   services.EmailVerificationServiceInterface
+	LoginProtection services.LoginProtectionServiceInterface
+	TwoFactor       services.TwoFactorServiceInterface
+	ApiKeys         services.ApiKeyServiceInterface
+	UserSessions    services.UserSessionServiceInterface
+	Privacy         services.PrivacyServiceInterface
+	Identities      services.IdentityServiceInterface
+	Organizations   services.OrganizationServiceInterface
+	OIDC            *oidc.Registry
+	Sessions        *scs.SessionManager
+	WsUpgrader      websocket.Upgrader
+
+	// TrustProxy takes the client IP from X-Forwarded-For / X-Real-IP. Enable
+	// it only behind a reverse proxy that sets these headers.
+	TrustProxy bool
+
+	// OIDCFrontendURL is the frontend page the browser returns to after an
+	// OpenID Connect login, with two_factor_required or oidc_error in the
+	// query string. Empty answers the callback with JSON instead.
+	OIDCFrontendURL string
 }
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleListApiKeys godoc
+// @Summary List API keys
+// @Description List the API keys of the logged-in user, revoked and expired ones included, newest first. Secrets are never returned.
+// @Tags users
+// @Produce json
+// @Success 200 {array} dto.ApiKeyResponse "API keys"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/api-keys [get]
+func (api *Api) handleListApiKeys(w http.ResponseWriter, r *http.Request) {
+	if !api.requireApiKeys(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	keys, err := api.ApiKeys.ListApiKeys(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Failed to list API keys",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	response := make([]dto.ApiKeyResponse, len(keys))
+	for i := range keys {
+		response[i] = toApiKeyResponse(&keys[i])
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleCreateApiKey godoc
+// @Summary Create an API key
+// @Description Create a key for scripts, sent in the X-API-Key header. Scopes: catalog:read (searches and stats) and catalog:import (CATMAT/CATSER imports, which also need the admin role). The key is in the response only; store it, it is not shown again.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateApiKeyReq true "Name, scopes and optional expiry"
+// @Success 201 {object} dto.CreatedApiKeyResponse "API key created"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "API key limit reached"
+// @Failure 422 {object} map[string]interface{} "Validation errors or invalid scope"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/api-keys [post]
+func (api *Api) handleCreateApiKey(w http.ResponseWriter, r *http.Request) {
+	if !api.requireApiKeys(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.CreateApiKeyReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	var expiresAt *time.Time
+	if data.ExpiresInDays != nil {
+		t := time.Now().AddDate(0, 0, *data.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	userID, _ := currentUserID(r)
+	created, err := api.ApiKeys.CreateApiKey(r.Context(), userID, data.Name, data.Scopes, expiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidScope):
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "invalid scope; use " + strings.Join(services.ValidScopes, " or "),
+			})
+		case errors.Is(err, services.ErrApiKeyLimit):
+			_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": fmt.Sprintf("at most %d active API keys per user; revoke one first", services.MaxApiKeysPerUser),
+			})
+		default:
+			logger.Log.Error("Failed to create API key",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, dto.CreatedApiKeyResponse{
+		ApiKeyResponse: toApiKeyResponse(&created.ApiKey),
+		Key:            created.Secret,
+	})
+}
+
+// handleRevokeApiKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key of the logged-in user. Requests with it fail from now on; it stays in the list with revoked_at.
+// @Tags users
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "API key not found or already revoked"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/api-keys/{id} [delete]
+func (api *Api) handleRevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	if !api.requireApiKeys(w, r) {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid id",
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if err := api.ApiKeys.RevokeApiKey(r.Context(), userID, id); err != nil {
+		if errors.Is(err, services.ErrApiKeyNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "API key not found",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to revoke API key",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toApiKeyResponse(k *pgstore.ApiKey) dto.ApiKeyResponse {
+	return dto.ApiKeyResponse{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  formatOptionalTime(k.ExpiresAt.Time),
+		LastUsedAt: formatOptionalTime(k.LastUsedAt.Time),
+		RevokedAt:  formatOptionalTime(k.RevokedAt.Time),
+		CreatedAt:  k.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func (api *Api) requireApiKeys(w http.ResponseWriter, r *http.Request) bool {
+	if api.ApiKeys == nil {
+		logger.Log.Error("API key service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "API keys not configured",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupApiKeyAPI() (*Api, *mocks.MockCatalogImportService, *mocks.MockApiKeyService) {
+	api, mockCatalog := setupCatalogAPI()
+	mockKeys := new(mocks.MockApiKeyService)
+	api.ApiKeys = mockKeys
+	return api, mockCatalog, mockKeys
+}
+
+func testApiKey(userID uuid.UUID, scopes ...string) *pgstore.ApiKey {
+	return &pgstore.ApiKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      "etl",
+		Prefix:    "gbk_abcdefgh",
+		Scopes:    scopes,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func apiKeyRequest(api *Api, method, path, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-API-Key", secret)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddleware_ApiKeyScopes(t *testing.T) {
+	api, mockCatalog, mockKeys := setupApiKeyAPI()
+	userID := uuid.New()
+	mockKeys.On("AuthenticateApiKey", mock.Anything, "gbk_read").Return(testApiKey(userID, services.ScopeCatalogRead), nil)
+	mockCatalog.On("GetCatalogStats", mock.Anything).Return(&dto.CatalogStatsResponse{}, nil)
+
+	rec := apiKeyRequest(api, http.MethodGet, "/api/v1/catalog/stats", "gbk_read")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// admin role, but the key lacks catalog:import
+	rec = apiKeyRequest(api, http.MethodPost, "/api/v1/catmat/import", "gbk_read")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "catalog:import")
+	mockCatalog.AssertNotCalled(t, "ImportCatmat", mock.Anything, mock.Anything)
+
+	// routes without a scope refuse keys
+	for _, path := range []string{"/api/v1/me/saved-searches", "/api/v1/users/api-keys", "/api/v1/admin/users"} {
+		rec = apiKeyRequest(api, http.MethodGet, path, "gbk_read")
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+}
+
+func TestAuthMiddleware_InvalidApiKey(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+	mockKeys.On("AuthenticateApiKey", mock.Anything, "gbk_revoked").Return(nil, services.ErrInvalidApiKey)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/stats", nil)
+	req.Header.Set("X-API-Key", "gbk_revoked")
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "a bad key is not rescued by the session")
+}
+
+func TestHandleCreateApiKey(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+	userID := uuid.New()
+	key := testApiKey(userID, services.ScopeCatalogRead)
+	mockKeys.On("CreateApiKey", mock.Anything, userID, "etl", []string{"catalog:read"}, mock.MatchedBy(func(t *time.Time) bool {
+		return t != nil && time.Until(*t) > 29*24*time.Hour
+	})).Return(&services.CreatedApiKey{ApiKey: *key, Secret: "gbk_abcdefgh-secret"}, nil)
+	days := 30
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/api-keys", dto.CreateApiKeyReq{
+		Name:          "etl",
+		Scopes:        []string{"catalog:read"},
+		ExpiresInDays: &days,
+	}, authCookie(api, userID))
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var response dto.CreatedApiKeyResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "gbk_abcdefgh-secret", response.Key)
+	assert.Equal(t, key.ID.String(), response.ID)
+	assert.Equal(t, []string{"catalog:read"}, response.Scopes)
+}
+
+func TestHandleCreateApiKey_InvalidScope(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+	userID := uuid.New()
+	mockKeys.On("CreateApiKey", mock.Anything, userID, "etl", []string{"catalog:write"}, (*time.Time)(nil)).
+		Return(nil, services.ErrInvalidScope)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/api-keys", dto.CreateApiKeyReq{
+		Name:   "etl",
+		Scopes: []string{"catalog:write"},
+	}, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestHandleCreateApiKey_ValidationError(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/api-keys", dto.CreateApiKeyReq{Name: "etl"}, authCookie(api, uuid.New()))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockKeys.AssertNotCalled(t, "CreateApiKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleRevokeApiKey(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+	userID := uuid.New()
+	keyID := uuid.New()
+	mockKeys.On("RevokeApiKey", mock.Anything, userID, keyID).Return(nil).Once()
+	mockKeys.On("RevokeApiKey", mock.Anything, userID, keyID).Return(services.ErrApiKeyNotFound)
+	cookie := authCookie(api, userID)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/api-keys/"+keyID.String(), nil, cookie)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodDelete, "/api/v1/users/api-keys/"+keyID.String(), nil, cookie)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleListApiKeys(t *testing.T) {
+	api, _, mockKeys := setupApiKeyAPI()
+	userID := uuid.New()
+	key := testApiKey(userID, services.ScopeCatalogRead)
+	key.LastUsedAt.Time = time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)
+	key.LastUsedAt.Valid = true
+	mockKeys.On("ListApiKeys", mock.Anything, userID).Return([]pgstore.ApiKey{*key}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/api-keys", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response []dto.ApiKeyResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "gbk_abcdefgh", response[0].Prefix)
+	require.NotNil(t, response[0].LastUsedAt)
+	assert.Equal(t, "2026-03-02T08:30:00Z", *response[0].LastUsedAt)
+	assert.NotContains(t, rec.Body.String(), "key_hash")
+}
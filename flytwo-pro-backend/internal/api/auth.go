@@ -1,11 +1,40 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
 	"github.com/gorilla/csrf"
+	"go.uber.org/zap"
 	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/tenant"
 	"net/http"
+	"slices"
+	"strings"
 )
 
+type contextKey string
+
+// userIDContextKey holds the uuid.UUID of the user AuthMiddleware let through.
+const userIDContextKey contextKey = "userID"
+
+// apiKeyScopesContextKey holds the scopes of the API key a request was
+// authenticated with. Session and bearer requests do not have it.
+const apiKeyScopesContextKey contextKey = "apiKeyScopes"
+
+// apiKeyHeader carries the API keys of scripts.
+const apiKeyHeader = "X-API-Key"
+
+// organizationHeader selects the organization of a single request, for
+// bearer tokens and API keys, which have no session to store it in.
+const organizationHeader = "X-Organization-ID"
+
+// activeOrganizationKey is the session key of the uuid.UUID of the
+// organization selected with PUT /users/me/organization.
+const activeOrganizationKey = "ActiveOrganizationId"
+
 func (api *Api) HandleGetCSRFToken(w http.ResponseWriter, r *http.Request) {
 	token := csrf.Token(r)
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
@@ -13,14 +42,319 @@ func (api *Api) HandleGetCSRFToken(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// AuthMiddleware accepts an X-API-Key header, an "Authorization: Bearer"
+// access token or a session cookie, in this order, and stores the user id in
+// the request context. A key or bearer header that fails validation is
+// rejected without looking at the others.
 func (api *Api) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !api.Sessions.Exists(r.Context(), "AuthenticatedUserId") {
+		var userID uuid.UUID
+		var scopes []string
+		var ok bool
+		secret := r.Header.Get(apiKeyHeader)
+		if secret != "" {
+			userID, scopes, ok = api.apiKeyUser(r.Context(), secret)
+		} else if token, isBearer := bearerToken(r); isBearer {
+			userID, ok = api.bearerUserID(token)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+		} else {
+			userID, ok = api.sessionUserID(r)
+			if ok {
+				api.touchSession(r)
+			}
+		}
+
+		if !ok {
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"message": "must be logged in",
+			})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		if secret != "" {
+			ctx = context.WithValue(ctx, apiKeyScopesContextKey, scopes)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireScope lets through API keys holding scope; sessions and bearer
+// tokens are not limited by scopes. It must run after AuthMiddleware.
+func (api *Api) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scopes, isApiKey := apiKeyScopes(r); isApiKey && !slices.Contains(scopes, scope) {
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+					"error": "API key lacks the " + scope + " scope",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RejectApiKeys keeps API keys out of routes without a scope, such as
+// account management. It must run after AuthMiddleware.
+func (api *Api) RejectApiKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, isApiKey := apiKeyScopes(r); isApiKey {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "API keys cannot access this endpoint",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole lets through users holding one of roles; admins always pass.
+// It must run after AuthMiddleware. Roles are read on every request, so a
+// change takes effect immediately, also for bearer tokens already issued.
+func (api *Api) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := currentUserID(r)
+			if !ok {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"message": "must be logged in",
+				})
+				return
+			}
+			if api.Roles == nil {
+				logger.Log.Error("Role service not configured")
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected internal server error",
+				})
+				return
+			}
+
+			userRoles, err := api.Roles.GetUserRoles(r.Context(), userID)
+			if err != nil {
+				logger.Log.Error("Failed to load user roles",
+					zap.Error(err),
+					zap.String("user_id", userID.String()))
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected internal server error",
+				})
+				return
+			}
+			if !services.HasAnyRole(userRoles, roles...) {
+				logger.Log.Warn("Access denied - missing role",
+					zap.String("user_id", userID.String()),
+					zap.Strings("required", roles),
+					zap.String("path", r.URL.Path))
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+					"error": "insufficient permissions",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireVerifiedEmail rejects users who have not confirmed their email
+// yet. It must run after AuthMiddleware; without an EmailVerifier
+// configured, verification is not enforced.
+func (api *Api) RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.EmailVerifier == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, ok := currentUserID(r)
+		if !ok {
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"message": "must be logged in",
+			})
+			return
+		}
+
+		verified, err := api.EmailVerifier.IsEmailVerified(r.Context(), userID)
+		if err != nil {
+			logger.Log.Error("Failed to check email verification",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+		if !verified {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "email not verified",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireTwoFactorEnrollment rejects users whose role requires two-factor
+// authentication until they enable it. It must run after AuthMiddleware;
+// without a TwoFactor service it lets everyone through.
+func (api *Api) RequireTwoFactorEnrollment(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.TwoFactor == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, ok := currentUserID(r)
+		if !ok {
 			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
 				"message": "must be logged in",
 			})
 			return
 		}
+
+		status, err := api.TwoFactor.Status(r.Context(), userID)
+		if err != nil {
+			logger.Log.Error("Failed to check two-factor status",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+		if status.Required && !status.Enabled {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "two-factor authentication required; enable it at /api/v1/users/2fa",
+			})
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// ResolveOrganization puts the organization the request acts on behalf of
+// into the context (see package tenant): the one named by the
+// X-Organization-ID header or, without it, the one selected in the session.
+// The user must be a member of it; a session selection the user lost access
+// to is dropped. It must run after AuthMiddleware; without an Organizations
+// service requests have no organization.
+func (api *Api) ResolveOrganization(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.Organizations == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, ok := currentUserID(r)
+		if !ok {
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"message": "must be logged in",
+			})
+			return
+		}
+
+		var orgID uuid.UUID
+		fromSession := false
+		if header := r.Header.Get(organizationHeader); header != "" {
+			id, err := uuid.Parse(header)
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+					"error": "invalid " + organizationHeader + " header",
+				})
+				return
+			}
+			orgID = id
+		} else if id, ok := api.Sessions.Get(r.Context(), activeOrganizationKey).(uuid.UUID); ok {
+			orgID, fromSession = id, true
+		} else {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		membership, err := api.Organizations.GetMembership(r.Context(), orgID, userID)
+		if err != nil {
+			if errors.Is(err, services.ErrNotOrganizationMember) {
+				if fromSession {
+					api.Sessions.Remove(r.Context(), activeOrganizationKey)
+				}
+				logger.Log.Warn("Access denied - not an organization member",
+					zap.String("user_id", userID.String()),
+					zap.String("organization_id", orgID.String()))
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+					"error": "not a member of the organization",
+				})
+				return
+			}
+			logger.Log.Error("Failed to check organization membership",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+				zap.String("organization_id", orgID.String()))
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+
+		ctx := tenant.WithOrganization(r.Context(), tenant.Organization{ID: orgID, Role: membership.Role})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// currentUserID returns the id of the user AuthMiddleware authenticated.
+func currentUserID(r *http.Request) (uuid.UUID, bool) {
+	userID, ok := r.Context().Value(userIDContextKey).(uuid.UUID)
+	return userID, ok
+}
+
+// apiKeyScopes returns the scopes of the API key the request was
+// authenticated with, and whether it was authenticated with one.
+func apiKeyScopes(r *http.Request) ([]string, bool) {
+	scopes, ok := r.Context().Value(apiKeyScopesContextKey).([]string)
+	return scopes, ok
+}
+
+// sessionUserID returns the authenticated user id stored in the session, if any.
+func (api *Api) sessionUserID(r *http.Request) (uuid.UUID, bool) {
+	userID, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	return userID, ok
+}
+
+func (api *Api) bearerUserID(token string) (uuid.UUID, bool) {
+	if api.Tokens == nil {
+		return uuid.Nil, false
+	}
+	userID, err := api.Tokens.ParseAccessToken(token)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// apiKeyUser returns the owner and scopes of a valid API key.
+func (api *Api) apiKeyUser(ctx context.Context, secret string) (uuid.UUID, []string, bool) {
+	if api.ApiKeys == nil {
+		return uuid.Nil, nil, false
+	}
+	key, err := api.ApiKeys.AuthenticateApiKey(ctx, strings.TrimSpace(secret))
+	if err != nil {
+		if !errors.Is(err, services.ErrInvalidApiKey) {
+			logger.Log.Error("Failed to authenticate API key", zap.Error(err))
+		}
+		return uuid.Nil, nil, false
+	}
+	return key.UserID, key.Scopes, true
+}
+
+// bearerToken reports whether the request carries an Authorization header
+// with the Bearer scheme, and its token.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
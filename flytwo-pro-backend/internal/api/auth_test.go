@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"gobid/internal/mocks"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -85,6 +87,87 @@ func TestAuthMiddleware_InvalidSessionData(t *testing.T) {
 
 	api.Sessions.LoadAndSave(api.AuthMiddleware(nextHandler)).ServeHTTP(recorder, req)
 
-	// Middleware only checks existence, so it should pass through
+	// A session value that is not a user id does not authenticate
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	// Arrange
+	api, _ := setupTestAPI()
+	mockTokens := new(mocks.MockTokenService)
+	api.Tokens = mockTokens
+
+	userID := uuid.New()
+	mockTokens.On("ParseAccessToken", "valid-token").Return(userID, nil)
+
+	var seen uuid.UUID
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = currentUserID(r)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	api.Sessions.LoadAndSave(api.AuthMiddleware(nextHandler)).ServeHTTP(recorder, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, userID, seen)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_InvalidBearerToken(t *testing.T) {
+	// Arrange
+	api, _ := setupTestAPI()
+	mockTokens := new(mocks.MockTokenService)
+	api.Tokens = mockTokens
+	mockTokens.On("ParseAccessToken", "expired-token").Return(uuid.Nil, errors.New("invalid or expired token"))
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// a valid session does not rescue an invalid bearer token
+	setReq := httptest.NewRequest(http.MethodGet, "/set-session", nil)
+	setRec := httptest.NewRecorder()
+	api.Sessions.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.Sessions.Put(r.Context(), "AuthenticatedUserId", uuid.New())
+	})).ServeHTTP(setRec, setReq)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(setRec.Result().Cookies()[0])
+	req.Header.Set("Authorization", "Bearer expired-token")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	api.Sessions.LoadAndSave(api.AuthMiddleware(nextHandler)).ServeHTTP(recorder, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "invalid_token")
+}
+
+func TestAuthMiddleware_SessionUserInContext(t *testing.T) {
+	// Arrange
+	api, _ := setupCatalogAPI()
+	userID := uuid.New()
+
+	var seen uuid.UUID
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = currentUserID(r)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(authCookie(api, userID))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	api.Sessions.LoadAndSave(api.AuthMiddleware(nextHandler)).ServeHTTP(recorder, req)
+
+	// Assert
 	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, userID, seen)
 }
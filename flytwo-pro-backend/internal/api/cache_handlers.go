@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/cache"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"math"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// handleCacheStats godoc
+// @Summary Estatísticas do cache
+// @Description Hits e misses por camada (L1 ristretto, L2 Redis ou Postgres) e por prefixo de chave, evicções do L1, erros de serialização e estado do L2. Contadores desde a subida da API.
+// @Tags cache
+// @Produce json
+// @Success 200 {object} dto.CacheStatsResponse "Estatísticas do cache"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/cache/stats [get]
+func (api *Api) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	if !api.requireCache(w, r) {
+		return
+	}
+
+	stats := api.Cache.Stats()
+
+	response := dto.CacheStatsResponse{
+		L1:                  toCacheTierStats(stats.L1),
+		L2:                  toCacheTierStats(stats.L2),
+		L1Evictions:         stats.L1Evictions,
+		L1CostUsed:          stats.L1CostUsed,
+		SerializationErrors: stats.SerializationErrors,
+		Prefixes:            make([]dto.CachePrefixStats, 0, len(stats.Prefixes)),
+		L2Health:            toCacheHealthResponse(stats.Health),
+	}
+	for prefix, p := range stats.Prefixes {
+		response.Prefixes = append(response.Prefixes, dto.CachePrefixStats{
+			Prefix:     prefix,
+			L1Hits:     p.L1Hits,
+			L2Hits:     p.L2Hits,
+			Misses:     p.Misses,
+			StaleHits:  p.StaleHits,
+			Loads:      p.Loads,
+			LoadErrors: p.LoadErrs,
+			HitRatio:   hitRatio(p.L1Hits+p.L2Hits, p.Misses),
+		})
+	}
+	sort.Slice(response.Prefixes, func(i, j int) bool {
+		return response.Prefixes[i].Prefix < response.Prefixes[j].Prefix
+	})
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handlePurgeCache godoc
+// @Summary Remove entradas do cache por prefixo
+// @Description Apaga do L2 as chaves que começam com o prefixo (ex: "catmat:", "catalog:stats") usando SCAN no Redis ou LIKE no Postgres, e limpa o L1 de todas as instâncias.
+// @Tags cache
+// @Accept json
+// @Produce json
+// @Param purge body dto.CachePurgeReq true "Prefixo das chaves"
+// @Success 200 {object} dto.CachePurgeResponse "Chaves removidas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 503 {object} map[string]interface{} "L2 indisponível"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/cache/purge [post]
+func (api *Api) handlePurgeCache(w http.ResponseWriter, r *http.Request) {
+	if !api.requireCache(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.CachePurgeReq](r)
+	if err != nil {
+		logger.Log.Debug("Validação falhou para limpeza do cache",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	deleted, err := api.Cache.Purge(r.Context(), data.Prefix)
+	if err != nil {
+		api.writeCacheError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Cache limpo por prefixo",
+		zap.String("prefix", data.Prefix),
+		zap.Int64("deleted", deleted))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.CachePurgeResponse{Prefix: data.Prefix, Deleted: deleted})
+}
+
+// handleFlushCache godoc
+// @Summary Limpa todo o cache
+// @Description Apaga todas as entradas do cache no L2 (no Redis, SCAN no banco configurado em GOBID_REDIS_DB) e no L1 de todas as instâncias. Os contadores de geração são mantidos.
+// @Tags cache
+// @Produce json
+// @Success 200 {object} dto.CachePurgeResponse "Chaves removidas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 503 {object} map[string]interface{} "L2 indisponível"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/cache/flush [post]
+func (api *Api) handleFlushCache(w http.ResponseWriter, r *http.Request) {
+	if !api.requireCache(w, r) {
+		return
+	}
+
+	deleted, err := api.Cache.Flush(r.Context())
+	if err != nil {
+		api.writeCacheError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Cache limpo", zap.Int64("deleted", deleted))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.CachePurgeResponse{Deleted: deleted})
+}
+
+func (api *Api) requireCache(w http.ResponseWriter, r *http.Request) bool {
+	if api.Cache == nil {
+		logger.Log.Error("Cache não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "cache não configurado",
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) writeCacheError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, cache.ErrL2Unavailable) {
+		_ = jsonutils.EncodeJson(w, r, http.StatusServiceUnavailable, map[string]any{
+			"error": "L2 do cache indisponível; apenas o L1 desta instância foi limpo",
+		})
+		return
+	}
+
+	logger.Log.Error("Erro ao limpar cache", zap.Error(err))
+	_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+		"error": "falha ao limpar cache",
+	})
+}
+
+func toCacheTierStats(t cache.TierStats) dto.CacheTierStats {
+	return dto.CacheTierStats{
+		Hits:     t.Hits,
+		Misses:   t.Misses,
+		HitRatio: hitRatio(t.Hits, t.Misses),
+	}
+}
+
+// hitRatio returns hits/(hits+misses) rounded to four decimals.
+func hitRatio(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return math.Round(float64(hits)/float64(hits+misses)*10000) / 10000
+}
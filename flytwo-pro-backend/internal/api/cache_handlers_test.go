@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/cache"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCacheStats_Success(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	api := setupCacheAPI(mockCache)
+
+	mockCache.On("Stats").Return(cache.Stats{
+		L1: cache.TierStats{Hits: 3, Misses: 1},
+		L2: cache.TierStats{Hits: 0, Misses: 1},
+		Prefixes: map[string]cache.PrefixStats{
+			"catser": {Misses: 1, Loads: 1},
+			"catmat": {L1Hits: 3},
+		},
+		L1Evictions: 2,
+		Health:      cache.Health{L2State: cache.L2Up},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/stats", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.CacheStatsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 0.75, resp.L1.HitRatio)
+	assert.Equal(t, 0.0, resp.L2.HitRatio)
+	assert.Equal(t, uint64(2), resp.L1Evictions)
+	assert.Len(t, resp.Prefixes, 2)
+	assert.Equal(t, "catmat", resp.Prefixes[0].Prefix)
+	assert.Equal(t, 1.0, resp.Prefixes[0].HitRatio)
+	assert.Equal(t, "up", resp.L2Health.L2State)
+	mockCache.AssertExpectations(t)
+}
+
+func TestHandleCacheStats_Unauthorized(t *testing.T) {
+	api := setupCacheAPI(new(mocks.MockCache))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/stats", nil)
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHandlePurgeCache_Success(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	api := setupCacheAPI(mockCache)
+
+	mockCache.On("Purge", mock.Anything, "catmat:").Return(int64(120), nil)
+
+	body, _ := json.Marshal(dto.CachePurgeReq{Prefix: "catmat:"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/purge", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.CachePurgeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(120), resp.Deleted)
+	assert.Equal(t, "catmat:", resp.Prefix)
+	mockCache.AssertExpectations(t)
+}
+
+func TestHandlePurgeCache_EmptyPrefix(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	api := setupCacheAPI(mockCache)
+
+	body, _ := json.Marshal(dto.CachePurgeReq{})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/purge", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockCache.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
+
+func TestHandleFlushCache_RedisUnavailable(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	api := setupCacheAPI(mockCache)
+
+	mockCache.On("Flush", mock.Anything).Return(int64(0), cache.ErrL2Unavailable)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	mockCache.AssertExpectations(t)
+}
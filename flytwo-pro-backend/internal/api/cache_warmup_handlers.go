@@ -0,0 +1,85 @@
+package api
+
+import (
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// handleCacheWarmupStatus godoc
+// @Summary Estado do aquecimento do cache
+// @Description Informa se um aquecimento está em andamento e o resultado do último: buscas aquecidas (configuradas em GOBID_CACHE_WARMUP_QUERIES e as mais frequentes do log de buscas), falhas e se as estatísticas do catálogo foram pré-calculadas.
+// @Tags cache
+// @Produce json
+// @Success 200 {object} dto.CacheWarmupStatusResponse "Estado do aquecimento"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/cache/warmup [get]
+func (api *Api) handleCacheWarmupStatus(w http.ResponseWriter, r *http.Request) {
+	if !api.requireCacheWarmup(w, r) {
+		return
+	}
+
+	running, last := api.CacheWarmup.Status()
+	response := dto.CacheWarmupStatusResponse{Running: running}
+	if last != nil {
+		response.LastRun = &dto.CacheWarmupRun{
+			Reason:      last.Reason,
+			StartedAt:   last.StartedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			FinishedAt:  last.FinishedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			DurationMs:  last.FinishedAt.Sub(last.StartedAt).Milliseconds(),
+			Queries:     last.Queries,
+			Warmed:      last.Warmed,
+			Failed:      last.Failed,
+			StatsWarmed: last.Stats,
+		}
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleTriggerCacheWarmup godoc
+// @Summary Dispara o aquecimento do cache
+// @Description Enfileira um aquecimento em segundo plano. Se já houver um pendente, ele atende este pedido e queued é false.
+// @Tags cache
+// @Produce json
+// @Success 202 {object} dto.CacheWarmupTriggerResponse "Aquecimento enfileirado"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/cache/warmup [post]
+func (api *Api) handleTriggerCacheWarmup(w http.ResponseWriter, r *http.Request) {
+	if !api.requireCacheWarmup(w, r) {
+		return
+	}
+
+	queued := api.CacheWarmup.Trigger("admin")
+	logger.Log.Info("Aquecimento do cache solicitado", zap.Bool("queued", queued))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusAccepted, dto.CacheWarmupTriggerResponse{Queued: queued})
+}
+
+func (api *Api) requireCacheWarmup(w http.ResponseWriter, r *http.Request) bool {
+	if api.CacheWarmup == nil {
+		logger.Log.Error("Aquecimento do cache não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "aquecimento do cache não configurado",
+		})
+		return false
+	}
+	return true
+}
+
+// warmCache re-populates the cache after an import invalidated a catalog.
+func (api *Api) warmCache(catalog string) {
+	if api.CacheWarmup == nil {
+		return
+	}
+	api.CacheWarmup.Trigger("import " + catalog)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCacheWarmupAPI() (*Api, *mocks.MockCatalogImportService, *mocks.MockCacheWarmupService) {
+	api, mockCatalog := setupCatalogAPI()
+	mockWarmup := new(mocks.MockCacheWarmupService)
+	api.CacheWarmup = mockWarmup
+	return api, mockCatalog, mockWarmup
+}
+
+func TestHandleCacheWarmupStatus(t *testing.T) {
+	api, _, mockWarmup := setupCacheWarmupAPI()
+
+	started := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	mockWarmup.On("Status").Return(false, &services.WarmupReport{
+		Reason:     "startup",
+		StartedAt:  started,
+		FinishedAt: started.Add(1200 * time.Millisecond),
+		Queries:    5,
+		Warmed:     4,
+		Failed:     1,
+		Stats:      true,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/warmup", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.CacheWarmupStatusResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Running)
+	assert.Equal(t, &dto.CacheWarmupRun{
+		Reason:      "startup",
+		StartedAt:   "2025-06-01T08:00:00Z",
+		FinishedAt:  "2025-06-01T08:00:01Z",
+		DurationMs:  1200,
+		Queries:     5,
+		Warmed:      4,
+		Failed:      1,
+		StatsWarmed: true,
+	}, resp.LastRun)
+	mockWarmup.AssertExpectations(t)
+}
+
+func TestHandleCacheWarmupStatus_NeverRan(t *testing.T) {
+	api, _, mockWarmup := setupCacheWarmupAPI()
+	mockWarmup.On("Status").Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/warmup", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"running":true}`, rec.Body.String())
+}
+
+func TestHandleTriggerCacheWarmup(t *testing.T) {
+	api, _, mockWarmup := setupCacheWarmupAPI()
+	mockWarmup.On("Trigger", "admin").Return(true)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/warmup", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"queued":true}`, rec.Body.String())
+	mockWarmup.AssertExpectations(t)
+}
+
+func TestHandleTriggerCacheWarmup_NotConfigured(t *testing.T) {
+	api, _ := setupCatalogAPI()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/warmup", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandleImportCatser_TriggersCacheWarmup(t *testing.T) {
+	api, mockCatalog, mockWarmup := setupCacheWarmupAPI()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "dummy.xlsx")
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte("dummy"))
+	writer.Close()
+
+	mockCatalog.On("ImportCatser", mock.Anything, mock.Anything).Return(&services.ImportResult{RowsRead: 1, RowsSaved: 1}, nil)
+	mockWarmup.On("Trigger", "import catser").Return(true)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/catser/import", body)
+	req.AddCookie(authCookie(api, uuid.New()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockWarmup.AssertExpectations(t)
+}
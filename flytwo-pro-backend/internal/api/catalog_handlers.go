@@ -7,6 +7,7 @@ import (
 	"gobid/internal/services"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -20,7 +21,10 @@ import (
 // @Param file formData file true "Arquivo .xlsx com colunas do CATMAT"
 // @Success 200 {object} services.ImportResult
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
 // @Failure 500 {object} map[string]interface{}
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /catmat/import [post]
 func (api *Api) handleImportCatmat(w http.ResponseWriter, r *http.Request) {
 	if api.CatalogService == nil {
@@ -60,6 +64,11 @@ func (api *Api) handleImportCatmat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if result.RowsSaved > 0 {
+		api.evaluateSavedSearches("catmat")
+		api.warmCache("catmat")
+	}
+
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, result)
 }
 
@@ -72,7 +81,10 @@ func (api *Api) handleImportCatmat(w http.ResponseWriter, r *http.Request) {
 // @Param file formData file true "Arquivo .xlsx com colunas do CATSER"
 // @Success 200 {object} services.ImportResult
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
 // @Failure 500 {object} map[string]interface{}
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /catser/import [post]
 func (api *Api) handleImportCatser(w http.ResponseWriter, r *http.Request) {
 	if api.CatalogService == nil {
@@ -112,6 +124,11 @@ func (api *Api) handleImportCatser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if result.RowsSaved > 0 {
+		api.evaluateSavedSearches("catser")
+		api.warmCache("catser")
+	}
+
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, result)
 }
 
@@ -131,6 +148,7 @@ func (api *Api) handleImportCatser(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} dto.CatmatSearchResponse "Resultados da busca paginados"
 // @Failure 401 {object} map[string]interface{} "Não autenticado"
 // @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
 // @Security ApiKeyAuth
 // @Router /catmat/search [get]
 func (api *Api) handleSearchCatmat(w http.ResponseWriter, r *http.Request) {
@@ -185,6 +203,7 @@ func (api *Api) handleSearchCatmat(w http.ResponseWriter, r *http.Request) {
 		zap.Any("pdm_code", params.PdmCode),
 		zap.Any("ncm_code", params.NcmCode))
 
+	start := time.Now()
 	result, err := api.CatalogService.SearchCatmat(r.Context(), params)
 	if err != nil {
 		logger.Log.Error("Erro ao pesquisar CATMAT",
@@ -209,6 +228,7 @@ func (api *Api) handleSearchCatmat(w http.ResponseWriter, r *http.Request) {
 		Limit:  result.Limit,
 		Offset: result.Offset,
 	}
+	response.SearchID = api.recordSearch(r, "catmat", params.Query, params, result.Total, time.Since(start))
 
 	for i, item := range result.Data {
 		response.Data[i] = dto.CatmatSearchItem{
@@ -249,6 +269,7 @@ func (api *Api) handleSearchCatmat(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} dto.CatserSearchResponse "Resultados da busca paginados"
 // @Failure 401 {object} map[string]interface{} "Não autenticado"
 // @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
 // @Security ApiKeyAuth
 // @Router /catser/search [get]
 func (api *Api) handleSearchCatser(w http.ResponseWriter, r *http.Request) {
@@ -303,6 +324,7 @@ func (api *Api) handleSearchCatser(w http.ResponseWriter, r *http.Request) {
 		zap.Any("service_code", params.ServiceCode),
 		zap.Any("status", params.Status))
 
+	start := time.Now()
 	result, err := api.CatalogService.SearchCatser(r.Context(), params)
 	if err != nil {
 		logger.Log.Error("Erro ao pesquisar CATSER",
@@ -327,6 +349,7 @@ func (api *Api) handleSearchCatser(w http.ResponseWriter, r *http.Request) {
 		Limit:  result.Limit,
 		Offset: result.Offset,
 	}
+	response.SearchID = api.recordSearch(r, "catser", params.Query, params, result.Total, time.Since(start))
 
 	for i, item := range result.Data {
 		response.Data[i] = dto.CatserSearchItem{
@@ -370,6 +393,7 @@ func parseIntParam(value string, defaultVal int32) int32 {
 // @Success 200 {object} dto.CatalogStatsResponse "Estatísticas do catálogo"
 // @Failure 401 {object} map[string]interface{} "Não autenticado"
 // @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
 // @Security ApiKeyAuth
 // @Router /catalog/stats [get]
 func (api *Api) handleCatalogStats(w http.ResponseWriter, r *http.Request) {
@@ -401,3 +425,99 @@ func (api *Api) handleCatalogStats(w http.ResponseWriter, r *http.Request) {
 
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, stats)
 }
+
+// handleCatalogExtendedStats godoc
+// @Summary Obtém estatísticas detalhadas do catálogo
+// @Description Retorna cobertura de NCM e embeddings, última importação de cada catálogo e o histórico de importações com o total de itens ao longo do tempo
+// @Tags catalog
+// @Produce json
+// @Param days query int false "Dias de histórico de importações (padrão 90, máximo 730)"
+// @Success 200 {object} dto.CatalogExtendedStatsResponse "Estatísticas detalhadas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /catalog/stats/extended [get]
+func (api *Api) handleCatalogExtendedStats(w http.ResponseWriter, r *http.Request) {
+	if api.CatalogService == nil {
+		logger.Log.Error("CatalogService não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de estatísticas indisponível",
+		})
+		return
+	}
+
+	days := parseIntParam(r.URL.Query().Get("days"), 90)
+	if days <= 0 {
+		days = 90
+	}
+	if days > 730 {
+		days = 730
+	}
+
+	stats, err := api.CatalogService.GetCatalogExtendedStats(r.Context(), int(days))
+	if err != nil {
+		logger.Log.Error("Erro ao obter estatísticas detalhadas do catálogo", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao obter estatísticas",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, stats)
+}
+
+// handleCatalogClassStats godoc
+// @Summary Obtém a quantidade de itens por classe de um grupo
+// @Description Detalha um grupo do catálogo CATMAT ou CATSER por classe
+// @Tags catalog
+// @Produce json
+// @Param catalog query string true "catmat ou catser"
+// @Param group_code query int true "Código do grupo"
+// @Success 200 {object} dto.CatalogClassStatsResponse "Itens por classe"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /catalog/stats/classes [get]
+func (api *Api) handleCatalogClassStats(w http.ResponseWriter, r *http.Request) {
+	if api.CatalogService == nil {
+		logger.Log.Error("CatalogService não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de estatísticas indisponível",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	catalog := query.Get("catalog")
+	if catalog != "catmat" && catalog != "catser" {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "catalog deve ser 'catmat' ou 'catser'",
+		})
+		return
+	}
+
+	groupCode, err := strconv.ParseInt(query.Get("group_code"), 10, 16)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "group_code inválido",
+		})
+		return
+	}
+
+	stats, err := api.CatalogService.GetCatalogClassStats(r.Context(), catalog, int16(groupCode))
+	if err != nil {
+		logger.Log.Error("Erro ao obter estatísticas por classe",
+			zap.Error(err),
+			zap.String("catalog", catalog),
+			zap.Int64("group_code", groupCode))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao obter estatísticas",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, stats)
+}
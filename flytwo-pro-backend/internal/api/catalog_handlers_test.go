@@ -30,6 +30,7 @@ func setupCatalogAPI() (*Api, *mocks.MockCatalogImportService) {
 		Router:         chi.NewMux(),
 		UserService:    new(mocks.MockUserService), // not used here
 		CatalogService: mockCatalog,
+		Roles:          adminRoles(),
 		Sessions:       scs.New(),
 		WsUpgrader:     defaultUpgrader(),
 	}
@@ -41,6 +42,13 @@ func defaultUpgrader() websocket.Upgrader {
 	return websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
 }
 
+// adminRoles makes every user an admin, so role checks pass.
+func adminRoles() *mocks.MockRoleService {
+	roles := new(mocks.MockRoleService)
+	roles.On("GetUserRoles", mock.Anything, mock.Anything).Return([]string{"admin"}, nil).Maybe()
+	return roles
+}
+
 func TestHandleImportCatmat_Success(t *testing.T) {
 	api, mockCatalog := setupCatalogAPI()
 	userID := uuid.New()
@@ -485,3 +493,97 @@ func TestHandleCatalogStats_EmptyData(t *testing.T) {
 
 	mockCatalog.AssertExpectations(t)
 }
+
+func TestHandleCatalogExtendedStats_Success(t *testing.T) {
+	api, mockCatalog := setupCatalogAPI()
+
+	ncmShare := 82.5
+	expected := &dto.CatalogExtendedStatsResponse{
+		Catmat: dto.CatalogCoverage{
+			Total:                1000,
+			NcmSharePct:          &ncmShare,
+			WithEmbedding:        250,
+			EmbeddingCoveragePct: 25,
+			LastImport:           &dto.CatalogImportSummary{Catalog: "catmat", RowsSaved: 1000, ItemTotal: 1000},
+		},
+		Catser:  dto.CatalogCoverage{Total: 500},
+		History: []dto.CatalogImportSummary{{Catalog: "catmat", ItemTotal: 900}, {Catalog: "catmat", ItemTotal: 1000}},
+	}
+	mockCatalog.On("GetCatalogExtendedStats", mock.Anything, 30).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/stats/extended?days=30", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp dto.CatalogExtendedStatsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 82.5, *resp.Catmat.NcmSharePct)
+	assert.Nil(t, resp.Catser.NcmSharePct)
+	assert.Nil(t, resp.Catser.LastImport)
+	assert.Len(t, resp.History, 2)
+
+	mockCatalog.AssertExpectations(t)
+}
+
+func TestHandleCatalogExtendedStats_DefaultDays(t *testing.T) {
+	api, mockCatalog := setupCatalogAPI()
+
+	mockCatalog.On("GetCatalogExtendedStats", mock.Anything, 90).Return(&dto.CatalogExtendedStatsResponse{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/stats/extended", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockCatalog.AssertExpectations(t)
+}
+
+func TestHandleCatalogClassStats_Success(t *testing.T) {
+	api, mockCatalog := setupCatalogAPI()
+
+	expected := &dto.CatalogClassStatsResponse{
+		Catalog:   "catmat",
+		GroupCode: 75,
+		Total:     30,
+		Classes: []dto.ClassCount{
+			{ClassCode: 7510, ClassName: "Artigos de escritório", Count: 20},
+			{ClassCode: 7530, ClassName: "Papelaria", Count: 10},
+		},
+	}
+	mockCatalog.On("GetCatalogClassStats", mock.Anything, "catmat", int16(75)).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/stats/classes?catalog=catmat&group_code=75", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp dto.CatalogClassStatsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Classes, 2)
+	assert.Equal(t, int64(30), resp.Total)
+
+	mockCatalog.AssertExpectations(t)
+}
+
+func TestHandleCatalogClassStats_InvalidParams(t *testing.T) {
+	api, _ := setupCatalogAPI()
+
+	for _, query := range []string{"catalog=foo&group_code=75", "catalog=catser", "catalog=catmat&group_code=abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/stats/classes?"+query, nil)
+		req.AddCookie(authCookie(api, uuid.New()))
+		rec := httptest.NewRecorder()
+
+		api.Router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
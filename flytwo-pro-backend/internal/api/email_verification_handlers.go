@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleVerifyEmail godoc
+// @Summary Verify the account email
+// @Description Confirm the email address with the token from the verification link. The token works once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailReq true "Verification token"
+// @Success 200 {object} map[string]interface{} "Email verified"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/verify-email [post]
+func (api *Api) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if !api.requireEmailVerifier(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.VerifyEmailReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	userID, err := api.EmailVerifier.VerifyEmail(r.Context(), data.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid or expired verification token",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to verify email", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	logger.Log.Info("Email verified", zap.String("user_id", userID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "email verified",
+	})
+}
+
+// handleResendVerificationEmail godoc
+// @Summary Resend the verification email
+// @Description Email a new verification link to the logged-in user, invalidating earlier links. Resends are throttled; a 429 carries Retry-After in seconds.
+// @Tags users
+// @Produce json
+// @Success 202 {object} map[string]interface{} "Verification email sent"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Email already verified"
+// @Failure 429 {object} map[string]interface{} "Too many requests"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/verify-email/resend [post]
+func (api *Api) handleResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	if !api.requireEmailVerifier(w, r) {
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "authentication required",
+		})
+		return
+	}
+
+	wait, err := api.EmailVerifier.SendVerification(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "email already verified",
+			})
+		case errors.Is(err, services.ErrVerificationThrottled):
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			_ = jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{
+				"error":       "verification email sent too recently",
+				"retry_after": seconds,
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+		default:
+			logger.Log.Error("Failed to resend verification email",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"message": "verification email sent",
+	})
+}
+
+// handleSetEmailVerification godoc
+// @Summary Define a verificação de email de um usuário
+// @Description Marca o email do usuário como verificado ou não verificado, sem o link de verificação. Exige o papel admin.
+// @Tags users-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param request body dto.SetEmailVerificationReq true "Estado da verificação"
+// @Success 200 {object} dto.EmailVerificationResponse "Verificação atualizada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/email-verification [put]
+func (api *Api) handleSetEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if !api.requireEmailVerifier(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.SetEmailVerificationReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	if err := api.EmailVerifier.SetEmailVerified(r.Context(), userID, *data.Verified); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao atualizar verificação de email", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao atualizar verificação de email",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	logger.Log.Info("Verificação de email atualizada",
+		zap.String("user_id", userID.String()),
+		zap.Bool("verified", *data.Verified),
+		zap.String("by", adminID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.EmailVerificationResponse{
+		ID:            userID.String(),
+		EmailVerified: *data.Verified,
+	})
+}
+
+func (api *Api) requireEmailVerifier(w http.ResponseWriter, r *http.Request) bool {
+	if api.EmailVerifier == nil {
+		logger.Log.Error("Email verification service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "email verification not configured",
+		})
+		return false
+	}
+	return true
+}
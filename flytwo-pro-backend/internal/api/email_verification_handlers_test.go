@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupEmailVerificationAPI() (*Api, *mocks.MockUserService, *mocks.MockEmailVerificationService) {
+	api, mockUsers := setupTestAPI()
+	mockVerifier := new(mocks.MockEmailVerificationService)
+	api.EmailVerifier = mockVerifier
+	api.Roles = adminRoles()
+	return api, mockUsers, mockVerifier
+}
+
+func TestHandleSignupUser_SendsVerificationEmail(t *testing.T) {
+	api, mockUsers, mockVerifier := setupEmailVerificationAPI()
+	userID := uuid.New()
+	mockUsers.On("CreateUser", mock.Anything, "testuser", "test@example.com", "Test1234").Return(userID, nil)
+	mockVerifier.On("SendVerification", mock.Anything, userID).Return(time.Duration(0), nil)
+	api.Roles.(*mocks.MockRoleService).On("BootstrapAdmin", mock.Anything).Return(false, nil)
+
+	rec := postJSON(t, api, "/api/v1/users/signup", dto.CreateUserReq{
+		UserName: "testuser",
+		Email:    "test@example.com",
+		Password: "Test1234",
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockVerifier.AssertExpectations(t)
+}
+
+func TestHandleVerifyEmail_Success(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	mockVerifier.On("VerifyEmail", mock.Anything, "verify-token").Return(uuid.New(), nil)
+
+	rec := postJSON(t, api, "/api/v1/users/verify-email", dto.VerifyEmailReq{Token: "verify-token"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHandleVerifyEmail_InvalidToken(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	mockVerifier.On("VerifyEmail", mock.Anything, "used-token").Return(uuid.Nil, services.ErrInvalidVerificationToken)
+
+	rec := postJSON(t, api, "/api/v1/users/verify-email", dto.VerifyEmailReq{Token: "used-token"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleResendVerificationEmail_Throttled(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	userID := uuid.New()
+	mockVerifier.On("SendVerification", mock.Anything, userID).
+		Return(42500*time.Millisecond, services.ErrVerificationThrottled)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/verify-email/resend", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "43", rec.Header().Get("Retry-After"))
+}
+
+func TestHandleResendVerificationEmail_AlreadyVerified(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	userID := uuid.New()
+	mockVerifier.On("SendVerification", mock.Anything, userID).Return(time.Duration(0), services.ErrEmailAlreadyVerified)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/verify-email/resend", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleResendVerificationEmail_RequiresAuth(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+
+	rec := postJSON(t, api, "/api/v1/users/verify-email/resend", nil)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockVerifier.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+}
+
+func TestRequireVerifiedEmail_BlocksUnverifiedUser(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	userID := uuid.New()
+	mockVerifier.On("IsEmailVerified", mock.Anything, userID).Return(false, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/saved-searches", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "email not verified")
+}
+
+func TestHandleGetCurrentUser_ReportsEmailVerification(t *testing.T) {
+	api, mockUsers, _ := setupEmailVerificationAPI()
+	userID := uuid.New()
+	verifiedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{
+		ID:              userID,
+		UserName:        "testuser",
+		Email:           "test@example.com",
+		CreatedAt:       verifiedAt.Add(-time.Hour),
+		EmailVerifiedAt: pgtype.Timestamptz{Time: verifiedAt, Valid: true},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var profile dto.UserProfileResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&profile))
+	assert.True(t, profile.EmailVerified)
+	if assert.NotNil(t, profile.EmailVerifiedAt) {
+		assert.Equal(t, "2025-03-01T12:00:00Z", *profile.EmailVerifiedAt)
+	}
+}
+
+func TestHandleSetEmailVerification_Success(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockVerifier.On("IsEmailVerified", mock.Anything, adminID).Return(true, nil)
+	mockVerifier.On("SetEmailVerified", mock.Anything, userID, true).Return(nil)
+
+	body, _ := json.Marshal(map[string]any{"verified": true})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/users/"+userID.String()+"/email-verification", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockVerifier.AssertExpectations(t)
+}
+
+func TestHandleSetEmailVerification_UserNotFound(t *testing.T) {
+	api, _, mockVerifier := setupEmailVerificationAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockVerifier.On("IsEmailVerified", mock.Anything, adminID).Return(true, nil)
+	mockVerifier.On("SetEmailVerified", mock.Anything, userID, false).Return(services.ErrUserNotFound)
+
+	body, _ := json.Marshal(map[string]any{"verified": false})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/users/"+userID.String()+"/email-verification", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"gobid/internal/cache"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"net/http"
+	"time"
+)
+
+// handleHealth godoc
+// @Summary Estado da API
+// @Description Informa o backend (redis ou postgres) e o estado do cache L2: up, down, circuit_open ou disabled. Responde 200 mesmo com o L2 fora, pois a API segue funcionando só com o L1.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse "Estado da API"
+// @Router /health [get]
+func (api *Api) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := cache.Health{L2State: cache.L2Disabled}
+	if api.Cache != nil {
+		health = api.Cache.Health()
+	}
+
+	status := "ok"
+	if health.L2State == cache.L2Down || health.L2State == cache.L2CircuitOpen {
+		status = "degraded"
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.HealthResponse{
+		Status: status,
+		Cache:  toCacheHealthResponse(health),
+	})
+}
+
+func toCacheHealthResponse(h cache.Health) dto.CacheHealthResponse {
+	return dto.CacheHealthResponse{
+		Backend:             h.Backend,
+		L2State:             string(h.L2State),
+		ConsecutiveFailures: h.ConsecutiveFailures,
+		LastError:           h.LastError,
+		LastCheck:           formatOptionalTime(h.LastCheck),
+		OpenUntil:           formatOptionalTime(h.OpenUntil),
+	}
+}
+
+func formatOptionalTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.UTC().Format("2006-01-02T15:04:05Z")
+	return &s
+}
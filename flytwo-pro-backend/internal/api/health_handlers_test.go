@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/cache"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCacheAPI(c *mocks.MockCache) *Api {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	api := &Api{
+		Router:      chi.NewMux(),
+		UserService: new(mocks.MockUserService),
+		Roles:       adminRoles(),
+		Sessions:    scs.New(),
+		WsUpgrader:  defaultUpgrader(),
+	}
+	if c != nil {
+		api.Cache = c
+	}
+	api.BindRoutes()
+	return api
+}
+
+func getHealth(t *testing.T, api *Api) dto.HealthResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.HealthResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestHandleHealth_L2Up(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	mockCache.On("Health").Return(cache.Health{Backend: "postgres", L2State: cache.L2Up, LastCheck: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)})
+
+	resp := getHealth(t, setupCacheAPI(mockCache))
+
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, "postgres", resp.Cache.Backend)
+	assert.Equal(t, "up", resp.Cache.L2State)
+	assert.Equal(t, "2025-05-01T12:00:00Z", *resp.Cache.LastCheck)
+	assert.Nil(t, resp.Cache.OpenUntil)
+	mockCache.AssertExpectations(t)
+}
+
+func TestHandleHealth_CircuitOpenIsDegraded(t *testing.T) {
+	mockCache := new(mocks.MockCache)
+	mockCache.On("Health").Return(cache.Health{
+		L2State:             cache.L2CircuitOpen,
+		ConsecutiveFailures: 5,
+		LastError:           "i/o timeout",
+		OpenUntil:           time.Now().Add(30 * time.Second),
+	})
+
+	resp := getHealth(t, setupCacheAPI(mockCache))
+
+	assert.Equal(t, "degraded", resp.Status)
+	assert.Equal(t, "circuit_open", resp.Cache.L2State)
+	assert.Equal(t, 5, resp.Cache.ConsecutiveFailures)
+	assert.NotNil(t, resp.Cache.OpenUntil)
+}
+
+func TestHandleHealth_NoCache(t *testing.T) {
+	resp := getHealth(t, setupCacheAPI(nil))
+
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, "disabled", resp.Cache.L2State)
+}
@@ -0,0 +1,564 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// handleListItemLists godoc
+// @Summary Lista as cestas do usuário
+// @Description Cestas de itens CATMAT/CATSER, alteradas mais recentemente primeiro. Com um órgão ativo (PUT /users/me/organization ou header X-Organization-ID) são as cestas compartilhadas do órgão; sem, as cestas pessoais.
+// @Tags item-lists
+// @Produce json
+// @Success 200 {array} dto.ItemListResponse "Cestas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists [get]
+func (api *Api) handleListItemLists(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	lists, err := api.ItemLists.ListItemLists(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Erro ao listar cestas", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar cestas",
+		})
+		return
+	}
+
+	response := make([]dto.ItemListResponse, len(lists))
+	for i, list := range lists {
+		response[i] = toItemListResponse(&pgstore.ItemList{
+			ID:             list.ID,
+			UserID:         list.UserID,
+			OrganizationID: list.OrganizationID,
+			Name:           list.Name,
+			Description:    list.Description,
+			CreatedAt:      list.CreatedAt,
+			UpdatedAt:      list.UpdatedAt,
+		})
+		response[i].LineCount = &lists[i].LineCount
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleCreateItemList godoc
+// @Summary Cria uma cesta
+// @Tags item-lists
+// @Accept json
+// @Produce json
+// @Param list body dto.ItemListReq true "Cesta"
+// @Success 201 {object} dto.ItemListResponse "Cesta criada"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists [post]
+func (api *Api) handleCreateItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	data, ok := decodeItemListJson[dto.ItemListReq](w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	list, err := api.ItemLists.CreateItemList(r.Context(), userID, services.ItemListInput{
+		Name:        data.Name,
+		Description: data.Description,
+	})
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toItemListResponse(list))
+}
+
+// handleGetItemList godoc
+// @Summary Detalha uma cesta
+// @Description Retorna a cesta com suas linhas. Linhas cujo item foi alterado por uma importação vêm com status "changed"; itens que não existem mais no catálogo, com "removed".
+// @Tags item-lists
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Success 200 {object} dto.ItemListDetailResponse "Cesta"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id} [get]
+func (api *Api) handleGetItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	detail, err := api.ItemLists.GetItemList(r.Context(), userID, id)
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toItemListDetailResponse(detail))
+}
+
+// handleUpdateItemList godoc
+// @Summary Atualiza nome e descrição de uma cesta
+// @Tags item-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Param list body dto.ItemListReq true "Cesta"
+// @Success 200 {object} dto.ItemListResponse "Cesta atualizada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id} [put]
+func (api *Api) handleUpdateItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := decodeItemListJson[dto.ItemListReq](w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	list, err := api.ItemLists.UpdateItemList(r.Context(), userID, id, services.ItemListInput{
+		Name:        data.Name,
+		Description: data.Description,
+	})
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toItemListResponse(list))
+}
+
+// handleDeleteItemList godoc
+// @Summary Remove uma cesta
+// @Tags item-lists
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Success 204 "Cesta removida"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id} [delete]
+func (api *Api) handleDeleteItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if err := api.ItemLists.DeleteItemList(r.Context(), userID, id); err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDuplicateItemList godoc
+// @Summary Duplica uma cesta
+// @Description Cria uma cópia da cesta com todas as linhas. Sem nome, usa "<nome> (cópia)".
+// @Tags item-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Param list body dto.ItemListDuplicateReq false "Nome da cópia"
+// @Success 201 {object} dto.ItemListResponse "Cópia criada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id}/duplicate [post]
+func (api *Api) handleDuplicateItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	var data dto.ItemListDuplicateReq
+	if r.ContentLength != 0 {
+		if data, ok = decodeItemListJson[dto.ItemListDuplicateReq](w, r); !ok {
+			return
+		}
+	}
+
+	userID, _ := currentUserID(r)
+	list, err := api.ItemLists.DuplicateItemList(r.Context(), userID, id, data.Name)
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toItemListResponse(list))
+}
+
+// handleExportItemList godoc
+// @Summary Exporta uma cesta em XLSX
+// @Tags item-lists
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "ID da cesta"
+// @Success 200 {file} file "Planilha da cesta"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id}/export [get]
+func (api *Api) handleExportItemList(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	userID, _ := currentUserID(r)
+	if _, err := api.ItemLists.ExportItemList(r.Context(), userID, id, &buf); err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cesta-%s.xlsx"`, id))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// handleAddItemListLine godoc
+// @Summary Adiciona um item à cesta
+// @Description Adiciona um item CATMAT (item_code) ou CATSER (service_code) com quantidade, unidade e observações
+// @Tags item-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Param line body dto.ItemListLineReq true "Linha"
+// @Success 201 {object} dto.ItemListLineResponse "Linha criada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta não encontrada"
+// @Failure 409 {object} map[string]interface{} "Item já está na cesta"
+// @Failure 422 {object} map[string]interface{} "Erros de validação ou item inexistente no catálogo"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id}/lines [post]
+func (api *Api) handleAddItemListLine(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := decodeItemListJson[dto.ItemListLineReq](w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	line, err := api.ItemLists.AddItemListLine(r.Context(), userID, id, services.ItemListLineInput{
+		Catalog:  data.Catalog,
+		ItemCode: data.ItemCode,
+		Quantity: data.Quantity,
+		Unit:     data.Unit,
+		Notes:    data.Notes,
+	})
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toItemListLineResponse(line))
+}
+
+// handleUpdateItemListLine godoc
+// @Summary Atualiza quantidade, unidade e observações de uma linha
+// @Tags item-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Param lineID path int true "ID da linha"
+// @Param line body dto.ItemListLineUpdateReq true "Linha"
+// @Success 200 {object} dto.ItemListLineResponse "Linha atualizada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta ou linha não encontrada"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id}/lines/{lineID} [put]
+func (api *Api) handleUpdateItemListLine(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+	lineID, ok := parseItemListLineID(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := decodeItemListJson[dto.ItemListLineUpdateReq](w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	line, err := api.ItemLists.UpdateItemListLine(r.Context(), userID, id, lineID, services.ItemListLineInput{
+		Quantity: data.Quantity,
+		Unit:     data.Unit,
+		Notes:    data.Notes,
+	})
+	if err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toItemListLineResponse(line))
+}
+
+// handleDeleteItemListLine godoc
+// @Summary Remove uma linha da cesta
+// @Tags item-lists
+// @Produce json
+// @Param id path string true "ID da cesta"
+// @Param lineID path int true "ID da linha"
+// @Success 204 "Linha removida"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Papel no órgão só permite consulta"
+// @Failure 404 {object} map[string]interface{} "Cesta ou linha não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/lists/{id}/lines/{lineID} [delete]
+func (api *Api) handleDeleteItemListLine(w http.ResponseWriter, r *http.Request) {
+	if !api.requireItemListService(w, r) {
+		return
+	}
+
+	id, ok := parseItemListID(w, r)
+	if !ok {
+		return
+	}
+	lineID, ok := parseItemListLineID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if err := api.ItemLists.DeleteItemListLine(r.Context(), userID, id, lineID); err != nil {
+		api.writeItemListError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeItemListJson[T any](w http.ResponseWriter, r *http.Request) (T, bool) {
+	data, problems, err := jsonutils.DecodeValidJson[T](r)
+	if err != nil {
+		logger.Log.Debug("Validação falhou para cesta",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return data, false
+	}
+	return data, true
+}
+
+func (api *Api) requireItemListService(w http.ResponseWriter, r *http.Request) bool {
+	if api.ItemLists == nil {
+		logger.Log.Error("ItemLists não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de cestas indisponível",
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) writeItemListError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrItemListNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "cesta não encontrada",
+		})
+	case errors.Is(err, services.ErrItemListLineNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "linha não encontrada",
+		})
+	case errors.Is(err, services.ErrItemListLineExists):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "item já está na cesta",
+		})
+	case errors.Is(err, services.ErrCatalogItemNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "item não encontrado no catálogo",
+		})
+	case errors.Is(err, services.ErrInvalidItemList):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "dados da cesta inválidos",
+		})
+	case errors.Is(err, services.ErrOrganizationReadOnly):
+		_ = jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+			"error": "seu papel no órgão só permite consultar as cestas",
+		})
+	default:
+		logger.Log.Error("Erro em cestas", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao processar cesta",
+		})
+	}
+}
+
+func parseItemListID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func parseItemListLineID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "lineID"), 10, 64)
+	if err != nil || id <= 0 {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id de linha inválido",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toItemListResponse(list *pgstore.ItemList) dto.ItemListResponse {
+	response := dto.ItemListResponse{
+		ID:          list.ID.String(),
+		Name:        list.Name,
+		Description: list.Description,
+		CreatedAt:   list.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   list.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if list.OrganizationID.Valid {
+		id := uuid.UUID(list.OrganizationID.Bytes).String()
+		response.OrganizationID = &id
+	}
+	return response
+}
+
+func toItemListLineResponse(line *pgstore.ItemListLine) dto.ItemListLineResponse {
+	return dto.ItemListLineResponse{
+		ID:              line.ID,
+		Catalog:         line.Catalog,
+		ItemCode:        line.ItemCode,
+		Quantity:        line.Quantity,
+		Unit:            line.Unit,
+		Notes:           line.Notes,
+		ItemDescription: line.ItemDescription,
+		Status:          services.ItemListLineOK,
+		CreatedAt:       line.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       line.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func toItemListDetailResponse(detail *services.ItemListDetail) dto.ItemListDetailResponse {
+	lineCount := int64(len(detail.Lines))
+	response := dto.ItemListDetailResponse{
+		ItemListResponse: toItemListResponse(&detail.ItemList),
+		Lines:            make([]dto.ItemListLineResponse, len(detail.Lines)),
+	}
+	response.LineCount = &lineCount
+
+	for i, line := range detail.Lines {
+		response.Lines[i] = dto.ItemListLineResponse{
+			ID:                 line.ID,
+			Catalog:            line.Catalog,
+			ItemCode:           line.ItemCode,
+			Quantity:           line.Quantity,
+			Unit:               line.Unit,
+			Notes:              line.Notes,
+			ItemDescription:    line.ItemDescription,
+			CurrentDescription: line.CurrentDescription,
+			Status:             line.Status,
+			CreatedAt:          line.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			UpdatedAt:          line.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if line.Status != services.ItemListLineOK {
+			response.FlaggedLines++
+		}
+	}
+	return response
+}
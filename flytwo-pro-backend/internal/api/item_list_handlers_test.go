@@ -0,0 +1,237 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupItemListAPI() (*Api, *mocks.MockItemListService) {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	mockLists := new(mocks.MockItemListService)
+	api := &Api{
+		Router:         chi.NewMux(),
+		UserService:    new(mocks.MockUserService),
+		CatalogService: new(mocks.MockCatalogImportService),
+		ItemLists:      mockLists,
+		Sessions:       scs.New(),
+		WsUpgrader:     defaultUpgrader(),
+	}
+	api.BindRoutes()
+	return api, mockLists
+}
+
+func TestHandleCreateItemList_Success(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+
+	created := &pgstore.ItemList{ID: uuid.New(), UserID: pgtype.UUID{Bytes: userID, Valid: true}, Name: "Escritório", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	mockLists.On("CreateItemList", mock.Anything, userID, services.ItemListInput{Name: "Escritório"}).Return(created, nil)
+
+	body, _ := json.Marshal(dto.ItemListReq{Name: "Escritório"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp dto.ItemListResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, created.ID.String(), resp.ID)
+	mockLists.AssertExpectations(t)
+}
+
+func TestHandleGetItemList_FlagsLines(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+	listID := uuid.New()
+
+	detail := &services.ItemListDetail{
+		ItemList: pgstore.ItemList{ID: listID, UserID: pgtype.UUID{Bytes: userID, Valid: true}, Name: "Escritório"},
+		Lines: []services.ItemListLine{
+			{ListItemListLinesRow: pgstore.ListItemListLinesRow{ID: 1, Catalog: "catmat", ItemCode: 150001, Quantity: 10}, Status: services.ItemListLineOK},
+			{ListItemListLinesRow: pgstore.ListItemListLinesRow{ID: 2, Catalog: "catmat", ItemCode: 150002, Quantity: 1}, Status: services.ItemListLineChanged},
+			{ListItemListLinesRow: pgstore.ListItemListLinesRow{ID: 3, Catalog: "catser", ItemCode: 2001, Quantity: 1}, Status: services.ItemListLineRemoved},
+		},
+	}
+	mockLists.On("GetItemList", mock.Anything, userID, listID).Return(detail, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/lists/"+listID.String(), nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.ItemListDetailResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Lines, 3)
+	assert.Equal(t, 2, resp.FlaggedLines)
+	assert.Equal(t, "changed", resp.Lines[1].Status)
+	assert.Equal(t, "removed", resp.Lines[2].Status)
+}
+
+func TestHandleGetItemList_NotFound(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+	listID := uuid.New()
+
+	mockLists.On("GetItemList", mock.Anything, userID, listID).Return(nil, services.ErrItemListNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/lists/"+listID.String(), nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleDuplicateItemList_WithoutBody(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+	listID := uuid.New()
+
+	copied := &pgstore.ItemList{ID: uuid.New(), UserID: pgtype.UUID{Bytes: userID, Valid: true}, Name: "Escritório (cópia)"}
+	mockLists.On("DuplicateItemList", mock.Anything, userID, listID, "").Return(copied, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists/"+listID.String()+"/duplicate", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockLists.AssertExpectations(t)
+}
+
+func TestHandleAddItemListLine_Success(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+	listID := uuid.New()
+
+	input := services.ItemListLineInput{Catalog: "catmat", ItemCode: 150001, Quantity: 2.5, Unit: "KG"}
+	line := &pgstore.ItemListLine{ID: 9, ListID: listID, Catalog: "catmat", ItemCode: 150001, Quantity: 2.5, Unit: "KG", ItemDescription: "Café"}
+	mockLists.On("AddItemListLine", mock.Anything, userID, listID, input).Return(line, nil)
+
+	body, _ := json.Marshal(dto.ItemListLineReq{Catalog: "catmat", ItemCode: 150001, Quantity: 2.5, Unit: "KG"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists/"+listID.String()+"/lines", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp dto.ItemListLineResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(9), resp.ID)
+	assert.Equal(t, "Café", resp.ItemDescription)
+	mockLists.AssertExpectations(t)
+}
+
+func TestHandleAddItemListLine_InvalidQuantity(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+
+	body, _ := json.Marshal(dto.ItemListLineReq{Catalog: "catmat", ItemCode: 150001, Quantity: -1})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists/"+uuid.NewString()+"/lines", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockLists.AssertNotCalled(t, "AddItemListLine", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAddItemListLine_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{services.ErrCatalogItemNotFound, http.StatusUnprocessableEntity},
+		{services.ErrItemListLineExists, http.StatusConflict},
+		{services.ErrItemListNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			api, mockLists := setupItemListAPI()
+			mockLists.On("AddItemListLine", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			body, _ := json.Marshal(dto.ItemListLineReq{Catalog: "catser", ItemCode: 2001, Quantity: 1})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists/"+uuid.NewString()+"/lines", bytes.NewReader(body))
+			req.AddCookie(authCookie(api, uuid.New()))
+			rec := httptest.NewRecorder()
+
+			api.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestHandleDeleteItemListLine_InvalidLineID(t *testing.T) {
+	api, _ := setupItemListAPI()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/me/lists/"+uuid.NewString()+"/lines/abc", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleExportItemList_Success(t *testing.T) {
+	api, mockLists := setupItemListAPI()
+	userID := uuid.New()
+	listID := uuid.New()
+
+	mockLists.On("ExportItemList", mock.Anything, userID, listID, mock.Anything).
+		Return(&services.ItemListDetail{}, nil).
+		Run(func(args mock.Arguments) {
+			_, _ = args.Get(3).(io.Writer).Write([]byte("xlsx"))
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/lists/"+listID.String()+"/export", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, xlsxContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), listID.String())
+	assert.Equal(t, "xlsx", rec.Body.String())
+}
+
+func TestHandleListItemLists_Unauthorized(t *testing.T) {
+	api, _ := setupItemListAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/lists", nil)
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
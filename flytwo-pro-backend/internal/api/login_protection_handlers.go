@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// allowLogin answers 429 with Retry-After and returns false while the
+// account or the client IP is blocked after failed logins.
+func (api *Api) allowLogin(w http.ResponseWriter, r *http.Request, email string) bool {
+	if api.LoginProtection == nil {
+		return true
+	}
+
+	wait := api.LoginProtection.Check(r.Context(), clientIP(r), email)
+	if wait <= 0 {
+		return true
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	logger.Log.Warn("Login blocked after failed attempts",
+		zap.String("email", email),
+		zap.String("ip", clientIP(r)),
+		zap.Int("retry_after", seconds))
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	_ = jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{
+		"error":       "too many failed login attempts; try again later",
+		"retry_after": seconds,
+	})
+	return false
+}
+
+// recordLoginFailure counts a wrong password or second-factor code. Other
+// errors are not the caller's fault and are not counted.
+func (api *Api) recordLoginFailure(r *http.Request, email string, err error) {
+	if api.LoginProtection == nil {
+		return
+	}
+	if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		api.LoginProtection.RecordFailure(r.Context(), clientIP(r), email)
+	}
+}
+
+// recordLoginSuccess clears the failures of the account once every factor
+// has been checked.
+func (api *Api) recordLoginSuccess(r *http.Request, email string, userID uuid.UUID) {
+	if api.LoginProtection == nil {
+		return
+	}
+	api.LoginProtection.RecordSuccess(r.Context(), clientIP(r), email, userID)
+}
+
+// clientIP returns the address of the client without the port. With
+// TrustProxy the RealIP middleware has already replaced RemoteAddr.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleUnlockUserLogin godoc
+// @Summary Desbloqueia o login de um usuário
+// @Description Zera as tentativas de login com senha errada da conta e remove o bloqueio temporário. Os bloqueios por IP são separados: sem o parâmetro ip eles continuam valendo, e o usuário segue bloqueado enquanto usar o mesmo IP (o IP aparece no evento login_lockout). A resposta informa o que foi desbloqueado. O desbloqueio fica registrado no log de eventos de segurança. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param ip query string false "IP a desbloquear junto com a conta"
+// @Success 200 {object} dto.LoginUnlockResponse "Login desbloqueado"
+// @Failure 400 {object} map[string]interface{} "ID ou IP inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/login-lock [delete]
+func (api *Api) handleUnlockUserLogin(w http.ResponseWriter, r *http.Request) {
+	if !api.requireLoginProtection(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	ip := r.URL.Query().Get("ip")
+	if ip != "" && net.ParseIP(ip) == nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "ip inválido",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	if err := api.LoginProtection.Unlock(r.Context(), userID, adminID, ip); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao desbloquear login", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao desbloquear login",
+		})
+		return
+	}
+
+	resp := dto.LoginUnlockResponse{
+		Message:         "login da conta desbloqueado; bloqueios por IP continuam valendo",
+		AccountUnlocked: true,
+	}
+	if ip != "" {
+		resp.Message = "login da conta e do IP desbloqueado"
+		resp.IPUnlocked = ip
+	}
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, resp)
+}
+
+// handleListSecurityEvents godoc
+// @Summary Lista eventos de segurança
+// @Description Log de auditoria com bloqueios (login_lockout) e desbloqueios (login_unlock) de login, do mais recente ao mais antigo. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param type query string false "Tipo do evento (login_lockout, login_unlock)"
+// @Param user_id query string false "ID do usuário"
+// @Param limit query int false "Limite de resultados (padrão 50, máximo 100)"
+// @Param offset query int false "Offset para paginação (padrão 0)"
+// @Success 200 {object} dto.SecurityEventListResponse "Eventos"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/security-events [get]
+func (api *Api) handleListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	if !api.requireLoginProtection(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	userID := uuid.Nil
+	if raw := query.Get("user_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "user_id inválido",
+			})
+			return
+		}
+		userID = parsed
+	}
+	limit := parseIntParam(query.Get("limit"), 50)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseIntParam(query.Get("offset"), 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	events, total, err := api.LoginProtection.ListSecurityEvents(r.Context(), query.Get("type"), userID, limit, offset)
+	if err != nil {
+		logger.Log.Error("Erro ao listar eventos de segurança", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar eventos de segurança",
+		})
+		return
+	}
+
+	response := dto.SecurityEventListResponse{
+		Data:   make([]dto.SecurityEventResponse, len(events)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i := range events {
+		response.Data[i] = toSecurityEventResponse(&events[i])
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+func toSecurityEventResponse(e *pgstore.SecurityEvent) dto.SecurityEventResponse {
+	item := dto.SecurityEventResponse{
+		ID:        e.ID,
+		EventType: e.EventType,
+		Details:   map[string]any{},
+		CreatedAt: e.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if e.UserID.Valid {
+		id := uuid.UUID(e.UserID.Bytes).String()
+		item.UserID = &id
+	}
+	if e.Email.Valid {
+		item.Email = &e.Email.String
+	}
+	if e.Ip.Valid {
+		item.IP = &e.Ip.String
+	}
+	_ = json.Unmarshal(e.Details, &item.Details)
+	return item
+}
+
+func (api *Api) requireLoginProtection(w http.ResponseWriter, r *http.Request) bool {
+	if api.LoginProtection == nil {
+		logger.Log.Error("LoginProtectionService não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "proteção de login indisponível",
+		})
+		return false
+	}
+	return true
+}
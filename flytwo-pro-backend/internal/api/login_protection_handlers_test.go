@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLoginProtectionAPI() (*Api, *mocks.MockUserService, *mocks.MockLoginProtectionService) {
+	api, mockUsers := setupTestAPI()
+	mockProtection := new(mocks.MockLoginProtectionService)
+	api.LoginProtection = mockProtection
+	api.Roles = adminRoles()
+	return api, mockUsers, mockProtection
+}
+
+func TestHandleLoginUser_BlockedAfterFailures(t *testing.T) {
+	api, mockUsers, mockProtection := setupLoginProtectionAPI()
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(1500 * time.Millisecond)
+
+	rec := postJSON(t, api, "/api/v1/users/login", dto.LoginUserReq{Email: "test@example.com", Password: "Test1234"})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	mockUsers.AssertNotCalled(t, "AuthenticateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleLoginUser_RecordsFailure(t *testing.T) {
+	api, mockUsers, mockProtection := setupLoginProtectionAPI()
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(time.Duration(0))
+	mockProtection.On("RecordFailure", mock.Anything, "192.0.2.1", "test@example.com").Return()
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "wrong-pass").
+		Return(uuid.UUID{}, services.ErrInvalidCredentials)
+
+	rec := postJSON(t, api, "/api/v1/users/login", dto.LoginUserReq{Email: "test@example.com", Password: "wrong-pass"})
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockProtection.AssertExpectations(t)
+}
+
+func TestHandleLoginUser_RecordsSuccess(t *testing.T) {
+	api, mockUsers, mockProtection := setupLoginProtectionAPI()
+	userID := uuid.New()
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(time.Duration(0))
+	mockProtection.On("RecordSuccess", mock.Anything, "192.0.2.1", "test@example.com", userID).Return()
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+
+	rec := postJSON(t, api, "/api/v1/users/login", dto.LoginUserReq{Email: "test@example.com", Password: "Test1234"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockProtection.AssertExpectations(t)
+}
+
+func TestHandleIssueToken_BlockedAfterFailures(t *testing.T) {
+	api, mockUsers, mockProtection := setupLoginProtectionAPI()
+	api.Tokens = new(mocks.MockTokenService)
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(15 * time.Minute)
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{GrantType: "password", Email: "test@example.com", Password: "Test1234"})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "900", rec.Header().Get("Retry-After"))
+	mockUsers.AssertNotCalled(t, "AuthenticateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleUnlockUserLogin_Success(t *testing.T) {
+	api, _, mockProtection := setupLoginProtectionAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockProtection.On("Unlock", mock.Anything, userID, adminID, "").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/login-lock", nil)
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.LoginUnlockResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.AccountUnlocked)
+	assert.Empty(t, resp.IPUnlocked)
+	mockProtection.AssertExpectations(t)
+}
+
+func TestHandleUnlockUserLogin_WithIP(t *testing.T) {
+	api, _, mockProtection := setupLoginProtectionAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockProtection.On("Unlock", mock.Anything, userID, adminID, "192.0.2.1").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/login-lock?ip=192.0.2.1", nil)
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.LoginUnlockResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "192.0.2.1", resp.IPUnlocked)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/login-lock?ip=not-an-ip", nil)
+	req.AddCookie(authCookie(api, adminID))
+	rec = httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockProtection.AssertExpectations(t)
+}
+
+func TestHandleUnlockUserLogin_UserNotFound(t *testing.T) {
+	api, _, mockProtection := setupLoginProtectionAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockProtection.On("Unlock", mock.Anything, userID, adminID, "").Return(services.ErrUserNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/login-lock", nil)
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleListSecurityEvents(t *testing.T) {
+	api, _, mockProtection := setupLoginProtectionAPI()
+	userID := uuid.New()
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockProtection.On("ListSecurityEvents", mock.Anything, services.SecurityEventLoginLockout, userID, int32(100), int32(0)).
+		Return([]pgstore.SecurityEvent{{
+			ID:        7,
+			EventType: services.SecurityEventLoginLockout,
+			UserID:    pgtype.UUID{Bytes: userID, Valid: true},
+			Email:     pgtype.Text{String: "test@example.com", Valid: true},
+			Ip:        pgtype.Text{String: "192.0.2.1", Valid: true},
+			Details:   []byte(`{"scope":"account","failures":10}`),
+			CreatedAt: createdAt,
+		}}, int64(1), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/security-events?type=login_lockout&user_id="+userID.String()+"&limit=500", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response dto.SecurityEventListResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, int64(1), response.Total)
+	if assert.Len(t, response.Data, 1) {
+		event := response.Data[0]
+		assert.Equal(t, userID.String(), *event.UserID)
+		assert.Equal(t, "192.0.2.1", *event.IP)
+		assert.Equal(t, "account", event.Details["scope"])
+		assert.Equal(t, "2025-03-01T12:00:00Z", event.CreatedAt)
+	}
+}
+
+func TestHandleListSecurityEvents_InvalidUserID(t *testing.T) {
+	api, _, _ := setupLoginProtectionAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/security-events?user_id=nope", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/oidc"
+	"gobid/internal/services"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// oidcLoginTTL is how long the user has to sign in at the identity provider.
+const oidcLoginTTL = 10 * time.Minute
+
+// Session keys of a login waiting for the identity provider's callback.
+const (
+	oidcProviderKey = "OIDCProvider"
+	oidcStateKey    = "OIDCState"
+	oidcNonceKey    = "OIDCNonce"
+	oidcVerifierKey = "OIDCVerifier"
+	oidcExpiresKey  = "OIDCExpires"
+)
+
+// handleListOIDCProviders godoc
+// @Summary List identity providers
+// @Description Identity providers (OpenID Connect) users can sign in with, for the login page. Send the browser to login_url to start the login. Empty when none is configured.
+// @Tags auth
+// @Produce json
+// @Success 200 {array} dto.OIDCProviderResponse "Providers"
+// @Router /auth/oidc/providers [get]
+func (api *Api) handleListOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	providers := api.OIDC.Providers()
+	response := make([]dto.OIDCProviderResponse, len(providers))
+	for i, p := range providers {
+		response[i] = dto.OIDCProviderResponse{
+			Name:     p.Name(),
+			Label:    p.Label(),
+			LoginURL: "/api/v1/auth/oidc/" + url.PathEscape(p.Name()) + "/login",
+		}
+	}
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleOIDCLogin godoc
+// @Summary Start an identity provider login
+// @Description Redirects the browser to the identity provider's login page (authorization code flow with PKCE). The provider sends the browser back to the callback, which signs the user in.
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} map[string]interface{} "Unknown provider"
+// @Failure 502 {object} map[string]interface{} "Identity provider unavailable"
+// @Router /auth/oidc/{provider}/login [get]
+func (api *Api) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "provider")
+	provider, ok := api.OIDC.Get(name)
+	if !ok {
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "unknown identity provider",
+		})
+		return
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		secret, err := oidc.RandomString()
+		if err != nil {
+			logger.Log.Error("Failed to generate OIDC login secrets", zap.Error(err))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+		secrets[i] = secret
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := provider.AuthCodeURL(r.Context(), state, nonce, verifier)
+	if err != nil {
+		logger.Log.Error("Identity provider unavailable",
+			zap.Error(err),
+			zap.String("provider", name))
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadGateway, map[string]any{
+			"error": "identity provider unavailable",
+		})
+		return
+	}
+
+	api.Sessions.Put(r.Context(), oidcProviderKey, name)
+	api.Sessions.Put(r.Context(), oidcStateKey, state)
+	api.Sessions.Put(r.Context(), oidcNonceKey, nonce)
+	api.Sessions.Put(r.Context(), oidcVerifierKey, verifier)
+	api.Sessions.Put(r.Context(), oidcExpiresKey, time.Now().Add(oidcLoginTTL).Unix())
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// handleOIDCCallback godoc
+// @Summary Complete an identity provider login
+// @Description Called by the identity provider after the user signs in. The account is found by the provider identity or its verified email, or created, and the session is logged in like POST /users/login. The browser is sent to the frontend; "two_factor_required=true" in the query string asks for POST /users/login/2fa and "oidc_error" reports a failure. Without a configured frontend URL the outcome is answered as JSON.
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Param code query string false "Authorization code"
+// @Param state query string true "State of the login"
+// @Success 302 "Redirect to the frontend"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired login"
+// @Failure 401 {object} map[string]interface{} "Login rejected by the provider"
+// @Failure 403 {object} map[string]interface{} "Email not verified by the provider"
+// @Failure 409 {object} map[string]interface{} "Existing account with an unverified email"
+// @Failure 502 {object} map[string]interface{} "Identity provider unavailable"
+// @Router /auth/oidc/{provider}/callback [get]
+func (api *Api) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if !api.requireIdentities(w, r) {
+		return
+	}
+
+	name := chi.URLParam(r, "provider")
+	expectedProvider := api.Sessions.PopString(r.Context(), oidcProviderKey)
+	expectedState := api.Sessions.PopString(r.Context(), oidcStateKey)
+	nonce := api.Sessions.PopString(r.Context(), oidcNonceKey)
+	verifier := api.Sessions.PopString(r.Context(), oidcVerifierKey)
+	expires := api.Sessions.GetInt64(r.Context(), oidcExpiresKey)
+	api.Sessions.Remove(r.Context(), oidcExpiresKey)
+
+	provider, ok := api.OIDC.Get(name)
+	if !ok || expectedProvider != name || expectedState == "" || time.Now().Unix() > expires ||
+		subtle.ConstantTimeCompare([]byte(expectedState), []byte(r.URL.Query().Get("state"))) != 1 {
+		api.failOIDCLogin(w, r, http.StatusBadRequest, "invalid_state", "invalid or expired login, try again")
+		return
+	}
+
+	if providerErr := r.URL.Query().Get("error"); providerErr != "" {
+		logger.Log.Info("Identity provider login not completed",
+			zap.String("provider", name),
+			zap.String("error", providerErr))
+		api.failOIDCLogin(w, r, http.StatusUnauthorized, "login_cancelled", "login not completed at the identity provider")
+		return
+	}
+
+	claims, err := provider.Exchange(r.Context(), r.URL.Query().Get("code"), verifier, nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) || errors.Is(err, oidc.ErrTokenExchange) {
+			logger.Log.Warn("Identity provider login rejected",
+				zap.Error(err),
+				zap.String("provider", name))
+			api.failOIDCLogin(w, r, http.StatusUnauthorized, "login_failed", "login rejected by the identity provider")
+			return
+		}
+		logger.Log.Error("Identity provider unavailable",
+			zap.Error(err),
+			zap.String("provider", name))
+		api.failOIDCLogin(w, r, http.StatusBadGateway, "provider_unavailable", "identity provider unavailable")
+		return
+	}
+
+	login, err := api.Identities.LoginWithIdentity(r.Context(), name, *claims)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdentityEmailNotVerified):
+			api.failOIDCLogin(w, r, http.StatusForbidden, "email_not_verified", "the identity provider did not verify your email")
+		case errors.Is(err, services.ErrIdentityAccountUnverified):
+			api.failOIDCLogin(w, r, http.StatusConflict, "account_email_unverified", "an account with this email exists but its email is not verified; sign in with the password and verify it first")
+		case errors.Is(err, services.ErrDuplicatedEmailOrUsername):
+			api.failOIDCLogin(w, r, http.StatusConflict, "account_conflict", "could not create the account, try again")
+		default:
+			logger.Log.Error("Failed to resolve identity",
+				zap.Error(err),
+				zap.String("provider", name))
+			api.failOIDCLogin(w, r, http.StatusInternalServerError, "internal_error", "unexpected internal server error")
+		}
+		return
+	}
+
+	if api.TwoFactor != nil {
+		status, err := api.TwoFactor.Status(r.Context(), login.UserID)
+		if err != nil {
+			logger.Log.Error("Failed to check two-factor status",
+				zap.Error(err),
+				zap.String("user_id", login.UserID.String()))
+			api.failOIDCLogin(w, r, http.StatusInternalServerError, "internal_error", "unexpected internal server error")
+			return
+		}
+		if status.Enabled {
+			if err := api.beginTwoFactorLogin(r, login.UserID, login.Email); err != nil {
+				api.failOIDCLogin(w, r, http.StatusInternalServerError, "internal_error", "unexpected internal server error")
+				return
+			}
+			logger.Log.Info("Identity provider login accepted; waiting for second factor",
+				zap.String("user_id", login.UserID.String()),
+				zap.String("provider", name))
+			api.finishOIDCLogin(w, r, http.StatusOK, url.Values{"two_factor_required": {"true"}}, map[string]any{
+				"message":             "two-factor code required",
+				"two_factor_required": true,
+			})
+			return
+		}
+	}
+
+	api.recordLoginSuccess(r, login.Email, login.UserID)
+
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		logger.Log.Error("Failed to renew session token",
+			zap.Error(err),
+			zap.String("user_id", login.UserID.String()))
+		api.failOIDCLogin(w, r, http.StatusInternalServerError, "internal_error", "unexpected internal server error")
+		return
+	}
+
+	api.Sessions.Put(r.Context(), "AuthenticatedUserId", login.UserID)
+	api.trackSession(r, login.UserID)
+
+	logger.Log.Info("User logged in with identity provider",
+		zap.String("user_id", login.UserID.String()),
+		zap.String("provider", name),
+		zap.Bool("linked", login.Linked),
+		zap.Bool("created", login.Created))
+
+	api.finishOIDCLogin(w, r, http.StatusOK, nil, map[string]any{
+		"message": "logged in successfully",
+		"user_id": login.UserID,
+	})
+}
+
+// failOIDCLogin ends an identity provider login with an error.
+func (api *Api) failOIDCLogin(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	api.finishOIDCLogin(w, r, status, url.Values{"oidc_error": {code}}, map[string]any{
+		"error": message,
+		"code":  code,
+	})
+}
+
+// finishOIDCLogin sends the browser back to the frontend with params in the
+// query string or, without OIDCFrontendURL, answers body as JSON.
+func (api *Api) finishOIDCLogin(w http.ResponseWriter, r *http.Request, status int, params url.Values, body map[string]any) {
+	if api.OIDCFrontendURL == "" {
+		_ = jsonutils.EncodeJson(w, r, status, body)
+		return
+	}
+
+	target, err := url.Parse(api.OIDCFrontendURL)
+	if err != nil {
+		logger.Log.Error("Invalid OIDC redirect URL", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, status, body)
+		return
+	}
+	q := target.Query()
+	for key, values := range params {
+		q[key] = values
+	}
+	target.RawQuery = q.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (api *Api) requireIdentities(w http.ResponseWriter, r *http.Request) bool {
+	if api.Identities == nil {
+		logger.Log.Error("Identity service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "identity provider login not configured",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/oidc"
+	"gobid/internal/oidc/oidctest"
+	"gobid/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const oidcCallbackURL = "http://localhost:3080/api/v1/auth/oidc/corp/callback"
+
+func setupOIDCAPI(t *testing.T) (*Api, *mocks.MockUserService, *mocks.MockIdentityService, *oidctest.Server) {
+	api, mockUsers := setupTestAPI()
+	mockIdentities := new(mocks.MockIdentityService)
+	srv := oidctest.NewServer(t)
+	api.Identities = mockIdentities
+	api.OIDC = oidc.NewRegistry(oidc.NewProvider(srv.Config("corp", oidcCallbackURL), nil))
+	return api, mockUsers, mockIdentities, srv
+}
+
+// startOIDCLogin opens the login endpoint, signs in at the mock provider and
+// returns the callback path and the session cookie of the login.
+func startOIDCLogin(t *testing.T, api *Api, srv *oidctest.Server) (string, *http.Cookie) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/corp/login", nil)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	callback, err := url.Parse(srv.Authorize(t, rec.Header().Get("Location")))
+	require.NoError(t, err)
+	return callback.RequestURI(), sessionCookie(t, rec)
+}
+
+func TestHandleListOIDCProviders(t *testing.T) {
+	api, _, _, _ := setupOIDCAPI(t)
+
+	rec := sendJSON(t, api, http.MethodGet, "/api/v1/auth/oidc/providers", nil, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var providers []dto.OIDCProviderResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &providers))
+	assert.Equal(t, []dto.OIDCProviderResponse{
+		{Name: "corp", Label: "corp", LoginURL: "/api/v1/auth/oidc/corp/login"},
+	}, providers)
+}
+
+func TestHandleOIDCLogin_UnknownProvider(t *testing.T) {
+	api, _, _, _ := setupOIDCAPI(t)
+
+	rec := sendJSON(t, api, http.MethodGet, "/api/v1/auth/oidc/other/login", nil, nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleOIDCCallback_LogsIn(t *testing.T) {
+	api, mockUsers, mockIdentities, srv := setupOIDCAPI(t)
+	userID := uuid.New()
+	mockIdentities.On("LoginWithIdentity", mock.Anything, "corp", oidc.Claims{
+		Subject:       "user-1",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+	}).Return(&services.IdentityLogin{UserID: userID, Email: "user@example.com", Created: true}, nil)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(nil, services.ErrUserNotFound)
+
+	callback, loginCookie := startOIDCLogin(t, api, srv)
+	assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", loginCookie).Code)
+
+	rec := getWithCookie(api, callback, loginCookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), userID.String())
+	cookie := sessionCookie(t, rec)
+	assert.NotEqual(t, loginCookie.Value, cookie.Value, "the session token is renewed")
+	assert.Equal(t, http.StatusNotFound, getWithCookie(api, "/api/v1/users/me", cookie).Code, "authenticated; the mock has no profile")
+
+	// The state works once.
+	rec = getWithCookie(api, callback, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockIdentities.AssertNumberOfCalls(t, "LoginWithIdentity", 1)
+}
+
+func TestHandleOIDCCallback_RedirectsToFrontend(t *testing.T) {
+	api, _, mockIdentities, srv := setupOIDCAPI(t)
+	api.OIDCFrontendURL = "http://localhost:4200/login/oidc?from=api"
+	mockIdentities.On("LoginWithIdentity", mock.Anything, "corp", mock.Anything).
+		Return(&services.IdentityLogin{UserID: uuid.New(), Email: "user@example.com"}, nil)
+
+	callback, loginCookie := startOIDCLogin(t, api, srv)
+	rec := getWithCookie(api, callback, loginCookie)
+
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "http://localhost:4200/login/oidc?from=api", rec.Header().Get("Location"))
+}
+
+func TestHandleOIDCCallback_WrongState(t *testing.T) {
+	api, _, mockIdentities, srv := setupOIDCAPI(t)
+	api.OIDCFrontendURL = "http://localhost:4200/login/oidc"
+
+	callback, loginCookie := startOIDCLogin(t, api, srv)
+	u, err := url.Parse(callback)
+	require.NoError(t, err)
+	q := u.Query()
+	q.Set("state", "forged")
+	u.RawQuery = q.Encode()
+
+	rec := getWithCookie(api, u.RequestURI(), loginCookie)
+
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "http://localhost:4200/login/oidc?oidc_error=invalid_state", rec.Header().Get("Location"))
+	mockIdentities.AssertNotCalled(t, "LoginWithIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleOIDCCallback_WithoutLoginSession(t *testing.T) {
+	api, _, mockIdentities, srv := setupOIDCAPI(t)
+
+	callback, _ := startOIDCLogin(t, api, srv)
+	rec := getWithCookie(api, callback, authCookie(api, uuid.New()))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockIdentities.AssertNotCalled(t, "LoginWithIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleOIDCCallback_RejectedIDToken(t *testing.T) {
+	api, _, mockIdentities, srv := setupOIDCAPI(t)
+	srv.Mutate = func(c jwt.MapClaims) { c["aud"] = "another-client" }
+
+	callback, loginCookie := startOIDCLogin(t, api, srv)
+	rec := getWithCookie(api, callback, loginCookie)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"login_failed"`)
+	mockIdentities.AssertNotCalled(t, "LoginWithIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleOIDCCallback_IdentityErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{err: services.ErrIdentityEmailNotVerified, status: http.StatusForbidden, code: "email_not_verified"},
+		{err: services.ErrIdentityAccountUnverified, status: http.StatusConflict, code: "account_email_unverified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			api, _, mockIdentities, srv := setupOIDCAPI(t)
+			mockIdentities.On("LoginWithIdentity", mock.Anything, "corp", mock.Anything).Return(nil, tt.err)
+
+			callback, loginCookie := startOIDCLogin(t, api, srv)
+			rec := getWithCookie(api, callback, loginCookie)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"code":"`+tt.code+`"`)
+			assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", loginCookie).Code)
+		})
+	}
+}
+
+func TestHandleOIDCCallback_TwoFactor(t *testing.T) {
+	api, mockUsers, mockIdentities, srv := setupOIDCAPI(t)
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	api.TwoFactor = mockTwoFactor
+	userID := uuid.New()
+	mockIdentities.On("LoginWithIdentity", mock.Anything, "corp", mock.Anything).
+		Return(&services.IdentityLogin{UserID: userID, Email: "user@example.com"}, nil)
+	mockTwoFactor.On("Status", mock.Anything, userID).Return(services.TwoFactorStatus{Enabled: true}, nil)
+	mockTwoFactor.On("Verify", mock.Anything, userID, "123456").Return(nil)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(nil, services.ErrUserNotFound)
+
+	callback, loginCookie := startOIDCLogin(t, api, srv)
+	rec := getWithCookie(api, callback, loginCookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"two_factor_required":true`)
+	cookie := sessionCookie(t, rec)
+	assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", cookie).Code)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/login/2fa", dto.TwoFactorCodeReq{Code: "123456"}, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusNotFound, getWithCookie(api, "/api/v1/users/me", sessionCookie(t, rec)).Code)
+}
@@ -0,0 +1,668 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// handleListMyOrganizations godoc
+// @Summary List the user's organizations
+// @Description Organizations (public agencies) the user is a member of, with the user's role in each. "active" marks the one selected with PUT /users/me/organization.
+// @Tags users
+// @Produce json
+// @Success 200 {array} dto.UserOrganizationResponse "Organizations"
+// @Failure 401 {object} map[string]interface{} "Not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal error"
+// @Router /users/me/organizations [get]
+func (api *Api) handleListMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	orgs, err := api.Organizations.ListUserOrganizations(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Failed to list user organizations",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	active, _ := api.Sessions.Get(r.Context(), activeOrganizationKey).(uuid.UUID)
+	response := make([]dto.UserOrganizationResponse, len(orgs))
+	for i, org := range orgs {
+		response[i] = dto.UserOrganizationResponse{
+			ID:       org.ID.String(),
+			Name:     org.Name,
+			CNPJ:     optionalText(org.Cnpj),
+			UASG:     optionalText(org.Uasg),
+			Role:     org.Role,
+			Active:   org.ID == active,
+			JoinedAt: org.JoinedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleSelectOrganization godoc
+// @Summary Select the active organization
+// @Description Stores in the session the organization the user works on behalf of. Lists and other shared data are then those of the organization, until DELETE /users/me/organization. Bearer tokens and API keys send the X-Organization-ID header on each request instead.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param organization body dto.SelectOrganizationReq true "Organization"
+// @Success 200 {object} dto.ActiveOrganizationResponse "Active organization"
+// @Failure 401 {object} map[string]interface{} "Not authenticated"
+// @Failure 403 {object} map[string]interface{} "Not a member of the organization"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal error"
+// @Router /users/me/organization [put]
+func (api *Api) handleSelectOrganization(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.SelectOrganizationReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+	orgID, _ := uuid.Parse(data.OrganizationID)
+
+	userID, _ := currentUserID(r)
+	membership, err := api.Organizations.GetMembership(r.Context(), orgID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotOrganizationMember) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"error": "not a member of the organization",
+			})
+			return
+		}
+		logger.Log.Error("Failed to check organization membership",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+			zap.String("organization_id", orgID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	api.Sessions.Put(r.Context(), activeOrganizationKey, orgID)
+
+	logger.Log.Info("Active organization selected",
+		zap.String("user_id", userID.String()),
+		zap.String("organization_id", orgID.String()))
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.ActiveOrganizationResponse{
+		ID:   orgID.String(),
+		Name: membership.OrganizationName,
+		Role: membership.Role,
+	})
+}
+
+// handleClearOrganization godoc
+// @Summary Leave the active organization
+// @Description Clears the organization selected in the session; the user works on personal data again.
+// @Tags users
+// @Success 204 "Cleared"
+// @Failure 401 {object} map[string]interface{} "Not authenticated"
+// @Router /users/me/organization [delete]
+func (api *Api) handleClearOrganization(w http.ResponseWriter, r *http.Request) {
+	api.Sessions.Remove(r.Context(), activeOrganizationKey)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListOrganizations godoc
+// @Summary Lista os órgãos
+// @Description Órgãos em ordem de nome, com o número de membros. q filtra por parte do nome ou pelo CNPJ ou UASG exatos. Exige o papel admin.
+// @Tags organizations
+// @Produce json
+// @Param q query string false "Nome, CNPJ ou UASG"
+// @Param limit query int false "Limite de resultados (padrão 50, máximo 100)"
+// @Param offset query int false "Offset para paginação (padrão 0)"
+// @Success 200 {object} dto.OrganizationListResponse "Órgãos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/organizations [get]
+func (api *Api) handleListOrganizations(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := parseIntParam(query.Get("limit"), 50)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseIntParam(query.Get("offset"), 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	orgs, total, err := api.Organizations.ListOrganizations(r.Context(), query.Get("q"), limit, offset)
+	if err != nil {
+		logger.Log.Error("Failed to list organizations", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar órgãos",
+		})
+		return
+	}
+
+	response := dto.OrganizationListResponse{
+		Data:   make([]dto.OrganizationResponse, len(orgs)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, org := range orgs {
+		response.Data[i] = toOrganizationResponse(&pgstore.Organization{
+			ID:        org.ID,
+			Name:      org.Name,
+			Cnpj:      org.Cnpj,
+			Uasg:      org.Uasg,
+			CreatedAt: org.CreatedAt,
+			UpdatedAt: org.UpdatedAt,
+		})
+		response.Data[i].MemberCount = &orgs[i].MemberCount
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleCreateOrganization godoc
+// @Summary Cadastra um órgão
+// @Description Cria o órgão sem membros; adicione os membros em /organizations/{id}/members. CNPJ (com ou sem pontuação) e UASG são opcionais e únicos. Exige o papel admin.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param organization body dto.OrganizationReq true "Órgão"
+// @Success 201 {object} dto.OrganizationResponse "Órgão criado"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 409 {object} map[string]interface{} "CNPJ ou UASG já cadastrado"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/organizations [post]
+func (api *Api) handleCreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.OrganizationReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	org, err := api.Organizations.CreateOrganization(r.Context(), services.OrganizationInput{
+		Name: data.Name,
+		CNPJ: data.CNPJ,
+		UASG: data.UASG,
+	})
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toOrganizationResponse(org))
+}
+
+// handleGetOrganization godoc
+// @Summary Detalha um órgão
+// @Tags organizations
+// @Produce json
+// @Param id path string true "ID do órgão"
+// @Success 200 {object} dto.OrganizationResponse "Órgão"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/organizations/{id} [get]
+func (api *Api) handleGetOrganization(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	id, ok := parseOrganizationID(w, r)
+	if !ok {
+		return
+	}
+
+	org, err := api.Organizations.GetOrganization(r.Context(), id)
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toOrganizationResponse(org))
+}
+
+// handleUpdateOrganization godoc
+// @Summary Atualiza um órgão
+// @Description Substitui nome, CNPJ e UASG; campos vazios removem o CNPJ ou a UASG. Exige o papel admin.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do órgão"
+// @Param organization body dto.OrganizationReq true "Órgão"
+// @Success 200 {object} dto.OrganizationResponse "Órgão atualizado"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão não encontrado"
+// @Failure 409 {object} map[string]interface{} "CNPJ ou UASG já cadastrado"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/organizations/{id} [put]
+func (api *Api) handleUpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	id, ok := parseOrganizationID(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.OrganizationReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	org, err := api.Organizations.UpdateOrganization(r.Context(), id, services.OrganizationInput{
+		Name: data.Name,
+		CNPJ: data.CNPJ,
+		UASG: data.UASG,
+	})
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toOrganizationResponse(org))
+}
+
+// handleDeleteOrganization godoc
+// @Summary Remove um órgão
+// @Description Remove o órgão, seus membros e as cestas compartilhadas dele. Exige o papel admin.
+// @Tags organizations
+// @Param id path string true "ID do órgão"
+// @Success 204 "Órgão removido"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/organizations/{id} [delete]
+func (api *Api) handleDeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	id, ok := parseOrganizationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.Organizations.DeleteOrganization(r.Context(), id); err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListOrganizationMembers godoc
+// @Summary Lista os membros de um órgão
+// @Description Membros com o papel de cada um no órgão (admin, member ou viewer). Disponível para os membros do órgão e para quem tem o papel admin.
+// @Tags organizations
+// @Produce json
+// @Param id path string true "ID do órgão"
+// @Success 200 {array} dto.OrganizationMemberResponse "Membros"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Router /organizations/{id}/members [get]
+func (api *Api) handleListOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	orgID, ok := parseOrganizationID(w, r)
+	if !ok || !api.authorizeOrganization(w, r, orgID) {
+		return
+	}
+
+	members, err := api.Organizations.ListMembers(r.Context(), orgID)
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	response := make([]dto.OrganizationMemberResponse, len(members))
+	for i, m := range members {
+		response[i] = dto.OrganizationMemberResponse{
+			UserID:    m.UserID.String(),
+			UserName:  m.UserName,
+			Email:     m.Email,
+			Role:      m.Role,
+			CreatedAt: m.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			UpdatedAt: m.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleAddOrganizationMember godoc
+// @Summary Adiciona um membro a um órgão
+// @Description Adiciona a conta com o email informado, ou muda o papel se ela já é membro. Disponível para os admins do órgão e para quem tem o papel admin.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do órgão"
+// @Param member body dto.AddOrganizationMemberReq true "Membro"
+// @Success 201 {object} dto.OrganizationMemberResponse "Membro adicionado"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão ou usuário não encontrado"
+// @Failure 409 {object} map[string]interface{} "Último admin do órgão"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Router /organizations/{id}/members [post]
+func (api *Api) handleAddOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	orgID, ok := parseOrganizationID(w, r)
+	if !ok || !api.authorizeOrganization(w, r, orgID, services.OrgRoleAdmin) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.AddOrganizationMemberReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	member, err := api.Organizations.AddMemberByEmail(r.Context(), orgID, data.Email, data.Role)
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toOrganizationMemberResponse(member))
+}
+
+// handleSetOrganizationMember godoc
+// @Summary Define o papel de um membro
+// @Description Adiciona o usuário ao órgão ou muda o papel dele. O último admin do órgão não pode ser rebaixado. Disponível para os admins do órgão e para quem tem o papel admin.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do órgão"
+// @Param userID path string true "ID do usuário"
+// @Param member body dto.OrganizationMemberReq true "Papel"
+// @Success 200 {object} dto.OrganizationMemberResponse "Membro"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão ou usuário não encontrado"
+// @Failure 409 {object} map[string]interface{} "Último admin do órgão"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Router /organizations/{id}/members/{userID} [put]
+func (api *Api) handleSetOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	orgID, ok := parseOrganizationID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := parseOrganizationMemberID(w, r)
+	if !ok || !api.authorizeOrganization(w, r, orgID, services.OrgRoleAdmin) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.OrganizationMemberReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	member, err := api.Organizations.SetMember(r.Context(), orgID, userID, data.Role)
+	if err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toOrganizationMemberResponse(member))
+}
+
+// handleRemoveOrganizationMember godoc
+// @Summary Remove um membro de um órgão
+// @Description O último admin do órgão não pode ser removido. Disponível para os admins do órgão e para quem tem o papel admin.
+// @Tags organizations
+// @Param id path string true "ID do órgão"
+// @Param userID path string true "ID do usuário"
+// @Success 204 "Membro removido"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Órgão não encontrado ou usuário não é membro"
+// @Failure 409 {object} map[string]interface{} "Último admin do órgão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Router /organizations/{id}/members/{userID} [delete]
+func (api *Api) handleRemoveOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	if !api.requireOrganizations(w, r) {
+		return
+	}
+
+	orgID, ok := parseOrganizationID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := parseOrganizationMemberID(w, r)
+	if !ok || !api.authorizeOrganization(w, r, orgID, services.OrgRoleAdmin) {
+		return
+	}
+
+	if err := api.Organizations.RemoveMember(r.Context(), orgID, userID); err != nil {
+		api.writeOrganizationError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeOrganization lets through members of the organization holding one
+// of roles (any member when roles is empty) and users with the admin role.
+func (api *Api) authorizeOrganization(w http.ResponseWriter, r *http.Request, orgID uuid.UUID, roles ...string) bool {
+	userID, _ := currentUserID(r)
+	membership, err := api.Organizations.GetMembership(r.Context(), orgID, userID)
+	switch {
+	case err == nil:
+		if len(roles) == 0 || slices.Contains(roles, membership.Role) {
+			return true
+		}
+	case !errors.Is(err, services.ErrNotOrganizationMember):
+		logger.Log.Error("Failed to check organization membership",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+			zap.String("organization_id", orgID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao verificar permissões",
+		})
+		return false
+	}
+
+	if api.Roles != nil {
+		userRoles, err := api.Roles.GetUserRoles(r.Context(), userID)
+		if err != nil {
+			logger.Log.Error("Failed to fetch user roles",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "falha ao verificar permissões",
+			})
+			return false
+		}
+		if slices.Contains(userRoles, services.RoleAdmin) {
+			return true
+		}
+	}
+
+	logger.Log.Warn("Access denied - organization role",
+		zap.String("user_id", userID.String()),
+		zap.String("organization_id", orgID.String()),
+		zap.Strings("required", roles))
+	_ = jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+		"error": "sem permissão neste órgão",
+	})
+	return false
+}
+
+func (api *Api) requireOrganizations(w http.ResponseWriter, r *http.Request) bool {
+	if api.Organizations == nil {
+		logger.Log.Error("Organization service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de órgãos indisponível",
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) writeOrganizationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "órgão não encontrado",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "usuário não encontrado",
+		})
+	case errors.Is(err, services.ErrNotOrganizationMember):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "usuário não é membro do órgão",
+		})
+	case errors.Is(err, services.ErrOrganizationExists):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "já existe um órgão com este CNPJ ou UASG",
+		})
+	case errors.Is(err, services.ErrLastOrganizationAdmin):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "o último admin do órgão não pode ser removido nem rebaixado",
+		})
+	case errors.Is(err, services.ErrInvalidOrganization):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "dados do órgão inválidos; confira o CNPJ e a UASG",
+		})
+	case errors.Is(err, services.ErrInvalidOrgRole):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "papel inválido; use admin, member ou viewer",
+		})
+	default:
+		logger.Log.Error("Organization request failed", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao processar órgão",
+		})
+	}
+}
+
+func parseOrganizationID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func parseOrganizationMemberID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id de usuário inválido",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func toOrganizationResponse(org *pgstore.Organization) dto.OrganizationResponse {
+	return dto.OrganizationResponse{
+		ID:        org.ID.String(),
+		Name:      org.Name,
+		CNPJ:      optionalText(org.Cnpj),
+		UASG:      optionalText(org.Uasg),
+		CreatedAt: org.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: org.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func toOrganizationMemberResponse(member *pgstore.OrganizationMember) dto.OrganizationMemberResponse {
+	return dto.OrganizationMemberResponse{
+		UserID:    member.UserID.String(),
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: member.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func optionalText(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
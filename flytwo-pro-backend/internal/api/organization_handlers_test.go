@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"gobid/internal/tenant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOrganizationAPI() (*Api, *mocks.MockOrganizationService, *mocks.MockItemListService) {
+	api, _ := setupTestAPI()
+	mockOrgs := new(mocks.MockOrganizationService)
+	mockLists := new(mocks.MockItemListService)
+	api.Organizations = mockOrgs
+	api.ItemLists = mockLists
+	return api, mockOrgs, mockLists
+}
+
+// inOrganization matches a context acting on behalf of orgID, or without
+// an organization when orgID is uuid.Nil.
+func inOrganization(orgID uuid.UUID) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		org, ok := tenant.FromContext(ctx)
+		if orgID == uuid.Nil {
+			return !ok
+		}
+		return ok && org.ID == orgID
+	})
+}
+
+func membership(orgID, userID uuid.UUID, role string) *pgstore.GetOrganizationMembershipRow {
+	return &pgstore.GetOrganizationMembershipRow{
+		OrganizationID:   orgID,
+		UserID:           userID,
+		Role:             role,
+		OrganizationName: "Ministério da Economia",
+	}
+}
+
+func TestResolveOrganization_Header(t *testing.T) {
+	api, mockOrgs, mockLists := setupOrganizationAPI()
+	userID, orgID, otherID := uuid.New(), uuid.New(), uuid.New()
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, services.OrgRoleViewer), nil)
+	mockOrgs.On("GetMembership", mock.Anything, otherID, userID).Return(nil, services.ErrNotOrganizationMember)
+	mockLists.On("ListItemLists", inOrganization(orgID), userID).Return([]pgstore.ListItemListsRow{}, nil)
+	mockLists.On("ListItemLists", inOrganization(uuid.Nil), userID).Return([]pgstore.ListItemListsRow{}, nil)
+	cookie := authCookie(api, userID)
+
+	get := func(header string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/lists", nil)
+		req.AddCookie(cookie)
+		if header != "" {
+			req.Header.Set(organizationHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		api.Router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get(orgID.String()))
+	assert.Equal(t, http.StatusOK, get(""))
+	assert.Equal(t, http.StatusForbidden, get(otherID.String()))
+	assert.Equal(t, http.StatusBadRequest, get("not-a-uuid"))
+	mockLists.AssertNumberOfCalls(t, "ListItemLists", 2)
+}
+
+func TestHandleSelectOrganization(t *testing.T) {
+	api, mockOrgs, mockLists := setupOrganizationAPI()
+	userID, orgID := uuid.New(), uuid.New()
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, services.OrgRoleMember), nil).Once()
+	mockOrgs.On("ListUserOrganizations", mock.Anything, userID).Return([]pgstore.ListUserOrganizationsRow{
+		{ID: orgID, Name: "Ministério da Economia", Role: services.OrgRoleMember, JoinedAt: time.Now()},
+		{ID: uuid.New(), Name: "Outro órgão", Role: services.OrgRoleViewer, JoinedAt: time.Now()},
+	}, nil)
+	mockLists.On("ListItemLists", inOrganization(orgID), userID).Return([]pgstore.ListItemListsRow{}, nil)
+	cookie := authCookie(api, userID)
+
+	rec := sendJSON(t, api, http.MethodPut, "/api/v1/users/me/organization",
+		dto.SelectOrganizationReq{OrganizationID: orgID.String()}, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var active dto.ActiveOrganizationResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &active))
+	assert.Equal(t, dto.ActiveOrganizationResponse{ID: orgID.String(), Name: "Ministério da Economia", Role: "member"}, active)
+
+	rec = getWithCookie(api, "/api/v1/users/me/organizations", cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var orgs []dto.UserOrganizationResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orgs))
+	require.Len(t, orgs, 2)
+	assert.True(t, orgs[0].Active)
+	assert.False(t, orgs[1].Active)
+
+	// The session now works on the organization's lists, while the user is
+	// still a member.
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, services.OrgRoleMember), nil).Once()
+	assert.Equal(t, http.StatusOK, getWithCookie(api, "/api/v1/me/lists", cookie).Code)
+
+	// Removed from the organization: refused once, then back to personal lists.
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(nil, services.ErrNotOrganizationMember).Once()
+	mockLists.On("ListItemLists", inOrganization(uuid.Nil), userID).Return([]pgstore.ListItemListsRow{}, nil)
+	assert.Equal(t, http.StatusForbidden, getWithCookie(api, "/api/v1/me/lists", cookie).Code)
+	assert.Equal(t, http.StatusOK, getWithCookie(api, "/api/v1/me/lists", cookie).Code)
+	mockLists.AssertExpectations(t)
+}
+
+func TestHandleSelectOrganization_NotMember(t *testing.T) {
+	api, mockOrgs, _ := setupOrganizationAPI()
+	userID, orgID := uuid.New(), uuid.New()
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(nil, services.ErrNotOrganizationMember)
+
+	rec := sendJSON(t, api, http.MethodPut, "/api/v1/users/me/organization",
+		dto.SelectOrganizationReq{OrganizationID: orgID.String()}, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestHandleCreateOrganization(t *testing.T) {
+	api, mockOrgs, _ := setupOrganizationAPI()
+	api.Roles = adminRoles()
+	input := services.OrganizationInput{Name: "Ministério da Economia", CNPJ: "00.394.460/0058-87", UASG: "170001"}
+	mockOrgs.On("CreateOrganization", mock.Anything, input).Return(&pgstore.Organization{ID: uuid.New(), Name: input.Name}, nil)
+	mockOrgs.On("CreateOrganization", mock.Anything, mock.Anything).Return(nil, services.ErrOrganizationExists)
+	cookie := authCookie(api, uuid.New())
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/admin/organizations",
+		dto.OrganizationReq{Name: input.Name, CNPJ: input.CNPJ, UASG: input.UASG}, cookie)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/admin/organizations",
+		dto.OrganizationReq{Name: "Outro", UASG: "170001"}, cookie)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/admin/organizations",
+		dto.OrganizationReq{Name: "Outro", UASG: "17A"}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestHandleAddOrganizationMember_Permissions(t *testing.T) {
+	orgID := uuid.New()
+	added := &pgstore.OrganizationMember{OrganizationID: orgID, UserID: uuid.New(), Role: services.OrgRoleMember}
+
+	tests := []struct {
+		name       string
+		orgRole    string // empty: not a member
+		adminRole  bool
+		wantStatus int
+	}{
+		{name: "organization admin", orgRole: services.OrgRoleAdmin, wantStatus: http.StatusCreated},
+		{name: "organization member", orgRole: services.OrgRoleMember, wantStatus: http.StatusForbidden},
+		{name: "global admin", adminRole: true, wantStatus: http.StatusCreated},
+		{name: "outsider", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, mockOrgs, _ := setupOrganizationAPI()
+			userID := uuid.New()
+			if tt.orgRole != "" {
+				mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, tt.orgRole), nil)
+			} else {
+				mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(nil, services.ErrNotOrganizationMember)
+			}
+			roles := new(mocks.MockRoleService)
+			if tt.adminRole {
+				roles.On("GetUserRoles", mock.Anything, userID).Return([]string{services.RoleAdmin}, nil)
+			} else {
+				roles.On("GetUserRoles", mock.Anything, userID).Return([]string{services.RoleBuyer}, nil)
+			}
+			api.Roles = roles
+			mockOrgs.On("AddMemberByEmail", mock.Anything, orgID, "buyer@example.com", "member").Return(added, nil)
+
+			rec := sendJSON(t, api, http.MethodPost, "/api/v1/organizations/"+orgID.String()+"/members",
+				dto.AddOrganizationMemberReq{Email: "buyer@example.com", Role: "member"}, authCookie(api, userID))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusCreated {
+				mockOrgs.AssertNotCalled(t, "AddMemberByEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandleRemoveOrganizationMember_LastAdmin(t *testing.T) {
+	api, mockOrgs, _ := setupOrganizationAPI()
+	userID, orgID := uuid.New(), uuid.New()
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, services.OrgRoleAdmin), nil)
+	mockOrgs.On("RemoveMember", mock.Anything, orgID, userID).Return(services.ErrLastOrganizationAdmin)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/organizations/"+orgID.String()+"/members/"+userID.String(), nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleCreateItemList_ReadOnlyOrganization(t *testing.T) {
+	api, mockOrgs, mockLists := setupOrganizationAPI()
+	userID, orgID := uuid.New(), uuid.New()
+	mockOrgs.On("GetMembership", mock.Anything, orgID, userID).Return(membership(orgID, userID, services.OrgRoleViewer), nil)
+	mockLists.On("CreateItemList", inOrganization(orgID), userID, mock.Anything).Return(nil, services.ErrOrganizationReadOnly)
+
+	body, _ := json.Marshal(dto.ItemListReq{Name: "Escritório"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/lists", bytes.NewReader(body))
+	req.Header.Set(organizationHeader, orgID.String())
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockLists.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"math"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// handleForgotPassword godoc
+// @Summary Request a password reset link
+// @Description Email a single-use link to reset the password. The response is the same whether or not the email belongs to an account. Requests are limited per email and per client IP; over the limit the answer is 429 with Retry-After.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordReq true "Account email"
+// @Success 202 {object} map[string]interface{} "Request accepted"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 429 {object} map[string]interface{} "Too many requests"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/password/forgot [post]
+func (api *Api) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePasswordReset(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.ForgotPasswordReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	if wait := api.PasswordReset.RequestReset(r.Context(), clientIP(r), data.Email); wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		_ = jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{
+			"error":       "too many password reset requests; try again later",
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"message": "if the email belongs to an account, a reset link has been sent",
+	})
+}
+
+// handleResetPassword godoc
+// @Summary Reset the password
+// @Description Set a new password with the token from the reset link. The token works once; every session of the account is logged out and its refresh tokens and API keys are revoked. Attempts are limited per client IP; over the limit the answer is 429 with Retry-After.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordReq true "Reset token and new password"
+// @Success 200 {object} map[string]interface{} "Password changed"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 429 {object} map[string]interface{} "Too many attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/password/reset [post]
+func (api *Api) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePasswordReset(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.ResetPasswordReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	if wait := api.PasswordReset.AllowResetAttempt(r.Context(), clientIP(r)); wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		_ = jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{
+			"error":       "too many password reset attempts; try again later",
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	userID, err := api.PasswordReset.ResetPassword(r.Context(), data.Token, data.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "invalid or expired reset token",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to reset password", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	logger.Log.Info("Password reset", zap.String("user_id", userID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "password changed; log in with the new password",
+	})
+}
+
+func (api *Api) requirePasswordReset(w http.ResponseWriter, r *http.Request) bool {
+	if api.PasswordReset == nil {
+		logger.Log.Error("Password reset service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "password reset not configured",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupPasswordResetAPI() (*Api, *mocks.MockUserService, *mocks.MockPasswordResetService) {
+	api, mockUsers := setupTestAPI()
+	mockReset := new(mocks.MockPasswordResetService)
+	api.PasswordReset = mockReset
+	return api, mockUsers, mockReset
+}
+
+func TestHandleForgotPassword_SameResponseForAnyEmail(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+	mockReset.On("RequestReset", mock.Anything, "192.0.2.1", mock.Anything).Return(time.Duration(0))
+
+	known := postJSON(t, api, "/api/v1/users/password/forgot", dto.ForgotPasswordReq{Email: "user@example.com"})
+	unknown := postJSON(t, api, "/api/v1/users/password/forgot", dto.ForgotPasswordReq{Email: "nobody@example.com"})
+
+	assert.Equal(t, http.StatusAccepted, known.Code)
+	assert.Equal(t, known.Code, unknown.Code)
+	assert.Equal(t, known.Body.String(), unknown.Body.String())
+	mockReset.AssertNumberOfCalls(t, "RequestReset", 2)
+}
+
+func TestHandleForgotPassword_InvalidEmail(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+
+	rec := postJSON(t, api, "/api/v1/users/password/forgot", dto.ForgotPasswordReq{Email: "not-an-email"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockReset.AssertNotCalled(t, "RequestReset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleForgotPassword_Throttled(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+	mockReset.On("RequestReset", mock.Anything, "192.0.2.1", "user@example.com").Return(90 * time.Second)
+
+	rec := postJSON(t, api, "/api/v1/users/password/forgot", dto.ForgotPasswordReq{Email: "user@example.com"})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+}
+
+func TestHandleResetPassword_Success(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+	userID := uuid.New()
+	// The service ends the user's sessions in the same transaction.
+	mockReset.On("AllowResetAttempt", mock.Anything, "192.0.2.1").Return(time.Duration(0))
+	mockReset.On("ResetPassword", mock.Anything, "reset-token", "new-password").Return(userID, nil)
+
+	rec := postJSON(t, api, "/api/v1/users/password/reset", dto.ResetPasswordReq{Token: "reset-token", Password: "new-password"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockReset.AssertExpectations(t)
+}
+
+func TestHandleResetPassword_InvalidToken(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+	mockReset.On("AllowResetAttempt", mock.Anything, "192.0.2.1").Return(time.Duration(0))
+	mockReset.On("ResetPassword", mock.Anything, "used-token", "new-password").Return(uuid.Nil, services.ErrInvalidResetToken)
+
+	rec := postJSON(t, api, "/api/v1/users/password/reset", dto.ResetPasswordReq{Token: "used-token", Password: "new-password"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleResetPassword_ShortPassword(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+
+	rec := postJSON(t, api, "/api/v1/users/password/reset", dto.ResetPasswordReq{Token: "reset-token", Password: "short"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockReset.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleResetPassword_Throttled(t *testing.T) {
+	api, _, mockReset := setupPasswordResetAPI()
+	mockReset.On("AllowResetAttempt", mock.Anything, "192.0.2.1").Return(30 * time.Minute)
+
+	rec := postJSON(t, api, "/api/v1/users/password/reset", dto.ResetPasswordReq{Token: "guess", Password: "new-password"})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1800", rec.Header().Get("Retry-After"))
+	mockReset.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything)
+}
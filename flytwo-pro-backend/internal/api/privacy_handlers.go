@@ -0,0 +1,672 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleExportUserData godoc
+// @Summary Export my data
+// @Description Everything stored about the logged-in user (LGPD access request): profile, sessions, app sign-ins, API keys, item lists, saved searches, notifications, search history and security events. Secrets such as the password hash are never included. format=zip returns one JSON file per section.
+// @Tags users
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} dto.UserDataExport "User data"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me/export [get]
+func (api *Api) handleExportUserData(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid format; use json or zip",
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	data, err := api.Privacy.ExportUserData(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to export user data",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	api.writeUserDataExport(w, r, format, toUserDataExport(data))
+}
+
+// handleDeleteAccount godoc
+// @Summary Delete my account
+// @Description Schedule the deletion of the logged-in user's account (LGPD). The account is deleted after the grace period; until then, logging in again and calling POST /users/me/deletion/cancel keeps it. Every session ends now, and refresh tokens and API keys are revoked. Lists, saved searches and notifications are deleted with the account; search history and security events are kept without the link to it. A wrong password counts as a failed login.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.DeleteAccountReq true "Current password"
+// @Success 202 {object} dto.AccountDeletionResponse "Deletion scheduled"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Deletion already requested, or last admin"
+// @Failure 422 {object} map[string]interface{} "Validation errors or wrong password"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me [delete]
+func (api *Api) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.DeleteAccountReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	user, err := api.UserService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		api.writeAccountDeletionError(w, r, userID, err)
+		return
+	}
+	if !api.allowLogin(w, r, user.Email) {
+		return
+	}
+	if id, err := api.UserService.AuthenticateUser(r.Context(), user.Email, data.Password); err != nil || id != userID {
+		if err == nil {
+			err = services.ErrInvalidCredentials
+		}
+		api.recordLoginFailure(r, user.Email, err)
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "Validation failed",
+				"fields": map[string]string{
+					"password": "is incorrect",
+				},
+			})
+			return
+		}
+		api.writeAccountDeletionError(w, r, userID, err)
+		return
+	}
+
+	deletion, err := api.Privacy.RequestDeletion(r.Context(), userID, userID, false)
+	if err != nil {
+		api.writeAccountDeletionError(w, r, userID, err)
+		return
+	}
+
+	// The service already ended the tracked sessions, this one included.
+	if err := api.Sessions.Destroy(r.Context()); err != nil {
+		logger.Log.Error("Failed to destroy current session", zap.Error(err))
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusAccepted, toAccountDeletionResponse(deletion))
+}
+
+// handleGetAccountDeletion godoc
+// @Summary Get my pending account deletion
+// @Description The pending deletion of the logged-in user's account, with the date it happens.
+// @Tags users
+// @Produce json
+// @Success 200 {object} dto.AccountDeletionResponse "Pending deletion"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "No pending deletion"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me/deletion [get]
+func (api *Api) handleGetAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	deletion, err := api.Privacy.GetPendingDeletion(r.Context(), userID)
+	if err != nil {
+		api.writeAccountDeletionError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toAccountDeletionResponse(deletion))
+}
+
+// handleCancelAccountDeletion godoc
+// @Summary Cancel my account deletion
+// @Description Keep the account of the logged-in user. Revoked refresh tokens and API keys stay revoked.
+// @Tags users
+// @Produce json
+// @Success 200 {object} dto.AccountDeletionResponse "Deletion cancelled"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "No pending deletion"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me/deletion/cancel [post]
+func (api *Api) handleCancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	deletion, err := api.Privacy.CancelDeletion(r.Context(), userID, userID)
+	if err != nil {
+		api.writeAccountDeletionError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toAccountDeletionResponse(deletion))
+}
+
+// handleListAccountDeletions godoc
+// @Summary Lista os pedidos de exclusão de conta
+// @Description Pedidos de exclusão (LGPD), dos mais recentes aos mais antigos. O email some depois que a conta é apagada; o pedido fica como registro. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param status query string false "pending, cancelled ou completed"
+// @Param limit query int false "Limite de resultados (padrão 50, máximo 100)"
+// @Param offset query int false "Offset para paginação (padrão 0)"
+// @Success 200 {object} dto.AccountDeletionListResponse "Pedidos"
+// @Failure 400 {object} map[string]interface{} "Status inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/account-deletions [get]
+func (api *Api) handleListAccountDeletions(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	status := query.Get("status")
+	if status != "" && !slices.Contains(services.AccountDeletionStatuses, status) {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "status inválido",
+		})
+		return
+	}
+	limit := parseIntParam(query.Get("limit"), 50)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseIntParam(query.Get("offset"), 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	deletions, total, err := api.Privacy.ListDeletions(r.Context(), status, limit, offset)
+	if err != nil {
+		logger.Log.Error("Erro ao listar pedidos de exclusão", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar pedidos de exclusão",
+		})
+		return
+	}
+
+	response := dto.AccountDeletionListResponse{
+		Data:   make([]dto.AccountDeletionResponse, len(deletions)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, d := range deletions {
+		item := toAccountDeletionResponse(&pgstore.AccountDeletion{
+			ID:           d.ID,
+			UserID:       d.UserID,
+			RequestedBy:  d.RequestedBy,
+			RequestedAt:  d.RequestedAt,
+			ScheduledFor: d.ScheduledFor,
+			CancelledAt:  d.CancelledAt,
+			CancelledBy:  d.CancelledBy,
+			CompletedAt:  d.CompletedAt,
+		})
+		if d.Email != "" {
+			item.Email = &d.Email
+		}
+		response.Data[i] = item
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleAdminExportUserData godoc
+// @Summary Exporta os dados de um usuário
+// @Description Tudo o que está guardado sobre o usuário, para atender pedidos LGPD que chegam por outros canais. Mesmo conteúdo de GET /users/me/export. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Produce application/zip
+// @Param id path string true "ID do usuário"
+// @Param format query string false "json (padrão) ou zip"
+// @Success 200 {object} dto.UserDataExport "Dados do usuário"
+// @Failure 400 {object} map[string]interface{} "ID ou formato inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/export [get]
+func (api *Api) handleAdminExportUserData(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	userID, ok := parseAdminUserID(w, r)
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "formato inválido; use json ou zip",
+		})
+		return
+	}
+
+	data, err := api.Privacy.ExportUserData(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao exportar dados do usuário", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao exportar dados",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	logger.Log.Info("Dados de usuário exportados por admin",
+		zap.String("user_id", userID.String()),
+		zap.String("by", adminID.String()))
+
+	api.writeUserDataExport(w, r, format, toUserDataExport(data))
+}
+
+// handleAdminDeleteUser godoc
+// @Summary Agenda a exclusão de uma conta
+// @Description Agenda a exclusão da conta após o prazo de carência, como em DELETE /users/me; com immediate=true a conta é apagada na hora. Sessões, refresh tokens e chaves de API são revogados. O último admin não pode ser excluído. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param immediate query bool false "Apaga sem prazo de carência"
+// @Success 200 {object} dto.AccountDeletionResponse "Conta apagada (immediate)"
+// @Success 202 {object} dto.AccountDeletionResponse "Exclusão agendada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 409 {object} map[string]interface{} "Exclusão já pedida ou último admin"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/deletion [post]
+func (api *Api) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	userID, ok := parseAdminUserID(w, r)
+	if !ok {
+		return
+	}
+	immediate, _ := strconv.ParseBool(r.URL.Query().Get("immediate"))
+
+	adminID, _ := currentUserID(r)
+	deletion, err := api.Privacy.RequestDeletion(r.Context(), userID, adminID, immediate)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+		case errors.Is(err, services.ErrDeletionAlreadyRequested):
+			_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "a exclusão desta conta já foi pedida",
+			})
+		case errors.Is(err, services.ErrLastAdmin):
+			_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "o último admin não pode ser excluído",
+			})
+		default:
+			logger.Log.Error("Erro ao excluir conta", zap.Error(err))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "falha ao excluir conta",
+			})
+		}
+		return
+	}
+
+	status := http.StatusAccepted
+	if deletion.CompletedAt.Valid {
+		status = http.StatusOK
+	}
+	_ = jsonutils.EncodeJson(w, r, status, toAccountDeletionResponse(deletion))
+}
+
+// handleAdminCancelDeletion godoc
+// @Summary Cancela a exclusão de uma conta
+// @Description Cancela a exclusão pendente da conta. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} dto.AccountDeletionResponse "Exclusão cancelada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Nenhuma exclusão pendente"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/deletion [delete]
+func (api *Api) handleAdminCancelDeletion(w http.ResponseWriter, r *http.Request) {
+	if !api.requirePrivacy(w, r) {
+		return
+	}
+
+	userID, ok := parseAdminUserID(w, r)
+	if !ok {
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	deletion, err := api.Privacy.CancelDeletion(r.Context(), userID, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrDeletionNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "nenhuma exclusão pendente",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao cancelar exclusão de conta", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao cancelar exclusão",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toAccountDeletionResponse(deletion))
+}
+
+func (api *Api) writeAccountDeletionError(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "user not found",
+		})
+	case errors.Is(err, services.ErrDeletionNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "no pending account deletion",
+		})
+	case errors.Is(err, services.ErrDeletionAlreadyRequested):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "account deletion already requested",
+		})
+	case errors.Is(err, services.ErrLastAdmin):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "the last admin cannot delete the account; make someone else admin first",
+		})
+	default:
+		logger.Log.Error("Failed to process account deletion",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+// writeUserDataExport writes the export as JSON, or as a ZIP with one JSON
+// file per section.
+func (api *Api) writeUserDataExport(w http.ResponseWriter, r *http.Request, format string, export dto.UserDataExport) {
+	name := fmt.Sprintf("dados-%s", export.Profile.ID)
+	if format != "zip" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, name))
+		_ = jsonutils.EncodeJson(w, r, http.StatusOK, export)
+		return
+	}
+
+	sections := []struct {
+		file string
+		data any
+	}{
+		{"perfil.json", map[string]any{"exported_at": export.ExportedAt, "profile": export.Profile, "pending_deletion": export.PendingDeletion}},
+		{"sessoes.json", export.Sessions},
+		{"acessos_app.json", export.RefreshTokens},
+		{"chaves_api.json", export.ApiKeys},
+		{"identidades.json", export.Identities},
+		{"orgaos.json", export.Organizations},
+		{"cestas.json", export.ItemLists},
+		{"buscas_salvas.json", export.SavedSearches},
+		{"notificacoes.json", export.Notifications},
+		{"historico_buscas.json", export.Searches},
+		{"cliques_buscas.json", export.SearchClicks},
+		{"eventos_seguranca.json", export.SecurityEvents},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, section := range sections {
+		f, err := zw.Create(section.file)
+		if err == nil {
+			enc := json.NewEncoder(f)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(section.data)
+		}
+		if err != nil {
+			logger.Log.Error("Failed to build data export archive", zap.Error(err))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logger.Log.Error("Failed to build data export archive", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, name))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+func toUserDataExport(data *services.UserData) dto.UserDataExport {
+	roles := data.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	export := dto.UserDataExport{
+		ExportedAt: data.ExportedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Profile: dto.UserProfileResponse{
+			ID:               data.User.ID.String(),
+			UserName:         data.User.UserName,
+			Email:            data.User.Email,
+			EmailVerified:    data.User.EmailVerifiedAt.Valid,
+			EmailVerifiedAt:  formatOptionalTime(data.User.EmailVerifiedAt.Time),
+			TwoFactorEnabled: data.TwoFactor.Enabled,
+			CreatedAt:        data.User.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			Roles:            roles,
+		},
+		Sessions:       make([]dto.SessionResponse, len(data.Sessions)),
+		RefreshTokens:  make([]dto.RefreshTokenExport, len(data.RefreshTokens)),
+		ApiKeys:        make([]dto.ApiKeyResponse, len(data.ApiKeys)),
+		Identities:     make([]dto.IdentityExport, len(data.Identities)),
+		Organizations:  make([]dto.OrganizationMembershipExport, len(data.Organizations)),
+		ItemLists:      make([]dto.ItemListDetailResponse, len(data.ItemLists)),
+		SavedSearches:  make([]dto.SavedSearchResponse, len(data.SavedSearches)),
+		Notifications:  make([]dto.NotificationResponse, len(data.Notifications)),
+		Searches:       make([]dto.SearchLogExport, len(data.Searches)),
+		SearchClicks:   make([]dto.SearchClickExport, len(data.SearchClicks)),
+		SecurityEvents: make([]dto.SecurityEventResponse, len(data.SecurityEvents)),
+	}
+
+	for i, s := range data.Sessions {
+		export.Sessions[i] = dto.SessionResponse{
+			ID:         s.ID.String(),
+			IP:         s.Ip,
+			UserAgent:  s.UserAgent,
+			CreatedAt:  s.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			LastSeenAt: s.LastSeenAt.UTC().Format("2006-01-02T15:04:05Z"),
+			ExpiresAt:  s.Expiry.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+	for i, t := range data.RefreshTokens {
+		export.RefreshTokens[i] = dto.RefreshTokenExport{
+			ID:        t.ID.String(),
+			CreatedAt: t.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			ExpiresAt: t.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+			UsedAt:    formatOptionalTime(t.UsedAt.Time),
+			RevokedAt: formatOptionalTime(t.RevokedAt.Time),
+		}
+	}
+	for i := range data.ApiKeys {
+		export.ApiKeys[i] = toApiKeyResponse(&data.ApiKeys[i])
+	}
+	for i, identity := range data.Identities {
+		export.Identities[i] = dto.IdentityExport{
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			CreatedAt:   identity.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			LastLoginAt: identity.LastLoginAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+	for i, org := range data.Organizations {
+		export.Organizations[i] = dto.OrganizationMembershipExport{
+			OrganizationID: org.ID.String(),
+			Name:           org.Name,
+			Role:           org.Role,
+			JoinedAt:       org.JoinedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+	for i := range data.ItemLists {
+		export.ItemLists[i] = toItemListDetailResponse(&data.ItemLists[i])
+	}
+	for i := range data.SavedSearches {
+		export.SavedSearches[i] = toSavedSearchResponse(&data.SavedSearches[i])
+	}
+	for i := range data.Notifications {
+		export.Notifications[i] = toNotificationResponse(&data.Notifications[i])
+	}
+	for i, s := range data.Searches {
+		item := dto.SearchLogExport{
+			ID:          s.ID.String(),
+			Catalog:     s.Catalog,
+			Query:       s.Query,
+			Filters:     map[string]any{},
+			ResultCount: s.ResultCount,
+			CreatedAt:   s.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+		_ = json.Unmarshal(s.Filters, &item.Filters)
+		export.Searches[i] = item
+	}
+	for i, c := range data.SearchClicks {
+		item := dto.SearchClickExport{
+			SearchID:  c.SearchID.String(),
+			ItemCode:  c.ItemCode,
+			CreatedAt: c.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+		if c.Position.Valid {
+			item.Position = &c.Position.Int32
+		}
+		export.SearchClicks[i] = item
+	}
+	for i := range data.SecurityEvents {
+		export.SecurityEvents[i] = toSecurityEventResponse(&data.SecurityEvents[i])
+	}
+	if data.PendingDeletion != nil {
+		pending := toAccountDeletionResponse(data.PendingDeletion)
+		export.PendingDeletion = &pending
+	}
+	return export
+}
+
+func toAccountDeletionResponse(d *pgstore.AccountDeletion) dto.AccountDeletionResponse {
+	response := dto.AccountDeletionResponse{
+		ID:           d.ID.String(),
+		UserID:       d.UserID.String(),
+		Status:       services.AccountDeletionPending,
+		RequestedBy:  d.RequestedBy.String(),
+		RequestedAt:  d.RequestedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		ScheduledFor: d.ScheduledFor.UTC().Format("2006-01-02T15:04:05Z"),
+		CancelledAt:  formatOptionalTime(d.CancelledAt.Time),
+		CompletedAt:  formatOptionalTime(d.CompletedAt.Time),
+	}
+	switch {
+	case d.CompletedAt.Valid:
+		response.Status = services.AccountDeletionCompleted
+	case d.CancelledAt.Valid:
+		response.Status = services.AccountDeletionCancelled
+	}
+	if d.CancelledBy.Valid {
+		id := uuid.UUID(d.CancelledBy.Bytes).String()
+		response.CancelledBy = &id
+	}
+	return response
+}
+
+func parseAdminUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (api *Api) requirePrivacy(w http.ResponseWriter, r *http.Request) bool {
+	if api.Privacy == nil {
+		logger.Log.Error("Privacy service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "privacy requests not configured",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPrivacyAPI() (*Api, *mocks.MockUserService, *mocks.MockPrivacyService) {
+	api, mockUsers := setupTestAPI()
+	mockPrivacy := new(mocks.MockPrivacyService)
+	api.Privacy = mockPrivacy
+	api.Roles = adminRoles()
+	return api, mockUsers, mockPrivacy
+}
+
+func sampleUserData(userID uuid.UUID) *services.UserData {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &services.UserData{
+		User: pgstore.User{
+			ID:           userID,
+			UserName:     "testuser",
+			Email:        "test@example.com",
+			PasswordHash: []byte("$2a$12$secret"),
+			CreatedAt:    created,
+		},
+		Roles: []string{"buyer"},
+		ItemLists: []services.ItemListDetail{
+			{ItemList: pgstore.ItemList{ID: uuid.New(), UserID: pgtype.UUID{Bytes: userID, Valid: true}, Name: "Escritório", CreatedAt: created, UpdatedAt: created}},
+		},
+		Searches: []pgstore.SearchLog{
+			{ID: uuid.New(), Catalog: "catmat", Query: "caneta", Filters: []byte(`{"group_code":75}`), ResultCount: 12, CreatedAt: created},
+		},
+		SecurityEvents: []pgstore.SecurityEvent{
+			{ID: 7, EventType: "login_locked", Email: pgtype.Text{String: "test@example.com", Valid: true}, Details: []byte(`{}`), CreatedAt: created},
+		},
+		ExportedAt: created,
+	}
+}
+
+func TestHandleExportUserData_JSON(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	mockPrivacy.On("ExportUserData", mock.Anything, userID).Return(sampleUserData(userID), nil)
+
+	rec := getWithCookie(api, "/api/v1/users/me/export", authCookie(api, userID))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	assert.NotContains(t, rec.Body.String(), "secret")
+	var export dto.UserDataExport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	assert.Equal(t, "test@example.com", export.Profile.Email)
+	assert.Equal(t, []string{"buyer"}, export.Profile.Roles)
+	require.Len(t, export.ItemLists, 1)
+	assert.Equal(t, "Escritório", export.ItemLists[0].Name)
+	require.Len(t, export.Searches, 1)
+	assert.Equal(t, float64(75), export.Searches[0].Filters["group_code"])
+	require.Len(t, export.SecurityEvents, 1)
+	assert.Empty(t, export.Sessions)
+	assert.Nil(t, export.PendingDeletion)
+}
+
+func TestHandleExportUserData_Zip(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	mockPrivacy.On("ExportUserData", mock.Anything, userID).Return(sampleUserData(userID), nil)
+
+	rec := getWithCookie(api, "/api/v1/users/me/export?format=zip", authCookie(api, userID))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	files := map[string]bool{}
+	for _, f := range archive.File {
+		files[f.Name] = true
+	}
+	assert.True(t, files["perfil.json"])
+	assert.True(t, files["cestas.json"])
+	assert.True(t, files["identidades.json"])
+	assert.True(t, files["orgaos.json"])
+	assert.True(t, files["eventos_seguranca.json"])
+}
+
+func TestHandleExportUserData_InvalidFormat(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+
+	rec := getWithCookie(api, "/api/v1/users/me/export?format=xml", authCookie(api, uuid.New()))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockPrivacy.AssertNotCalled(t, "ExportUserData", mock.Anything, mock.Anything)
+}
+
+func TestHandleDeleteAccount_WrongPassword(t *testing.T) {
+	api, mockUsers, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "test@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Wrong1234").Return(uuid.Nil, services.ErrInvalidCredentials)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/me", dto.DeleteAccountReq{Password: "Wrong1234"}, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"password"`)
+	mockPrivacy.AssertNotCalled(t, "RequestDeletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleDeleteAccount_SchedulesAndLogsOut(t *testing.T) {
+	api, mockUsers, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	scheduled := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "test@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+	mockPrivacy.On("RequestDeletion", mock.Anything, userID, userID, false).Return(&pgstore.AccountDeletion{
+		ID:           uuid.New(),
+		UserID:       userID,
+		RequestedBy:  userID,
+		RequestedAt:  scheduled.AddDate(0, 0, -30),
+		ScheduledFor: scheduled,
+	}, nil)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/me", dto.DeleteAccountReq{Password: "Test1234"}, cookie)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var response dto.AccountDeletionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, services.AccountDeletionPending, response.Status)
+	assert.Equal(t, "2026-02-01T12:00:00Z", response.ScheduledFor)
+
+	// The other sessions are ended by the service through user_session.
+	assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", cookie).Code)
+}
+
+func TestHandleDeleteAccount_LastAdmin(t *testing.T) {
+	api, mockUsers, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "admin@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "admin@example.com", "Test1234").Return(userID, nil)
+	mockPrivacy.On("RequestDeletion", mock.Anything, userID, userID, false).Return(nil, services.ErrLastAdmin)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/me", dto.DeleteAccountReq{Password: "Test1234"}, cookie)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, http.StatusOK, getWithCookie(api, "/api/v1/users/me", cookie).Code)
+}
+
+func TestHandleCancelAccountDeletion(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+	userID := uuid.New()
+	otherID := uuid.New()
+	now := time.Now()
+	mockPrivacy.On("CancelDeletion", mock.Anything, userID, userID).Return(&pgstore.AccountDeletion{
+		ID:           uuid.New(),
+		UserID:       userID,
+		RequestedBy:  userID,
+		RequestedAt:  now,
+		ScheduledFor: now.AddDate(0, 0, 30),
+		CancelledAt:  pgtype.Timestamptz{Time: now, Valid: true},
+		CancelledBy:  pgtype.UUID{Bytes: userID, Valid: true},
+	}, nil)
+	mockPrivacy.On("CancelDeletion", mock.Anything, otherID, otherID).Return(nil, services.ErrDeletionNotFound)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/me/deletion/cancel", nil, authCookie(api, userID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/me/deletion/cancel", nil, authCookie(api, otherID))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleAdminDeleteUser_Immediate(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	mockPrivacy.On("RequestDeletion", mock.Anything, userID, adminID, true).Return(&pgstore.AccountDeletion{
+		ID:           uuid.New(),
+		UserID:       userID,
+		RequestedBy:  adminID,
+		RequestedAt:  now,
+		ScheduledFor: now,
+		CompletedAt:  pgtype.Timestamptz{Time: now, Valid: true},
+	}, nil)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/admin/users/"+userID.String()+"/deletion?immediate=true", nil, authCookie(api, adminID))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"completed"`)
+}
+
+func TestHandleListAccountDeletions(t *testing.T) {
+	api, _, mockPrivacy := setupPrivacyAPI()
+	adminID := uuid.New()
+	now := time.Now()
+	mockPrivacy.On("ListDeletions", mock.Anything, "pending", int32(50), int32(0)).Return([]pgstore.ListAccountDeletionsRow{
+		{ID: uuid.New(), UserID: uuid.New(), RequestedBy: adminID, RequestedAt: now, ScheduledFor: now, Email: "old@example.com"},
+	}, int64(1), nil)
+	cookie := authCookie(api, adminID)
+
+	rec := getWithCookie(api, "/api/v1/admin/account-deletions?status=pending", cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response dto.AccountDeletionListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "old@example.com", *response.Data[0].Email)
+	assert.Equal(t, int64(1), response.Total)
+
+	rec = getWithCookie(api, "/api/v1/admin/account-deletions?status=unknown", cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"gobid/internal/services"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -11,12 +13,15 @@ func (api *Api) BindRoutes() {
 	api.Router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:4200"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-API-Key", "X-Organization-ID"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	if api.TrustProxy {
+		api.Router.Use(middleware.RealIP)
+	}
 	api.Router.Use(middleware.RequestID, middleware.Logger, middleware.Recoverer, api.Sessions.LoadAndSave)
 
 	// Swagger documentation routes (OpenAPI 3 output)
@@ -24,23 +29,162 @@ func (api *Api) BindRoutes() {
 
 	api.Router.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
+			r.Get("/health", api.handleHealth)
+
 			r.Group(func(r chi.Router) {
-				r.Use(api.AuthMiddleware)
-				r.Post("/catmat/import", api.handleImportCatmat)
-				r.Post("/catser/import", api.handleImportCatser)
-				r.Get("/catmat/search", api.handleSearchCatmat)
-				r.Get("/catser/search", api.handleSearchCatser)
-				r.Get("/catalog/stats", api.handleCatalogStats)
+				r.Use(api.AuthMiddleware, api.RequireVerifiedEmail, api.RequireTwoFactorEnrollment)
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireRole(services.RoleAdmin), api.RequireScope(services.ScopeCatalogImport))
+					r.Post("/catmat/import", api.handleImportCatmat)
+					r.Post("/catser/import", api.handleImportCatser)
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireScope(services.ScopeCatalogRead))
+					r.Get("/catmat/search", api.handleSearchCatmat)
+					r.Get("/catser/search", api.handleSearchCatser)
+					r.Get("/catalog/stats", api.handleCatalogStats)
+					r.Get("/catalog/stats/extended", api.handleCatalogExtendedStats)
+					r.Get("/catalog/stats/classes", api.handleCatalogClassStats)
+					r.Post("/search/clicks", api.handleSearchClick)
+				})
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(api.AuthMiddleware, api.RejectApiKeys, api.RequireVerifiedEmail, api.RequireTwoFactorEnrollment)
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireRole(services.RoleManager))
+					r.Route("/search/synonyms", func(r chi.Router) {
+						r.Get("/", api.handleListSynonyms)
+						r.Post("/", api.handleCreateSynonym)
+						r.Get("/expand", api.handleExpandSynonyms)
+						r.Put("/{id}", api.handleUpdateSynonym)
+						r.Delete("/{id}", api.handleDeleteSynonym)
+					})
+					r.Route("/search/reports", func(r chi.Router) {
+						r.Get("/top-queries", api.handleTopSearchQueries)
+						r.Get("/zero-results", api.handleZeroResultSearchQueries)
+						r.Get("/slowest", api.handleSlowestSearchQueries)
+					})
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireRole(services.RoleAdmin))
+					r.Route("/cache", func(r chi.Router) {
+						r.Get("/stats", api.handleCacheStats)
+						r.Post("/purge", api.handlePurgeCache)
+						r.Post("/flush", api.handleFlushCache)
+						r.Get("/warmup", api.handleCacheWarmupStatus)
+						r.Post("/warmup", api.handleTriggerCacheWarmup)
+					})
+					r.Get("/security-events", api.handleListSecurityEvents)
+					r.Get("/account-deletions", api.handleListAccountDeletions)
+					r.Route("/organizations", func(r chi.Router) {
+						r.Get("/", api.handleListOrganizations)
+						r.Post("/", api.handleCreateOrganization)
+						r.Get("/{id}", api.handleGetOrganization)
+						r.Put("/{id}", api.handleUpdateOrganization)
+						r.Delete("/{id}", api.handleDeleteOrganization)
+					})
+					r.Get("/2fa/required-roles", api.handleGetTwoFactorRequiredRoles)
+					r.Put("/2fa/required-roles", api.handleSetTwoFactorRequiredRoles)
+					r.Route("/users", func(r chi.Router) {
+						r.Get("/", api.handleListUsers)
+						r.Put("/{id}/roles", api.handleUpdateUserRoles)
+						r.Put("/{id}/email-verification", api.handleSetEmailVerification)
+						r.Delete("/{id}/login-lock", api.handleUnlockUserLogin)
+						r.Delete("/{id}/2fa", api.handleResetUserTwoFactor)
+						r.Delete("/{id}/sessions", api.handleForceLogoutUser)
+						r.Get("/{id}/export", api.handleAdminExportUserData)
+						r.Post("/{id}/deletion", api.handleAdminDeleteUser)
+						r.Delete("/{id}/deletion", api.handleAdminCancelDeletion)
+					})
+				})
+			})
+
+			r.Route("/me", func(r chi.Router) {
+				r.Use(api.AuthMiddleware, api.RejectApiKeys, api.RequireVerifiedEmail, api.RequireTwoFactorEnrollment, api.ResolveOrganization)
+				r.Route("/saved-searches", func(r chi.Router) {
+					r.Get("/", api.handleListSavedSearches)
+					r.Post("/", api.handleCreateSavedSearch)
+					r.Put("/{id}", api.handleUpdateSavedSearch)
+					r.Delete("/{id}", api.handleDeleteSavedSearch)
+				})
+				r.Route("/lists", func(r chi.Router) {
+					r.Get("/", api.handleListItemLists)
+					r.Post("/", api.handleCreateItemList)
+					r.Get("/{id}", api.handleGetItemList)
+					r.Put("/{id}", api.handleUpdateItemList)
+					r.Delete("/{id}", api.handleDeleteItemList)
+					r.Post("/{id}/duplicate", api.handleDuplicateItemList)
+					r.Get("/{id}/export", api.handleExportItemList)
+					r.Post("/{id}/lines", api.handleAddItemListLine)
+					r.Put("/{id}/lines/{lineID}", api.handleUpdateItemListLine)
+					r.Delete("/{id}/lines/{lineID}", api.handleDeleteItemListLine)
+				})
+				r.Route("/notifications", func(r chi.Router) {
+					r.Get("/", api.handleListNotifications)
+					r.Post("/read-all", api.handleMarkAllNotificationsRead)
+					r.Post("/{id}/read", api.handleMarkNotificationRead)
+				})
+			})
+
+			r.Route("/organizations/{id}/members", func(r chi.Router) {
+				r.Use(api.AuthMiddleware, api.RejectApiKeys, api.RequireVerifiedEmail, api.RequireTwoFactorEnrollment)
+				r.Get("/", api.handleListOrganizationMembers)
+				r.Post("/", api.handleAddOrganizationMember)
+				r.Put("/{userID}", api.handleSetOrganizationMember)
+				r.Delete("/{userID}", api.handleRemoveOrganizationMember)
+			})
+
+			r.Route("/auth", func(r chi.Router) {
+				r.Post("/token", api.handleIssueToken)
+				r.Post("/revoke", api.handleRevokeToken)
+				r.Route("/oidc", func(r chi.Router) {
+					r.Get("/providers", api.handleListOIDCProviders)
+					r.Get("/{provider}/login", api.handleOIDCLogin)
+					r.Get("/{provider}/callback", api.handleOIDCCallback)
+				})
 			})
 
 			r.Route("/users", func(r chi.Router) {
 				r.Post("/signup", api.handleSignupUser)
 				r.Post("/login", api.handleLoginUser)
+				r.Post("/login/2fa", api.handleLoginTwoFactor)
+				r.Post("/password/forgot", api.handleForgotPassword)
+				r.Post("/password/reset", api.handleResetPassword)
+				r.Post("/verify-email", api.handleVerifyEmail)
 
 				r.Group(func(r chi.Router) {
-					r.Use(api.AuthMiddleware)
+					r.Use(api.AuthMiddleware, api.RejectApiKeys)
 					r.Post("/logout", api.handleLogoutUser)
 					r.Get("/me", api.handleGetCurrentUser)
+					r.Patch("/me", api.handleUpdateCurrentUser)
+					r.Post("/me/password", api.handleChangePassword)
+					r.Delete("/me", api.handleDeleteAccount)
+					r.Get("/me/export", api.handleExportUserData)
+					r.Get("/me/organizations", api.handleListMyOrganizations)
+					r.Put("/me/organization", api.handleSelectOrganization)
+					r.Delete("/me/organization", api.handleClearOrganization)
+					r.Get("/me/deletion", api.handleGetAccountDeletion)
+					r.Post("/me/deletion/cancel", api.handleCancelAccountDeletion)
+					r.Post("/verify-email/resend", api.handleResendVerificationEmail)
+					r.Route("/2fa", func(r chi.Router) {
+						r.Get("/", api.handleGetTwoFactorStatus)
+						r.Post("/enroll", api.handleEnrollTwoFactor)
+						r.Post("/confirm", api.handleConfirmTwoFactor)
+						r.Post("/disable", api.handleDisableTwoFactor)
+						r.Post("/recovery-codes", api.handleRegenerateRecoveryCodes)
+					})
+					r.Route("/sessions", func(r chi.Router) {
+						r.Get("/", api.handleListSessions)
+						r.Delete("/", api.handleRevokeOtherSessions)
+						r.Delete("/{id}", api.handleRevokeSession)
+					})
+					r.Route("/api-keys", func(r chi.Router) {
+						r.Use(api.RequireVerifiedEmail, api.RequireTwoFactorEnrollment)
+						r.Get("/", api.handleListApiKeys)
+						r.Post("/", api.handleCreateApiKey)
+						r.Delete("/{id}", api.handleRevokeApiKey)
+					})
 				})
 			})
 		})
@@ -0,0 +1,464 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// savedSearchEvaluationTimeout bounds the background re-evaluation started by an import.
+const savedSearchEvaluationTimeout = 10 * time.Minute
+
+// handleListSavedSearches godoc
+// @Summary Lista as buscas salvas do usuário
+// @Tags saved-searches
+// @Produce json
+// @Success 200 {array} dto.SavedSearchResponse "Buscas salvas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/saved-searches [get]
+func (api *Api) handleListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	searches, err := api.SavedSearches.ListSavedSearches(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Erro ao listar buscas salvas", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar buscas salvas",
+		})
+		return
+	}
+
+	response := make([]dto.SavedSearchResponse, len(searches))
+	for i := range searches {
+		response[i] = toSavedSearchResponse(&searches[i])
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleCreateSavedSearch godoc
+// @Summary Salva uma busca
+// @Description Salva termo e filtros de uma busca CATMAT/CATSER. Após cada importação, itens novos que casam com a busca geram uma notificação.
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param search body dto.SavedSearchReq true "Busca salva"
+// @Success 201 {object} dto.SavedSearchResponse "Busca salva"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 409 {object} map[string]interface{} "Limite de buscas salvas atingido"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/saved-searches [post]
+func (api *Api) handleCreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	input, ok := decodeSavedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	search, err := api.SavedSearches.CreateSavedSearch(r.Context(), userID, input)
+	if err != nil {
+		api.writeSavedSearchError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Busca salva criada",
+		zap.String("id", search.ID.String()),
+		zap.String("catalog", search.Catalog))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toSavedSearchResponse(search))
+}
+
+// handleUpdateSavedSearch godoc
+// @Summary Atualiza uma busca salva
+// @Description Substitui nome, termo e filtros. Os itens que já casam com a nova busca não geram notificação.
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da busca salva"
+// @Param search body dto.SavedSearchReq true "Busca salva"
+// @Success 200 {object} dto.SavedSearchResponse "Busca atualizada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 404 {object} map[string]interface{} "Busca não encontrada"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/saved-searches/{id} [put]
+func (api *Api) handleUpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	id, ok := parseSavedSearchID(w, r)
+	if !ok {
+		return
+	}
+
+	input, ok := decodeSavedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	search, err := api.SavedSearches.UpdateSavedSearch(r.Context(), userID, id, input)
+	if err != nil {
+		api.writeSavedSearchError(w, r, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toSavedSearchResponse(search))
+}
+
+// handleDeleteSavedSearch godoc
+// @Summary Remove uma busca salva
+// @Tags saved-searches
+// @Produce json
+// @Param id path string true "ID da busca salva"
+// @Success 204 "Busca removida"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 404 {object} map[string]interface{} "Busca não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/saved-searches/{id} [delete]
+func (api *Api) handleDeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	id, ok := parseSavedSearchID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if err := api.SavedSearches.DeleteSavedSearch(r.Context(), userID, id); err != nil {
+		api.writeSavedSearchError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListNotifications godoc
+// @Summary Lista as notificações do usuário
+// @Description Notificações de itens novos encontrados pelas buscas salvas após importações, mais recentes primeiro
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Apenas não lidas"
+// @Param limit query int false "Limite de resultados (padrão 50, máximo 100)"
+// @Param offset query int false "Offset para paginação (padrão 0)"
+// @Success 200 {object} dto.NotificationListResponse "Notificações"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/notifications [get]
+func (api *Api) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := parseIntParam(query.Get("limit"), 50)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseIntParam(query.Get("offset"), 0)
+	if offset < 0 {
+		offset = 0
+	}
+	unreadOnly, _ := strconv.ParseBool(query.Get("unread"))
+
+	userID, _ := currentUserID(r)
+	notifications, unread, err := api.SavedSearches.ListNotifications(r.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		logger.Log.Error("Erro ao listar notificações", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar notificações",
+		})
+		return
+	}
+
+	response := dto.NotificationListResponse{
+		Data:   make([]dto.NotificationResponse, len(notifications)),
+		Unread: unread,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i := range notifications {
+		response.Data[i] = toNotificationResponse(&notifications[i])
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleMarkNotificationRead godoc
+// @Summary Marca uma notificação como lida
+// @Tags notifications
+// @Produce json
+// @Param id path int true "ID da notificação"
+// @Success 204 "Notificação marcada como lida"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 404 {object} map[string]interface{} "Notificação não encontrada ou já lida"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/notifications/{id}/read [post]
+func (api *Api) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if err := api.SavedSearches.MarkNotificationRead(r.Context(), userID, id); err != nil {
+		api.writeSavedSearchError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMarkAllNotificationsRead godoc
+// @Summary Marca todas as notificações como lidas
+// @Tags notifications
+// @Produce json
+// @Success 204 "Notificações marcadas como lidas"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /me/notifications/read-all [post]
+func (api *Api) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSavedSearchService(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	if _, err := api.SavedSearches.MarkAllNotificationsRead(r.Context(), userID); err != nil {
+		api.writeSavedSearchError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// evaluateSavedSearches re-runs the saved searches of a catalog in the
+// background, so the import response does not wait for the notifications.
+func (api *Api) evaluateSavedSearches(catalog string) {
+	if api.SavedSearches == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), savedSearchEvaluationTimeout)
+		defer cancel()
+
+		notified, err := api.SavedSearches.EvaluateCatalog(ctx, catalog)
+		if err != nil {
+			logger.Log.Error("Erro ao reavaliar buscas salvas",
+				zap.String("catalog", catalog),
+				zap.Int("notifications", notified),
+				zap.Error(err))
+			return
+		}
+		logger.Log.Info("Buscas salvas reavaliadas",
+			zap.String("catalog", catalog),
+			zap.Int("notifications", notified))
+	}()
+}
+
+// decodeSavedSearch validates the payload and maps it to the params of its catalog.
+func decodeSavedSearch(w http.ResponseWriter, r *http.Request) (services.SavedSearchInput, bool) {
+	data, problems, err := jsonutils.DecodeValidJson[dto.SavedSearchReq](r)
+	if err == nil {
+		problems = savedSearchFilterProblems(data)
+	}
+	if err != nil || len(problems) > 0 {
+		logger.Log.Debug("Validação falhou para busca salva",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return services.SavedSearchInput{}, false
+	}
+
+	input := services.SavedSearchInput{Name: data.Name, Catalog: data.Catalog}
+	switch data.Catalog {
+	case "catmat":
+		input.Catmat = services.CatmatSearchParams{
+			Query:     data.Query,
+			GroupCode: data.GroupCode,
+			ClassCode: data.ClassCode,
+			PdmCode:   data.PdmCode,
+			NcmCode:   data.NcmCode,
+		}
+	case "catser":
+		input.Catser = services.CatserSearchParams{
+			Query:       data.Query,
+			GroupCode:   data.GroupCode,
+			ClassCode:   data.ClassCode,
+			ServiceCode: data.ServiceCode,
+			Status:      data.Status,
+		}
+	}
+	return input, true
+}
+
+// savedSearchFilterProblems rejects filters that belong to the other catalog.
+func savedSearchFilterProblems(data dto.SavedSearchReq) map[string]string {
+	problems := map[string]string{}
+	if data.Catalog == "catmat" {
+		if data.ServiceCode != nil {
+			problems["service_code"] = "only applies to catser"
+		}
+		if data.Status != nil {
+			problems["status"] = "only applies to catser"
+		}
+	}
+	if data.Catalog == "catser" {
+		if data.PdmCode != nil {
+			problems["pdm_code"] = "only applies to catmat"
+		}
+		if data.NcmCode != nil {
+			problems["ncm_code"] = "only applies to catmat"
+		}
+	}
+	return problems
+}
+
+func (api *Api) requireSavedSearchService(w http.ResponseWriter, r *http.Request) bool {
+	if api.SavedSearches == nil {
+		logger.Log.Error("SavedSearches não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de buscas salvas indisponível",
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) writeSavedSearchError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrSavedSearchNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "busca salva não encontrada",
+		})
+	case errors.Is(err, services.ErrNotificationNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "notificação não encontrada",
+		})
+	case errors.Is(err, services.ErrInvalidSavedSearch):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "informe um termo de busca ou ao menos um filtro",
+		})
+	case errors.Is(err, services.ErrSavedSearchLimit):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "limite de buscas salvas atingido",
+		})
+	default:
+		logger.Log.Error("Erro em buscas salvas", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao processar buscas salvas",
+		})
+	}
+}
+
+func parseSavedSearchID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func toSavedSearchResponse(search *pgstore.SavedSearch) dto.SavedSearchResponse {
+	response := dto.SavedSearchResponse{
+		ID:        search.ID.String(),
+		Name:      search.Name,
+		Catalog:   search.Catalog,
+		Query:     search.Query,
+		CreatedAt: search.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: search.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if search.GroupCode.Valid {
+		response.GroupCode = &search.GroupCode.Int16
+	}
+	if search.ClassCode.Valid {
+		response.ClassCode = &search.ClassCode.Int32
+	}
+	if search.PdmCode.Valid {
+		response.PdmCode = &search.PdmCode.Int32
+	}
+	if search.NcmCode.Valid {
+		response.NcmCode = &search.NcmCode.String
+	}
+	if search.ServiceCode.Valid {
+		response.ServiceCode = &search.ServiceCode.Int32
+	}
+	if search.Status.Valid {
+		response.Status = &search.Status.String
+	}
+	if search.LastEvaluatedAt.Valid {
+		evaluated := search.LastEvaluatedAt.Time.Format("2006-01-02T15:04:05Z")
+		response.LastEvaluatedAt = &evaluated
+	}
+	return response
+}
+
+func toNotificationResponse(n *pgstore.Notification) dto.NotificationResponse {
+	response := dto.NotificationResponse{
+		ID:              n.ID,
+		SavedSearchName: n.SavedSearchName,
+		Catalog:         n.Catalog,
+		ItemCodes:       n.ItemCodes,
+		ItemCount:       len(n.ItemCodes),
+		CreatedAt:       n.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if n.SavedSearchID.Valid {
+		id := uuid.UUID(n.SavedSearchID.Bytes).String()
+		response.SavedSearchID = &id
+	}
+	if n.ReadAt.Valid {
+		readAt := n.ReadAt.Time.Format("2006-01-02T15:04:05Z")
+		response.ReadAt = &readAt
+	}
+	return response
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSavedSearchAPI() (*Api, *mocks.MockCatalogImportService, *mocks.MockSavedSearchService) {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	mockCatalog := new(mocks.MockCatalogImportService)
+	mockSaved := new(mocks.MockSavedSearchService)
+	api := &Api{
+		Router:         chi.NewMux(),
+		UserService:    new(mocks.MockUserService),
+		CatalogService: mockCatalog,
+		SavedSearches:  mockSaved,
+		Roles:          adminRoles(),
+		Sessions:       scs.New(),
+		WsUpgrader:     defaultUpgrader(),
+	}
+	api.BindRoutes()
+	return api, mockCatalog, mockSaved
+}
+
+func TestHandleCreateSavedSearch_Success(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+	userID := uuid.New()
+	groupCode := int16(75)
+
+	input := services.SavedSearchInput{
+		Name:    "Papel",
+		Catalog: "catmat",
+		Catmat:  services.CatmatSearchParams{Query: "papel a4", GroupCode: &groupCode},
+	}
+	created := &pgstore.SavedSearch{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      "Papel",
+		Catalog:   "catmat",
+		Query:     "papel a4",
+		GroupCode: pgtype.Int2{Int16: 75, Valid: true},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	mockSaved.On("CreateSavedSearch", mock.Anything, userID, input).Return(created, nil)
+
+	body, _ := json.Marshal(dto.SavedSearchReq{Name: "Papel", Catalog: "catmat", Query: "papel a4", GroupCode: &groupCode})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/saved-searches", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp dto.SavedSearchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, created.ID.String(), resp.ID)
+	assert.Equal(t, int16(75), *resp.GroupCode)
+	assert.Nil(t, resp.PdmCode)
+	mockSaved.AssertExpectations(t)
+}
+
+func TestHandleCreateSavedSearch_FilterFromOtherCatalog(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+	pdmCode := int32(12345)
+
+	body, _ := json.Marshal(dto.SavedSearchReq{Name: "Limpeza", Catalog: "catser", Query: "limpeza", PdmCode: &pdmCode})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/saved-searches", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "pdm_code")
+	mockSaved.AssertNotCalled(t, "CreateSavedSearch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleCreateSavedSearch_InvalidCatalog(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+
+	body, _ := json.Marshal(dto.SavedSearchReq{Name: "X", Catalog: "foo", Query: "x"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/saved-searches", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockSaved.AssertNotCalled(t, "CreateSavedSearch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleCreateSavedSearch_Limit(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+
+	mockSaved.On("CreateSavedSearch", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrSavedSearchLimit)
+
+	body, _ := json.Marshal(dto.SavedSearchReq{Name: "Papel", Catalog: "catmat", Query: "papel"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/saved-searches", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleDeleteSavedSearch_NotFound(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+	userID := uuid.New()
+	id := uuid.New()
+
+	mockSaved.On("DeleteSavedSearch", mock.Anything, userID, id).Return(services.ErrSavedSearchNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/me/saved-searches/"+id.String(), nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockSaved.AssertExpectations(t)
+}
+
+func TestHandleListNotifications_Success(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+	userID := uuid.New()
+	searchID := uuid.New()
+
+	notifications := []pgstore.Notification{{
+		ID:              1,
+		UserID:          userID,
+		SavedSearchID:   pgtype.UUID{Bytes: searchID, Valid: true},
+		SavedSearchName: "Papel",
+		Catalog:         "catmat",
+		ItemCodes:       []int32{150001, 150002},
+		CreatedAt:       time.Now(),
+	}}
+	mockSaved.On("ListNotifications", mock.Anything, userID, true, int32(10), int32(0)).Return(notifications, int64(1), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/notifications?unread=true&limit=10", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.NotificationListResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1), resp.Unread)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, 2, resp.Data[0].ItemCount)
+	assert.Equal(t, searchID.String(), *resp.Data[0].SavedSearchID)
+	assert.Nil(t, resp.Data[0].ReadAt)
+	mockSaved.AssertExpectations(t)
+}
+
+func TestHandleListNotifications_Unauthorized(t *testing.T) {
+	api, _, _ := setupSavedSearchAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/notifications", nil)
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHandleMarkNotificationRead_NotFound(t *testing.T) {
+	api, _, mockSaved := setupSavedSearchAPI()
+	userID := uuid.New()
+
+	mockSaved.On("MarkNotificationRead", mock.Anything, userID, int64(5)).Return(services.ErrNotificationNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/notifications/5/read", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockSaved.AssertExpectations(t)
+}
+
+func TestHandleImportCatmat_EvaluatesSavedSearches(t *testing.T) {
+	api, mockCatalog, mockSaved := setupSavedSearchAPI()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "dummy.xlsx")
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte("dummy"))
+	writer.Close()
+
+	mockCatalog.On("ImportCatmat", mock.Anything, mock.Anything).Return(&services.ImportResult{RowsRead: 1, RowsSaved: 1}, nil)
+
+	evaluated := make(chan struct{})
+	mockSaved.On("EvaluateCatalog", mock.Anything, "catmat").Return(2, nil).Run(func(mock.Arguments) {
+		close(evaluated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/catmat/import", body)
+	req.AddCookie(authCookie(api, uuid.New()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	select {
+	case <-evaluated:
+	case <-time.After(time.Second):
+		t.Fatal("saved searches were not evaluated after import")
+	}
+	mockSaved.AssertExpectations(t)
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	searchReportDefaultDays  = 7
+	searchReportDefaultLimit = 20
+	searchReportMaxLimit     = 100
+)
+
+// recordSearch queues a search for analytics and returns the id exposed to
+// clients for click-through tracking. Params are stored as filters, minus the query.
+func (api *Api) recordSearch(r *http.Request, catalog, query string, params any, total int64, latency time.Duration) string {
+	if api.SearchAnalytics == nil {
+		return ""
+	}
+
+	filters := map[string]any{}
+	if b, err := json.Marshal(params); err == nil {
+		_ = json.Unmarshal(b, &filters)
+	}
+	delete(filters, "q")
+
+	userID, _ := currentUserID(r)
+	entry := services.SearchLogEntry{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Catalog:     catalog,
+		Query:       query,
+		Filters:     filters,
+		ResultCount: total,
+		Latency:     latency,
+	}
+	api.SearchAnalytics.RecordSearch(entry)
+
+	return entry.ID.String()
+}
+
+// handleSearchClick godoc
+// @Summary Registra clique em resultado de busca
+// @Description Associa o item clicado ao search_id devolvido por /catmat/search ou /catser/search
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param click body dto.SearchClickReq true "Clique no resultado"
+// @Success 204 "Clique registrado"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /search/clicks [post]
+func (api *Api) handleSearchClick(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSearchAnalytics(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.SearchClickReq](r)
+	if err != nil {
+		logger.Log.Debug("Validação falhou para clique de busca",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	click := services.SearchClick{
+		SearchID: uuid.MustParse(data.SearchID),
+		UserID:   userID,
+		ItemCode: data.ItemCode,
+		Position: data.Position,
+	}
+
+	if err := api.SearchAnalytics.RecordClick(r.Context(), click); err != nil {
+		logger.Log.Error("Erro ao registrar clique de busca", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao registrar clique",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTopSearchQueries godoc
+// @Summary Consultas mais buscadas
+// @Description Consultas mais frequentes no período, com taxa de cliques
+// @Tags search
+// @Produce json
+// @Param from query string false "Início (YYYY-MM-DD ou RFC3339, padrão 7 dias atrás)"
+// @Param to query string false "Fim exclusivo (YYYY-MM-DD ou RFC3339, padrão agora)"
+// @Param catalog query string false "catmat ou catser"
+// @Param limit query int false "Limite de resultados (padrão 20, máximo 100)"
+// @Success 200 {object} dto.SearchReportResponse "Relatório"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/reports/top-queries [get]
+func (api *Api) handleTopSearchQueries(w http.ResponseWriter, r *http.Request) {
+	api.serveSearchReport(w, r, func(s services.SearchAnalyticsServiceInterface) searchReport {
+		return s.TopQueries
+	})
+}
+
+// handleZeroResultSearchQueries godoc
+// @Summary Consultas sem resultado
+// @Description Consultas que não retornaram itens no período
+// @Tags search
+// @Produce json
+// @Param from query string false "Início (YYYY-MM-DD ou RFC3339, padrão 7 dias atrás)"
+// @Param to query string false "Fim exclusivo (YYYY-MM-DD ou RFC3339, padrão agora)"
+// @Param catalog query string false "catmat ou catser"
+// @Param limit query int false "Limite de resultados (padrão 20, máximo 100)"
+// @Success 200 {object} dto.SearchReportResponse "Relatório"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/reports/zero-results [get]
+func (api *Api) handleZeroResultSearchQueries(w http.ResponseWriter, r *http.Request) {
+	api.serveSearchReport(w, r, func(s services.SearchAnalyticsServiceInterface) searchReport {
+		return s.ZeroResultQueries
+	})
+}
+
+// handleSlowestSearchQueries godoc
+// @Summary Consultas mais lentas
+// @Description Consultas com maior latência no período
+// @Tags search
+// @Produce json
+// @Param from query string false "Início (YYYY-MM-DD ou RFC3339, padrão 7 dias atrás)"
+// @Param to query string false "Fim exclusivo (YYYY-MM-DD ou RFC3339, padrão agora)"
+// @Param catalog query string false "catmat ou catser"
+// @Param limit query int false "Limite de resultados (padrão 20, máximo 100)"
+// @Success 200 {object} dto.SearchReportResponse "Relatório"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/reports/slowest [get]
+func (api *Api) handleSlowestSearchQueries(w http.ResponseWriter, r *http.Request) {
+	api.serveSearchReport(w, r, func(s services.SearchAnalyticsServiceInterface) searchReport {
+		return s.SlowestQueries
+	})
+}
+
+type searchReport func(ctx context.Context, params services.SearchReportParams) ([]dto.SearchQueryStat, error)
+
+// serveSearchReport parses the date range, catalog and limit shared by the
+// analytics reports and runs the one picked by selectReport.
+func (api *Api) serveSearchReport(w http.ResponseWriter, r *http.Request, selectReport func(services.SearchAnalyticsServiceInterface) searchReport) {
+	if !api.requireSearchAnalytics(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	now := time.Now()
+
+	params := services.SearchReportParams{
+		From:  now.AddDate(0, 0, -searchReportDefaultDays),
+		To:    now,
+		Limit: parseIntParam(query.Get("limit"), searchReportDefaultLimit),
+	}
+	if params.Limit <= 0 {
+		params.Limit = searchReportDefaultLimit
+	}
+	if params.Limit > searchReportMaxLimit {
+		params.Limit = searchReportMaxLimit
+	}
+
+	var err error
+	if from := query.Get("from"); from != "" {
+		if params.From, err = parseReportTime(from); err != nil {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "parâmetro 'from' inválido",
+			})
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if params.To, err = parseReportTime(to); err != nil {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "parâmetro 'to' inválido",
+			})
+			return
+		}
+	}
+	if !params.From.Before(params.To) {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "'from' deve ser anterior a 'to'",
+		})
+		return
+	}
+
+	if catalog := query.Get("catalog"); catalog != "" {
+		if catalog != "catmat" && catalog != "catser" {
+			_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "catalog deve ser 'catmat' ou 'catser'",
+			})
+			return
+		}
+		params.Catalog = &catalog
+	}
+
+	stats, err := selectReport(api.SearchAnalytics)(r.Context(), params)
+	if err != nil {
+		logger.Log.Error("Erro ao gerar relatório de buscas", zap.Error(err), zap.String("path", r.URL.Path))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao gerar relatório",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.SearchReportResponse{
+		From: params.From.Format(time.RFC3339),
+		To:   params.To.Format(time.RFC3339),
+		Data: stats,
+	})
+}
+
+// parseReportTime accepts a date (YYYY-MM-DD) or a RFC3339 timestamp.
+func parseReportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (api *Api) requireSearchAnalytics(w http.ResponseWriter, r *http.Request) bool {
+	if api.SearchAnalytics == nil {
+		logger.Log.Error("SearchAnalytics não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de análise de buscas indisponível",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSearchAnalyticsAPI() (*Api, *mocks.MockCatalogImportService, *mocks.MockSearchAnalyticsService) {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	mockCatalog := new(mocks.MockCatalogImportService)
+	mockAnalytics := new(mocks.MockSearchAnalyticsService)
+	api := &Api{
+		Router:          chi.NewMux(),
+		UserService:     new(mocks.MockUserService),
+		CatalogService:  mockCatalog,
+		SearchAnalytics: mockAnalytics,
+		Roles:           adminRoles(),
+		Sessions:        scs.New(),
+		WsUpgrader:      defaultUpgrader(),
+	}
+	api.BindRoutes()
+	return api, mockCatalog, mockAnalytics
+}
+
+func TestHandleSearchCatmat_RecordsSearch(t *testing.T) {
+	api, mockCatalog, mockAnalytics := setupSearchAnalyticsAPI()
+	userID := uuid.New()
+
+	result := &services.SearchResult[services.CatmatSearchItem]{
+		Data:  []services.CatmatSearchItem{},
+		Total: 0,
+		Limit: 50,
+	}
+	mockCatalog.On("SearchCatmat", mock.Anything, mock.Anything).Return(result, nil)
+	mockAnalytics.On("RecordSearch", mock.MatchedBy(func(e services.SearchLogEntry) bool {
+		return e.Catalog == "catmat" &&
+			e.Query == "Papel A4" &&
+			e.UserID == userID &&
+			e.ResultCount == 0 &&
+			e.Filters["group_code"] == float64(75) &&
+			e.Filters["q"] == nil
+	})).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catmat/search?q=Papel+A4&group_code=75", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.CatmatSearchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	_, err := uuid.Parse(resp.SearchID)
+	assert.NoError(t, err)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestHandleSearchCatser_RecordsSearch(t *testing.T) {
+	api, mockCatalog, mockAnalytics := setupSearchAnalyticsAPI()
+
+	result := &services.SearchResult[services.CatserSearchItem]{
+		Data:  []services.CatserSearchItem{{ID: 1}},
+		Total: 1,
+		Limit: 50,
+	}
+	mockCatalog.On("SearchCatser", mock.Anything, mock.Anything).Return(result, nil)
+	mockAnalytics.On("RecordSearch", mock.MatchedBy(func(e services.SearchLogEntry) bool {
+		return e.Catalog == "catser" && e.Query == "limpeza" && e.ResultCount == 1
+	})).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catser/search?q=limpeza", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestHandleSearchClick_Success(t *testing.T) {
+	api, _, mockAnalytics := setupSearchAnalyticsAPI()
+	userID := uuid.New()
+	searchID := uuid.New()
+	position := int32(2)
+
+	mockAnalytics.On("RecordClick", mock.Anything, services.SearchClick{
+		SearchID: searchID,
+		UserID:   userID,
+		ItemCode: 150001,
+		Position: &position,
+	}).Return(nil)
+
+	body, _ := json.Marshal(dto.SearchClickReq{SearchID: searchID.String(), ItemCode: 150001, Position: &position})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/search/clicks", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestHandleSearchClick_InvalidSearchID(t *testing.T) {
+	api, _, mockAnalytics := setupSearchAnalyticsAPI()
+
+	body, _ := json.Marshal(dto.SearchClickReq{SearchID: "not-a-uuid", ItemCode: 150001})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/search/clicks", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockAnalytics.AssertNotCalled(t, "RecordClick", mock.Anything, mock.Anything)
+}
+
+func TestHandleTopSearchQueries_DateRange(t *testing.T) {
+	api, _, mockAnalytics := setupSearchAnalyticsAPI()
+
+	stats := []dto.SearchQueryStat{{Catalog: "catmat", Query: "papel a4", Searches: 10, SearchesWithClick: 4, ClickThroughRate: 0.4}}
+	mockAnalytics.On("TopQueries", mock.Anything, mock.MatchedBy(func(p services.SearchReportParams) bool {
+		return p.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			p.To.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) &&
+			p.Catalog != nil && *p.Catalog == "catmat" &&
+			p.Limit == 10
+	})).Return(stats, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/reports/top-queries?from=2025-01-01&to=2025-02-01&catalog=catmat&limit=10", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.SearchReportResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, 0.4, resp.Data[0].ClickThroughRate)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestHandleZeroResultSearchQueries_Defaults(t *testing.T) {
+	api, _, mockAnalytics := setupSearchAnalyticsAPI()
+
+	mockAnalytics.On("ZeroResultQueries", mock.Anything, mock.MatchedBy(func(p services.SearchReportParams) bool {
+		return p.Catalog == nil && p.Limit == searchReportDefaultLimit && p.From.Before(p.To)
+	})).Return([]dto.SearchQueryStat{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/reports/zero-results", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockAnalytics.AssertExpectations(t)
+}
+
+func TestHandleSlowestSearchQueries_InvalidRange(t *testing.T) {
+	api, _, mockAnalytics := setupSearchAnalyticsAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/reports/slowest?from=2025-02-01&to=2025-01-01", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockAnalytics.AssertNotCalled(t, "SlowestQueries", mock.Anything, mock.Anything)
+}
+
+func TestHandleSlowestSearchQueries_InvalidCatalog(t *testing.T) {
+	api, _, _ := setupSearchAnalyticsAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/reports/slowest?catalog=foo", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// handleListSynonyms godoc
+// @Summary Lista os grupos de sinônimos da busca
+// @Description Retorna o dicionário de sinônimos usado na expansão das buscas CATMAT/CATSER
+// @Tags search
+// @Produce json
+// @Success 200 {array} dto.SearchSynonymResponse "Grupos de sinônimos"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/synonyms [get]
+func (api *Api) handleListSynonyms(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSynonymService(w, r) {
+		return
+	}
+
+	groups, err := api.SynonymService.ListSynonyms(r.Context())
+	if err != nil {
+		logger.Log.Error("Erro ao listar sinônimos", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar sinônimos",
+		})
+		return
+	}
+
+	response := make([]dto.SearchSynonymResponse, len(groups))
+	for i := range groups {
+		response[i] = toSearchSynonymResponse(&groups[i])
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleCreateSynonym godoc
+// @Summary Cria um grupo de sinônimos
+// @Description Cadastra termos equivalentes (ex: "A4" e "papel sulfite"). Buscas afetadas deixam de usar o cache antigo.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param synonym body dto.SearchSynonymReq true "Termos equivalentes"
+// @Success 201 {object} dto.SearchSynonymResponse "Grupo criado"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/synonyms [post]
+func (api *Api) handleCreateSynonym(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSynonymService(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.SearchSynonymReq](r)
+	if err != nil {
+		logger.Log.Debug("Validação falhou para grupo de sinônimos",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	group, err := api.SynonymService.CreateSynonym(r.Context(), data.Terms)
+	if err != nil {
+		api.writeSynonymError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Grupo de sinônimos criado",
+		zap.Int64("id", group.ID),
+		zap.Strings("terms", group.Terms))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusCreated, toSearchSynonymResponse(group))
+}
+
+// handleUpdateSynonym godoc
+// @Summary Atualiza um grupo de sinônimos
+// @Description Substitui os termos de um grupo existente
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do grupo"
+// @Param synonym body dto.SearchSynonymReq true "Termos equivalentes"
+// @Success 200 {object} dto.SearchSynonymResponse "Grupo atualizado"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Grupo não encontrado"
+// @Failure 422 {object} map[string]interface{} "Erros de validação"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/synonyms/{id} [put]
+func (api *Api) handleUpdateSynonym(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSynonymService(w, r) {
+		return
+	}
+
+	id, ok := parseSynonymID(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.SearchSynonymReq](r)
+	if err != nil {
+		logger.Log.Debug("Validação falhou para grupo de sinônimos",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	group, err := api.SynonymService.UpdateSynonym(r.Context(), id, data.Terms)
+	if err != nil {
+		api.writeSynonymError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Grupo de sinônimos atualizado",
+		zap.Int64("id", group.ID),
+		zap.Strings("terms", group.Terms))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, toSearchSynonymResponse(group))
+}
+
+// handleDeleteSynonym godoc
+// @Summary Remove um grupo de sinônimos
+// @Tags search
+// @Produce json
+// @Param id path int true "ID do grupo"
+// @Success 204 "Grupo removido"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Grupo não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/search/synonyms/{id} [delete]
+func (api *Api) handleDeleteSynonym(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSynonymService(w, r) {
+		return
+	}
+
+	id, ok := parseSynonymID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.SynonymService.DeleteSynonym(r.Context(), id); err != nil {
+		api.writeSynonymError(w, r, err)
+		return
+	}
+
+	logger.Log.Info("Grupo de sinônimos removido", zap.Int64("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleExpandSynonyms godoc
+// @Summary Mostra a expansão de uma consulta pelos sinônimos
+// @Description Retorna o texto enviado ao websearch_to_tsquery após aplicar o dicionário de sinônimos
+// @Tags search
+// @Produce json
+// @Param q query string true "Termo de busca"
+// @Success 200 {object} dto.SearchSynonymExpandResponse "Consulta expandida"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Security BearerAuth
+// @Router /admin/search/synonyms/expand [get]
+func (api *Api) handleExpandSynonyms(w http.ResponseWriter, r *http.Request) {
+	if !api.requireSynonymService(w, r) {
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.SearchSynonymExpandResponse{
+		Query:    q,
+		Expanded: api.SynonymService.ExpandQuery(r.Context(), q),
+	})
+}
+
+func (api *Api) requireSynonymService(w http.ResponseWriter, r *http.Request) bool {
+	if api.SynonymService == nil {
+		logger.Log.Error("SynonymService não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de sinônimos indisponível",
+		})
+		return false
+	}
+	return true
+}
+
+func (api *Api) writeSynonymError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrSynonymNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "grupo de sinônimos não encontrado",
+		})
+	case errors.Is(err, services.ErrInvalidSynonymGroup):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "informe ao menos dois termos distintos",
+		})
+	default:
+		logger.Log.Error("Erro ao salvar sinônimos", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao salvar sinônimos",
+		})
+	}
+}
+
+func parseSynonymID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toSearchSynonymResponse(group *pgstore.SearchSynonym) dto.SearchSynonymResponse {
+	return dto.SearchSynonymResponse{
+		ID:        group.ID,
+		Terms:     group.Terms,
+		CreatedAt: group.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: group.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
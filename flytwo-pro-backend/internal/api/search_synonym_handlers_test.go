@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSynonymAPI() (*Api, *mocks.MockSearchSynonymService) {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	mockSynonyms := new(mocks.MockSearchSynonymService)
+	api := &Api{
+		Router:         chi.NewMux(),
+		UserService:    new(mocks.MockUserService),
+		CatalogService: new(mocks.MockCatalogImportService),
+		SynonymService: mockSynonyms,
+		Roles:          adminRoles(),
+		Sessions:       scs.New(),
+		WsUpgrader:     defaultUpgrader(),
+	}
+	api.BindRoutes()
+	return api, mockSynonyms
+}
+
+func TestHandleListSynonyms_Success(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	groups := []pgstore.SearchSynonym{
+		{ID: 1, Terms: []string{"a4", "papel sulfite"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	mockSynonyms.On("ListSynonyms", mock.Anything).Return(groups, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/synonyms", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp []dto.SearchSynonymResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp, 1)
+	assert.Equal(t, []string{"a4", "papel sulfite"}, resp[0].Terms)
+	mockSynonyms.AssertExpectations(t)
+}
+
+func TestHandleCreateSynonym_Success(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	terms := []string{"HD", "disco rígido"}
+	created := &pgstore.SearchSynonym{ID: 7, Terms: []string{"hd", "disco rígido"}}
+	mockSynonyms.On("CreateSynonym", mock.Anything, terms).Return(created, nil)
+
+	body, _ := json.Marshal(dto.SearchSynonymReq{Terms: terms})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/search/synonyms", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp dto.SearchSynonymResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(7), resp.ID)
+	mockSynonyms.AssertExpectations(t)
+}
+
+func TestHandleCreateSynonym_ValidationError(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	body, _ := json.Marshal(dto.SearchSynonymReq{Terms: []string{"a4"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/search/synonyms", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockSynonyms.AssertNotCalled(t, "CreateSynonym", mock.Anything, mock.Anything)
+}
+
+func TestHandleCreateSynonym_DuplicateTerms(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	terms := []string{"EPI", "epi"}
+	mockSynonyms.On("CreateSynonym", mock.Anything, terms).Return(nil, services.ErrInvalidSynonymGroup)
+
+	body, _ := json.Marshal(dto.SearchSynonymReq{Terms: terms})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/search/synonyms", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockSynonyms.AssertExpectations(t)
+}
+
+func TestHandleUpdateSynonym_NotFound(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	terms := []string{"a4", "papel sulfite"}
+	mockSynonyms.On("UpdateSynonym", mock.Anything, int64(99), terms).Return(nil, services.ErrSynonymNotFound)
+
+	body, _ := json.Marshal(dto.SearchSynonymReq{Terms: terms})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/search/synonyms/99", bytes.NewReader(body))
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockSynonyms.AssertExpectations(t)
+}
+
+func TestHandleDeleteSynonym_Success(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	mockSynonyms.On("DeleteSynonym", mock.Anything, int64(3)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/search/synonyms/3", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockSynonyms.AssertExpectations(t)
+}
+
+func TestHandleDeleteSynonym_InvalidID(t *testing.T) {
+	api, _ := setupSynonymAPI()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/search/synonyms/abc", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleExpandSynonyms(t *testing.T) {
+	api, mockSynonyms := setupSynonymAPI()
+
+	mockSynonyms.On("ExpandQuery", mock.Anything, "a4").Return(`a4 or "papel sulfite"`)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/synonyms/expand?q=a4", nil)
+	req.AddCookie(authCookie(api, uuid.New()))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.SearchSynonymExpandResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, `a4 or "papel sulfite"`, resp.Expanded)
+}
+
+func TestHandleListSynonyms_Unauthorized(t *testing.T) {
+	api, _ := setupSynonymAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/synonyms", nil)
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
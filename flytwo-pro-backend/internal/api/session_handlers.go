@@ -0,0 +1,259 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// trackSession records the metadata of the session that just logged in.
+// Failing to record it is logged but does not fail the login.
+func (api *Api) trackSession(r *http.Request, userID uuid.UUID) {
+	if api.UserSessions == nil {
+		return
+	}
+	token := api.Sessions.Token(r.Context())
+	if err := api.UserSessions.RecordLogin(r.Context(), token, userID, clientIP(r), r.UserAgent()); err != nil {
+		logger.Log.Error("Failed to record session",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+	}
+}
+
+// touchSession updates the last-seen time of the current session.
+func (api *Api) touchSession(r *http.Request) {
+	if api.UserSessions == nil {
+		return
+	}
+	if err := api.UserSessions.Touch(r.Context(), api.Sessions.Token(r.Context())); err != nil {
+		logger.Log.Warn("Failed to update session last seen", zap.Error(err))
+	}
+}
+
+// untrackSession forgets the metadata of the current session on logout.
+func (api *Api) untrackSession(r *http.Request) {
+	if api.UserSessions == nil {
+		return
+	}
+	token := api.Sessions.Token(r.Context())
+	if token == "" {
+		return
+	}
+	if err := api.UserSessions.EndSession(r.Context(), token); err != nil {
+		logger.Log.Warn("Failed to forget session", zap.Error(err))
+	}
+}
+
+// revokeOtherSessions ends every tracked web session of a user except the
+// one of this request, e.g. after the password changed.
+func (api *Api) revokeOtherSessions(r *http.Request, userID uuid.UUID) error {
+	if api.UserSessions == nil {
+		return nil
+	}
+	keepToken := ""
+	if api.Sessions.Exists(r.Context(), "AuthenticatedUserId") {
+		keepToken = api.Sessions.Token(r.Context())
+	}
+	_, err := api.UserSessions.RevokeOtherSessions(r.Context(), userID, keepToken)
+	return err
+}
+
+// handleListSessions godoc
+// @Summary List active sessions
+// @Description List the web sessions of the logged-in user with IP, user agent and last activity, most recent first. The session of this request has current set.
+// @Tags users
+// @Produce json
+// @Success 200 {array} dto.SessionResponse "Active sessions"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/sessions [get]
+func (api *Api) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	if !api.requireUserSessions(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	sessions, err := api.UserSessions.ListSessions(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Failed to list sessions",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	current := api.Sessions.Token(r.Context())
+	response := make([]dto.SessionResponse, len(sessions))
+	for i, s := range sessions {
+		response[i] = dto.SessionResponse{
+			ID:         s.ID.String(),
+			IP:         s.Ip,
+			UserAgent:  s.UserAgent,
+			CreatedAt:  s.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			LastSeenAt: s.LastSeenAt.UTC().Format("2006-01-02T15:04:05Z"),
+			ExpiresAt:  s.Expiry.UTC().Format("2006-01-02T15:04:05Z"),
+			Current:    current != "" && s.Token == current,
+		}
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleRevokeSession godoc
+// @Summary Revoke a session
+// @Description End one web session of the logged-in user, e.g. a browser left logged in elsewhere. Revoking the current session logs this client out.
+// @Tags users
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 204 "Session revoked"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/sessions/{id} [delete]
+func (api *Api) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	if !api.requireUserSessions(w, r) {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid id",
+		})
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	token, err := api.UserSessions.RevokeSession(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "session not found",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to revoke session",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	// Without Destroy, LoadAndSave would not clear the cookie of this client.
+	if token == api.Sessions.Token(r.Context()) {
+		if err := api.Sessions.Destroy(r.Context()); err != nil {
+			logger.Log.Error("Failed to destroy current session", zap.Error(err))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRevokeOtherSessions godoc
+// @Summary Revoke all other sessions
+// @Description End every web session of the logged-in user except the current one.
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Number of sessions revoked"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/sessions [delete]
+func (api *Api) handleRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	if !api.requireUserSessions(w, r) {
+		return
+	}
+
+	userID, _ := currentUserID(r)
+	revoked, err := api.UserSessions.RevokeOtherSessions(r.Context(), userID, api.Sessions.Token(r.Context()))
+	if err != nil {
+		logger.Log.Error("Failed to revoke other sessions",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "other sessions revoked",
+		"revoked": revoked,
+	})
+}
+
+// handleForceLogoutUser godoc
+// @Summary Encerra as sessões de um usuário
+// @Description Encerra todas as sessões web do usuário e revoga os refresh tokens do app. Access tokens já emitidos valem até expirar. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} map[string]interface{} "Sessões encerradas"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/sessions [delete]
+func (api *Api) handleForceLogoutUser(w http.ResponseWriter, r *http.Request) {
+	if !api.requireUserSessions(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	revoked, err := api.UserSessions.ForceLogout(r.Context(), userID, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao encerrar sessões do usuário", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao encerrar sessões",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "sessões encerradas",
+		"revoked": revoked,
+	})
+}
+
+func (api *Api) requireUserSessions(w http.ResponseWriter, r *http.Request) bool {
+	if api.UserSessions == nil {
+		logger.Log.Error("User session service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "session management not configured",
+		})
+		return false
+	}
+	return true
+}
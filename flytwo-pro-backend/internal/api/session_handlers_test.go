@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSessionAPI() (*Api, *mocks.MockUserService, *mocks.MockUserSessionService) {
+	api, mockUsers := setupTestAPI()
+	mockSessions := new(mocks.MockUserSessionService)
+	mockSessions.On("Touch", mock.Anything, mock.Anything).Return(nil).Maybe()
+	api.UserSessions = mockSessions
+	api.Roles = adminRoles()
+	return api, mockUsers, mockSessions
+}
+
+func getWithCookie(api *Api, path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleLoginUser_TracksSession(t *testing.T) {
+	api, mockUsers, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+	mockSessions.On("RecordLogin", mock.Anything, mock.AnythingOfType("string"), userID, "192.0.2.1", "etl-test/1.0").Return(nil)
+
+	body := `{"email":"test@example.com","password":"Test1234"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "etl-test/1.0")
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookie := sessionCookie(t, rec)
+	mockSessions.AssertCalled(t, "RecordLogin", mock.Anything, cookie.Value, userID, "192.0.2.1", "etl-test/1.0")
+}
+
+func TestHandleLogoutUser_ForgetsSession(t *testing.T) {
+	api, _, mockSessions := setupSessionAPI()
+	cookie := authCookie(api, uuid.New())
+	mockSessions.On("EndSession", mock.Anything, cookie.Value).Return(nil)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/logout", nil, cookie)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestHandleListSessions_MarksCurrent(t *testing.T) {
+	api, _, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockSessions.On("ListSessions", mock.Anything, userID).Return([]pgstore.ListUserSessionsRow{
+		{ID: uuid.New(), Token: cookie.Value, Ip: "192.0.2.1", UserAgent: "Firefox", CreatedAt: seen, LastSeenAt: seen, Expiry: seen.Add(24 * time.Hour)},
+		{ID: uuid.New(), Token: "other-token", Ip: "198.51.100.7", UserAgent: "curl", CreatedAt: seen, LastSeenAt: seen, Expiry: seen.Add(24 * time.Hour)},
+	}, nil)
+
+	rec := getWithCookie(api, "/api/v1/users/sessions", cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response []dto.SessionResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response, 2)
+	assert.True(t, response[0].Current)
+	assert.False(t, response[1].Current)
+	assert.Equal(t, "2026-03-02T12:00:00Z", response[0].ExpiresAt)
+	assert.NotContains(t, rec.Body.String(), "other-token", "tokens are never returned")
+	mockSessions.AssertCalled(t, "Touch", mock.Anything, cookie.Value)
+}
+
+func TestHandleRevokeSession_Current(t *testing.T) {
+	api, _, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	sessionID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockSessions.On("RevokeSession", mock.Anything, userID, sessionID).Return(cookie.Value, nil)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/sessions/"+sessionID.String(), nil, cookie)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", cookie).Code)
+}
+
+func TestHandleRevokeSession_NotFound(t *testing.T) {
+	api, _, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	sessionID := uuid.New()
+	mockSessions.On("RevokeSession", mock.Anything, userID, sessionID).Return("", services.ErrSessionNotFound)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/sessions/"+sessionID.String(), nil, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleRevokeOtherSessions(t *testing.T) {
+	api, mockUsers, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(nil, services.ErrUserNotFound)
+	cookie := authCookie(api, userID)
+	mockSessions.On("RevokeOtherSessions", mock.Anything, userID, cookie.Value).Return(int64(3), nil)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/users/sessions", nil, cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"revoked":3`)
+	assert.Equal(t, http.StatusNotFound, getWithCookie(api, "/api/v1/users/me", cookie).Code,
+		"the current session stays logged in")
+}
+
+func TestHandleForceLogoutUser(t *testing.T) {
+	api, _, mockSessions := setupSessionAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	missingID := uuid.New()
+	mockSessions.On("ForceLogout", mock.Anything, userID, adminID).Return(int64(2), nil)
+	mockSessions.On("ForceLogout", mock.Anything, missingID, adminID).Return(int64(0), services.ErrUserNotFound)
+	cookie := authCookie(api, adminID)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/sessions", nil, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"revoked":2`)
+
+	rec = sendJSON(t, api, http.MethodDelete, "/api/v1/admin/users/"+missingID.String()+"/sessions", nil, cookie)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/swaggo/http-swagger"
 	"github.com/swaggo/swag"
 )
@@ -40,6 +41,7 @@ func (api *Api) handleSwaggerDocV3(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to convert to openapi v3", http.StatusInternalServerError)
 		return
 	}
+	useBearerScheme(v3)
 
 	out, err := json.MarshalIndent(v3, "", "  ")
 	if err != nil {
@@ -50,3 +52,18 @@ func (api *Api) handleSwaggerDocV3(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_, _ = w.Write(out)
 }
+
+// useBearerScheme turns BearerAuth into an http bearer scheme. Swagger 2.0
+// can only describe it as an apiKey on the Authorization header.
+func useBearerScheme(doc *openapi3.T) {
+	if doc.Components == nil {
+		return
+	}
+	ref, ok := doc.Components.SecuritySchemes["BearerAuth"]
+	if !ok || ref.Value == nil {
+		return
+	}
+	scheme := openapi3.NewJWTSecurityScheme()
+	scheme.Description = ref.Value.Description
+	ref.Value = scheme
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "gobid/docs"
+)
+
+func TestHandleSwaggerDocV3_SecuritySchemes(t *testing.T) {
+	api, _ := setupTestAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		Components struct {
+			SecuritySchemes map[string]struct {
+				Type   string `json:"type"`
+				Scheme string `json:"scheme"`
+				In     string `json:"in"`
+				Name   string `json:"name"`
+			} `json:"securitySchemes"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+	bearer := doc.Components.SecuritySchemes["BearerAuth"]
+	assert.Equal(t, "http", bearer.Type)
+	assert.Equal(t, "bearer", bearer.Scheme)
+	apiKey := doc.Components.SecuritySchemes["ApiKeyAuth"]
+	assert.Equal(t, "apiKey", apiKey.Type)
+	assert.Equal(t, "header", apiKey.In)
+	assert.Equal(t, "X-API-Key", apiKey.Name)
+}
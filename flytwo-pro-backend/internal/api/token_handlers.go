@@ -0,0 +1,159 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// handleIssueToken godoc
+// @Summary Issue bearer tokens
+// @Description Exchange credentials (grant_type "password") or a refresh token (grant_type "refresh_token") for a short-lived access token and a new refresh token. Send the access token as "Authorization: Bearer <token>". Each refresh token works once; reusing one revokes every token issued from the same login. Accounts with two-factor authentication must also send "otp_code" (a TOTP or recovery code) with the password grant.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TokenReq true "Grant"
+// @Success 200 {object} dto.TokenResponse "Tokens issued"
+// @Failure 401 {object} map[string]interface{} "Invalid credentials or refresh token"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth/token [post]
+func (api *Api) handleIssueToken(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTokens(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.TokenReq](r)
+	if err != nil {
+		logger.Log.Debug("Validation failed for token request",
+			zap.Error(err),
+			zap.Any("validation_errors", problems))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	var pair *services.TokenPair
+	if data.GrantType == "password" {
+		if !api.allowLogin(w, r, data.Email) {
+			return
+		}
+
+		userID, authErr := api.UserService.AuthenticateUser(r.Context(), data.Email, data.Password)
+		api.recordLoginFailure(r, data.Email, authErr)
+		if authErr != nil {
+			if errors.Is(authErr, services.ErrInvalidCredentials) {
+				logger.Log.Warn("Token request failed - invalid credentials",
+					zap.String("email", data.Email))
+
+				_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+					"error": "invalid email or password",
+				})
+				return
+			}
+
+			logger.Log.Error("Unexpected error authenticating token request",
+				zap.Error(authErr),
+				zap.String("email", data.Email))
+
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+		if !api.verifyTokenSecondFactor(w, r, userID, data.Email, data.OTPCode) {
+			return
+		}
+		api.recordLoginSuccess(r, data.Email, userID)
+		pair, err = api.Tokens.IssueTokens(r.Context(), userID)
+	} else {
+		pair, err = api.Tokens.Refresh(r.Context(), data.RefreshToken)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "refresh token already used; log in again",
+			})
+		case errors.Is(err, services.ErrInvalidToken):
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "invalid or expired refresh token",
+			})
+		default:
+			logger.Log.Error("Failed to issue tokens", zap.Error(err))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+		}
+		return
+	}
+
+	now := time.Now()
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.TokenResponse{
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(pair.AccessExpiresAt.Sub(now).Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresIn: int64(pair.RefreshExpiresAt.Sub(now).Seconds()),
+	})
+}
+
+// handleRevokeToken godoc
+// @Summary Revoke a refresh token
+// @Description Log out a bearer token client: the refresh token and every token issued from the same login stop working. Access tokens already issued stay valid until they expire. Unknown tokens are accepted.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RevokeTokenReq true "Refresh token"
+// @Success 200 {object} map[string]interface{} "Token revoked"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth/revoke [post]
+func (api *Api) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTokens(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.RevokeTokenReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	if err := api.Tokens.Revoke(r.Context(), data.RefreshToken); err != nil {
+		logger.Log.Error("Failed to revoke refresh token", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "token revoked",
+	})
+}
+
+func (api *Api) requireTokens(w http.ResponseWriter, r *http.Request) bool {
+	if api.Tokens == nil {
+		logger.Log.Error("Token service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "token authentication not configured",
+		})
+		return false
+	}
+	return true
+}
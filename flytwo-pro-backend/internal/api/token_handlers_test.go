@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTokenAPI() (*Api, *mocks.MockUserService, *mocks.MockTokenService) {
+	api, mockUsers := setupTestAPI()
+	mockTokens := new(mocks.MockTokenService)
+	api.Tokens = mockTokens
+	return api, mockUsers, mockTokens
+}
+
+func postJSON(t *testing.T, api *Api, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func testTokenPair() *services.TokenPair {
+	now := time.Now()
+	return &services.TokenPair{
+		AccessToken:      "access",
+		AccessExpiresAt:  now.Add(15 * time.Minute),
+		RefreshToken:     "refresh",
+		RefreshExpiresAt: now.Add(30 * 24 * time.Hour),
+	}
+}
+
+func TestHandleIssueToken_PasswordGrant(t *testing.T) {
+	api, mockUsers, mockTokens := setupTokenAPI()
+	userID := uuid.New()
+
+	mockUsers.On("AuthenticateUser", mock.Anything, "user@example.com", "secret123").Return(userID, nil)
+	mockTokens.On("IssueTokens", mock.Anything, userID).Return(testTokenPair(), nil)
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{
+		GrantType: "password",
+		Email:     "user@example.com",
+		Password:  "secret123",
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.TokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "access", resp.AccessToken)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.InDelta(t, 900, resp.ExpiresIn, 2)
+	assert.Equal(t, "refresh", resp.RefreshToken)
+	assert.InDelta(t, 30*24*3600, resp.RefreshExpiresIn, 2)
+	mockUsers.AssertExpectations(t)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestHandleIssueToken_InvalidCredentials(t *testing.T) {
+	api, mockUsers, mockTokens := setupTokenAPI()
+	mockUsers.On("AuthenticateUser", mock.Anything, "user@example.com", "wrong").Return(uuid.Nil, services.ErrInvalidCredentials)
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{
+		GrantType: "password",
+		Email:     "user@example.com",
+		Password:  "wrong",
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockTokens.AssertNotCalled(t, "IssueTokens", mock.Anything, mock.Anything)
+}
+
+func TestHandleIssueToken_RefreshGrant(t *testing.T) {
+	api, _, mockTokens := setupTokenAPI()
+	mockTokens.On("Refresh", mock.Anything, "old-refresh").Return(testTokenPair(), nil)
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{
+		GrantType:    "refresh_token",
+		RefreshToken: "old-refresh",
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestHandleIssueToken_RefreshErrors(t *testing.T) {
+	for _, err := range []error{services.ErrRefreshTokenReused, services.ErrInvalidToken} {
+		api, _, mockTokens := setupTokenAPI()
+		mockTokens.On("Refresh", mock.Anything, "old-refresh").Return(nil, err)
+
+		rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{
+			GrantType:    "refresh_token",
+			RefreshToken: "old-refresh",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, err.Error())
+	}
+}
+
+func TestHandleIssueToken_ValidationError(t *testing.T) {
+	api, _, _ := setupTokenAPI()
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{GrantType: "password"})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{GrantType: "client_credentials"})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestHandleRevokeToken(t *testing.T) {
+	api, _, mockTokens := setupTokenAPI()
+	mockTokens.On("Revoke", mock.Anything, "refresh").Return(nil)
+
+	rec := postJSON(t, api, "/api/v1/auth/revoke", dto.RevokeTokenReq{RefreshToken: "refresh"})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestHandleGetCurrentUser_BearerToken(t *testing.T) {
+	api, mockUsers, mockTokens := setupTokenAPI()
+	userID := uuid.New()
+
+	mockTokens.On("ParseAccessToken", "access").Return(userID, nil)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{
+		ID:        userID,
+		UserName:  "mobile",
+		Email:     "mobile@example.com",
+		CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer access")
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.UserProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, userID.String(), resp.ID)
+}
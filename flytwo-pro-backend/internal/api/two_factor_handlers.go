@@ -0,0 +1,580 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// twoFactorLoginTTL is how long a password-checked login waits for the
+// second factor.
+const twoFactorLoginTTL = 5 * time.Minute
+
+// Session keys of a login waiting for the second factor.
+const (
+	pendingTwoFactorUserKey    = "PendingTwoFactorUserId"
+	pendingTwoFactorEmailKey   = "PendingTwoFactorEmail"
+	pendingTwoFactorExpiresKey = "PendingTwoFactorExpires"
+)
+
+// startTwoFactorLogin remembers a login whose password was right and asks
+// for the second factor. The session is not authenticated yet.
+func (api *Api) startTwoFactorLogin(w http.ResponseWriter, r *http.Request, userID uuid.UUID, email string) {
+	if err := api.beginTwoFactorLogin(r, userID, email); err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+
+	logger.Log.Info("Password accepted; waiting for second factor",
+		zap.String("user_id", userID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":             "two-factor code required",
+		"two_factor_required": true,
+	})
+}
+
+// beginTwoFactorLogin stores the pending login in a fresh session, for
+// POST /users/login/2fa to complete.
+func (api *Api) beginTwoFactorLogin(r *http.Request, userID uuid.UUID, email string) error {
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		logger.Log.Error("Failed to renew session token",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		return err
+	}
+
+	api.Sessions.Remove(r.Context(), "AuthenticatedUserId")
+	api.Sessions.Put(r.Context(), pendingTwoFactorUserKey, userID)
+	api.Sessions.Put(r.Context(), pendingTwoFactorEmailKey, email)
+	api.Sessions.Put(r.Context(), pendingTwoFactorExpiresKey, time.Now().Add(twoFactorLoginTTL).Unix())
+	return nil
+}
+
+// handleLoginTwoFactor godoc
+// @Summary Complete a two-factor login
+// @Description Second step of POST /users/login for accounts with two-factor authentication. Send a code from the authenticator app or a recovery code within 5 minutes of the password step.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeReq true "TOTP or recovery code"
+// @Success 200 {object} map[string]interface{} "Login successful"
+// @Failure 401 {object} map[string]interface{} "Invalid code or no pending login"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/login/2fa [post]
+func (api *Api) handleLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTwoFactor(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	userID, ok := api.Sessions.Get(r.Context(), pendingTwoFactorUserKey).(uuid.UUID)
+	expires := api.Sessions.GetInt64(r.Context(), pendingTwoFactorExpiresKey)
+	if !ok || time.Now().Unix() > expires {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "no pending login; log in with your password again",
+		})
+		return
+	}
+	email := api.Sessions.GetString(r.Context(), pendingTwoFactorEmailKey)
+
+	if !api.allowLogin(w, r, email) {
+		return
+	}
+
+	if err := api.TwoFactor.Verify(r.Context(), userID, data.Code); err != nil {
+		api.recordLoginFailure(r, email, err)
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			logger.Log.Warn("Login failed - invalid two-factor code",
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error": "invalid two-factor code",
+			})
+			return
+		}
+
+		logger.Log.Error("Failed to verify two-factor code",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+	api.recordLoginSuccess(r, email, userID)
+
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		logger.Log.Error("Failed to renew session token",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return
+	}
+	api.Sessions.Remove(r.Context(), pendingTwoFactorUserKey)
+	api.Sessions.Remove(r.Context(), pendingTwoFactorEmailKey)
+	api.Sessions.Remove(r.Context(), pendingTwoFactorExpiresKey)
+	api.Sessions.Put(r.Context(), "AuthenticatedUserId", userID)
+	api.trackSession(r, userID)
+
+	logger.Log.Info("User logged in with two-factor authentication",
+		zap.String("user_id", userID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "logged in successfully",
+		"user_id": userID,
+	})
+}
+
+// verifyTokenSecondFactor checks otp_code on password grants of accounts
+// with two-factor authentication and answers 401 when it is missing or
+// wrong.
+func (api *Api) verifyTokenSecondFactor(w http.ResponseWriter, r *http.Request, userID uuid.UUID, email, code string) bool {
+	if api.TwoFactor == nil {
+		return true
+	}
+
+	status, err := api.TwoFactor.Status(r.Context(), userID)
+	if err != nil {
+		logger.Log.Error("Failed to check two-factor status",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return false
+	}
+	if !status.Enabled {
+		return true
+	}
+
+	if code == "" {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error":               "two-factor code required",
+			"two_factor_required": true,
+		})
+		return false
+	}
+
+	if err := api.TwoFactor.Verify(r.Context(), userID, code); err != nil {
+		api.recordLoginFailure(r, email, err)
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			logger.Log.Warn("Token request failed - invalid two-factor code",
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+				"error":               "invalid two-factor code",
+				"two_factor_required": true,
+			})
+			return false
+		}
+
+		logger.Log.Error("Failed to verify two-factor code",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+		return false
+	}
+	return true
+}
+
+// handleGetTwoFactorStatus godoc
+// @Summary Get two-factor status
+// @Description Whether two-factor authentication is enabled for the logged-in user and whether one of their roles requires it.
+// @Tags users
+// @Produce json
+// @Success 200 {object} dto.TwoFactorStatusResponse "Two-factor status"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/2fa [get]
+func (api *Api) handleGetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := api.TwoFactor.Status(r.Context(), userID)
+	if err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.TwoFactorStatusResponse{
+		Enabled:  status.Enabled,
+		Required: status.Required,
+	})
+}
+
+// handleEnrollTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Create a TOTP secret for the logged-in user. Add it to an authenticator app (otpauth_uri can be shown as a QR code) and confirm with POST /users/2fa/confirm. Enrolling again before confirming replaces the secret.
+// @Tags users
+// @Produce json
+// @Success 200 {object} dto.TwoFactorEnrollResponse "TOTP secret"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Two-factor already enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/2fa/enroll [post]
+func (api *Api) handleEnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	enrollment, err := api.TwoFactor.Enroll(r.Context(), userID)
+	if err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.TwoFactorEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+}
+
+// handleConfirmTwoFactor godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication with the first code from the authenticator app. The response lists recovery codes, each usable once instead of a TOTP code; they are not shown again.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeReq true "TOTP code"
+// @Success 200 {object} dto.RecoveryCodesResponse "Two-factor enabled"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Not enrolled or already enabled"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/2fa/confirm [post]
+func (api *Api) handleConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	codes, err := api.TwoFactor.Confirm(r.Context(), userID, data.Code)
+	if err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// handleDisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off with a current TOTP or recovery code. Not allowed while a role of the user requires it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeReq true "TOTP or recovery code"
+// @Success 200 {object} map[string]interface{} "Two-factor disabled"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Not enabled or required by role"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/2fa/disable [post]
+func (api *Api) handleDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	status, err := api.TwoFactor.Status(r.Context(), userID)
+	if err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+	if status.Required {
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "two-factor authentication is required for your role",
+		})
+		return
+	}
+
+	if err := api.TwoFactor.Disable(r.Context(), userID, data.Code); err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "two-factor authentication disabled",
+	})
+}
+
+// handleRegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes with new ones, after checking a current TOTP or recovery code. The old codes stop working.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeReq true "TOTP or recovery code"
+// @Success 200 {object} dto.RecoveryCodesResponse "New recovery codes"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 409 {object} map[string]interface{} "Two-factor not enabled"
+// @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/2fa/recovery-codes [post]
+func (api *Api) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := api.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	codes, err := api.TwoFactor.RegenerateRecoveryCodes(r.Context(), userID, data.Code)
+	if err != nil {
+		api.twoFactorError(w, r, userID, err)
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// handleGetTwoFactorRequiredRoles godoc
+// @Summary Lista os papéis que exigem 2FA
+// @Description Usuários com um destes papéis precisam ativar a autenticação em dois fatores para usar as áreas restritas. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Success 200 {object} dto.TwoFactorRolesResponse "Papéis"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/2fa/required-roles [get]
+func (api *Api) handleGetTwoFactorRequiredRoles(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTwoFactor(w, r) {
+		return
+	}
+
+	roles, err := api.TwoFactor.RequiredRoles(r.Context())
+	if err != nil {
+		logger.Log.Error("Erro ao listar papéis que exigem 2FA", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar papéis",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.TwoFactorRolesResponse{Roles: roles})
+}
+
+// handleSetTwoFactorRequiredRoles godoc
+// @Summary Define os papéis que exigem 2FA
+// @Description Substitui a lista de papéis cujos usuários precisam ativar a autenticação em dois fatores. Enquanto não ativarem, esses usuários só acessam o próprio perfil e a ativação do 2FA. Exige o papel admin.
+// @Tags users-admin
+// @Accept json
+// @Produce json
+// @Param roles body dto.UpdateUserRolesReq true "Papéis"
+// @Success 200 {object} dto.TwoFactorRolesResponse "Papéis atualizados"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 422 {object} map[string]interface{} "Papel inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/2fa/required-roles [put]
+func (api *Api) handleSetTwoFactorRequiredRoles(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTwoFactor(w, r) {
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.UpdateUserRolesReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	roles, err := api.TwoFactor.SetRequiredRoles(r.Context(), data.Roles)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRole) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "papel inválido; use admin, manager, buyer ou viewer",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao atualizar papéis que exigem 2FA", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao atualizar papéis",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	logger.Log.Info("Papéis que exigem 2FA atualizados",
+		zap.Strings("roles", roles),
+		zap.String("by", adminID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.TwoFactorRolesResponse{Roles: roles})
+}
+
+// handleResetUserTwoFactor godoc
+// @Summary Remove o 2FA de um usuário
+// @Description Desativa a autenticação em dois fatores e apaga os códigos de recuperação do usuário, para quem perdeu o aparelho. Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} map[string]interface{} "2FA removido"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "2FA não ativado"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/2fa [delete]
+func (api *Api) handleResetUserTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if !api.requireTwoFactor(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	if err := api.TwoFactor.Reset(r.Context(), userID); err != nil {
+		if errors.Is(err, services.ErrTwoFactorNotEnabled) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "2FA não ativado para este usuário",
+			})
+			return
+		}
+
+		logger.Log.Error("Erro ao remover 2FA", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao remover 2FA",
+		})
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	logger.Log.Info("2FA removido pelo admin",
+		zap.String("user_id", userID.String()),
+		zap.String("by", adminID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "2FA removido",
+	})
+}
+
+// twoFactorUser returns the logged-in user for the self-service endpoints.
+func (api *Api) twoFactorUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if !api.requireTwoFactor(w, r) {
+		return uuid.Nil, false
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "authentication required",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// twoFactorError maps TwoFactorService errors of the self-service endpoints.
+func (api *Api) twoFactorError(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid two-factor code",
+		})
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "two-factor authentication already enabled",
+		})
+	case errors.Is(err, services.ErrTwoFactorNotEnrolled):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "start the enrollment first",
+		})
+	case errors.Is(err, services.ErrTwoFactorNotEnabled):
+		_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": "two-factor authentication not enabled",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "user not found",
+		})
+	default:
+		logger.Log.Error("Two-factor request failed",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected internal server error",
+		})
+	}
+}
+
+func (api *Api) requireTwoFactor(w http.ResponseWriter, r *http.Request) bool {
+	if api.TwoFactor == nil {
+		logger.Log.Error("Two-factor service not configured")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "two-factor authentication not configured",
+		})
+		return false
+	}
+	return true
+}
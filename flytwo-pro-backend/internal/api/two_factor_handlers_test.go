@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTwoFactorAPI() (*Api, *mocks.MockUserService, *mocks.MockTwoFactorService) {
+	api, mockUsers := setupTestAPI()
+	mockTwoFactor := new(mocks.MockTwoFactorService)
+	api.TwoFactor = mockTwoFactor
+	api.Roles = adminRoles()
+	return api, mockUsers, mockTwoFactor
+}
+
+// sendJSON sends body to path, with cookie when set.
+func sendJSON(t *testing.T, api *Api, method, path string, body any, cookie *http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	cookies := rec.Result().Cookies()
+	require.NotEmpty(t, cookies, "response sets the session cookie")
+	return cookies[0]
+}
+
+func TestHandleLoginUser_TwoFactorLogin(t *testing.T) {
+	api, mockUsers, mockTwoFactor := setupTwoFactorAPI()
+	userID := uuid.New()
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(nil, services.ErrUserNotFound)
+	mockTwoFactor.On("Status", mock.Anything, userID).Return(services.TwoFactorStatus{Enabled: true}, nil)
+	mockTwoFactor.On("Verify", mock.Anything, userID, "000000").Return(services.ErrInvalidTwoFactorCode)
+	mockTwoFactor.On("Verify", mock.Anything, userID, "123456").Return(nil)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/login",
+		dto.LoginUserReq{Email: "test@example.com", Password: "Test1234"}, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"two_factor_required":true`)
+	cookie := sessionCookie(t, rec)
+
+	// the password alone does not authenticate the session
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.AddCookie(cookie)
+	me := httptest.NewRecorder()
+	api.Router.ServeHTTP(me, req)
+	assert.Equal(t, http.StatusUnauthorized, me.Code)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/login/2fa", dto.TwoFactorCodeReq{Code: "000000"}, cookie)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/login/2fa", dto.TwoFactorCodeReq{Code: "123456"}, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookie = sessionCookie(t, rec)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.AddCookie(cookie)
+	me = httptest.NewRecorder()
+	api.Router.ServeHTTP(me, req)
+	assert.Equal(t, http.StatusNotFound, me.Code, "authenticated; the mock has no profile")
+}
+
+func TestHandleLoginTwoFactor_NoPendingLogin(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+
+	rec := postJSON(t, api, "/api/v1/users/login/2fa", dto.TwoFactorCodeReq{Code: "123456"})
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockTwoFactor.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleIssueToken_RequiresOTPCode(t *testing.T) {
+	api, mockUsers, mockTwoFactor := setupTwoFactorAPI()
+	mockTokens := new(mocks.MockTokenService)
+	api.Tokens = mockTokens
+	userID := uuid.New()
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+	mockTwoFactor.On("Status", mock.Anything, userID).Return(services.TwoFactorStatus{Enabled: true}, nil)
+	mockTwoFactor.On("Verify", mock.Anything, userID, "123456").Return(nil)
+	mockTokens.On("IssueTokens", mock.Anything, userID).Return(testTokenPair(), nil)
+
+	rec := postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{GrantType: "password", Email: "test@example.com", Password: "Test1234"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"two_factor_required":true`)
+	mockTokens.AssertNotCalled(t, "IssueTokens", mock.Anything, mock.Anything)
+
+	rec = postJSON(t, api, "/api/v1/auth/token", dto.TokenReq{GrantType: "password", Email: "test@example.com", Password: "Test1234", OTPCode: "123456"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequireTwoFactorEnrollment_BlocksRequiredRole(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	userID := uuid.New()
+	mockTwoFactor.On("Status", mock.Anything, userID).Return(services.TwoFactorStatus{Required: true}, nil)
+	mockTwoFactor.On("Enroll", mock.Anything, userID).Return(&services.TwoFactorEnrollment{
+		Secret: "JBSWY3DPEHPK3PXP",
+		URI:    "otpauth://totp/FlyTwo%20Pro:test@example.com?secret=JBSWY3DPEHPK3PXP",
+	}, nil)
+	cookie := authCookie(api, userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/saved-searches", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "two-factor authentication required")
+
+	// enrollment stays reachable
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/2fa/enroll", nil, cookie)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "otpauth://totp/")
+}
+
+func TestHandleConfirmTwoFactor(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	userID := uuid.New()
+	mockTwoFactor.On("Confirm", mock.Anything, userID, "123456").Return([]string{"AAAAA-BBBBB", "CCCCC-DDDDD"}, nil)
+	mockTwoFactor.On("Confirm", mock.Anything, userID, "000000").Return(nil, services.ErrInvalidTwoFactorCode)
+	cookie := authCookie(api, userID)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/2fa/confirm", dto.TwoFactorCodeReq{Code: "123456"}, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response dto.RecoveryCodesResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, []string{"AAAAA-BBBBB", "CCCCC-DDDDD"}, response.RecoveryCodes)
+
+	rec = sendJSON(t, api, http.MethodPost, "/api/v1/users/2fa/confirm", dto.TwoFactorCodeReq{Code: "000000"}, cookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleEnrollTwoFactor_AlreadyEnabled(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	userID := uuid.New()
+	mockTwoFactor.On("Enroll", mock.Anything, userID).Return(nil, services.ErrTwoFactorAlreadyEnabled)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/2fa/enroll", nil, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandleDisableTwoFactor_RequiredByRole(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	userID := uuid.New()
+	mockTwoFactor.On("Status", mock.Anything, userID).Return(services.TwoFactorStatus{Enabled: true, Required: true}, nil)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/2fa/disable", dto.TwoFactorCodeReq{Code: "123456"}, authCookie(api, userID))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockTwoFactor.AssertNotCalled(t, "Disable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleSetTwoFactorRequiredRoles(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	adminID := uuid.New()
+	mockTwoFactor.On("Status", mock.Anything, adminID).Return(services.TwoFactorStatus{Enabled: true, Required: true}, nil)
+	mockTwoFactor.On("SetRequiredRoles", mock.Anything, []string{"admin"}).Return([]string{"admin"}, nil)
+	mockTwoFactor.On("SetRequiredRoles", mock.Anything, []string{"owner"}).Return(nil, services.ErrInvalidRole)
+	cookie := authCookie(api, adminID)
+
+	rec := sendJSON(t, api, http.MethodPut, "/api/v1/admin/2fa/required-roles", dto.UpdateUserRolesReq{Roles: []string{"admin"}}, cookie)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodPut, "/api/v1/admin/2fa/required-roles", dto.UpdateUserRolesReq{Roles: []string{"owner"}}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestHandleResetUserTwoFactor_NotEnabled(t *testing.T) {
+	api, _, mockTwoFactor := setupTwoFactorAPI()
+	adminID := uuid.New()
+	userID := uuid.New()
+	mockTwoFactor.On("Status", mock.Anything, adminID).Return(services.TwoFactorStatus{}, nil)
+	mockTwoFactor.On("Reset", mock.Anything, userID).Return(services.ErrTwoFactorNotEnabled)
+
+	rec := sendJSON(t, api, http.MethodDelete, "/api/v1/admin/users/"+userID.String()+"/2fa", nil, authCookie(api, adminID))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"gobid/internal/dto"
+	"gobid/internal/jsonutils"
+	"gobid/internal/logger"
+	"gobid/internal/services"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// handleListUsers godoc
+// @Summary Lista os usuários e seus papéis
+// @Description Usuários em ordem de cadastro, com os papéis de cada um (admin, manager, buyer, viewer). Exige o papel admin.
+// @Tags users-admin
+// @Produce json
+// @Param limit query int false "Limite de resultados (padrão 50, máximo 100)"
+// @Param offset query int false "Offset para paginação (padrão 0)"
+// @Success 200 {object} dto.AdminUserListResponse "Usuários"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (api *Api) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	if !api.requireRoleService(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := parseIntParam(query.Get("limit"), 50)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseIntParam(query.Get("offset"), 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := api.Roles.ListUsers(r.Context(), limit, offset)
+	if err != nil {
+		logger.Log.Error("Erro ao listar usuários", zap.Error(err))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "falha ao listar usuários",
+		})
+		return
+	}
+
+	response := dto.AdminUserListResponse{
+		Data:   make([]dto.AdminUserResponse, len(users)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, u := range users {
+		response.Data[i] = dto.AdminUserResponse{
+			ID:              u.ID.String(),
+			UserName:        u.UserName,
+			Email:           u.Email,
+			EmailVerified:   u.EmailVerifiedAt.Valid,
+			EmailVerifiedAt: formatOptionalTime(u.EmailVerifiedAt.Time),
+			CreatedAt:       u.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			Roles:           u.Roles,
+		}
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// handleUpdateUserRoles godoc
+// @Summary Define os papéis de um usuário
+// @Description Substitui todos os papéis do usuário. Uma lista vazia remove o acesso às áreas restritas. O último admin não pode perder o papel admin. Exige o papel admin.
+// @Tags users-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param roles body dto.UpdateUserRolesReq true "Papéis"
+// @Success 200 {object} dto.UserRolesResponse "Papéis atualizados"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 401 {object} map[string]interface{} "Não autenticado"
+// @Failure 403 {object} map[string]interface{} "Sem permissão"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 409 {object} map[string]interface{} "Último admin"
+// @Failure 422 {object} map[string]interface{} "Papel inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno"
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles [put]
+func (api *Api) handleUpdateUserRoles(w http.ResponseWriter, r *http.Request) {
+	if !api.requireRoleService(w, r) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "id inválido",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.UpdateUserRolesReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	roles, err := api.Roles.SetUserRoles(r.Context(), userID, data.Roles)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole):
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "papel inválido; use admin, manager, buyer ou viewer",
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "usuário não encontrado",
+			})
+		case errors.Is(err, services.ErrLastAdmin):
+			_ = jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "o último admin não pode perder o papel admin",
+			})
+		default:
+			logger.Log.Error("Erro ao atualizar papéis", zap.Error(err))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "falha ao atualizar papéis",
+			})
+		}
+		return
+	}
+
+	adminID, _ := currentUserID(r)
+	logger.Log.Info("Papéis do usuário atualizados",
+		zap.String("user_id", userID.String()),
+		zap.Strings("roles", roles),
+		zap.String("by", adminID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, dto.UserRolesResponse{
+		ID:    userID.String(),
+		Roles: roles,
+	})
+}
+
+func (api *Api) requireRoleService(w http.ResponseWriter, r *http.Request) bool {
+	if api.Roles == nil {
+		logger.Log.Error("RoleService não configurado")
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "serviço de papéis indisponível",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupUserAdminAPI() (*Api, *mocks.MockUserService, *mocks.MockRoleService) {
+	if err := logger.InitLogger(true); err != nil {
+		panic(err)
+	}
+
+	mockUsers := new(mocks.MockUserService)
+	mockRoles := new(mocks.MockRoleService)
+	api := &Api{
+		Router:         chi.NewMux(),
+		UserService:    mockUsers,
+		CatalogService: new(mocks.MockCatalogImportService),
+		SynonymService: new(mocks.MockSearchSynonymService),
+		Roles:          mockRoles,
+		Sessions:       scs.New(),
+		WsUpgrader:     defaultUpgrader(),
+	}
+	api.BindRoutes()
+	return api, mockUsers, mockRoles
+}
+
+func TestRequireRole_BuyerCannotImport(t *testing.T) {
+	api, _, mockRoles := setupUserAdminAPI()
+	userID := uuid.New()
+	mockRoles.On("GetUserRoles", mock.Anything, userID).Return([]string{"buyer"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/catmat/import", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.JSONEq(t, `{"error":"insufficient permissions"}`, rec.Body.String())
+}
+
+func TestRequireRole_ManagerReachesSynonymsButNotCache(t *testing.T) {
+	api, _, mockRoles := setupUserAdminAPI()
+	userID := uuid.New()
+	mockRoles.On("GetUserRoles", mock.Anything, userID).Return([]string{"manager"}, nil)
+	api.SynonymService.(*mocks.MockSearchSynonymService).On("ListSynonyms", mock.Anything).Return([]pgstore.SearchSynonym{}, nil)
+	cookie := authCookie(api, userID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/search/synonyms/", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRequireRole_Unauthenticated(t *testing.T) {
+	api, _, mockRoles := setupUserAdminAPI()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/", nil)
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockRoles.AssertNotCalled(t, "GetUserRoles", mock.Anything, mock.Anything)
+}
+
+func TestHandleListUsers(t *testing.T) {
+	api, _, mockRoles := setupUserAdminAPI()
+	adminID := uuid.New()
+	mockRoles.On("GetUserRoles", mock.Anything, adminID).Return([]string{"admin"}, nil)
+
+	userID := uuid.New()
+	mockRoles.On("ListUsers", mock.Anything, int32(10), int32(20)).Return([]pgstore.ListUsersWithRolesRow{
+		{
+			ID:        userID,
+			UserName:  "comprador",
+			Email:     "comprador@example.com",
+			CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			Roles:     []string{"buyer", "viewer"},
+		},
+	}, int64(21), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/?limit=10&offset=20", nil)
+	req.AddCookie(authCookie(api, adminID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.AdminUserListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(21), resp.Total)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, dto.AdminUserResponse{
+		ID:        userID.String(),
+		UserName:  "comprador",
+		Email:     "comprador@example.com",
+		CreatedAt: "2025-03-01T12:00:00Z",
+		Roles:     []string{"buyer", "viewer"},
+	}, resp.Data[0])
+}
+
+func TestHandleUpdateUserRoles(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"success", nil, http.StatusOK},
+		{"invalid role", services.ErrInvalidRole, http.StatusUnprocessableEntity},
+		{"user not found", services.ErrUserNotFound, http.StatusNotFound},
+		{"last admin", services.ErrLastAdmin, http.StatusConflict},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, _, mockRoles := setupUserAdminAPI()
+			adminID := uuid.New()
+			userID := uuid.New()
+			mockRoles.On("GetUserRoles", mock.Anything, adminID).Return([]string{"admin"}, nil)
+			if tc.err != nil {
+				mockRoles.On("SetUserRoles", mock.Anything, userID, []string{"manager"}).Return(nil, tc.err)
+			} else {
+				mockRoles.On("SetUserRoles", mock.Anything, userID, []string{"manager"}).Return([]string{"manager"}, nil)
+			}
+
+			body, _ := json.Marshal(dto.UpdateUserRolesReq{Roles: []string{"manager"}})
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/users/"+userID.String()+"/roles", bytes.NewReader(body))
+			req.AddCookie(authCookie(api, adminID))
+			rec := httptest.NewRecorder()
+			api.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			mockRoles.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleGetCurrentUser_IncludesRoles(t *testing.T) {
+	api, mockUsers, mockRoles := setupUserAdminAPI()
+	userID := uuid.New()
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{
+		ID:        userID,
+		UserName:  "gestor",
+		Email:     "gestor@example.com",
+		CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}, nil)
+	mockRoles.On("GetUserRoles", mock.Anything, userID).Return([]string{"buyer", "manager"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	req.AddCookie(authCookie(api, userID))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.UserProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"buyer", "manager"}, resp.Roles)
+}
+
+func TestHandleSignupUser_BootstrapsAdmin(t *testing.T) {
+	api, mockUsers, mockRoles := setupUserAdminAPI()
+	mockUsers.On("CreateUser", mock.Anything, "primeiro", "admin@example.com", "password123").Return(uuid.New(), nil)
+	mockRoles.On("BootstrapAdmin", mock.Anything).Return(true, nil)
+
+	body, _ := json.Marshal(dto.CreateUserReq{UserName: "primeiro", Email: "admin@example.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/signup", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockRoles.AssertExpectations(t)
+}
@@ -6,7 +6,9 @@ import (
 	"gobid/internal/jsonutils"
 	"gobid/internal/logger"
 	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -74,6 +76,21 @@ func (api *Api) handleSignupUser(w http.ResponseWriter, r *http.Request) {
 		zap.String("user_id", id.String()),
 		zap.String("email", data.Email))
 
+	if api.Roles != nil {
+		if _, err := api.Roles.BootstrapAdmin(r.Context()); err != nil {
+			logger.Log.Error("Failed to bootstrap admin after signup", zap.Error(err))
+		}
+	}
+
+	if api.EmailVerifier != nil {
+		// The account exists either way; the user can ask for a new link.
+		if _, err := api.EmailVerifier.SendVerification(r.Context(), id); err != nil {
+			logger.Log.Error("Failed to send verification email after signup",
+				zap.Error(err),
+				zap.String("user_id", id.String()))
+		}
+	}
+
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
 		"user_id": id,
 	})
@@ -81,7 +98,7 @@ func (api *Api) handleSignupUser(w http.ResponseWriter, r *http.Request) {
 
 // handleLoginUser godoc
 // @Summary User login
-// @Description Authenticate a user and create a session
+// @Description Authenticate a user and create a session. When the account has two-factor authentication, the response carries "two_factor_required": true and the login is completed by POST /users/login/2fa.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -89,6 +106,7 @@ func (api *Api) handleSignupUser(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]interface{} "Login successful"
 // @Failure 401 {object} map[string]interface{} "Invalid credentials"
 // @Failure 422 {object} map[string]interface{} "Validation errors"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /users/login [post]
 func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
@@ -109,7 +127,12 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 
 	logger.Log.Info("User login attempt", zap.String("email", data.Email))
 
+	if !api.allowLogin(w, r, data.Email) {
+		return
+	}
+
 	id, err := api.UserService.AuthenticateUser(r.Context(), data.Email, data.Password)
+	api.recordLoginFailure(r, data.Email, err)
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidCredentials) {
 			logger.Log.Warn("Login failed - invalid credentials",
@@ -131,6 +154,26 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if api.TwoFactor != nil {
+		status, err := api.TwoFactor.Status(r.Context(), id)
+		if err != nil {
+			logger.Log.Error("Failed to check two-factor status",
+				zap.Error(err),
+				zap.String("user_id", id.String()))
+
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected internal server error",
+			})
+			return
+		}
+		if status.Enabled {
+			api.startTwoFactorLogin(w, r, id, data.Email)
+			return
+		}
+	}
+
+	api.recordLoginSuccess(r, data.Email, id)
+
 	err = api.Sessions.RenewToken(r.Context())
 	if err != nil {
 		logger.Log.Error("Failed to renew session token",
@@ -144,6 +187,7 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	api.Sessions.Put(r.Context(), "AuthenticatedUserId", id)
+	api.trackSession(r, id)
 
 	logger.Log.Info("User logged in successfully",
 		zap.String("user_id", id.String()),
@@ -163,10 +207,11 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Success 200 {object} map[string]interface{} "Logout successful"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
-// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /users/logout [post]
 func (api *Api) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
 	userId := api.Sessions.Get(r.Context(), "AuthenticatedUserId")
+	api.untrackSession(r)
 
 	err := api.Sessions.RenewToken(r.Context())
 	if err != nil {
@@ -198,12 +243,11 @@ func (api *Api) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
 // @Failure 401 {object} map[string]interface{} "User not authenticated"
 // @Failure 404 {object} map[string]interface{} "User not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
-// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /users/me [get]
 func (api *Api) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from session
-	userIDValue := api.Sessions.Get(r.Context(), "AuthenticatedUserId")
-	if userIDValue == nil {
+	userID, ok := currentUserID(r)
+	if !ok {
 		logger.Log.Warn("Unauthenticated access attempt to /me endpoint",
 			zap.String("remote_addr", r.RemoteAddr))
 
@@ -213,18 +257,6 @@ func (api *Api) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Type assert the user ID
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		logger.Log.Error("Invalid user ID type in session",
-			zap.Any("user_id_value", userIDValue))
-
-		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-			"error": "internal server error",
-		})
-		return
-	}
-
 	// Log the request
 	logger.Log.Info("Fetching user profile",
 		zap.String("user_id", userID.String()))
@@ -252,12 +284,16 @@ func (api *Api) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Prepare response
-	response := dto.UserProfileResponse{
-		ID:        user.ID.String(),
-		UserName:  user.UserName,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	response, err := api.userProfile(r, user)
+	if err != nil {
+		logger.Log.Error("Failed to fetch user roles or two-factor status",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "internal server error",
+		})
+		return
 	}
 
 	logger.Log.Info("User profile fetched successfully",
@@ -266,3 +302,291 @@ func (api *Api) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
 }
+
+// handleUpdateCurrentUser godoc
+// @Summary Update current user profile
+// @Description Change the user name and/or email of the logged-in user; omitted fields are kept. Changing the email requires current_password, since password reset links go to the new address; wrong passwords count as failed logins. A new email must be verified again: a verification link is sent to it, and links sent to the old address stop working.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateUserReq true "Fields to change"
+// @Success 200 {object} dto.UserProfileResponse "Updated profile"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 422 {object} map[string]interface{} "Validation errors, missing or wrong current password, or email/username already exists"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me [patch]
+func (api *Api) handleUpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "authentication required",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.UpdateUserReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+	if data.UserName == nil && data.Email == nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "nothing to update; send user_name and/or email",
+		})
+		return
+	}
+	if data.Email != nil && !api.checkEmailChangePassword(w, r, userID, *data.Email, data.CurrentPassword) {
+		return
+	}
+
+	user, emailChanged, err := api.UserService.UpdateUser(r.Context(), userID, data.UserName, data.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDuplicatedEmailOrUsername):
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "email or username already exists",
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+		default:
+			logger.Log.Error("Failed to update user profile",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "internal server error",
+			})
+		}
+		return
+	}
+
+	logger.Log.Info("User profile updated",
+		zap.String("user_id", userID.String()),
+		zap.Bool("email_changed", emailChanged))
+
+	if emailChanged && api.EmailVerifier != nil {
+		// The change is saved either way; the user can ask for a new link.
+		if _, err := api.EmailVerifier.SendVerification(r.Context(), userID); err != nil {
+			logger.Log.Error("Failed to send verification email after email change",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+		}
+	}
+
+	response, err := api.userProfile(r, user)
+	if err != nil {
+		logger.Log.Error("Failed to fetch user roles or two-factor status",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "internal server error",
+		})
+		return
+	}
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, response)
+}
+
+// checkEmailChangePassword requires the current password when email differs
+// from the user's address, so a stolen session cannot move the account to
+// an address the attacker controls. It writes the error response and
+// returns false when the change may not proceed.
+func (api *Api) checkEmailChangePassword(w http.ResponseWriter, r *http.Request, userID uuid.UUID, email, password string) bool {
+	user, err := api.UserService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "user not found",
+			})
+			return false
+		}
+		logger.Log.Error("Failed to get user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "internal server error",
+		})
+		return false
+	}
+	if strings.TrimSpace(email) == user.Email {
+		return true
+	}
+
+	if password == "" {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "Validation failed",
+			"fields": map[string]string{
+				"current_password": "is required to change the email",
+			},
+		})
+		return false
+	}
+	if !api.allowLogin(w, r, user.Email) {
+		return false
+	}
+	if id, err := api.UserService.AuthenticateUser(r.Context(), user.Email, password); err != nil || id != userID {
+		if err == nil {
+			err = services.ErrInvalidCredentials
+		}
+		api.recordLoginFailure(r, user.Email, err)
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"error": "Validation failed",
+				"fields": map[string]string{
+					"current_password": "is incorrect",
+				},
+			})
+			return false
+		}
+		logger.Log.Error("Failed to check current password",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "internal server error",
+		})
+		return false
+	}
+	return true
+}
+
+// handleChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the logged-in user, given the current one. The session token is rotated, every other web session is ended and the refresh tokens of the mobile app are revoked. API keys are kept so scripts keep working; revoke them at DELETE /users/api-keys/{id}. Wrong current passwords count as failed logins.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.ChangePasswordReq true "Current and new password"
+// @Success 200 {object} map[string]interface{} "Password changed"
+// @Failure 401 {object} map[string]interface{} "User not authenticated"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 422 {object} map[string]interface{} "Validation errors or wrong current password"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /users/me/password [post]
+func (api *Api) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"error": "authentication required",
+		})
+		return
+	}
+
+	data, problems, err := jsonutils.DecodeValidJson[dto.ChangePasswordReq](r)
+	if err != nil {
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":  "Validation failed",
+			"fields": problems,
+		})
+		return
+	}
+
+	user, err := api.UserService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		api.writeChangePasswordError(w, r, userID, err)
+		return
+	}
+	if !api.allowLogin(w, r, user.Email) {
+		return
+	}
+
+	if err := api.UserService.ChangePassword(r.Context(), userID, data.CurrentPassword, data.NewPassword); err != nil {
+		api.recordLoginFailure(r, user.Email, err)
+		api.writeChangePasswordError(w, r, userID, err)
+		return
+	}
+
+	// A new token for this client; the old one may have leaked with the old
+	// password. Bearer clients have no session to rotate.
+	if api.Sessions.Exists(r.Context(), "AuthenticatedUserId") {
+		api.untrackSession(r)
+		if err := api.Sessions.RenewToken(r.Context()); err != nil {
+			logger.Log.Error("Failed to renew session token after password change",
+				zap.Error(err),
+				zap.String("user_id", userID.String()))
+			_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "internal server error",
+			})
+			return
+		}
+		api.trackSession(r, userID)
+	}
+
+	// The renewed session is tracked under its new token, so it survives.
+	if err := api.revokeOtherSessions(r, userID); err != nil {
+		logger.Log.Error("Failed to end other sessions after password change",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+	}
+
+	logger.Log.Info("Password changed",
+		zap.String("user_id", userID.String()))
+
+	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "password changed",
+	})
+}
+
+func (api *Api) writeChangePasswordError(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
+		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": "Validation failed",
+			"fields": map[string]string{
+				"current_password": "is incorrect",
+			},
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		_ = jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "user not found",
+		})
+	default:
+		logger.Log.Error("Failed to change password",
+			zap.Error(err),
+			zap.String("user_id", userID.String()))
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "internal server error",
+		})
+	}
+}
+
+// userProfile builds the profile of user with its roles and two-factor
+// status.
+func (api *Api) userProfile(r *http.Request, user *pgstore.User) (dto.UserProfileResponse, error) {
+	roles := []string{}
+	if api.Roles != nil {
+		var err error
+		roles, err = api.Roles.GetUserRoles(r.Context(), user.ID)
+		if err != nil {
+			return dto.UserProfileResponse{}, err
+		}
+	}
+
+	twoFactorEnabled := false
+	if api.TwoFactor != nil {
+		status, err := api.TwoFactor.Status(r.Context(), user.ID)
+		if err != nil {
+			return dto.UserProfileResponse{}, err
+		}
+		twoFactorEnabled = status.Enabled
+	}
+
+	return dto.UserProfileResponse{
+		ID:               user.ID.String(),
+		UserName:         user.UserName,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerifiedAt.Valid,
+		EmailVerifiedAt:  formatOptionalTime(user.EmailVerifiedAt.Time),
+		TwoFactorEnabled: twoFactorEnabled,
+		CreatedAt:        user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		Roles:            roles,
+	}, nil
+}
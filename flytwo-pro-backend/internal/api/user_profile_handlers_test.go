@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/json"
+	"gobid/internal/dto"
+	"gobid/internal/mocks"
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestHandleUpdateCurrentUser_Success(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	api.Roles = adminRoles()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("UpdateUser", mock.Anything, userID, strPtr("newname"), (*string)(nil)).Return(&pgstore.User{
+		ID:        userID,
+		UserName:  "newname",
+		Email:     "test@example.com",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, false, nil)
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{UserName: strPtr("newname")}, cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response dto.UserProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "newname", response.UserName)
+	assert.Equal(t, "test@example.com", response.Email)
+	assert.Equal(t, []string{"admin"}, response.Roles)
+	mockUsers.AssertExpectations(t)
+}
+
+func TestHandleUpdateCurrentUser_EmailChangeSendsVerification(t *testing.T) {
+	api, mockUsers, mockVerifier := setupEmailVerificationAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "old@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "old@example.com", "Test1234").Return(userID, nil)
+	mockUsers.On("UpdateUser", mock.Anything, userID, (*string)(nil), strPtr("new@example.com")).Return(&pgstore.User{
+		ID:       userID,
+		UserName: "testuser",
+		Email:    "new@example.com",
+	}, true, nil)
+	mockVerifier.On("SendVerification", mock.Anything, userID).Return(time.Duration(0), nil)
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{Email: strPtr("new@example.com"), CurrentPassword: "Test1234"}, cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response dto.UserProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.EmailVerified)
+	mockVerifier.AssertExpectations(t)
+}
+
+func TestHandleUpdateCurrentUser_Duplicate(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "test@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Test1234").Return(userID, nil)
+	mockUsers.On("UpdateUser", mock.Anything, userID, (*string)(nil), strPtr("taken@example.com")).
+		Return(nil, false, services.ErrDuplicatedEmailOrUsername)
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{Email: strPtr("taken@example.com"), CurrentPassword: "Test1234"}, cookie)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "email or username already exists")
+}
+
+func TestHandleUpdateCurrentUser_EmailChangeRequiresPassword(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	mockProtection := new(mocks.MockLoginProtectionService)
+	api.LoginProtection = mockProtection
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, UserName: "testuser", Email: "test@example.com"}, nil)
+	mockUsers.On("AuthenticateUser", mock.Anything, "test@example.com", "Wrong1234").Return(uuid.Nil, services.ErrInvalidCredentials)
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(time.Duration(0))
+	mockProtection.On("RecordFailure", mock.Anything, "192.0.2.1", "test@example.com").Return()
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{Email: strPtr("attacker@example.com")}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "current_password")
+
+	rec = sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{
+		Email:           strPtr("attacker@example.com"),
+		CurrentPassword: "Wrong1234",
+	}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "is incorrect")
+
+	mockUsers.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockProtection.AssertNumberOfCalls(t, "RecordFailure", 1)
+}
+
+func TestHandleUpdateCurrentUser_SameEmailNeedsNoPassword(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	user := &pgstore.User{ID: userID, UserName: "newname", Email: "test@example.com"}
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+	mockUsers.On("UpdateUser", mock.Anything, userID, strPtr("newname"), strPtr("test@example.com")).Return(user, false, nil)
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{
+		UserName: strPtr("newname"),
+		Email:    strPtr("test@example.com"),
+	}, cookie)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUsers.AssertNotCalled(t, "AuthenticateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleUpdateCurrentUser_RejectsEmptyAndInvalidBodies(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	cookie := authCookie(api, uuid.New())
+
+	rec := sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", map[string]any{}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = sendJSON(t, api, http.MethodPatch, "/api/v1/users/me", dto.UpdateUserReq{Email: strPtr("not-an-email")}, cookie)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	mockUsers.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleChangePassword_WrongCurrentPassword(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	mockProtection := new(mocks.MockLoginProtectionService)
+	api.LoginProtection = mockProtection
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "test@example.com"}, nil)
+	mockProtection.On("Check", mock.Anything, "192.0.2.1", "test@example.com").Return(time.Duration(0))
+	mockProtection.On("RecordFailure", mock.Anything, "192.0.2.1", "test@example.com").Return()
+	mockUsers.On("ChangePassword", mock.Anything, userID, "Wrong1234", "NewPass1234").Return(services.ErrInvalidCredentials)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/me/password", dto.ChangePasswordReq{
+		CurrentPassword: "Wrong1234",
+		NewPassword:     "NewPass1234",
+	}, cookie)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "current_password")
+	mockProtection.AssertExpectations(t)
+}
+
+func TestHandleChangePassword_SameAsCurrent(t *testing.T) {
+	api, mockUsers := setupTestAPI()
+	cookie := authCookie(api, uuid.New())
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/me/password", dto.ChangePasswordReq{
+		CurrentPassword: "Test1234",
+		NewPassword:     "Test1234",
+	}, cookie)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	mockUsers.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleChangePassword_RotatesSessionAndEndsOthers(t *testing.T) {
+	api, mockUsers, mockSessions := setupSessionAPI()
+	userID := uuid.New()
+	cookie := authCookie(api, userID)
+	mockUsers.On("GetUserByID", mock.Anything, userID).Return(&pgstore.User{ID: userID, Email: "test@example.com"}, nil)
+	mockUsers.On("ChangePassword", mock.Anything, userID, "Test1234", "NewPass1234").Return(nil)
+	mockSessions.On("EndSession", mock.Anything, cookie.Value).Return(nil)
+	mockSessions.On("RecordLogin", mock.Anything, mock.Anything, userID, "192.0.2.1", mock.Anything).Return(nil)
+	var kept string
+	mockSessions.On("RevokeOtherSessions", mock.Anything, userID, mock.Anything).
+		Run(func(args mock.Arguments) { kept = args.String(2) }).
+		Return(int64(2), nil)
+
+	rec := sendJSON(t, api, http.MethodPost, "/api/v1/users/me/password", dto.ChangePasswordReq{
+		CurrentPassword: "Test1234",
+		NewPassword:     "NewPass1234",
+	}, cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	renewed := sessionCookie(t, rec)
+	assert.NotEqual(t, cookie.Value, renewed.Value)
+
+	// The other sessions are ended through user_session; the renewed one is kept.
+	assert.Equal(t, renewed.Value, kept)
+	assert.Equal(t, http.StatusUnauthorized, getWithCookie(api, "/api/v1/users/me", cookie).Code)
+	assert.Equal(t, http.StatusOK, getWithCookie(api, "/api/v1/users/me", renewed).Code)
+	mockSessions.AssertExpectations(t)
+}
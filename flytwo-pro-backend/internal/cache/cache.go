@@ -2,36 +2,90 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
-	"github.com/redis/go-redis/v9"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
-// Config holds cache settings for L1 (ristretto) and L2 (redis).
+// Config holds cache settings for L1 (ristretto) and L2 (Redis or Postgres).
 type Config struct {
 	L1NumCounters int64
 	L1MaxCost     int64
 	L1BufferItems int64
 	TTL           time.Duration
+	// StaleTTL is how long an expired entry is still served by GetOrLoad
+	// while a single goroutine refreshes it. Zero disables it.
+	StaleTTL time.Duration
+	// PrefixTTLs overrides TTL for keys starting with a prefix; the longest
+	// matching prefix wins (see ParsePrefixTTLs).
+	PrefixTTLs map[string]time.Duration
+	// NegativeTTL is used instead for empty values (see EmptyChecker). Zero
+	// caches them like any other value.
+	NegativeTTL time.Duration
 
+	// Codec serializes values (default JSONCodec). Values whose encoded size
+	// reaches CompressMinBytes (default 1024) are compressed with
+	// Compression: CompressionNone (default), CompressionGzip or
+	// CompressionZstd. The L1 cost of an entry is its stored size.
+	Codec            Codec
+	Compression      string
+	CompressMinBytes int
+
+	// Store is the L2 tier. When nil and EnableL2 is set, a RedisStore is
+	// built from the Redis fields.
+	Store         Store
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
 	EnableL2      bool
 	Logger        *zap.Logger
+
+	// InvalidationChannel is the channel used to evict L1 entries on the
+	// other instances when Store is a Broadcaster. Defaults to
+	// DefaultInvalidationChannel.
+	InvalidationChannel string
+
+	// HealthCheckInterval is how often L2 is pinged to detect that it went
+	// down or came back (default 5s). L2Timeout bounds every Redis command
+	// (default 500ms). After BreakerThreshold consecutive command errors
+	// (default 5) L2 is bypassed for BreakerCooldown (default 30s).
+	HealthCheckInterval time.Duration
+	L2Timeout           time.Duration
+	BreakerThreshold    int
+	BreakerCooldown     time.Duration
 }
 
 type Cache struct {
-	l1  *ristretto.Cache
-	l2  *redis.Client
-	ttl time.Duration
-	log *zap.Logger
+	l1          *ristretto.Cache
+	l2          Store
+	bus         Broadcaster
+	ttl         time.Duration
+	staleTTL    time.Duration
+	prefixTTLs  []prefixTTL
+	negativeTTL time.Duration
+	codec       Codec
+	compression byte
+	compressMin int
+	log         *zap.Logger
+	loads       singleflight.Group
+
+	gens   generations
+	health l2Health
+	stats  counters
+
+	instanceID string
+	channel    string
+	cancel     context.CancelFunc
+	bg         sync.WaitGroup
 }
 
-// New builds a cache with L1 (ristretto) and optional L2 (redis).
+// New builds a cache with L1 (ristretto) and an optional L2 store. An
+// unreachable L2 does not fail: it is enabled as soon as it answers.
 func New(cfg Config) (*Cache, error) {
 	log := cfg.Logger
 	if log == nil {
@@ -42,22 +96,30 @@ func New(cfg Config) (*Cache, error) {
 		NumCounters: cfg.L1NumCounters,
 		MaxCost:     cfg.L1MaxCost,
 		BufferItems: cfg.L1BufferItems,
+		Metrics:     true,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	var l2 *redis.Client
-	if cfg.EnableL2 && cfg.RedisAddr != "" {
-		l2 = redis.NewClient(&redis.Options{
+	compression, err := compressionID(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	codec := cfg.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	l2 := cfg.Store
+	if l2 == nil && cfg.EnableL2 && cfg.RedisAddr != "" {
+		l2 = NewRedisStore(RedisConfig{
 			Addr:     cfg.RedisAddr,
 			Password: cfg.RedisPassword,
 			DB:       cfg.RedisDB,
+			Timeout:  cfg.L2Timeout,
+			Logger:   log,
 		})
-		if err := l2.Ping(context.Background()).Err(); err != nil {
-			log.Warn("redis ping failed, disabling L2 cache", zap.Error(err))
-			l2 = nil
-		}
 	}
 
 	ttl := cfg.TTL
@@ -65,72 +127,229 @@ func New(cfg Config) (*Cache, error) {
 		ttl = 5 * time.Minute
 	}
 
-	return &Cache{
-		l1:  l1,
-		l2:  l2,
-		ttl: ttl,
-		log: log,
-	}, nil
+	channel := cfg.InvalidationChannel
+	if channel == "" {
+		channel = DefaultInvalidationChannel
+	}
+
+	c := &Cache{
+		l1:          l1,
+		l2:          l2,
+		ttl:         ttl,
+		staleTTL:    max(cfg.StaleTTL, 0),
+		prefixTTLs:  sortPrefixTTLs(cfg.PrefixTTLs),
+		negativeTTL: max(cfg.NegativeTTL, 0),
+		codec:       codec,
+		compression: compression,
+		compressMin: withDefault(cfg.CompressMinBytes, defaultCompressMinBytes),
+		log:         log,
+		health: l2Health{
+			threshold: withDefault(cfg.BreakerThreshold, defaultBreakerThreshold),
+			cooldown:  withDefault(cfg.BreakerCooldown, defaultBreakerCooldown),
+		},
+		instanceID: uuid.NewString(),
+		channel:    channel,
+	}
+
+	if l2 == nil {
+		return c, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	pingCtx, cancelPing := context.WithTimeout(ctx, 2*time.Second)
+	err = l2.Ping(pingCtx)
+	cancelPing()
+	c.health.setReachable(err, time.Now())
+	if err != nil {
+		log.Warn("L2 cache ping failed, disabled until it recovers", zap.String("backend", l2.Name()), zap.Error(err))
+	}
+
+	if bus, ok := l2.(Broadcaster); ok {
+		c.bus = bus
+		c.bg.Add(1)
+		go func() {
+			defer c.bg.Done()
+			bus.Subscribe(ctx, channel, c.apply, c.resetL1)
+		}()
+	}
+	c.bg.Add(1)
+	go c.healthLoop(ctx, withDefault(cfg.HealthCheckInterval, defaultHealthCheckInterval))
+
+	return c, nil
+}
+
+func withDefault[T int | time.Duration](v, def T) T {
+	if v <= 0 {
+		return def
+	}
+	return v
 }
 
-// Get tries L1 then L2. On hit, unmarshals JSON into dest.
+// Get tries L1 then L2. On a fresh hit, decodes the value into dest. Stale
+// entries kept for GetOrLoad count as misses.
 func (c *Cache) Get(ctx context.Context, key string, dest any) (bool, error) {
 	if c == nil {
 		return false, nil
 	}
 
+	v, fresh, found, err := c.read(ctx, key)
+	if err != nil || !found || !fresh {
+		return false, err
+	}
+	return true, c.decode(v, dest)
+}
+
+// Set writes to L2 (if enabled) and L1. Other instances drop their L1 copy of
+// the key so the next read picks up the new value from L2.
+func (c *Cache) Set(ctx context.Context, key string, value any) error {
+	if c == nil {
+		return nil
+	}
+	v, err := c.encode(value)
+	if err != nil {
+		return err
+	}
+
+	c.write(ctx, key, v, c.ttlFor(key, value))
+	return nil
+}
+
+// storedValue is an encoded value and the flags needed to decode it.
+type storedValue struct {
+	flags byte
+	body  []byte
+}
+
+// read returns the value stored under key, trying L1 then L2, and counts
+// the lookup.
+func (c *Cache) read(ctx context.Context, key string) (v storedValue, fresh, found bool, err error) {
+	counters := c.stats.prefix(key)
+	defer func() {
+		switch {
+		case !found:
+			counters.misses.Add(1)
+		case !fresh:
+			counters.staleHits.Add(1)
+		}
+	}()
+
 	if val, ok := c.l1.Get(key); ok {
 		if b, ok := val.([]byte); ok {
-			c.log.Debug("cache hit L1", zap.String("key", key))
-			return true, json.Unmarshal(b, dest)
+			if freshUntil, flags, body, ok := decodeEntry(b); ok {
+				counters.l1Hits.Add(1)
+				c.log.Debug("cache hit L1", zap.String("key", key))
+				return storedValue{flags, body}, time.Now().Before(freshUntil), true, nil
+			}
 		}
 	}
+	c.stats.l1Misses.Add(1)
 
-	if c.l2 == nil {
-		return false, nil
+	if !c.l2Available() {
+		return storedValue{}, false, false, nil
 	}
 
-	b, err := c.l2.Get(ctx, key).Bytes()
+	b, err := c.l2.Get(ctx, key)
+	c.recordL2(err)
 	if err != nil {
-		if err == redis.Nil {
-			return false, nil
+		c.stats.l2Misses.Add(1)
+		if errors.Is(err, ErrNotFound) {
+			return storedValue{}, false, false, nil
 		}
-		return false, err
+		return storedValue{}, false, false, err
 	}
 
-	_ = c.l1.SetWithTTL(key, b, int64(len(b)), c.ttl)
+	freshUntil, flags, body, ok := decodeEntry(b)
+	if !ok {
+		c.stats.l2Misses.Add(1)
+		c.stats.serialization.Add(1)
+		return storedValue{}, false, false, nil
+	}
+
+	if remaining := time.Until(freshUntil) + c.staleTTL; remaining > 0 {
+		_ = c.l1.SetWithTTL(key, b, int64(len(b)), remaining)
+	}
+	counters.l2Hits.Add(1)
 	c.log.Debug("cache hit L2", zap.String("key", key))
-	return true, json.Unmarshal(b, dest)
+	return storedValue{flags, body}, time.Now().Before(freshUntil), true, nil
 }
 
-// Set writes to L2 (if enabled) and L1.
-func (c *Cache) Set(ctx context.Context, key string, value any) error {
-	if c == nil {
-		return nil
-	}
-	b, err := json.Marshal(value)
+// encode serializes a value, counting failures.
+func (c *Cache) encode(value any) (storedValue, error) {
+	flags, body, err := c.encodeValue(value)
 	if err != nil {
+		c.stats.serialization.Add(1)
+		return storedValue{}, err
+	}
+	return storedValue{flags, body}, nil
+}
+
+// decode deserializes a stored value, counting failures.
+func (c *Cache) decode(v storedValue, dest any) error {
+	if err := decodeValue(v.flags, v.body, dest); err != nil {
+		c.stats.serialization.Add(1)
 		return err
 	}
+	return nil
+}
 
-	if c.l2 != nil {
-		if err := c.l2.Set(ctx, key, b, c.ttl).Err(); err != nil {
-			c.log.Warn("failed to set redis cache", zap.Error(err))
+// write stores an encoded value in L2 (if enabled) and L1. Entries are kept
+// for their TTL plus the stale window.
+func (c *Cache) write(ctx context.Context, key string, v storedValue, ttl time.Duration) {
+	b := encodeEntry(time.Now().Add(ttl), v.flags, v.body)
+	keep := ttl + c.staleTTL
+
+	if c.l2Available() {
+		err := c.l2.Set(ctx, key, b, keep)
+		c.recordL2(err)
+		if err != nil {
+			c.log.Warn("failed to set L2 cache", zap.String("backend", c.l2.Name()), zap.Error(err))
 		} else {
 			c.log.Debug("cache set L2", zap.String("key", key))
 		}
 	}
 
-	c.l1.SetWithTTL(key, b, int64(len(b)), c.ttl)
+	c.l1.SetWithTTL(key, b, int64(len(b)), keep)
 	c.log.Debug("cache set L1", zap.String("key", key), zap.Int("bytes", len(b)))
+	c.publish(ctx, invalidation{Keys: []string{key}})
+}
+
+// Delete removes keys from L2 and L1 on every instance.
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if c == nil || len(keys) == 0 {
+		return nil
+	}
+
+	for _, key := range keys {
+		c.l1.Del(key)
+	}
+
+	if c.l2 == nil {
+		return nil
+	}
+	if !c.l2Available() {
+		return ErrL2Unavailable
+	}
+	_, err := c.l2.Delete(ctx, keys...)
+	c.recordL2(err)
+	if err != nil {
+		return err
+	}
+	c.publish(ctx, invalidation{Keys: keys})
 	return nil
 }
 
-// Close releases Redis resources (L1 has no Close).
+// Close stops the background goroutines and closes the L2 store (L1 has no
+// Close).
 func (c *Cache) Close() {
 	if c == nil {
 		return
 	}
+	if c.cancel != nil {
+		c.cancel()
+		c.bg.Wait()
+	}
 	if c.l2 != nil {
 		_ = c.l2.Close()
 	}
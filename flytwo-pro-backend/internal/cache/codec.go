@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec serializes cached values. Every entry records the codec that wrote
+// it, so changing GOBID_CACHE_CODEC does not break entries already stored.
+type Codec interface {
+	ID() byte
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec stores values as JSON.
+type JSONCodec struct{}
+
+func (JSONCodec) ID() byte                           { return 1 }
+func (JSONCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// MsgpackCodec stores values as MessagePack, honoring the json struct tags so
+// DTOs need no extra annotations.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ID() byte { return 2 }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+var codecs = map[byte]Codec{
+	JSONCodec{}.ID():    JSONCodec{},
+	MsgpackCodec{}.ID(): MsgpackCodec{},
+}
+
+// CodecByName returns the codec for "json" (or empty) and "msgpack".
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "msgpack":
+		return MsgpackCodec{}, nil
+	default:
+		return nil, fmt.Errorf("cache: unknown codec %q", name)
+	}
+}
+
+// Compression algorithms applied to encoded values above
+// Config.CompressMinBytes.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+const (
+	compressionNone byte = 0
+	compressionGzip byte = 1
+	compressionZstd byte = 2
+)
+
+func compressionID(name string) (byte, error) {
+	switch name {
+	case "", CompressionNone:
+		return compressionNone, nil
+	case CompressionGzip:
+		return compressionGzip, nil
+	case CompressionZstd:
+		return compressionZstd, nil
+	default:
+		return 0, fmt.Errorf("cache: unknown compression %q", name)
+	}
+}
+
+// zstd encoders and decoders are expensive to build and safe for concurrent
+// EncodeAll/DecodeAll calls, so one of each is shared.
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func zstdCodecs() (*zstd.Encoder, *zstd.Decoder) {
+	zstdOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		zstdDecoder, _ = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder
+}
+
+func compress(id byte, data []byte) ([]byte, error) {
+	switch id {
+	case compressionGzip:
+		var buf bytes.Buffer
+		w, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionZstd:
+		enc, _ := zstdCodecs()
+		return enc.EncodeAll(data, nil), nil
+	default:
+		return data, nil
+	}
+}
+
+func decompress(id byte, data []byte) ([]byte, error) {
+	switch id {
+	case compressionNone:
+		return data, nil
+	case compressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case compressionZstd:
+		_, dec := zstdCodecs()
+		return dec.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("cache: unknown compression id %d", id)
+	}
+}
+
+// encodeValue serializes v and compresses it when it is large enough. The
+// returned flags record the codec (low nibble) and compression (high nibble).
+func (c *Cache) encodeValue(v any) (flags byte, body []byte, err error) {
+	body, err = c.codec.Marshal(v)
+	if err != nil {
+		return 0, nil, err
+	}
+	flags = c.codec.ID()
+	if c.compression != compressionNone && len(body) >= c.compressMin {
+		compressed, err := compress(c.compression, body)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(compressed) < len(body) {
+			body = compressed
+			flags |= c.compression << 4
+		}
+	}
+	return flags, body, nil
+}
+
+func decodeValue(flags byte, body []byte, dest any) error {
+	codec, ok := codecs[flags&0x0f]
+	if !ok {
+		return fmt.Errorf("cache: unknown codec id %d", flags&0x0f)
+	}
+	data, err := decompress(flags>>4, body)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, dest)
+}
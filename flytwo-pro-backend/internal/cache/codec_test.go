@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page struct {
+	Data  []item `json:"data"`
+	Total int64  `json:"total"`
+}
+
+type item struct {
+	Code        int32   `json:"item_code"`
+	Description string  `json:"item_description"`
+	NcmCode     *string `json:"ncm_code,omitempty"`
+	Rank        float32 `json:"rank"`
+}
+
+func (p *page) IsEmpty() bool { return len(p.Data) == 0 }
+
+func bigPage() *page {
+	ncm := "48025610"
+	p := &page{Total: 200}
+	for i := range 200 {
+		p.Data = append(p.Data, item{Code: int32(i), Description: strings.Repeat("PAPEL A4 SULFITE ", 4), NcmCode: &ncm, Rank: 0.5})
+	}
+	return p
+}
+
+func TestDecodeEntry_OldLayoutIsMiss(t *testing.T) {
+	_, _, _, ok := decodeEntry([]byte(`{"total":1}`))
+	assert.False(t, ok)
+	_, _, _, ok = decodeEntry([]byte{1, 0, 0, 0, 0, 0, 0, 0, 0, '1'})
+	assert.False(t, ok)
+
+	fresh := time.Now().Add(time.Minute)
+	got, flags, body, ok := decodeEntry(encodeEntry(fresh, 0x21, []byte(`"x"`)))
+	assert.True(t, ok)
+	assert.True(t, fresh.Equal(got))
+	assert.Equal(t, byte(0x21), flags)
+	assert.Equal(t, `"x"`, string(body))
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	for _, codecName := range []string{"json", "msgpack"} {
+		for _, compression := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+			t.Run(codecName+"/"+compression, func(t *testing.T) {
+				ctx := context.Background()
+				codec, err := CodecByName(codecName)
+				require.NoError(t, err)
+				c, err := New(Config{
+					L1NumCounters: 1000,
+					L1MaxCost:     1 << 20,
+					L1BufferItems: 64,
+					Codec:         codec,
+					Compression:   compression,
+				})
+				require.NoError(t, err)
+				t.Cleanup(c.Close)
+
+				want := bigPage()
+				require.NoError(t, c.Set(ctx, "catmat:q=papel", want))
+				c.l1.Wait()
+
+				var got page
+				ok, err := c.Get(ctx, "catmat:q=papel", &got)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, *want, got)
+			})
+		}
+	}
+}
+
+func TestEncodeValue_CompressesAboveThreshold(t *testing.T) {
+	c, err := New(Config{
+		L1NumCounters:    1000,
+		L1MaxCost:        1 << 20,
+		L1BufferItems:    64,
+		Codec:            MsgpackCodec{},
+		Compression:      CompressionZstd,
+		CompressMinBytes: 512,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	jsonBody, _ := JSONCodec{}.Marshal(bigPage())
+
+	flags, body, err := c.encodeValue(bigPage())
+	require.NoError(t, err)
+	assert.Equal(t, MsgpackCodec{}.ID()|compressionZstd<<4, flags)
+	assert.Less(t, len(body), len(jsonBody)/4)
+
+	flags, _, err = c.encodeValue(&page{})
+	require.NoError(t, err)
+	assert.Equal(t, MsgpackCodec{}.ID(), flags, "small values are not compressed")
+}
+
+func TestCodec_ReadsEntriesWrittenWithAnotherCodec(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	writer, err := New(Config{L1NumCounters: 1000, L1MaxCost: 1 << 20, L1BufferItems: 64,
+		RedisAddr: mr.Addr(), EnableL2: true, Codec: MsgpackCodec{}, Compression: CompressionGzip, CompressMinBytes: 1})
+	require.NoError(t, err)
+	t.Cleanup(writer.Close)
+	reader := newTestCache(t, mr.Addr())
+
+	require.NoError(t, writer.Set(ctx, "catalog:stats", bigPage()))
+
+	var got page
+	ok, err := reader.Get(ctx, "catalog:stats", &got)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, got.Data, 200)
+}
+
+func TestCodecByName_Unknown(t *testing.T) {
+	_, err := CodecByName("xml")
+	assert.Error(t, err)
+
+	_, err = New(Config{L1NumCounters: 10, L1MaxCost: 10, L1BufferItems: 64, Compression: "lz4"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// entryVersion prefixes every stored value. Values written with another
+// layout (including plain JSON from older versions) are read as misses.
+const entryVersion byte = 2
+
+const entryHeaderSize = 10
+
+// encodeEntry stores the codec/compression flags and the instant the value
+// stops being fresh in front of the body. Between freshUntil and the storage
+// TTL the entry is stale: Get ignores it and GetOrLoad may serve it while
+// refreshing.
+func encodeEntry(freshUntil time.Time, flags byte, body []byte) []byte {
+	b := make([]byte, entryHeaderSize+len(body))
+	b[0] = entryVersion
+	b[1] = flags
+	binary.BigEndian.PutUint64(b[2:entryHeaderSize], uint64(freshUntil.UnixNano()))
+	copy(b[entryHeaderSize:], body)
+	return b
+}
+
+func decodeEntry(b []byte) (freshUntil time.Time, flags byte, body []byte, ok bool) {
+	if len(b) < entryHeaderSize || b[0] != entryVersion {
+		return time.Time{}, 0, nil, false
+	}
+	nanos := int64(binary.BigEndian.Uint64(b[2:entryHeaderSize]))
+	return time.Unix(0, nanos), b[1], b[entryHeaderSize:], true
+}
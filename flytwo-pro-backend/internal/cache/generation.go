@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// generationRefresh is how long an L1 copy of a namespace generation is
+// trusted before it is read again from L2. Bumps made by other instances
+// arrive through pub/sub; this interval bounds staleness if a message is lost.
+const generationRefresh = 5 * time.Second
+
+const generationKeyPrefix = "cache:gen:"
+
+type generationEntry struct {
+	value     int64
+	fetchedAt time.Time
+}
+
+// generations keeps the L1 copy of every namespace generation. It is a plain
+// map instead of ristretto because an evicted generation would fall back to
+// an older value and resurrect stale entries.
+type generations struct {
+	mu      sync.Mutex
+	entries map[string]generationEntry
+}
+
+func (g *generations) get(namespace string) (generationEntry, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e, ok := g.entries[namespace]
+	return e, ok
+}
+
+func (g *generations) set(namespace string, value int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]generationEntry)
+	}
+	g.entries[namespace] = generationEntry{value: value, fetchedAt: time.Now()}
+}
+
+func (g *generations) incr(namespace string) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]generationEntry)
+	}
+	e := g.entries[namespace]
+	e.value++
+	e.fetchedAt = time.Now()
+	g.entries[namespace] = e
+	return e.value
+}
+
+// advance stores a generation received from another instance, ignoring
+// values older than the local copy.
+func (g *generations) advance(namespace string, value int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]generationEntry)
+	}
+	if e, ok := g.entries[namespace]; ok && e.value > value {
+		return
+	}
+	g.entries[namespace] = generationEntry{value: value, fetchedAt: time.Now()}
+}
+
+// refresh stores a generation read from L2 and restarts its refresh
+// interval. A higher local copy wins: it holds bumps whose Incr failed, and
+// going back to the L2 value would resurrect the entries they hid.
+func (g *generations) refresh(namespace string, value int64) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]generationEntry)
+	}
+	if e, ok := g.entries[namespace]; ok && e.value > value {
+		value = e.value
+	}
+	g.entries[namespace] = generationEntry{value: value, fetchedAt: time.Now()}
+	return value
+}
+
+// bump stores the result of an L2 Incr, moving past the local copy when a
+// failed Incr left it ahead of L2.
+func (g *generations) bump(namespace string, value int64) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]generationEntry)
+	}
+	if e, ok := g.entries[namespace]; ok && e.value >= value {
+		value = e.value + 1
+	}
+	g.entries[namespace] = generationEntry{value: value, fetchedAt: time.Now()}
+	return value
+}
+
+// expire forces every generation to be read again from L2.
+func (g *generations) expire() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for ns, e := range g.entries {
+		e.fetchedAt = time.Time{}
+		g.entries[ns] = e
+	}
+}
+
+// Generation returns the current generation of a namespace. Callers bake it
+// into their keys so that Invalidate makes every older entry unreachable.
+// When L2 is unavailable the last known L1 value is used.
+func (c *Cache) Generation(ctx context.Context, namespace string) int64 {
+	if c == nil {
+		return 0
+	}
+
+	local, ok := c.gens.get(namespace)
+	if !c.l2Available() || (ok && time.Since(local.fetchedAt) < generationRefresh) {
+		return local.value
+	}
+
+	var value int64
+	b, err := c.l2.Get(ctx, generationKeyPrefix+namespace)
+	c.recordL2(err)
+	if err == nil {
+		value, err = strconv.ParseInt(string(b), 10, 64)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		c.log.Warn("failed to read cache generation", zap.String("namespace", namespace), zap.Error(err))
+		return local.value
+	}
+
+	return c.gens.refresh(namespace, value)
+}
+
+// Invalidate bumps the generation of a namespace, making all of its entries
+// unreachable in O(1). Old entries expire through their TTL.
+func (c *Cache) Invalidate(ctx context.Context, namespace string) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.l2 == nil {
+		value := c.gens.incr(namespace)
+		c.log.Debug("cache namespace invalidated", zap.String("namespace", namespace), zap.Int64("generation", value))
+		return nil
+	}
+	if !c.l2Available() {
+		c.gens.incr(namespace)
+		return ErrL2Unavailable
+	}
+
+	value, err := c.l2.Incr(ctx, generationKeyPrefix+namespace)
+	c.recordL2(err)
+	if err != nil {
+		// Still move the local copy forward so this instance stops serving
+		// stale entries.
+		c.gens.incr(namespace)
+		return err
+	}
+
+	value = c.gens.bump(namespace, value)
+	c.publish(ctx, invalidation{Namespace: namespace, Generation: value})
+	c.log.Debug("cache namespace invalidated", zap.String("namespace", namespace), zap.Int64("generation", value))
+	return nil
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T, redisAddr string) *Cache {
+	t.Helper()
+	c, err := New(Config{
+		L1NumCounters: 1000,
+		L1MaxCost:     1 << 20,
+		L1BufferItems: 64,
+		TTL:           time.Minute,
+		RedisAddr:     redisAddr,
+		EnableL2:      redisAddr != "",
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestGeneration_L1Only(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	assert.Equal(t, int64(0), c.Generation(ctx, "catmat"))
+	assert.NoError(t, c.Invalidate(ctx, "catmat"))
+	assert.NoError(t, c.Invalidate(ctx, "catmat"))
+
+	assert.Equal(t, int64(2), c.Generation(ctx, "catmat"))
+	assert.Equal(t, int64(0), c.Generation(ctx, "catser"))
+}
+
+func TestGeneration_SharedThroughRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+
+	assert.NoError(t, a.Invalidate(ctx, "catser"))
+	assert.Equal(t, int64(1), a.Generation(ctx, "catser"))
+	assert.Equal(t, int64(1), b.Generation(ctx, "catser"))
+}
+
+func TestGeneration_RefreshesFromRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+
+	assert.Equal(t, int64(0), c.Generation(ctx, "catser"))
+
+	// A bump whose invalidation message was lost.
+	mr.Set(DefaultRedisPrefix+generationKeyPrefix+"catser", "4")
+	assert.Equal(t, int64(0), c.Generation(ctx, "catser"))
+
+	c.gens.entries["catser"] = generationEntry{value: 0, fetchedAt: time.Now().Add(-generationRefresh)}
+	assert.Equal(t, int64(4), c.Generation(ctx, "catser"))
+}
+
+func TestGeneration_RedisDownKeepsLocalValue(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+
+	assert.NoError(t, c.Invalidate(ctx, "catmat"))
+	mr.Close()
+
+	assert.Error(t, c.Invalidate(ctx, "catmat"))
+	assert.Equal(t, int64(2), c.Generation(ctx, "catmat"))
+}
+
+func TestGeneration_RedisRestartDoesNotGoBack(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+
+	assert.NoError(t, c.Invalidate(ctx, "catmat"))
+	mr.Close()
+	assert.Error(t, c.Invalidate(ctx, "catmat"))
+	require.NoError(t, mr.Restart())
+
+	c.gens.expire()
+	assert.Equal(t, int64(2), c.Generation(ctx, "catmat"))
+
+	// The next bump moves past the local copy, not just past L2.
+	assert.NoError(t, c.Invalidate(ctx, "catmat"))
+	assert.Equal(t, int64(3), c.Generation(ctx, "catmat"))
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrL2Unavailable is returned by operations that must reach L2 while it is
+// down or bypassed by the circuit breaker.
+var ErrL2Unavailable = errors.New("cache: L2 unavailable")
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultBreakerThreshold    = 5
+	defaultBreakerCooldown     = 30 * time.Second
+	defaultL2Timeout           = 500 * time.Millisecond
+)
+
+// L2State describes whether the L2 tier is being used.
+type L2State string
+
+const (
+	// L2Disabled means no L2 store is configured.
+	L2Disabled L2State = "disabled"
+	// L2Up means reads and writes go to the L2 store.
+	L2Up L2State = "up"
+	// L2Down means the health loop cannot reach the L2 store.
+	L2Down L2State = "down"
+	// L2CircuitOpen means L2 answers pings but is bypassed after repeated
+	// command errors until the cooldown ends.
+	L2CircuitOpen L2State = "circuit_open"
+)
+
+// Health is a snapshot of the L2 state.
+type Health struct {
+	// Backend is the L2 store name ("redis", "postgres"), empty when disabled.
+	Backend             string
+	L2State             L2State
+	ConsecutiveFailures int
+	LastError           string
+	LastCheck           time.Time
+	OpenUntil           time.Time
+}
+
+// l2Health tracks L2 reachability (from the health loop) and a circuit
+// breaker fed by the result of every command.
+type l2Health struct {
+	mu        sync.Mutex
+	reachable bool
+	failures  int
+	openUntil time.Time
+	lastErr   string
+	lastCheck time.Time
+
+	threshold int
+	cooldown  time.Duration
+}
+
+// allow reports whether a command may be sent to L2. After the cooldown
+// the breaker is half-open: commands go through and the next failure opens
+// it again.
+func (h *l2Health) allow(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.reachable && !now.Before(h.openUntil)
+}
+
+// record feeds the breaker and reports whether it has just opened.
+func (h *l2Health) record(err error, now time.Time) (opened bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.failures = 0
+		return false
+	}
+	h.failures++
+	h.lastErr = err.Error()
+	if h.failures >= h.threshold && !now.Before(h.openUntil) {
+		h.openUntil = now.Add(h.cooldown)
+		return true
+	}
+	return false
+}
+
+// setReachable stores a health check result and reports whether the
+// reachability changed.
+func (h *l2Health) setReachable(err error, now time.Time) (changed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastCheck = now
+	if err != nil {
+		h.lastErr = err.Error()
+	}
+	reachable := err == nil
+	changed = h.reachable != reachable
+	h.reachable = reachable
+	return changed
+}
+
+func (h *l2Health) snapshot(now time.Time) Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := L2Up
+	switch {
+	case !h.reachable:
+		state = L2Down
+	case now.Before(h.openUntil):
+		state = L2CircuitOpen
+	}
+	return Health{
+		L2State:             state,
+		ConsecutiveFailures: h.failures,
+		LastError:           h.lastErr,
+		LastCheck:           h.lastCheck,
+		OpenUntil:           h.openUntil,
+	}
+}
+
+// Health reports the current L2 state.
+func (c *Cache) Health() Health {
+	if c == nil || c.l2 == nil {
+		return Health{L2State: L2Disabled}
+	}
+	h := c.health.snapshot(time.Now())
+	h.Backend = c.l2.Name()
+	return h
+}
+
+// l2Available reports whether L2 is configured and should be used now.
+func (c *Cache) l2Available() bool {
+	return c.l2 != nil && c.health.allow(time.Now())
+}
+
+// recordL2 feeds the result of an L2 command to the circuit breaker. A
+// missing key is not a failure.
+func (c *Cache) recordL2(err error) {
+	if errors.Is(err, ErrNotFound) {
+		err = nil
+	}
+	if c.health.record(err, time.Now()) {
+		c.log.Warn("L2 cache failing, bypassing it",
+			zap.String("backend", c.l2.Name()), zap.Duration("cooldown", c.health.cooldown), zap.Error(err))
+	}
+}
+
+// ping checks L2 once and updates the reachability state.
+func (c *Cache) ping(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	err := c.l2.Ping(ctx)
+	if !c.health.setReachable(err, time.Now()) {
+		return
+	}
+	if err != nil {
+		c.log.Warn("L2 cache unreachable, disabled until it recovers", zap.String("backend", c.l2.Name()), zap.Error(err))
+		return
+	}
+	// Generations may have been bumped while we could not read them.
+	c.gens.expire()
+	c.log.Info("L2 cache reachable, enabled", zap.String("backend", c.l2.Name()))
+}
+
+// healthLoop pings L2 until Close, re-enabling it once it is reachable.
+func (c *Cache) healthLoop(ctx context.Context, interval time.Duration) {
+	defer c.bg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.ping(ctx)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_DisabledWithoutRedis(t *testing.T) {
+	c := newTestCache(t, "")
+	assert.Equal(t, L2Disabled, c.Health().L2State)
+
+	var nilCache *Cache
+	assert.Equal(t, L2Disabled, nilCache.Health().L2State)
+}
+
+func TestHealth_RedisStartingAfterAPI(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	c, err := New(Config{
+		L1NumCounters:       1000,
+		L1MaxCost:           1 << 20,
+		L1BufferItems:       64,
+		TTL:                 time.Minute,
+		RedisAddr:           addr,
+		EnableL2:            true,
+		HealthCheckInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	assert.Equal(t, L2Down, c.Health().L2State)
+	require.NoError(t, c.Set(ctx, "k", "only-l1"))
+
+	require.NoError(t, mr.Restart())
+	assert.Eventually(t, func() bool {
+		return c.Health().L2State == L2Up
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.Set(ctx, "k", "both"))
+	assert.True(t, mr.Exists(DefaultRedisPrefix+"k"))
+}
+
+func TestHealth_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	c, err := New(Config{
+		L1NumCounters:       1000,
+		L1MaxCost:           1 << 20,
+		L1BufferItems:       64,
+		TTL:                 time.Minute,
+		RedisAddr:           mr.Addr(),
+		EnableL2:            true,
+		HealthCheckInterval: time.Hour,
+		BreakerThreshold:    2,
+		BreakerCooldown:     100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	mr.SetError("LOADING dataset in memory")
+	var v string
+	for range 2 {
+		_, err := c.Get(ctx, "k", &v)
+		assert.Error(t, err)
+	}
+
+	health := c.Health()
+	assert.Equal(t, L2CircuitOpen, health.L2State)
+	assert.Equal(t, 2, health.ConsecutiveFailures)
+	assert.Contains(t, health.LastError, "LOADING")
+
+	// While open, Redis is not touched and reads fall back to a miss.
+	commands := mr.CommandCount()
+	ok, err := c.Get(ctx, "k", &v)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, commands, mr.CommandCount())
+
+	// Half-open after the cooldown: a success closes the breaker.
+	mr.SetError("")
+	time.Sleep(120 * time.Millisecond)
+	_, err = c.Get(ctx, "k", &v)
+	assert.NoError(t, err)
+	assert.Equal(t, L2Up, c.Health().L2State)
+	assert.Equal(t, 0, c.Health().ConsecutiveFailures)
+}
+
+func TestInvalidate_L2Unavailable(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	c := newTestCache(t, addr)
+
+	assert.ErrorIs(t, c.Invalidate(ctx, "catmat"), ErrL2Unavailable)
+	assert.Equal(t, int64(1), c.Generation(ctx, "catmat"))
+	assert.ErrorIs(t, c.Delete(ctx, "k"), ErrL2Unavailable)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// refreshTimeout bounds a background refresh of a stale entry, which no
+// longer has a request context to inherit a deadline from.
+const refreshTimeout = 30 * time.Second
+
+// Loader produces the value to cache for a key.
+type Loader func(ctx context.Context) (any, error)
+
+// GetOrLoad decodes the cached value of key into dest, calling loader on a
+// miss. Concurrent misses for the same key share a single loader call. A
+// stale entry (see Config.StaleTTL) is returned immediately while one
+// goroutine refreshes it in the background. Loader errors are not cached.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, dest any, loader Loader) error {
+	if c == nil {
+		v, err := loader(ctx)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, dest)
+	}
+
+	v, fresh, found, err := c.read(ctx, key)
+	if err != nil {
+		c.log.Debug("cache get error", zap.String("key", key), zap.Error(err))
+	}
+	if found {
+		if !fresh {
+			c.refresh(ctx, key, loader)
+		}
+		return c.decode(v, dest)
+	}
+
+	c.log.Debug("cache miss", zap.String("key", key))
+	// The shared load must not fail because the first caller went away.
+	ch := c.loads.DoChan(key, func() (any, error) {
+		return c.load(context.WithoutCancel(ctx), key, loader)
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return res.Err
+		}
+		return c.decode(res.Val.(storedValue), dest)
+	}
+}
+
+// refresh reloads a stale entry once, no matter how many readers see it.
+func (c *Cache) refresh(ctx context.Context, key string, loader Loader) {
+	c.loads.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		v, err := c.load(ctx, key, loader)
+		if err != nil {
+			c.log.Warn("cache refresh failed, serving stale value", zap.String("key", key), zap.Error(err))
+		}
+		return v, err
+	})
+}
+
+func (c *Cache) load(ctx context.Context, key string, loader Loader) (storedValue, error) {
+	counters := c.stats.prefix(key)
+	counters.loads.Add(1)
+
+	value, err := loader(ctx)
+	if err != nil {
+		counters.loadErrs.Add(1)
+		return storedValue{}, err
+	}
+	v, err := c.encode(value)
+	if err != nil {
+		return storedValue{}, err
+	}
+	c.write(ctx, key, v, c.ttlFor(key, value))
+	return v, nil
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrLoad_CoalescesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return map[string]int{"total": 42}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]map[string]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, c.GetOrLoad(ctx, "catalog:stats", &results[i], loader))
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, r := range results {
+		assert.Equal(t, 42, r["total"])
+	}
+}
+
+func TestGetOrLoad_HitSkipsLoader(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	require.NoError(t, c.Set(ctx, "k", "cached"))
+	c.l1.Wait()
+
+	var v string
+	err := c.GetOrLoad(ctx, "k", &v, func(context.Context) (any, error) {
+		assert.Fail(t, "loader called on a fresh hit")
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", v)
+}
+
+func TestGetOrLoad_ServesStaleWhileRefreshing(t *testing.T) {
+	ctx := context.Background()
+	c, err := New(Config{
+		L1NumCounters: 1000,
+		L1MaxCost:     1 << 20,
+		L1BufferItems: 64,
+		TTL:           20 * time.Millisecond,
+		StaleTTL:      time.Minute,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	require.NoError(t, c.Set(ctx, "k", "old"))
+	c.l1.Wait()
+	time.Sleep(30 * time.Millisecond)
+
+	refreshed := make(chan struct{})
+	var calls atomic.Int32
+	loader := func(context.Context) (any, error) {
+		calls.Add(1)
+		defer close(refreshed)
+		return "new", nil
+	}
+
+	var v string
+	assert.NoError(t, c.GetOrLoad(ctx, "k", &v, loader))
+	assert.Equal(t, "old", v)
+
+	// Get ignores the stale entry.
+	ok, err := c.Get(ctx, "k", &v)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+	c.l1.Wait()
+
+	assert.Eventually(t, func() bool {
+		var got string
+		ok, _ := c.Get(ctx, "k", &got)
+		return ok && got == "new"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestGetOrLoad_ErrorIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	var v string
+	err := c.GetOrLoad(ctx, "k", &v, func(context.Context) (any, error) {
+		return nil, errors.New("db down")
+	})
+	assert.EqualError(t, err, "db down")
+
+	err = c.GetOrLoad(ctx, "k", &v, func(context.Context) (any, error) {
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", v)
+}
+
+func TestGetOrLoad_NilCacheLoads(t *testing.T) {
+	var c *Cache
+	var v []int
+
+	err := c.GetOrLoad(context.Background(), "k", &v, func(context.Context) (any, error) {
+		return []int{1, 2}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, v)
+}
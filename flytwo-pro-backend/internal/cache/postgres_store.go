@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCleanupInterval = time.Minute
+	cleanupBatchSize       = 5000
+)
+
+// PostgresConfig configures a PostgresStore. Timeout bounds every query
+// (default 500ms); expired rows are deleted every CleanupInterval (default
+// 1m).
+type PostgresConfig struct {
+	Timeout         time.Duration
+	CleanupInterval time.Duration
+	Logger          *zap.Logger
+}
+
+// PostgresStore keeps L2 entries in the UNLOGGED cache_entry table (migration
+// 011), for deployments without Redis. Invalidations are broadcast with
+// LISTEN/NOTIFY, which holds one pool connection while subscribed.
+type PostgresStore struct {
+	pool    *pgxpool.Pool
+	timeout time.Duration
+	log     *zap.Logger
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     sync.WaitGroup
+}
+
+// NewPostgresStore starts the cleanup loop. The pool is not closed by Close.
+func NewPostgresStore(pool *pgxpool.Pool, cfg PostgresConfig) *PostgresStore {
+	log := cfg.Logger
+	if log == nil {
+		log = zap.NewNop()
+	}
+	s := &PostgresStore{
+		pool:    pool,
+		timeout: withDefault(cfg.Timeout, defaultL2Timeout),
+		log:     log,
+		stop:    make(chan struct{}),
+	}
+	s.done.Add(1)
+	go s.cleanupLoop(withDefault(cfg.CleanupInterval, defaultCleanupInterval))
+	return s
+}
+
+func (s *PostgresStore) Name() string { return "postgres" }
+
+func (s *PostgresStore) Get(ctx context.Context, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var value []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT value FROM cache_entry
+		WHERE key = $1 AND (expires_at IS NULL OR expires_at > now())
+	`, key).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+func (s *PostgresStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO cache_entry (key, value, expires_at)
+		VALUES ($1, $2, now() + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+	`, key, value, ttl.Seconds())
+	return err
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, keys ...string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM cache_entry
+		WHERE key = ANY($1) AND (expires_at IS NULL OR expires_at > now())
+	`, keys)
+	return tag.RowsAffected(), err
+}
+
+// DeletePrefix runs without the per-query timeout: like a Redis SCAN it may
+// walk the whole table.
+func (s *PostgresStore) DeletePrefix(ctx context.Context, prefix, keep string) (int64, error) {
+	query := `DELETE FROM cache_entry WHERE key LIKE $1 ESCAPE '\'`
+	args := []any{escapeLike(prefix) + "%"}
+	if keep != "" {
+		query += ` AND key NOT LIKE $2 ESCAPE '\'`
+		args = append(args, escapeLike(keep)+"%")
+	}
+	tag, err := s.pool.Exec(ctx, query, args...)
+	return tag.RowsAffected(), err
+}
+
+// Incr stores the counter as decimal text, like Redis INCR.
+func (s *PostgresStore) Incr(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var value int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO cache_entry (key, value, expires_at)
+		VALUES ($1, convert_to('1', 'UTF8'), NULL)
+		ON CONFLICT (key) DO UPDATE
+		SET value = convert_to((convert_from(cache_entry.value, 'UTF8')::bigint + 1)::text, 'UTF8'),
+		    expires_at = NULL
+		RETURNING convert_from(value, 'UTF8')::bigint
+	`, key).Scan(&value)
+	return value, err
+}
+
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Also fails when migration 011 has not been applied.
+	_, err := s.pool.Exec(ctx, `SELECT 1 FROM cache_entry LIMIT 1`)
+	return err
+}
+
+// Close stops the cleanup loop. It may be called more than once.
+func (s *PostgresStore) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.done.Wait()
+	return nil
+}
+
+func (s *PostgresStore) Publish(ctx context.Context, channel string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, string(payload))
+	return err
+}
+
+// Subscribe LISTENs on a dedicated connection, opening a new one with
+// backoff whenever it is lost.
+func (s *PostgresStore) Subscribe(ctx context.Context, channel string, handle func(payload []byte), resync func()) {
+	backoff := minResubscribeBackoff
+	subscribed := false
+	for {
+		err := s.listen(ctx, channel, handle, func() {
+			if subscribed {
+				s.log.Info("cache invalidation subscription restored, clearing L1")
+				resync()
+			}
+			subscribed = true
+			backoff = minResubscribeBackoff
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.log.Warn("cache invalidation subscription lost", zap.Error(err), zap.Duration("retry_in", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxResubscribeBackoff)
+	}
+}
+
+func (s *PostgresStore) listen(ctx context.Context, channel string, handle func(payload []byte), listening func()) error {
+	pooled, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection never goes back to the pool still LISTENing.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	listening()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle([]byte(n.Payload))
+	}
+}
+
+// cleanupLoop deletes expired rows in batches until Close. Get already
+// ignores them; this only reclaims space.
+func (s *PostgresStore) cleanupLoop(interval time.Duration) {
+	defer s.done.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if n, err := s.cleanup(context.Background()); err != nil {
+				s.log.Warn("failed to delete expired cache entries", zap.Error(err))
+			} else if n > 0 {
+				s.log.Debug("expired cache entries deleted", zap.Int64("rows", n))
+			}
+		}
+	}
+}
+
+func (s *PostgresStore) cleanup(ctx context.Context) (int64, error) {
+	var deleted int64
+	for {
+		tag, err := s.pool.Exec(ctx, `
+			DELETE FROM cache_entry
+			WHERE ctid IN (
+				SELECT ctid FROM cache_entry
+				WHERE expires_at <= now()
+				LIMIT $1
+			)
+		`, cleanupBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += tag.RowsAffected()
+		if tag.RowsAffected() < cleanupBatchSize {
+			return deleted, nil
+		}
+		select {
+		case <-s.stop:
+			return deleted, nil
+		default:
+		}
+	}
+}
+
+// escapeLike quotes the characters LIKE treats as patterns.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
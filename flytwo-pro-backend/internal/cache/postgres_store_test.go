@@ -0,0 +1,138 @@
+//go:build integration
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPool connects to the database used by the API integration tests,
+// which must have migration 011 applied.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	env := func(key, def string) string {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+		return def
+	}
+	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		env("GOBID_DATABASE_USER", "ADM"), env("GOBID_DATABASE_PASSWORD", "2104"),
+		env("GOBID_DATABASE_HOST", "localhost"), env("GOBID_DATABASE_PORT", "5580"),
+		env("GOBID_DATABASE_NAME", "gobid"))
+
+	pool, err := pgxpool.New(context.Background(), connStr)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	require.NoError(t, pool.Ping(context.Background()))
+
+	_, err = pool.Exec(context.Background(), `TRUNCATE cache_entry`)
+	require.NoError(t, err)
+	return pool
+}
+
+func newPostgresStore(t *testing.T, pool *pgxpool.Pool) *PostgresStore {
+	t.Helper()
+	s := NewPostgresStore(pool, PostgresConfig{Timeout: 2 * time.Second, CleanupInterval: time.Hour})
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// waitListeners blocks until n connections LISTEN on the invalidation channel.
+func waitListeners(t *testing.T, pool *pgxpool.Pool, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		var count int
+		err := pool.QueryRow(context.Background(), `
+			SELECT count(*) FROM pg_stat_activity
+			WHERE datname = current_database() AND query LIKE 'LISTEN %'
+		`).Scan(&count)
+		return err == nil && count >= n
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestPostgresStore_GetSetExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := newPostgresStore(t, newTestPool(t))
+
+	_, err := s.Get(ctx, "catmat:q=papel")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Set(ctx, "catmat:q=papel", []byte("v1"), time.Minute))
+	require.NoError(t, s.Set(ctx, "catmat:q=papel", []byte("v2"), time.Minute))
+	b, err := s.Get(ctx, "catmat:q=papel")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(b))
+
+	require.NoError(t, s.Set(ctx, "catmat:q=old", []byte("x"), 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	_, err = s.Get(ctx, "catmat:q=old")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	n, err := s.cleanup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = s.Delete(ctx, "catmat:q=papel", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestPostgresStore_IncrAndDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	s := newPostgresStore(t, newTestPool(t))
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := s.Incr(ctx, generationKeyPrefix+"catmat")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	require.NoError(t, s.Set(ctx, "catmat:q=a", []byte("a"), time.Minute))
+	require.NoError(t, s.Set(ctx, "catmat_q", []byte("b"), time.Minute))
+	require.NoError(t, s.Set(ctx, "catser:q=c", []byte("c"), time.Minute))
+
+	// "_" is a LIKE wildcard and must match literally.
+	n, err := s.DeletePrefix(ctx, "catmat:", generationKeyPrefix)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = s.DeletePrefix(ctx, "", generationKeyPrefix)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	b, err := s.Get(ctx, generationKeyPrefix+"catmat")
+	require.NoError(t, err)
+	assert.Equal(t, "3", string(b))
+}
+
+func TestPostgresStore_CacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+	a := newStoreCache(t, newPostgresStore(t, pool))
+	b := newStoreCache(t, newPostgresStore(t, pool))
+	require.NotNil(t, a.bus)
+	assert.Equal(t, "postgres", a.Health().Backend)
+	waitListeners(t, pool, 2)
+
+	require.NoError(t, a.Set(ctx, "catmat:q=papel", "v1"))
+	assert.Equal(t, "v1", getString(t, b, "catmat:q=papel"))
+
+	// b now holds v1 in L1; the NOTIFY from a evicts it.
+	require.NoError(t, a.Set(ctx, "catmat:q=papel", "v2"))
+	assert.Eventually(t, func() bool {
+		return getString(t, b, "catmat:q=papel") == "v2"
+	}, 3*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, a.Invalidate(ctx, "catmat"))
+	assert.Eventually(t, func() bool {
+		return b.Generation(ctx, "catmat") == 1
+	}, 3*time.Second, 20*time.Millisecond)
+}
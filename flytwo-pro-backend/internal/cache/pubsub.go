@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+)
+
+// DefaultInvalidationChannel is the Redis channel (or Postgres NOTIFY
+// channel) used when Config.InvalidationChannel is empty.
+const DefaultInvalidationChannel = "gobid:cache:invalidate"
+
+// maxInvalidationPayload keeps messages under the 8000 byte limit of a
+// Postgres NOTIFY. Larger key lists are sent as a request to clear L1.
+const maxInvalidationPayload = 7000
+
+// invalidation is the message published to the other instances. It carries
+// a list of keys to evict from L1, a namespace generation, or a request to
+// clear L1 after a purge.
+type invalidation struct {
+	Origin     string   `json:"origin"`
+	Keys       []string `json:"keys,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Generation int64    `json:"generation,omitempty"`
+	ClearL1    bool     `json:"clear_l1,omitempty"`
+}
+
+// publish notifies the other instances. Failures are logged only: the other
+// L1s still converge through their TTL and the generation refresh.
+func (c *Cache) publish(ctx context.Context, msg invalidation) {
+	if c.bus == nil || !c.l2Available() {
+		return
+	}
+	msg.Origin = c.instanceID
+	b, err := json.Marshal(msg)
+	if err == nil && len(b) > maxInvalidationPayload {
+		msg.Keys, msg.ClearL1 = nil, true
+		b, err = json.Marshal(msg)
+	}
+	if err != nil {
+		c.log.Warn("failed to encode cache invalidation", zap.Error(err))
+		return
+	}
+	err = c.bus.Publish(ctx, c.channel, b)
+	c.recordL2(err)
+	if err != nil {
+		c.log.Warn("failed to publish cache invalidation", zap.Error(err))
+	}
+}
+
+func (c *Cache) apply(payload []byte) {
+	var msg invalidation
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.log.Warn("invalid cache invalidation message", zap.Error(err))
+		return
+	}
+	if msg.Origin == c.instanceID {
+		return
+	}
+
+	if msg.ClearL1 {
+		c.l1.Clear()
+	}
+	for _, key := range msg.Keys {
+		c.l1.Del(key)
+	}
+	if msg.Namespace != "" {
+		c.gens.advance(msg.Namespace, msg.Generation)
+	}
+	c.log.Debug("cache invalidation received",
+		zap.Int("keys", len(msg.Keys)), zap.String("namespace", msg.Namespace))
+}
+
+// resetL1 drops everything this instance may have missed while unsubscribed.
+func (c *Cache) resetL1() {
+	c.l1.Clear()
+	c.gens.expire()
+}
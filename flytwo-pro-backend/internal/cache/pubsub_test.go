@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitSubscribers blocks until n instances listen on the invalidation channel.
+func waitSubscribers(t *testing.T, mr *miniredis.Miniredis, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(DefaultInvalidationChannel)[DefaultInvalidationChannel] == n
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func getString(t *testing.T, c *Cache, key string) string {
+	t.Helper()
+	var v string
+	ok, err := c.Get(context.Background(), key, &v)
+	require.NoError(t, err)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+func TestPubSub_OverwriteEvictsOtherL1(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+	waitSubscribers(t, mr, 2)
+
+	require.NoError(t, a.Set(ctx, "catmat:q=papel", "v1"))
+	assert.Equal(t, "v1", getString(t, b, "catmat:q=papel"))
+	b.l1.Wait()
+	_, inL1 := b.l1.Get("catmat:q=papel")
+	require.True(t, inL1)
+
+	require.NoError(t, a.Set(ctx, "catmat:q=papel", "v2"))
+
+	assert.Eventually(t, func() bool {
+		return getString(t, b, "catmat:q=papel") == "v2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestPubSub_DeleteEvictsOtherL1(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+	waitSubscribers(t, mr, 2)
+
+	require.NoError(t, a.Set(ctx, "catalog:stats", "stats"))
+	assert.Equal(t, "stats", getString(t, b, "catalog:stats"))
+	b.l1.Wait()
+
+	require.NoError(t, a.Delete(ctx, "catalog:stats"))
+
+	assert.False(t, mr.Exists(DefaultRedisPrefix+"catalog:stats"))
+	assert.Eventually(t, func() bool {
+		return getString(t, b, "catalog:stats") == ""
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestPubSub_NamespaceInvalidation(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+	waitSubscribers(t, mr, 2)
+
+	// b trusts its L1 copy for generationRefresh; the message must beat it.
+	assert.Equal(t, int64(0), b.Generation(ctx, "catmat"))
+	require.NoError(t, a.Invalidate(ctx, "catmat"))
+
+	assert.Eventually(t, func() bool {
+		return b.Generation(ctx, "catmat") == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPubSub_ReconnectClearsL1(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+	waitSubscribers(t, mr, 1)
+
+	require.NoError(t, c.Set(ctx, "catser:q=limpeza", "v1"))
+	c.l1.Wait()
+
+	// Changes made while the subscription is down are never announced.
+	mr.Close()
+	require.NoError(t, mr.Restart())
+	require.NoError(t, mr.Set(DefaultRedisPrefix+"catser:q=limpeza", string(encodeEntry(time.Now().Add(time.Minute), JSONCodec{}.ID(), []byte(`"v2"`)))))
+
+	waitSubscribers(t, mr, 1)
+	assert.Eventually(t, func() bool {
+		return getString(t, c, "catser:q=limpeza") == "v2"
+	}, 3*time.Second, 20*time.Millisecond)
+}
+
+func TestPubSub_NoopWithoutL2(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	assert.Nil(t, c.bus)
+	assert.NoError(t, c.Set(ctx, "k", "v"))
+	assert.NoError(t, c.Delete(ctx, "k"))
+	assert.Equal(t, "", getString(t, c, "k"))
+}
+
+func TestApply_IgnoresOwnMessages(t *testing.T) {
+	c := newTestCache(t, "")
+	c.gens.set("catmat", 3)
+
+	c.apply([]byte(`{"origin":"` + c.instanceID + `","namespace":"catmat","generation":7}`))
+	assert.Equal(t, int64(3), c.Generation(context.Background(), "catmat"))
+
+	c.apply([]byte(`{"origin":"other","namespace":"catmat","generation":7}`))
+	assert.Equal(t, int64(7), c.Generation(context.Background(), "catmat"))
+
+	c.apply([]byte(`{"origin":"other","namespace":"catmat","generation":5}`))
+	assert.Equal(t, int64(7), c.Generation(context.Background(), "catmat"))
+}
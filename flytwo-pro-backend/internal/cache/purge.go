@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Purge deletes every entry whose key starts with prefix (e.g. "catmat:" or
+// "catalog:stats") and returns how many L2 keys were removed. ristretto
+// cannot enumerate its keys, so L1 is cleared entirely on every instance.
+// Namespace generations are kept.
+func (c *Cache) Purge(ctx context.Context, prefix string) (int64, error) {
+	if c == nil {
+		return 0, nil
+	}
+
+	c.l1.Clear()
+
+	if c.l2 == nil {
+		return 0, nil
+	}
+	if !c.l2Available() {
+		return 0, ErrL2Unavailable
+	}
+
+	deleted, err := c.l2.DeletePrefix(ctx, prefix, generationKeyPrefix)
+	c.recordL2(err)
+	if err != nil {
+		return deleted, err
+	}
+
+	c.publish(ctx, invalidation{ClearL1: true})
+	c.log.Info("cache purged", zap.String("prefix", prefix), zap.Int64("deleted", deleted))
+	return deleted, nil
+}
+
+// Flush removes every cache entry. With Redis only the cache namespace is
+// scanned (see RedisConfig.Prefix); other keys in the DB are left alone.
+func (c *Cache) Flush(ctx context.Context) (int64, error) {
+	return c.Purge(ctx, "")
+}
@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	purgeScanCount = 500
+	purgeBatchSize = 500
+)
+
+const (
+	minResubscribeBackoff = 100 * time.Millisecond
+	maxResubscribeBackoff = 5 * time.Second
+)
+
+// DefaultRedisPrefix namespaces every cache key stored in Redis.
+const DefaultRedisPrefix = "gobid:cache:"
+
+// RedisConfig configures a RedisStore. Timeout bounds every command (default
+// 500ms); Prefix namespaces the keys (default DefaultRedisPrefix).
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+	Prefix   string
+	Logger   *zap.Logger
+}
+
+// RedisStore keeps L2 entries in Redis and broadcasts invalidations through
+// pub/sub. Keys are stored under its prefix, so purges never reach keys
+// written by other components sharing the DB.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+	log    *zap.Logger
+}
+
+// NewRedisStore builds the client without connecting; the cache health loop
+// detects when Redis becomes reachable.
+func NewRedisStore(cfg RedisConfig) *RedisStore {
+	log := cfg.Logger
+	if log == nil {
+		log = zap.NewNop()
+	}
+	timeout := withDefault(cfg.Timeout, defaultL2Timeout)
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  2 * timeout,
+			ReadTimeout:  timeout,
+			WriteTimeout: timeout,
+		}),
+		prefix: prefix,
+		log:    log,
+	}
+}
+
+func (s *RedisStore) Name() string { return "redis" }
+
+func (s *RedisStore) key(key string) string { return s.prefix + key }
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := s.client.Get(ctx, s.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.key(key), value, ttl).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) (int64, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.key(key)
+	}
+	return s.client.Del(ctx, prefixed...).Result()
+}
+
+// DeletePrefix walks the store's namespace with SCAN so Redis is never
+// blocked like KEYS would, then UNLINKs the keys in batches. An empty prefix
+// matches every cache key, never the rest of the DB.
+func (s *RedisStore) DeletePrefix(ctx context.Context, prefix, keep string) (int64, error) {
+	// Collect first, delete after: the scan then sees a stable key set.
+	var keys []string
+	iter := s.client.Scan(ctx, 0, escapeGlob(s.key(prefix))+"*", purgeScanCount).Iterator()
+	for iter.Next(ctx) {
+		if key := iter.Val(); keep == "" || !strings.HasPrefix(key, s.key(keep)) {
+			keys = append(keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for batch := range slices.Chunk(keys, purgeBatchSize) {
+		n, err := s.client.Unlink(ctx, batch...).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string) (int64, error) {
+	return s.client.Incr(ctx, s.key(key)).Result()
+}
+
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+func (s *RedisStore) Publish(ctx context.Context, channel string, payload []byte) error {
+	return s.client.Publish(ctx, channel, payload).Err()
+}
+
+// Subscribe relies on go-redis to reconnect the subscription; resync is
+// called on every "subscribe" confirmation after the first one.
+func (s *RedisStore) Subscribe(ctx context.Context, channel string, handle func(payload []byte), resync func()) {
+	sub := s.client.Subscribe(ctx, channel)
+	// Receive blocks on the connection; closing it is what unblocks it.
+	stop := context.AfterFunc(ctx, func() { _ = sub.Close() })
+	defer stop()
+	defer sub.Close()
+
+	backoff := minResubscribeBackoff
+	subscribed := false
+	for {
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+				return
+			}
+			s.log.Warn("cache invalidation subscription lost", zap.Error(err), zap.Duration("retry_in", backoff))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxResubscribeBackoff)
+			continue
+		}
+		backoff = minResubscribeBackoff
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			if subscribed {
+				s.log.Info("cache invalidation subscription restored, clearing L1")
+				resync()
+			}
+			subscribed = true
+		case *redis.Message:
+			handle([]byte(m.Payload))
+		}
+	}
+}
+
+// escapeGlob quotes the characters SCAN MATCH treats as patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
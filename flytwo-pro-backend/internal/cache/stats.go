@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PrefixStats counts lookups of the keys sharing a prefix (the text before the
+// first ':', e.g. "catmat" or "catalog").
+type PrefixStats struct {
+	L1Hits    uint64
+	L2Hits    uint64
+	Misses    uint64
+	StaleHits uint64
+	Loads     uint64
+	LoadErrs  uint64
+}
+
+// TierStats counts lookups answered (or not) by one tier. L2 only sees the
+// lookups L1 missed.
+type TierStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Stats is a snapshot of the cache counters since the process started.
+type Stats struct {
+	L1                  TierStats
+	L2                  TierStats
+	L1Evictions         uint64
+	L1CostUsed          uint64
+	SerializationErrors uint64
+	Prefixes            map[string]PrefixStats
+	Health              Health
+}
+
+type prefixCounters struct {
+	l1Hits    atomic.Uint64
+	l2Hits    atomic.Uint64
+	misses    atomic.Uint64
+	staleHits atomic.Uint64
+	loads     atomic.Uint64
+	loadErrs  atomic.Uint64
+}
+
+type counters struct {
+	l1Misses      atomic.Uint64
+	l2Misses      atomic.Uint64
+	serialization atomic.Uint64
+
+	mu       sync.RWMutex
+	prefixes map[string]*prefixCounters
+}
+
+// prefix returns the counters of the key's prefix, creating them on first use.
+func (s *counters) prefix(key string) *prefixCounters {
+	name, _, _ := strings.Cut(key, ":")
+
+	s.mu.RLock()
+	p, ok := s.prefixes[name]
+	s.mu.RUnlock()
+	if ok {
+		return p
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.prefixes[name]; ok {
+		return p
+	}
+	if s.prefixes == nil {
+		s.prefixes = make(map[string]*prefixCounters)
+	}
+	p = &prefixCounters{}
+	s.prefixes[name] = p
+	return p
+}
+
+// Stats returns the hit/miss counters per tier and per key prefix.
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{Prefixes: map[string]PrefixStats{}, Health: Health{L2State: L2Disabled}}
+	}
+
+	stats := Stats{
+		SerializationErrors: c.stats.serialization.Load(),
+		Prefixes:            map[string]PrefixStats{},
+		Health:              c.Health(),
+	}
+
+	c.stats.mu.RLock()
+	for name, p := range c.stats.prefixes {
+		ps := PrefixStats{
+			L1Hits:    p.l1Hits.Load(),
+			L2Hits:    p.l2Hits.Load(),
+			Misses:    p.misses.Load(),
+			StaleHits: p.staleHits.Load(),
+			Loads:     p.loads.Load(),
+			LoadErrs:  p.loadErrs.Load(),
+		}
+		stats.Prefixes[name] = ps
+		stats.L1.Hits += ps.L1Hits
+		stats.L2.Hits += ps.L2Hits
+	}
+	c.stats.mu.RUnlock()
+
+	stats.L1.Misses = c.stats.l1Misses.Load()
+	stats.L2.Misses = c.stats.l2Misses.Load()
+
+	if m := c.l1.Metrics; m != nil {
+		stats.L1Evictions = m.KeysEvicted()
+		stats.L1CostUsed = m.CostAdded() - m.CostEvicted()
+	}
+	return stats
+}
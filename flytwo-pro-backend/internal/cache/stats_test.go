@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_CountsPerTierAndPrefix(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+
+	var v string
+	require.NoError(t, a.GetOrLoad(ctx, "catmat:q=papel", &v, func(context.Context) (any, error) {
+		return "papel", nil
+	}))
+	a.l1.Wait()
+	_, _ = a.Get(ctx, "catmat:q=papel", &v)
+
+	_, _ = b.Get(ctx, "catmat:q=papel", &v)
+	_, _ = b.Get(ctx, "catser:q=limpeza", &v)
+
+	_ = a.GetOrLoad(ctx, "catalog:stats", &v, func(context.Context) (any, error) {
+		return nil, errors.New("db down")
+	})
+
+	sa := a.Stats()
+	assert.Equal(t, uint64(1), sa.L1.Hits)
+	assert.Equal(t, uint64(1), sa.Prefixes["catmat"].Misses)
+	assert.Equal(t, uint64(1), sa.Prefixes["catmat"].Loads)
+	assert.Equal(t, uint64(1), sa.Prefixes["catalog"].LoadErrs)
+	assert.Equal(t, L2Up, sa.Health.L2State)
+
+	sb := b.Stats()
+	assert.Equal(t, uint64(1), sb.L2.Hits)
+	assert.Equal(t, uint64(1), sb.L2.Misses)
+	assert.Equal(t, uint64(2), sb.L1.Misses)
+	assert.Equal(t, uint64(1), sb.Prefixes["catmat"].L2Hits)
+	assert.Equal(t, uint64(1), sb.Prefixes["catser"].Misses)
+}
+
+func TestStats_SerializationErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+
+	assert.Error(t, c.Set(ctx, "k", func() {}))
+	require.NoError(t, c.Set(ctx, "k", "text"))
+	c.l1.Wait()
+
+	var n int
+	_, err := c.Get(ctx, "k", &n)
+	assert.Error(t, err)
+
+	assert.Equal(t, uint64(2), c.Stats().SerializationErrors)
+}
+
+func TestStats_NilCache(t *testing.T) {
+	var c *Cache
+	stats := c.Stats()
+	assert.Equal(t, L2Disabled, stats.Health.L2State)
+	assert.Empty(t, stats.Prefixes)
+}
+
+func TestPurge_ByPrefix(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	a := newTestCache(t, mr.Addr())
+	b := newTestCache(t, mr.Addr())
+	waitSubscribers(t, mr, 2)
+
+	for i := range 1200 {
+		require.NoError(t, a.Set(ctx, fmt.Sprintf("catmat:q=%d", i), i))
+	}
+	require.NoError(t, a.Set(ctx, "catser:q=limpeza", "limpeza"))
+	require.NoError(t, a.Set(ctx, "catalog:stats|v=catmat.0,catser.0", "stats"))
+	require.NoError(t, a.Invalidate(ctx, "catmat"))
+
+	assert.Equal(t, "limpeza", getString(t, b, "catser:q=limpeza"))
+	b.l1.Wait()
+
+	deleted, err := a.Purge(ctx, "catmat:")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1200), deleted)
+
+	assert.False(t, mr.Exists(DefaultRedisPrefix+"catmat:q=0"))
+	assert.True(t, mr.Exists(DefaultRedisPrefix+"catser:q=limpeza"))
+	assert.True(t, mr.Exists(DefaultRedisPrefix+generationKeyPrefix+"catmat"))
+
+	// b dropped its whole L1 and reads catser again from L2.
+	assert.Eventually(t, func() bool {
+		_, inL1 := b.l1.Get("catser:q=limpeza")
+		return !inL1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	deleted, err = a.Purge(ctx, "catalog:stats")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestFlush_KeepsGenerations(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+
+	require.NoError(t, c.Set(ctx, "catmat:q=papel", "papel"))
+	require.NoError(t, c.Set(ctx, "catser:q=limpeza", "limpeza"))
+	require.NoError(t, c.Invalidate(ctx, "catser"))
+	c.l1.Wait()
+
+	deleted, err := c.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Equal(t, []string{DefaultRedisPrefix + generationKeyPrefix + "catser"}, mr.Keys())
+	assert.Equal(t, "", getString(t, c, "catmat:q=papel"))
+}
+
+func TestFlush_LeavesKeysOutsideTheCacheNamespace(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := newTestCache(t, mr.Addr())
+	require.NoError(t, mr.Set("gobid:ratelimit:lock:user@example.com", "1"))
+	require.NoError(t, c.Set(ctx, "catmat:q=papel", "papel"))
+
+	deleted, err := c.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.True(t, mr.Exists("gobid:ratelimit:lock:user@example.com"))
+
+	_, err = c.Purge(ctx, "gobid:")
+	require.NoError(t, err)
+	assert.True(t, mr.Exists("gobid:ratelimit:lock:user@example.com"))
+}
+
+func TestPurge_WithoutL2ClearsL1(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, "")
+	require.NoError(t, c.Set(ctx, "catmat:q=papel", "papel"))
+	c.l1.Wait()
+
+	deleted, err := c.Purge(ctx, "catmat:")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+	assert.Equal(t, "", getString(t, c, "catmat:q=papel"))
+}
+
+func TestEscapeGlob(t *testing.T) {
+	assert.Equal(t, `catmat:q=a\*b\?\[x\]\\`, escapeGlob(`catmat:q=a*b?[x]\`))
+	assert.Equal(t, "catalog:stats", escapeGlob("catalog:stats"))
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Store.Get for missing or expired keys.
+var ErrNotFound = errors.New("cache: key not found")
+
+// Store is the shared L2 tier. Values are opaque bytes (see encodeEntry) and
+// expire after the ttl given to Set. RedisStore and PostgresStore implement it.
+type Store interface {
+	// Name identifies the backend in logs and health reports.
+	Name() string
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys and returns how many existed.
+	Delete(ctx context.Context, keys ...string) (int64, error)
+	// DeletePrefix removes every key starting with prefix, except those
+	// starting with keep, and returns how many were removed.
+	DeletePrefix(ctx context.Context, prefix, keep string) (int64, error)
+	// Incr atomically increments a counter that never expires, starting at 1.
+	// Get returns its value as a decimal string.
+	Incr(ctx context.Context, key string) (int64, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// Broadcaster is implemented by stores that can deliver invalidation messages
+// to the other instances. Without it each L1 converges through its TTL and
+// the generation refresh only.
+type Broadcaster interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe calls handle for every message until ctx is done. Messages
+	// sent while the subscription is down are lost, so resync is called
+	// whenever it is re-established.
+	Subscribe(ctx context.Context, channel string, handle func(payload []byte), resync func())
+}
@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is a Store without Broadcaster, standing in for any L2 backend.
+type memStore struct {
+	mu      sync.Mutex
+	entries map[string]memEntry
+}
+
+type memEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func newMemStore() *memStore {
+	return &memStore{entries: map[string]memEntry{}}
+}
+
+func (s *memStore) Name() string { return "memory" }
+
+func (s *memStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || (!e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt)) {
+		return nil, ErrNotFound
+	}
+	return e.value, nil
+}
+
+func (s *memStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memStore) Delete(_ context.Context, keys ...string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for _, key := range keys {
+		if _, ok := s.entries[key]; ok {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memStore) DeletePrefix(_ context.Context, prefix, keep string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) && (keep == "" || !strings.HasPrefix(key, keep)) {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memStore) Incr(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, _ := strconv.ParseInt(string(s.entries[key].value), 10, 64)
+	n++
+	s.entries[key] = memEntry{value: []byte(strconv.FormatInt(n, 10))}
+	return n, nil
+}
+
+func (s *memStore) Ping(context.Context) error { return nil }
+func (s *memStore) Close() error               { return nil }
+
+func newStoreCache(t *testing.T, store Store) *Cache {
+	t.Helper()
+	c, err := New(Config{
+		L1NumCounters: 1000,
+		L1MaxCost:     1 << 20,
+		L1BufferItems: 64,
+		TTL:           time.Minute,
+		Store:         store,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestStore_SharedBetweenInstances(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	a := newStoreCache(t, store)
+	b := newStoreCache(t, store)
+
+	assert.Nil(t, a.bus)
+	assert.Equal(t, Health{Backend: "memory", L2State: L2Up, LastCheck: a.Health().LastCheck}, a.Health())
+
+	require.NoError(t, a.Set(ctx, "catmat:q=papel", "v1"))
+	assert.Equal(t, "v1", getString(t, b, "catmat:q=papel"))
+	assert.Equal(t, uint64(1), b.Stats().L2.Hits)
+
+	require.NoError(t, a.Invalidate(ctx, "catmat"))
+	assert.Equal(t, int64(1), a.Generation(ctx, "catmat"))
+	assert.Equal(t, int64(1), b.Generation(ctx, "catmat"))
+}
+
+func TestStore_PurgeKeepsGenerations(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	c := newStoreCache(t, store)
+
+	require.NoError(t, c.Invalidate(ctx, "catmat"))
+	require.NoError(t, c.Set(ctx, "catmat:q=a", "a"))
+	require.NoError(t, c.Set(ctx, "catser:q=b", "b"))
+
+	deleted, err := c.Purge(ctx, "catmat:")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = store.Get(ctx, generationKeyPrefix+"catmat")
+	assert.NoError(t, err)
+	_, err = store.Get(ctx, "catser:q=b")
+	assert.NoError(t, err)
+	_, err = store.Get(ctx, "catmat:q=a")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPublish_LargeKeyListClearsL1(t *testing.T) {
+	c := newTestCache(t, "")
+	keys := make([]string, 0, 200)
+	for i := range 200 {
+		keys = append(keys, "catmat:search:q="+strings.Repeat("x", 50)+strconv.Itoa(i))
+	}
+
+	var sent []byte
+	c.bus = busFunc(func(payload []byte) { sent = payload })
+	c.l2 = newMemStore()
+	c.health.setReachable(nil, time.Now())
+
+	c.publish(context.Background(), invalidation{Keys: keys})
+	require.NotNil(t, sent)
+	assert.LessOrEqual(t, len(sent), maxInvalidationPayload)
+	assert.Contains(t, string(sent), `"clear_l1":true`)
+	assert.NotContains(t, string(sent), `"keys"`)
+}
+
+// busFunc records published payloads.
+type busFunc func(payload []byte)
+
+func (f busFunc) Publish(_ context.Context, _ string, payload []byte) error {
+	f(payload)
+	return nil
+}
+
+func (busFunc) Subscribe(context.Context, string, func([]byte), func()) {}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultCompressMinBytes = 1024
+
+// EmptyChecker is implemented by values that can be empty results, such as a
+// search page without items. Empty values are cached for Config.NegativeTTL.
+type EmptyChecker interface {
+	IsEmpty() bool
+}
+
+type prefixTTL struct {
+	prefix string
+	ttl    time.Duration
+}
+
+// sortPrefixTTLs orders the overrides longest prefix first so the most
+// specific one matches.
+func sortPrefixTTLs(m map[string]time.Duration) []prefixTTL {
+	ttls := make([]prefixTTL, 0, len(m))
+	for prefix, ttl := range m {
+		if prefix != "" && ttl > 0 {
+			ttls = append(ttls, prefixTTL{prefix: prefix, ttl: ttl})
+		}
+	}
+	sort.Slice(ttls, func(i, j int) bool {
+		if len(ttls[i].prefix) != len(ttls[j].prefix) {
+			return len(ttls[i].prefix) > len(ttls[j].prefix)
+		}
+		return ttls[i].prefix < ttls[j].prefix
+	})
+	return ttls
+}
+
+// ttlFor returns how long value stays fresh under key.
+func (c *Cache) ttlFor(key string, value any) time.Duration {
+	ttl := c.ttl
+	for _, p := range c.prefixTTLs {
+		if strings.HasPrefix(key, p.prefix) {
+			ttl = p.ttl
+			break
+		}
+	}
+	if c.negativeTTL > 0 {
+		if e, ok := value.(EmptyChecker); ok && e.IsEmpty() {
+			return min(ttl, c.negativeTTL)
+		}
+	}
+	return ttl
+}
+
+// ParsePrefixTTLs parses "prefix=seconds" pairs separated by commas, e.g.
+// "catalog:stats=3600,catmat:=300".
+func ParsePrefixTTLs(s string) (map[string]time.Duration, error) {
+	ttls := map[string]time.Duration{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		prefix, secs, ok := strings.Cut(pair, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || prefix == "" {
+			return nil, fmt.Errorf("cache: invalid TTL override %q", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(secs))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("cache: invalid TTL seconds in %q", pair)
+		}
+		ttls[prefix] = time.Duration(n) * time.Second
+	}
+	return ttls, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrefixTTLs(t *testing.T) {
+	ttls, err := ParsePrefixTTLs(" catalog:stats=3600, catmat:=300 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"catalog:stats": time.Hour,
+		"catmat:":       5 * time.Minute,
+	}, ttls)
+
+	for _, bad := range []string{"catmat:", "=300", "catmat:=0", "catmat:=abc"} {
+		_, err := ParsePrefixTTLs(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestTTLFor(t *testing.T) {
+	c, err := New(Config{
+		L1NumCounters: 1000,
+		L1MaxCost:     1 << 20,
+		L1BufferItems: 64,
+		TTL:           5 * time.Minute,
+		PrefixTTLs: map[string]time.Duration{
+			"catalog:":               10 * time.Minute,
+			"catalog:stats":          time.Hour,
+			"catalog:stats:classes:": 2 * time.Hour,
+		},
+		NegativeTTL: 30 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	full := bigPage()
+	assert.Equal(t, 5*time.Minute, c.ttlFor("catmat:q=papel", full))
+	assert.Equal(t, time.Hour, c.ttlFor("catalog:stats|v=catmat.0,catser.0", full))
+	assert.Equal(t, 2*time.Hour, c.ttlFor("catalog:stats:classes:catmat:g=75", full))
+	assert.Equal(t, 10*time.Minute, c.ttlFor("catalog:other", full))
+
+	assert.Equal(t, 30*time.Second, c.ttlFor("catmat:q=xyz", &page{}))
+	assert.Equal(t, 30*time.Second, c.ttlFor("catalog:stats", &page{}))
+}
+
+func TestNegativeCaching_ShorterTTLInRedis(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c, err := New(Config{
+		L1NumCounters: 1000,
+		L1MaxCost:     1 << 20,
+		L1BufferItems: 64,
+		TTL:           5 * time.Minute,
+		NegativeTTL:   20 * time.Second,
+		RedisAddr:     mr.Addr(),
+		EnableL2:      true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	var got page
+	require.NoError(t, c.GetOrLoad(ctx, "catmat:q=xyz", &got, func(context.Context) (any, error) {
+		return &page{}, nil
+	}))
+	require.NoError(t, c.GetOrLoad(ctx, "catmat:q=papel", &got, func(context.Context) (any, error) {
+		return bigPage(), nil
+	}))
+
+	assert.Equal(t, 20*time.Second, mr.TTL(DefaultRedisPrefix+"catmat:q=xyz"))
+	assert.Equal(t, 5*time.Minute, mr.TTL(DefaultRedisPrefix+"catmat:q=papel"))
+}
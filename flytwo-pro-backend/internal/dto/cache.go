@@ -0,0 +1,82 @@
+package dto
+
+// HealthResponse is returned by the health endpoint. Status is "degraded" when
+// a configured dependency is not being used; the API still serves requests.
+type HealthResponse struct {
+	Status string              `json:"status"`
+	Cache  CacheHealthResponse `json:"cache"`
+}
+
+// CacheHealthResponse describes the L2 tier (Redis or Postgres) of the search cache.
+type CacheHealthResponse struct {
+	Backend             string  `json:"backend,omitempty"`
+	L2State             string  `json:"l2_state"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	LastError           string  `json:"last_error,omitempty"`
+	LastCheck           *string `json:"last_check,omitempty"`
+	OpenUntil           *string `json:"open_until,omitempty"`
+}
+
+// CacheStatsResponse reports cache counters since the API started
+type CacheStatsResponse struct {
+	L1                  CacheTierStats      `json:"l1"`
+	L2                  CacheTierStats      `json:"l2"`
+	L1Evictions         uint64              `json:"l1_evictions"`
+	L1CostUsed          uint64              `json:"l1_cost_used"`
+	SerializationErrors uint64              `json:"serialization_errors"`
+	Prefixes            []CachePrefixStats  `json:"prefixes"`
+	L2Health            CacheHealthResponse `json:"l2_health"`
+}
+
+// CacheTierStats counts hits and misses of one cache tier
+type CacheTierStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// CachePrefixStats counts lookups of the keys sharing a prefix (catmat, catser, catalog)
+type CachePrefixStats struct {
+	Prefix     string  `json:"prefix"`
+	L1Hits     uint64  `json:"l1_hits"`
+	L2Hits     uint64  `json:"l2_hits"`
+	Misses     uint64  `json:"misses"`
+	StaleHits  uint64  `json:"stale_hits"`
+	Loads      uint64  `json:"loads"`
+	LoadErrors uint64  `json:"load_errors"`
+	HitRatio   float64 `json:"hit_ratio"`
+}
+
+// CachePurgeReq selects the keys to purge by prefix (e.g. "catmat:", "catalog:stats")
+type CachePurgeReq struct {
+	Prefix string `json:"prefix" validate:"required,max=200"`
+}
+
+// CachePurgeResponse reports how many Redis keys were removed
+type CachePurgeResponse struct {
+	Prefix  string `json:"prefix,omitempty"`
+	Deleted int64  `json:"deleted"`
+}
+
+// CacheWarmupStatusResponse reports the cache warm-up state
+type CacheWarmupStatusResponse struct {
+	Running bool            `json:"running"`
+	LastRun *CacheWarmupRun `json:"last_run,omitempty"`
+}
+
+// CacheWarmupRun describes a finished warm-up
+type CacheWarmupRun struct {
+	Reason      string `json:"reason"`
+	StartedAt   string `json:"started_at"`
+	FinishedAt  string `json:"finished_at"`
+	DurationMs  int64  `json:"duration_ms"`
+	Queries     int    `json:"queries"`
+	Warmed      int    `json:"warmed"`
+	Failed      int    `json:"failed"`
+	StatsWarmed bool   `json:"stats_warmed"`
+}
+
+// CacheWarmupTriggerResponse tells whether a new warm-up was queued
+type CacheWarmupTriggerResponse struct {
+	Queued bool `json:"queued"`
+}
@@ -2,10 +2,11 @@ package dto
 
 // CatmatSearchResponse represents the paginated response for CATMAT search
 type CatmatSearchResponse struct {
-	Data   []CatmatSearchItem `json:"data"`
-	Total  int64              `json:"total"`
-	Limit  int32              `json:"limit"`
-	Offset int32              `json:"offset"`
+	Data     []CatmatSearchItem `json:"data"`
+	Total    int64              `json:"total"`
+	Limit    int32              `json:"limit"`
+	Offset   int32              `json:"offset"`
+	SearchID string             `json:"search_id,omitempty"`
 }
 
 // CatmatSearchItem represents a single CATMAT search result item
@@ -25,10 +26,11 @@ type CatmatSearchItem struct {
 
 // CatserSearchResponse represents the paginated response for CATSER search
 type CatserSearchResponse struct {
-	Data   []CatserSearchItem `json:"data"`
-	Total  int64              `json:"total"`
-	Limit  int32              `json:"limit"`
-	Offset int32              `json:"offset"`
+	Data     []CatserSearchItem `json:"data"`
+	Total    int64              `json:"total"`
+	Limit    int32              `json:"limit"`
+	Offset   int32              `json:"offset"`
+	SearchID string             `json:"search_id,omitempty"`
 }
 
 // CatserSearchItem represents a single CATSER search result item
@@ -66,3 +68,49 @@ type StatusCount struct {
 	Status string `json:"status"`
 	Count  int64  `json:"count"`
 }
+
+// CatalogExtendedStatsResponse represents coverage, freshness and import history of both catalogs
+type CatalogExtendedStatsResponse struct {
+	Catmat  CatalogCoverage        `json:"catmat"`
+	Catser  CatalogCoverage        `json:"catser"`
+	History []CatalogImportSummary `json:"history"`
+}
+
+// CatalogCoverage represents the size and data coverage of a catalog
+type CatalogCoverage struct {
+	Total                int64                 `json:"total"`
+	Groups               int64                 `json:"groups"`
+	Classes              int64                 `json:"classes"`
+	WithNcm              *int64                `json:"with_ncm,omitempty"`
+	NcmSharePct          *float64              `json:"ncm_share_pct,omitempty"`
+	WithEmbedding        int64                 `json:"with_embedding"`
+	EmbeddingCoveragePct float64               `json:"embedding_coverage_pct"`
+	LastImport           *CatalogImportSummary `json:"last_import,omitempty"`
+}
+
+// CatalogImportSummary represents a finished catalog import
+type CatalogImportSummary struct {
+	Catalog     string `json:"catalog"`
+	RowsRead    int32  `json:"rows_read"`
+	RowsSaved   int32  `json:"rows_saved"`
+	RowsSkipped int32  `json:"rows_skipped"`
+	ItemTotal   int64  `json:"item_total"`
+	StartedAt   string `json:"started_at"`
+	FinishedAt  string `json:"finished_at"`
+	DurationMs  int64  `json:"duration_ms"`
+}
+
+// ClassCount represents item count per class
+type ClassCount struct {
+	ClassCode int32  `json:"class_code"`
+	ClassName string `json:"class_name"`
+	Count     int64  `json:"count"`
+}
+
+// CatalogClassStatsResponse represents the per-class breakdown of a group
+type CatalogClassStatsResponse struct {
+	Catalog   string       `json:"catalog"`
+	GroupCode int16        `json:"group_code"`
+	Total     int64        `json:"total"`
+	Classes   []ClassCount `json:"classes"`
+}
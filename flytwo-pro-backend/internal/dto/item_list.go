@@ -0,0 +1,63 @@
+package dto
+
+// ItemListReq represents the payload to create or update an item list
+type ItemListReq struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// ItemListDuplicateReq represents the optional name of a list copy
+type ItemListDuplicateReq struct {
+	Name string `json:"name" validate:"max=100"`
+}
+
+// ItemListLineReq represents the payload to add a catalog item to a list
+type ItemListLineReq struct {
+	Catalog  string  `json:"catalog" validate:"required,oneof=catmat catser"`
+	ItemCode int32   `json:"item_code" validate:"required,min=1"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit" validate:"max=20"`
+	Notes    string  `json:"notes" validate:"max=1000"`
+}
+
+// ItemListLineUpdateReq represents the payload to update a list line
+type ItemListLineUpdateReq struct {
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     string  `json:"unit" validate:"max=20"`
+	Notes    string  `json:"notes" validate:"max=1000"`
+}
+
+// ItemListResponse represents an item list without its lines.
+// OrganizationID is set on lists shared with an organization.
+type ItemListResponse struct {
+	ID             string  `json:"id"`
+	OrganizationID *string `json:"organization_id,omitempty"`
+	Name           string  `json:"name"`
+	Description    string  `json:"description"`
+	LineCount      *int64  `json:"line_count,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+// ItemListLineResponse represents a list line. Status is "ok", "changed"
+// (the item was changed by an import after being added) or "removed".
+type ItemListLineResponse struct {
+	ID                 int64   `json:"id"`
+	Catalog            string  `json:"catalog"`
+	ItemCode           int32   `json:"item_code"`
+	Quantity           float64 `json:"quantity"`
+	Unit               string  `json:"unit"`
+	Notes              string  `json:"notes"`
+	ItemDescription    string  `json:"item_description"`
+	CurrentDescription string  `json:"current_description,omitempty"`
+	Status             string  `json:"status,omitempty"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+}
+
+// ItemListDetailResponse represents an item list with its lines
+type ItemListDetailResponse struct {
+	ItemListResponse
+	Lines        []ItemListLineResponse `json:"lines"`
+	FlaggedLines int                    `json:"flagged_lines"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+// OrganizationReq represents the payload to create or update an organization.
+// CNPJ and UASG are optional; the CNPJ may be formatted.
+type OrganizationReq struct {
+	Name string `json:"name" validate:"required,max=200"`
+	CNPJ string `json:"cnpj" validate:"max=18"`
+	UASG string `json:"uasg" validate:"omitempty,len=6,numeric"`
+}
+
+// OrganizationResponse represents an organization
+type OrganizationResponse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	CNPJ        *string `json:"cnpj,omitempty"`
+	UASG        *string `json:"uasg,omitempty"`
+	MemberCount *int64  `json:"member_count,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// OrganizationListResponse represents a page of organizations
+type OrganizationListResponse struct {
+	Data   []OrganizationResponse `json:"data"`
+	Total  int64                  `json:"total"`
+	Limit  int32                  `json:"limit"`
+	Offset int32                  `json:"offset"`
+}
+
+// OrganizationMemberReq sets the role of a member (admin, member or viewer)
+type OrganizationMemberReq struct {
+	Role string `json:"role" validate:"required,oneof=admin member viewer"`
+}
+
+// AddOrganizationMemberReq adds the account with the given email to an
+// organization
+type AddOrganizationMemberReq struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin member viewer"`
+}
+
+// OrganizationMemberResponse represents a member of an organization
+type OrganizationMemberResponse struct {
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// UserOrganizationResponse represents an organization the user is a member
+// of. Active marks the one selected in the session.
+type UserOrganizationResponse struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	CNPJ     *string `json:"cnpj,omitempty"`
+	UASG     *string `json:"uasg,omitempty"`
+	Role     string  `json:"role"`
+	Active   bool    `json:"active"`
+	JoinedAt string  `json:"joined_at"`
+}
+
+// SelectOrganizationReq selects the organization the session works on
+type SelectOrganizationReq struct {
+	OrganizationID string `json:"organization_id" validate:"required,uuid"`
+}
+
+// ActiveOrganizationResponse represents the organization the session works on
+type ActiveOrganizationResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
@@ -0,0 +1,92 @@
+package dto
+
+// DeleteAccountReq confirms the deletion of the logged-in user's account
+type DeleteAccountReq struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// AccountDeletionResponse represents an account deletion request
+type AccountDeletionResponse struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	Email        *string `json:"email,omitempty"`
+	Status       string  `json:"status"`
+	RequestedBy  string  `json:"requested_by"`
+	RequestedAt  string  `json:"requested_at"`
+	ScheduledFor string  `json:"scheduled_for"`
+	CancelledAt  *string `json:"cancelled_at,omitempty"`
+	CancelledBy  *string `json:"cancelled_by,omitempty"`
+	CompletedAt  *string `json:"completed_at,omitempty"`
+}
+
+// AccountDeletionListResponse represents a page of account deletion requests
+type AccountDeletionListResponse struct {
+	Data   []AccountDeletionResponse `json:"data"`
+	Total  int64                     `json:"total"`
+	Limit  int32                     `json:"limit"`
+	Offset int32                     `json:"offset"`
+}
+
+// RefreshTokenExport represents a mobile app sign-in, without the token
+type RefreshTokenExport struct {
+	ID        string  `json:"id"`
+	CreatedAt string  `json:"created_at"`
+	ExpiresAt string  `json:"expires_at"`
+	UsedAt    *string `json:"used_at,omitempty"`
+	RevokedAt *string `json:"revoked_at,omitempty"`
+}
+
+// IdentityExport represents an identity provider account linked to the user
+type IdentityExport struct {
+	Provider    string `json:"provider"`
+	Subject     string `json:"subject"`
+	Email       string `json:"email"`
+	CreatedAt   string `json:"created_at"`
+	LastLoginAt string `json:"last_login_at"`
+}
+
+// OrganizationMembershipExport represents an organization the user is a
+// member of
+type OrganizationMembershipExport struct {
+	OrganizationID string `json:"organization_id"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+	JoinedAt       string `json:"joined_at"`
+}
+
+// SearchLogExport represents a catalog search made by the user
+type SearchLogExport struct {
+	ID          string         `json:"id"`
+	Catalog     string         `json:"catalog"`
+	Query       string         `json:"query"`
+	Filters     map[string]any `json:"filters"`
+	ResultCount int64          `json:"result_count"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+// SearchClickExport represents a click of the user on a search result
+type SearchClickExport struct {
+	SearchID  string `json:"search_id"`
+	ItemCode  int32  `json:"item_code"`
+	Position  *int32 `json:"position,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// UserDataExport represents everything stored about a user (LGPD data
+// subject access request)
+type UserDataExport struct {
+	ExportedAt      string                         `json:"exported_at"`
+	Profile         UserProfileResponse            `json:"profile"`
+	Sessions        []SessionResponse              `json:"sessions"`
+	RefreshTokens   []RefreshTokenExport           `json:"refresh_tokens"`
+	ApiKeys         []ApiKeyResponse               `json:"api_keys"`
+	Identities      []IdentityExport               `json:"identities"`
+	Organizations   []OrganizationMembershipExport `json:"organizations"`
+	ItemLists       []ItemListDetailResponse       `json:"item_lists"`
+	SavedSearches   []SavedSearchResponse          `json:"saved_searches"`
+	Notifications   []NotificationResponse         `json:"notifications"`
+	Searches        []SearchLogExport              `json:"searches"`
+	SearchClicks    []SearchClickExport            `json:"search_clicks"`
+	SecurityEvents  []SecurityEventResponse        `json:"security_events"`
+	PendingDeletion *AccountDeletionResponse       `json:"pending_deletion,omitempty"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+// SearchSynonymReq represents the payload to create or replace a synonym group
+type SearchSynonymReq struct {
+	Terms []string `json:"terms" validate:"required,min=2,max=20,dive,required,max=100"`
+}
+
+// SearchSynonymResponse represents a group of equivalent search terms
+type SearchSynonymResponse struct {
+	ID        int64    `json:"id"`
+	Terms     []string `json:"terms"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// SearchSynonymExpandResponse shows how a query is rewritten by the synonym dictionary
+type SearchSynonymExpandResponse struct {
+	Query    string `json:"query"`
+	Expanded string `json:"expanded"`
+}
+
+// SearchClickReq represents a click on a search result
+type SearchClickReq struct {
+	SearchID string `json:"search_id" validate:"required,uuid"`
+	ItemCode int32  `json:"item_code" validate:"required,min=1"`
+	Position *int32 `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// SearchQueryStat represents aggregated analytics for a normalized query
+type SearchQueryStat struct {
+	Catalog           string  `json:"catalog"`
+	Query             string  `json:"query"`
+	Searches          int64   `json:"searches"`
+	SearchesWithClick int64   `json:"searches_with_click"`
+	ClickThroughRate  float64 `json:"click_through_rate"`
+	AvgResults        float64 `json:"avg_results"`
+	AvgLatencyMs      float64 `json:"avg_latency_ms"`
+	MaxLatencyMs      int32   `json:"max_latency_ms"`
+	LastSearchedAt    string  `json:"last_searched_at"`
+}
+
+// SearchReportResponse represents a search analytics report over a date range
+type SearchReportResponse struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	Data []SearchQueryStat `json:"data"`
+}
+
+// SavedSearchReq represents the payload to create or replace a saved search.
+// pdm_code and ncm_code apply to catmat; service_code and status to catser.
+type SavedSearchReq struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Catalog     string  `json:"catalog" validate:"required,oneof=catmat catser"`
+	Query       string  `json:"q" validate:"max=500"`
+	GroupCode   *int16  `json:"group_code,omitempty"`
+	ClassCode   *int32  `json:"class_code,omitempty"`
+	PdmCode     *int32  `json:"pdm_code,omitempty"`
+	NcmCode     *string `json:"ncm_code,omitempty"`
+	ServiceCode *int32  `json:"service_code,omitempty"`
+	Status      *string `json:"status,omitempty"`
+}
+
+// SavedSearchResponse represents a saved search
+type SavedSearchResponse struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Catalog         string  `json:"catalog"`
+	Query           string  `json:"q"`
+	GroupCode       *int16  `json:"group_code,omitempty"`
+	ClassCode       *int32  `json:"class_code,omitempty"`
+	PdmCode         *int32  `json:"pdm_code,omitempty"`
+	NcmCode         *string `json:"ncm_code,omitempty"`
+	ServiceCode     *int32  `json:"service_code,omitempty"`
+	Status          *string `json:"status,omitempty"`
+	LastEvaluatedAt *string `json:"last_evaluated_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+// NotificationResponse represents new items matched by a saved search after an import
+type NotificationResponse struct {
+	ID              int64   `json:"id"`
+	SavedSearchID   *string `json:"saved_search_id,omitempty"`
+	SavedSearchName string  `json:"saved_search_name"`
+	Catalog         string  `json:"catalog"`
+	ItemCodes       []int32 `json:"item_codes"`
+	ItemCount       int     `json:"item_count"`
+	ReadAt          *string `json:"read_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// NotificationListResponse represents a page of notifications
+type NotificationListResponse struct {
+	Data   []NotificationResponse `json:"data"`
+	Unread int64                  `json:"unread"`
+	Limit  int32                  `json:"limit"`
+	Offset int32                  `json:"offset"`
+}
@@ -27,8 +27,211 @@ type ErrorResponse struct {
 
 // UserProfileResponse represents the logged-in user's profile data
 type UserProfileResponse struct {
-	ID        string `json:"id"`
-	UserName  string `json:"user_name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
-}
\ No newline at end of file
+	ID               string   `json:"id"`
+	UserName         string   `json:"user_name"`
+	Email            string   `json:"email"`
+	EmailVerified    bool     `json:"email_verified"`
+	EmailVerifiedAt  *string  `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled bool     `json:"two_factor_enabled"`
+	CreatedAt        string   `json:"created_at"`
+	Roles            []string `json:"roles"`
+}
+
+// TokenReq requests bearer tokens, either with credentials (grant_type
+// "password") or with a refresh token (grant_type "refresh_token")
+type TokenReq struct {
+	GrantType    string `json:"grant_type" validate:"required,oneof=password refresh_token"`
+	Email        string `json:"email" validate:"required_if=GrantType password,omitempty,email"`
+	Password     string `json:"password" validate:"required_if=GrantType password"`
+	RefreshToken string `json:"refresh_token" validate:"required_if=GrantType refresh_token"`
+	// OTPCode is the TOTP or recovery code of accounts with two-factor
+	// authentication (password grant only)
+	OTPCode string `json:"otp_code"`
+}
+
+// RevokeTokenReq represents the request payload to revoke a refresh token
+type RevokeTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenResponse carries a bearer access token and its refresh token
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// AdminUserResponse represents a user as seen by user administration
+type AdminUserResponse struct {
+	ID              string   `json:"id"`
+	UserName        string   `json:"user_name"`
+	Email           string   `json:"email"`
+	EmailVerified   bool     `json:"email_verified"`
+	EmailVerifiedAt *string  `json:"email_verified_at,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+	Roles           []string `json:"roles"`
+}
+
+// AdminUserListResponse represents a page of users
+type AdminUserListResponse struct {
+	Data   []AdminUserResponse `json:"data"`
+	Total  int64               `json:"total"`
+	Limit  int32               `json:"limit"`
+	Offset int32               `json:"offset"`
+}
+
+// UpdateUserRolesReq replaces the roles of a user (admin, manager, buyer, viewer)
+type UpdateUserRolesReq struct {
+	Roles []string `json:"roles" validate:"required"`
+}
+
+// UserRolesResponse represents the roles of a user after an update
+type UserRolesResponse struct {
+	ID    string   `json:"id"`
+	Roles []string `json:"roles"`
+}
+
+// ForgotPasswordReq requests a password reset link
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordReq sets a new password with the token from the reset link
+type ResetPasswordReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// VerifyEmailReq confirms an email address with the token from the verification link
+type VerifyEmailReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// SetEmailVerificationReq marks a user's email as verified or unverified
+type SetEmailVerificationReq struct {
+	Verified *bool `json:"verified" validate:"required"`
+}
+
+// EmailVerificationResponse represents the verification state of a user
+type EmailVerificationResponse struct {
+	ID            string `json:"id"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// SecurityEventResponse represents an entry of the security event log
+type SecurityEventResponse struct {
+	ID        int64          `json:"id"`
+	EventType string         `json:"event_type"`
+	UserID    *string        `json:"user_id,omitempty"`
+	Email     *string        `json:"email,omitempty"`
+	IP        *string        `json:"ip,omitempty"`
+	Details   map[string]any `json:"details"`
+	CreatedAt string         `json:"created_at"`
+}
+
+// SecurityEventListResponse represents a page of security events
+type SecurityEventListResponse struct {
+	Data   []SecurityEventResponse `json:"data"`
+	Total  int64                   `json:"total"`
+	Limit  int32                   `json:"limit"`
+	Offset int32                   `json:"offset"`
+}
+
+// LoginUnlockResponse tells what an admin unlock cleared. IP locks are kept
+// unless ip_unlocked names the address that was cleared.
+type LoginUnlockResponse struct {
+	Message         string `json:"message"`
+	AccountUnlocked bool   `json:"account_unlocked"`
+	IPUnlocked      string `json:"ip_unlocked,omitempty"`
+}
+
+// TwoFactorCodeReq carries a TOTP code or a recovery code
+type TwoFactorCodeReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorStatusResponse tells whether two-factor authentication is on and
+// whether a role of the user requires it
+type TwoFactorStatusResponse struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
+
+// TwoFactorEnrollResponse carries a new TOTP secret; otpauth_uri can be shown
+// as a QR code for authenticator apps
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse lists recovery codes; they are shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorRolesResponse lists the roles that require two-factor authentication
+type TwoFactorRolesResponse struct {
+	Roles []string `json:"roles"`
+}
+
+// CreateApiKeyReq represents the payload to create an API key (scopes:
+// catalog:read, catalog:import). Without expires_in_days the key does not
+// expire.
+type CreateApiKeyReq struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays *int     `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=3650"`
+}
+
+// ApiKeyResponse represents an API key without its secret
+type ApiKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// CreatedApiKeyResponse represents a new API key; the key is shown only once
+type CreatedApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+// SessionResponse represents a web session of the user
+type SessionResponse struct {
+	ID         string `json:"id"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Current    bool   `json:"current"`
+}
+
+// UpdateUserReq represents the payload to update the logged-in user's
+// profile; omitted fields are kept. Changing the email requires the current
+// password.
+type UpdateUserReq struct {
+	UserName        *string `json:"user_name,omitempty" validate:"omitempty,min=3,max=50,username"`
+	Email           *string `json:"email,omitempty" validate:"omitempty,email"`
+	CurrentPassword string  `json:"current_password,omitempty"`
+}
+
+// ChangePasswordReq represents the payload to change the logged-in user's password
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,nefield=CurrentPassword"`
+}
+
+// OIDCProviderResponse represents an identity provider users can sign in with
+type OIDCProviderResponse struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	LoginURL string `json:"login_url"`
+}
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer is the development mailer: each message is written to Dir as
+// an .eml file (openable by any mail client) and logged. With an empty Dir
+// the message is only logged, body included.
+type FileMailer struct {
+	dir  string
+	from string
+	log  *zap.Logger
+	seq  atomic.Int64
+}
+
+func NewFileMailer(dir, from string, log *zap.Logger) (*FileMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("mailer: create %s: %w", dir, err)
+		}
+	}
+	if from == "" {
+		from = "FlyTwo Pro <nao-responda@localhost>"
+	}
+	return &FileMailer{dir: dir, from: from, log: log}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := format(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	if m.dir == "" {
+		m.log.Info("email (not sent)",
+			zap.String("to", msg.To),
+			zap.String("subject", msg.Subject),
+			zap.String("body", msg.Body))
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%04d.eml", now.UTC().Format("20060102T150405.000"), m.seq.Add(1))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return fmt.Errorf("mailer: write %s: %w", path, err)
+	}
+	m.log.Info("email written",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("path", path))
+	return nil
+}
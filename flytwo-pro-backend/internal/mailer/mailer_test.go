@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFormat(t *testing.T) {
+	data, err := format("FlyTwo <no-reply@example.com>", Message{
+		To:      "user@example.com",
+		Subject: "Redefinição de senha",
+		Body:    "Olá, use o código abaixo.",
+	}, time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Redefinição de senha", subject)
+	assert.Equal(t, "quoted-printable", parsed.Header.Get("Content-Transfer-Encoding"))
+}
+
+func TestFormat_RejectsHeaderInjection(t *testing.T) {
+	_, err := format("a@example.com", Message{To: "user@example.com", Subject: "hi\r\nBcc: x@example.com"}, time.Now())
+	assert.Error(t, err)
+
+	_, err = format("a@example.com", Message{To: "not an address", Subject: "hi"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestFileMailer_WritesEml(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewFileMailer(dir, "", zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, m.Send(context.Background(), Message{To: "user@example.com", Subject: "Teste", Body: "corpo"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "corpo")
+}
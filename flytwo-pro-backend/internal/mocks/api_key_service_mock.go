@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+)
+
+type MockApiKeyService struct {
+	mock.Mock
+}
+
+func (m *MockApiKeyService) CreateApiKey(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*services.CreatedApiKey, error) {
+	args := m.Called(ctx, userID, name, scopes, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CreatedApiKey), args.Error(1)
+}
+
+func (m *MockApiKeyService) ListApiKeys(ctx context.Context, userID uuid.UUID) ([]pgstore.ApiKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.ApiKey), args.Error(1)
+}
+
+func (m *MockApiKeyService) RevokeApiKey(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockApiKeyService) AuthenticateApiKey(ctx context.Context, secret string) (*pgstore.ApiKey, error) {
+	args := m.Called(ctx, secret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ApiKey), args.Error(1)
+}
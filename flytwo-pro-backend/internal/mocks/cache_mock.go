@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/cache"
+)
+
+type MockCache struct {
+	mock.Mock
+}
+
+func (m *MockCache) Health() cache.Health {
+	args := m.Called()
+	return args.Get(0).(cache.Health)
+}
+
+func (m *MockCache) Stats() cache.Stats {
+	args := m.Called()
+	return args.Get(0).(cache.Stats)
+}
+
+func (m *MockCache) Purge(ctx context.Context, prefix string) (int64, error) {
+	args := m.Called(ctx, prefix)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCache) Flush(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
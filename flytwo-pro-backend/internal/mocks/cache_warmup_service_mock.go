@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+)
+
+type MockCacheWarmupService struct {
+	mock.Mock
+}
+
+func (m *MockCacheWarmupService) Trigger(reason string) bool {
+	args := m.Called(reason)
+	return args.Bool(0)
+}
+
+func (m *MockCacheWarmupService) Status() (bool, *services.WarmupReport) {
+	args := m.Called()
+	if args.Get(1) == nil {
+		return args.Bool(0), nil
+	}
+	return args.Bool(0), args.Get(1).(*services.WarmupReport)
+}
@@ -53,3 +53,19 @@ func (m *MockCatalogImportService) GetCatalogStats(ctx context.Context) (*dto.Ca
 	}
 	return args.Get(0).(*dto.CatalogStatsResponse), args.Error(1)
 }
+
+func (m *MockCatalogImportService) GetCatalogExtendedStats(ctx context.Context, historyDays int) (*dto.CatalogExtendedStatsResponse, error) {
+	args := m.Called(ctx, historyDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CatalogExtendedStatsResponse), args.Error(1)
+}
+
+func (m *MockCatalogImportService) GetCatalogClassStats(ctx context.Context, catalog string, groupCode int16) (*dto.CatalogClassStatsResponse, error) {
+	args := m.Called(ctx, catalog, groupCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CatalogClassStatsResponse), args.Error(1)
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) SetEmailVerified(ctx context.Context, userID uuid.UUID, verified bool) error {
+	args := m.Called(ctx, userID, verified)
+	return args.Error(0)
+}
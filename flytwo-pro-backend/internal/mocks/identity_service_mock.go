@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/oidc"
+	"gobid/internal/services"
+)
+
+type MockIdentityService struct {
+	mock.Mock
+}
+
+func (m *MockIdentityService) LoginWithIdentity(ctx context.Context, provider string, claims oidc.Claims) (*services.IdentityLogin, error) {
+	args := m.Called(ctx, provider, claims)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.IdentityLogin), args.Error(1)
+}
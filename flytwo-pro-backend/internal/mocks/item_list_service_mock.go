@@ -0,0 +1,90 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+)
+
+type MockItemListService struct {
+	mock.Mock
+}
+
+func (m *MockItemListService) ListItemLists(ctx context.Context, userID uuid.UUID) ([]pgstore.ListItemListsRow, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.ListItemListsRow), args.Error(1)
+}
+
+func (m *MockItemListService) GetItemList(ctx context.Context, userID, id uuid.UUID) (*services.ItemListDetail, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ItemListDetail), args.Error(1)
+}
+
+func (m *MockItemListService) CreateItemList(ctx context.Context, userID uuid.UUID, input services.ItemListInput) (*pgstore.ItemList, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ItemList), args.Error(1)
+}
+
+func (m *MockItemListService) UpdateItemList(ctx context.Context, userID, id uuid.UUID, input services.ItemListInput) (*pgstore.ItemList, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ItemList), args.Error(1)
+}
+
+func (m *MockItemListService) DeleteItemList(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockItemListService) DuplicateItemList(ctx context.Context, userID, id uuid.UUID, name string) (*pgstore.ItemList, error) {
+	args := m.Called(ctx, userID, id, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ItemList), args.Error(1)
+}
+
+func (m *MockItemListService) AddItemListLine(ctx context.Context, userID, listID uuid.UUID, input services.ItemListLineInput) (*pgstore.ItemListLine, error) {
+	args := m.Called(ctx, userID, listID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ItemListLine), args.Error(1)
+}
+
+func (m *MockItemListService) UpdateItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64, input services.ItemListLineInput) (*pgstore.ItemListLine, error) {
+	args := m.Called(ctx, userID, listID, lineID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.ItemListLine), args.Error(1)
+}
+
+func (m *MockItemListService) DeleteItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64) error {
+	args := m.Called(ctx, userID, listID, lineID)
+	return args.Error(0)
+}
+
+func (m *MockItemListService) ExportItemList(ctx context.Context, userID, id uuid.UUID, w io.Writer) (*services.ItemListDetail, error) {
+	args := m.Called(ctx, userID, id, w)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ItemListDetail), args.Error(1)
+}
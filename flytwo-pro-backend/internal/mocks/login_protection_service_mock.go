@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/store/pgstore"
+)
+
+type MockLoginProtectionService struct {
+	mock.Mock
+}
+
+func (m *MockLoginProtectionService) Check(ctx context.Context, ip, email string) time.Duration {
+	args := m.Called(ctx, ip, email)
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockLoginProtectionService) RecordFailure(ctx context.Context, ip, email string) {
+	m.Called(ctx, ip, email)
+}
+
+func (m *MockLoginProtectionService) RecordSuccess(ctx context.Context, ip, email string, userID uuid.UUID) {
+	m.Called(ctx, ip, email, userID)
+}
+
+func (m *MockLoginProtectionService) Unlock(ctx context.Context, userID, adminID uuid.UUID, ip string) error {
+	args := m.Called(ctx, userID, adminID, ip)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) ListSecurityEvents(ctx context.Context, eventType string, userID uuid.UUID, limit, offset int32) ([]pgstore.SecurityEvent, int64, error) {
+	args := m.Called(ctx, eventType, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]pgstore.SecurityEvent), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+)
+
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationService) ListOrganizations(ctx context.Context, query string, limit, offset int32) ([]pgstore.ListOrganizationsRow, int64, error) {
+	args := m.Called(ctx, query, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]pgstore.ListOrganizationsRow), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOrganizationService) GetOrganization(ctx context.Context, id uuid.UUID) (*pgstore.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) CreateOrganization(ctx context.Context, input services.OrganizationInput) (*pgstore.Organization, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) UpdateOrganization(ctx context.Context, id uuid.UUID, input services.OrganizationInput) (*pgstore.Organization, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationService) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationService) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserOrganizationsRow, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.ListUserOrganizationsRow), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetMembership(ctx context.Context, orgID, userID uuid.UUID) (*pgstore.GetOrganizationMembershipRow, error) {
+	args := m.Called(ctx, orgID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.GetOrganizationMembershipRow), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]pgstore.ListOrganizationMembersRow, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.ListOrganizationMembersRow), args.Error(1)
+}
+
+func (m *MockOrganizationService) SetMember(ctx context.Context, orgID, userID uuid.UUID, role string) (*pgstore.OrganizationMember, error) {
+	args := m.Called(ctx, orgID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationService) AddMemberByEmail(ctx context.Context, orgID uuid.UUID, email, role string) (*pgstore.OrganizationMember, error) {
+	args := m.Called(ctx, orgID, email, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordResetService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetService) RequestReset(ctx context.Context, ip, email string) time.Duration {
+	args := m.Called(ctx, ip, email)
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockPasswordResetService) AllowResetAttempt(ctx context.Context, ip string) time.Duration {
+	args := m.Called(ctx, ip)
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockPasswordResetService) ResetPassword(ctx context.Context, token, password string) (uuid.UUID, error) {
+	args := m.Called(ctx, token, password)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+)
+
+type MockPrivacyService struct {
+	mock.Mock
+}
+
+func (m *MockPrivacyService) ExportUserData(ctx context.Context, userID uuid.UUID) (*services.UserData, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UserData), args.Error(1)
+}
+
+func (m *MockPrivacyService) RequestDeletion(ctx context.Context, userID, requestedBy uuid.UUID, immediate bool) (*pgstore.AccountDeletion, error) {
+	args := m.Called(ctx, userID, requestedBy, immediate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.AccountDeletion), args.Error(1)
+}
+
+func (m *MockPrivacyService) CancelDeletion(ctx context.Context, userID, cancelledBy uuid.UUID) (*pgstore.AccountDeletion, error) {
+	args := m.Called(ctx, userID, cancelledBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.AccountDeletion), args.Error(1)
+}
+
+func (m *MockPrivacyService) GetPendingDeletion(ctx context.Context, userID uuid.UUID) (*pgstore.AccountDeletion, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.AccountDeletion), args.Error(1)
+}
+
+func (m *MockPrivacyService) ListDeletions(ctx context.Context, status string, limit, offset int32) ([]pgstore.ListAccountDeletionsRow, int64, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]pgstore.ListAccountDeletionsRow), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/store/pgstore"
+)
+
+type MockRoleService struct {
+	mock.Mock
+}
+
+func (m *MockRoleService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoleService) SetUserRoles(ctx context.Context, userID uuid.UUID, roles []string) ([]string, error) {
+	args := m.Called(ctx, userID, roles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoleService) ListUsers(ctx context.Context, limit, offset int32) ([]pgstore.ListUsersWithRolesRow, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]pgstore.ListUsersWithRolesRow), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRoleService) BootstrapAdmin(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/services"
+	"gobid/internal/store/pgstore"
+)
+
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) ListSavedSearches(ctx context.Context, userID uuid.UUID) ([]pgstore.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) CreateSavedSearch(ctx context.Context, userID uuid.UUID, input services.SavedSearchInput) (*pgstore.SavedSearch, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) UpdateSavedSearch(ctx context.Context, userID, id uuid.UUID, input services.SavedSearchInput) (*pgstore.SavedSearch, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) EvaluateCatalog(ctx context.Context, catalog string) (int, error) {
+	args := m.Called(ctx, catalog)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSavedSearchService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]pgstore.Notification, int64, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]pgstore.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSavedSearchService) MarkNotificationRead(ctx context.Context, userID uuid.UUID, id int64) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/dto"
+	"gobid/internal/services"
+)
+
+type MockSearchAnalyticsService struct {
+	mock.Mock
+}
+
+func (m *MockSearchAnalyticsService) RecordSearch(entry services.SearchLogEntry) {
+	m.Called(entry)
+}
+
+func (m *MockSearchAnalyticsService) RecordClick(ctx context.Context, click services.SearchClick) error {
+	args := m.Called(ctx, click)
+	return args.Error(0)
+}
+
+func (m *MockSearchAnalyticsService) TopQueries(ctx context.Context, params services.SearchReportParams) ([]dto.SearchQueryStat, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SearchQueryStat), args.Error(1)
+}
+
+func (m *MockSearchAnalyticsService) ZeroResultQueries(ctx context.Context, params services.SearchReportParams) ([]dto.SearchQueryStat, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SearchQueryStat), args.Error(1)
+}
+
+func (m *MockSearchAnalyticsService) SlowestQueries(ctx context.Context, params services.SearchReportParams) ([]dto.SearchQueryStat, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SearchQueryStat), args.Error(1)
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/store/pgstore"
+)
+
+type MockSearchSynonymService struct {
+	mock.Mock
+}
+
+func (m *MockSearchSynonymService) ListSynonyms(ctx context.Context) ([]pgstore.SearchSynonym, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.SearchSynonym), args.Error(1)
+}
+
+func (m *MockSearchSynonymService) CreateSynonym(ctx context.Context, terms []string) (*pgstore.SearchSynonym, error) {
+	args := m.Called(ctx, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.SearchSynonym), args.Error(1)
+}
+
+func (m *MockSearchSynonymService) UpdateSynonym(ctx context.Context, id int64, terms []string) (*pgstore.SearchSynonym, error) {
+	args := m.Called(ctx, id, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pgstore.SearchSynonym), args.Error(1)
+}
+
+func (m *MockSearchSynonymService) DeleteSynonym(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSearchSynonymService) ExpandQuery(ctx context.Context, query string) string {
+	args := m.Called(ctx, query)
+	return args.String(0)
+}
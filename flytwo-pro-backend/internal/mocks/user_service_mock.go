@@ -33,3 +33,18 @@ func (m *MockUserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*p
 	}
 	return args.Get(0).(*pgstore.User), args.Error(1)
 }
+
+// UpdateUser mocks the UpdateUser method
+func (m *MockUserService) UpdateUser(ctx context.Context, userID uuid.UUID, userName, email *string) (*pgstore.User, bool, error) {
+	args := m.Called(ctx, userID, userName, email)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*pgstore.User), args.Bool(1), args.Error(2)
+}
+
+// ChangePassword mocks the ChangePassword method
+func (m *MockUserService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	args := m.Called(ctx, userID, currentPassword, newPassword)
+	return args.Error(0)
+}
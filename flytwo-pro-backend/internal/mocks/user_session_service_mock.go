@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"gobid/internal/store/pgstore"
+)
+
+type MockUserSessionService struct {
+	mock.Mock
+}
+
+func (m *MockUserSessionService) RecordLogin(ctx context.Context, token string, userID uuid.UUID, ip, userAgent string) error {
+	args := m.Called(ctx, token, userID, ip, userAgent)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionService) Touch(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionService) EndSession(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserSessionsRow, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pgstore.ListUserSessionsRow), args.Error(1)
+}
+
+func (m *MockUserSessionService) RevokeSession(ctx context.Context, userID, id uuid.UUID) (string, error) {
+	args := m.Called(ctx, userID, id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserSessionService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, keepToken string) (int64, error) {
+	args := m.Called(ctx, userID, keepToken)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserSessionService) ForceLogout(ctx context.Context, userID, adminID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID, adminID)
+	return args.Get(0).(int64), args.Error(1)
+}
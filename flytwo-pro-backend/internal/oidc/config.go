@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// LoadConfigs reads the providers from the environment:
+//
+//	GOBID_OIDC_PROVIDERS=keycloak,govbr
+//	GOBID_OIDC_CALLBACK_BASE_URL=https://api.example.com/api/v1/auth/oidc
+//	GOBID_OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET (optional),
+//	_LABEL, _SCOPES, _TRUST_EMAIL and _CALLBACK_URL (all optional)
+//
+// NAME is the provider name in upper case with "-" replaced by "_". The
+// callback URL defaults to <base>/<name>/callback. No providers configured
+// returns nil.
+func LoadConfigs(getenv func(string) string) ([]Config, error) {
+	var configs []Config
+	for _, name := range strings.Split(getenv("GOBID_OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !providerName.MatchString(name) {
+			return nil, fmt.Errorf("oidc: invalid provider name %q", name)
+		}
+		if slices.ContainsFunc(configs, func(c Config) bool { return c.Name == name }) {
+			return nil, fmt.Errorf("oidc: provider %q listed twice", name)
+		}
+
+		prefix := "GOBID_OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg := Config{
+			Name:         name,
+			Label:        getenv(prefix + "LABEL"),
+			Issuer:       getenv(prefix + "ISSUER"),
+			ClientID:     getenv(prefix + "CLIENT_ID"),
+			ClientSecret: getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  getenv(prefix + "CALLBACK_URL"),
+			Scopes:       strings.FieldsFunc(getenv(prefix+"SCOPES"), func(r rune) bool { return r == ' ' || r == ',' }),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("oidc: provider %q needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+		}
+		if cfg.RedirectURL == "" {
+			base := strings.TrimSuffix(getenv("GOBID_OIDC_CALLBACK_BASE_URL"), "/")
+			if base == "" {
+				return nil, fmt.Errorf("oidc: provider %q needs GOBID_OIDC_CALLBACK_BASE_URL or %sCALLBACK_URL", name, prefix)
+			}
+			cfg.RedirectURL = base + "/" + name + "/callback"
+		}
+		if len(cfg.Scopes) > 0 && !slices.Contains(cfg.Scopes, "openid") {
+			cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+		}
+		if v := getenv(prefix + "TRUST_EMAIL"); v != "" {
+			trust, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("oidc: %sTRUST_EMAIL: %w", prefix, err)
+			}
+			cfg.TrustEmail = trust
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// Registry holds the configured providers in configuration order. A nil
+// Registry has no providers.
+type Registry struct {
+	providers []*Provider
+}
+
+func NewRegistry(providers ...*Provider) *Registry {
+	return &Registry{providers: providers}
+}
+
+// Get returns the provider with the given name.
+func (r *Registry) Get(name string) (*Provider, bool) {
+	if r == nil {
+		return nil, false
+	}
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Providers lists the providers in configuration order.
+func (r *Registry) Providers() []*Provider {
+	if r == nil {
+		return nil
+	}
+	return r.providers
+}
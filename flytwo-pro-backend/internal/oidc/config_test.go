@@ -0,0 +1,92 @@
+package oidc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gobid/internal/oidc"
+)
+
+func envFunc(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestLoadConfigs(t *testing.T) {
+	configs, err := oidc.LoadConfigs(envFunc(map[string]string{
+		"GOBID_OIDC_PROVIDERS":              " Keycloak , gov-br ",
+		"GOBID_OIDC_CALLBACK_BASE_URL":      "https://api.example.com/api/v1/auth/oidc/",
+		"GOBID_OIDC_KEYCLOAK_ISSUER":        "https://sso.example.com/realms/gov",
+		"GOBID_OIDC_KEYCLOAK_CLIENT_ID":     "gobid",
+		"GOBID_OIDC_KEYCLOAK_CLIENT_SECRET": "secret",
+		"GOBID_OIDC_KEYCLOAK_LABEL":         "Login corporativo",
+		"GOBID_OIDC_GOV_BR_ISSUER":          "https://sso.acesso.gov.br/",
+		"GOBID_OIDC_GOV_BR_CLIENT_ID":       "gobid-govbr",
+		"GOBID_OIDC_GOV_BR_SCOPES":          "email,profile govbr_confiabilidades",
+		"GOBID_OIDC_GOV_BR_TRUST_EMAIL":     "true",
+		"GOBID_OIDC_GOV_BR_CALLBACK_URL":    "https://gobid.example.com/govbr/callback",
+	}))
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+
+	assert.Equal(t, oidc.Config{
+		Name:         "keycloak",
+		Label:        "Login corporativo",
+		Issuer:       "https://sso.example.com/realms/gov",
+		ClientID:     "gobid",
+		ClientSecret: "secret",
+		RedirectURL:  "https://api.example.com/api/v1/auth/oidc/keycloak/callback",
+		Scopes:       []string{},
+	}, configs[0])
+
+	assert.Equal(t, "gov-br", configs[1].Name)
+	assert.Equal(t, "https://gobid.example.com/govbr/callback", configs[1].RedirectURL)
+	assert.Equal(t, []string{"openid", "email", "profile", "govbr_confiabilidades"}, configs[1].Scopes)
+	assert.True(t, configs[1].TrustEmail)
+}
+
+func TestLoadConfigs_None(t *testing.T) {
+	configs, err := oidc.LoadConfigs(envFunc(nil))
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+}
+
+func TestLoadConfigs_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "missing issuer", env: map[string]string{
+			"GOBID_OIDC_PROVIDERS":         "kc",
+			"GOBID_OIDC_CALLBACK_BASE_URL": "https://api.example.com/oidc",
+			"GOBID_OIDC_KC_CLIENT_ID":      "gobid",
+		}},
+		{name: "missing redirect", env: map[string]string{
+			"GOBID_OIDC_PROVIDERS":    "kc",
+			"GOBID_OIDC_KC_ISSUER":    "https://sso.example.com",
+			"GOBID_OIDC_KC_CLIENT_ID": "gobid",
+		}},
+		{name: "invalid name", env: map[string]string{"GOBID_OIDC_PROVIDERS": "kc/1"}},
+		{name: "duplicated", env: map[string]string{
+			"GOBID_OIDC_PROVIDERS":         "kc,KC",
+			"GOBID_OIDC_CALLBACK_BASE_URL": "https://api.example.com/oidc",
+			"GOBID_OIDC_KC_ISSUER":         "https://sso.example.com",
+			"GOBID_OIDC_KC_CLIENT_ID":      "gobid",
+		}},
+		{name: "invalid trust email", env: map[string]string{
+			"GOBID_OIDC_PROVIDERS":         "kc",
+			"GOBID_OIDC_CALLBACK_BASE_URL": "https://api.example.com/oidc",
+			"GOBID_OIDC_KC_ISSUER":         "https://sso.example.com",
+			"GOBID_OIDC_KC_CLIENT_ID":      "gobid",
+			"GOBID_OIDC_KC_TRUST_EMAIL":    "sim",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := oidc.LoadConfigs(envFunc(tt.env))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gobid/internal/oidc"
+	"gobid/internal/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:3080/api/v1/auth/oidc/test/callback"
+
+// login runs the browser part of the flow against the mock provider and
+// returns the code and state sent to the callback.
+func login(t *testing.T, srv *oidctest.Server, p *oidc.Provider, state, nonce, verifier string) (string, string) {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+
+	callback, err := url.Parse(srv.Authorize(t, authURL))
+	require.NoError(t, err)
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	srv := oidctest.NewServer(t)
+	p := oidc.NewProvider(srv.Config("test", redirectURL), nil)
+
+	authURL, err := p.AuthCodeURL(context.Background(), "st", "nc", "verifier")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, srv.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, oidctest.ClientID, q.Get("client_id"))
+	assert.Equal(t, redirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, "st", q.Get("state"))
+	assert.Equal(t, "nc", q.Get("nonce"))
+	assert.Equal(t, oidc.CodeChallenge("verifier"), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	srv := oidctest.NewServer(t)
+	p := oidc.NewProvider(srv.Config("test", redirectURL), nil)
+
+	code, state := login(t, srv, p, "st", "nc", "verifier")
+	assert.Equal(t, "st", state)
+
+	claims, err := p.Exchange(context.Background(), code, "verifier", "nc")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "Test User", claims.Name)
+
+	// A code is redeemed once.
+	_, err = p.Exchange(context.Background(), code, "verifier", "nc")
+	assert.ErrorIs(t, err, oidc.ErrTokenExchange)
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	srv := oidctest.NewServer(t)
+	p := oidc.NewProvider(srv.Config("test", redirectURL), nil)
+
+	code, _ := login(t, srv, p, "st", "nc", "verifier")
+
+	_, err := p.Exchange(context.Background(), code, "another-verifier", "nc")
+	assert.ErrorIs(t, err, oidc.ErrTokenExchange)
+	assert.Contains(t, err.Error(), "invalid_grant")
+}
+
+func TestProvider_Exchange_WrongClientSecret(t *testing.T) {
+	srv := oidctest.NewServer(t)
+	cfg := srv.Config("test", redirectURL)
+	cfg.ClientSecret = "wrong"
+	p := oidc.NewProvider(cfg, nil)
+
+	code, _ := login(t, srv, p, "st", "nc", "verifier")
+
+	_, err := p.Exchange(context.Background(), code, "verifier", "nc")
+	assert.ErrorIs(t, err, oidc.ErrTokenExchange)
+}
+
+func TestProvider_Exchange_RejectsIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		nonce  string
+		mutate func(jwt.MapClaims)
+		forge  bool
+	}{
+		{name: "wrong nonce", nonce: "other"},
+		{name: "expired", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", mutate: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "wrong audience", mutate: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "wrong issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "issued in the future", mutate: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{name: "missing subject", mutate: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "azp of another client", mutate: func(c jwt.MapClaims) {
+			c["aud"] = []string{oidctest.ClientID, "someone-else"}
+			c["azp"] = "someone-else"
+		}},
+		{name: "signed by another key", forge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := oidctest.NewServer(t)
+			srv.Mutate = tt.mutate
+			if tt.forge {
+				srv.ForgeKey = oidctest.NewServer(t).Key
+			}
+			p := oidc.NewProvider(srv.Config("test", redirectURL), nil)
+
+			code, _ := login(t, srv, p, "st", "nc", "verifier")
+			nonce := "nc"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			_, err := p.Exchange(context.Background(), code, "verifier", nonce)
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProvider_EmailVerified(t *testing.T) {
+	unverified := false
+
+	tests := []struct {
+		name       string
+		user       oidctest.User
+		trustEmail bool
+		want       bool
+	}{
+		{name: "claim absent", user: oidctest.User{Subject: "s", Email: "a@example.com"}, want: false},
+		{name: "claim absent, trusted", user: oidctest.User{Subject: "s", Email: "a@example.com"}, trustEmail: true, want: true},
+		{name: "claim false, trusted", user: oidctest.User{Subject: "s", Email: "a@example.com", EmailVerified: &unverified}, trustEmail: true, want: false},
+		{name: "no email, trusted", user: oidctest.User{Subject: "s"}, trustEmail: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := oidctest.NewServer(t)
+			srv.SetUser(tt.user)
+			cfg := srv.Config("test", redirectURL)
+			cfg.TrustEmail = tt.trustEmail
+			p := oidc.NewProvider(cfg, nil)
+
+			code, _ := login(t, srv, p, "st", "nc", "verifier")
+			claims, err := p.Exchange(context.Background(), code, "verifier", "nc")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, claims.EmailVerified)
+		})
+	}
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	srv := oidctest.NewServer(t)
+	cfg := srv.Config("test", redirectURL)
+	cfg.Issuer = srv.URL + "/realms/other"
+	p := oidc.NewProvider(cfg, nil)
+
+	_, err := p.AuthCodeURL(context.Background(), "st", "nc", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops expired keys.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps counters in the process. Each instance counts on its
+// own, so with several replicas an attacker gets one budget per replica.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryEntry struct {
+	failures    int64
+	expiresAt   time.Time
+	lockedUntil time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]*memoryEntry{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Name() string { return "memory" }
+
+func (s *MemoryStore) Fail(_ context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	e := s.entries[key]
+	if e == nil {
+		e = &memoryEntry{}
+		s.entries[key] = e
+	}
+	if !now.Before(e.expiresAt) {
+		e.failures = 0
+	}
+	e.failures++
+	e.expiresAt = now.Add(window)
+	return e.failures, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil {
+		e = &memoryEntry{}
+		s.entries[key] = e
+	}
+	e.lockedUntil = s.now().Add(d)
+	return nil
+}
+
+func (s *MemoryStore) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil {
+		return 0, nil
+	}
+	if d := e.lockedUntil.Sub(s.now()); d > 0 {
+		return d, nil
+	}
+	return 0, nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil {
+		return 0, nil
+	}
+	delete(s.entries, key)
+	if !s.now().Before(e.expiresAt) {
+		return 0, nil
+	}
+	return e.failures, nil
+}
+
+// sweep drops keys whose counter and lock both expired. Callers hold mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) && !now.Before(e.lockedUntil) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig configures a RedisStore. Timeout bounds every command (default
+// 500ms); Prefix namespaces the keys (default "gobid:ratelimit:").
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+	Prefix   string
+}
+
+// RedisStore shares counters and locks between every API instance.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(cfg RedisConfig) *RedisStore {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = "gobid:ratelimit:"
+	}
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  2 * timeout,
+			ReadTimeout:  timeout,
+			WriteTimeout: timeout,
+		}),
+		prefix: prefix,
+	}
+}
+
+func (s *RedisStore) Name() string { return "redis" }
+
+func (s *RedisStore) failuresKey(key string) string { return s.prefix + "fail:" + key }
+func (s *RedisStore) lockKey(key string) string     { return s.prefix + "lock:" + key }
+
+func (s *RedisStore) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	k := s.failuresKey(key)
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, k)
+		pipe.PExpire(ctx, k, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	return s.client.Set(ctx, s.lockKey(key), 1, d).Err()
+}
+
+func (s *RedisStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, s.lockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL is negative when the key is missing or has no expiry
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) (int64, error) {
+	var get *redis.StringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, s.failuresKey(key))
+		pipe.Del(ctx, s.failuresKey(key), s.lockKey(key))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	failures, err := get.Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return failures, err
+}
+
+// Ping checks that Redis answers.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close releases the Redis connections.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
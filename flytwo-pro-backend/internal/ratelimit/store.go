@@ -0,0 +1,25 @@
+// Package ratelimit keeps failed-attempt counters and temporary locks, in
+// Redis when several instances must agree or in memory otherwise.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store counts failures per key and holds timed locks. Counters expire after
+// a quiet window; locks expire on their own.
+type Store interface {
+	// Name identifies the backend ("redis", "memory").
+	Name() string
+	// Fail adds a failure to key and returns the new count. The count is
+	// forgotten after window without failures.
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Lock blocks key for d, replacing any lock in place.
+	Lock(ctx context.Context, key string, d time.Duration) error
+	// LockedFor returns how long key stays locked, or zero.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset clears the failures and the lock of key and returns the failure
+	// count it had.
+	Reset(ctx context.Context, key string) (int64, error)
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock moves time forward for a backend under test.
+type clock func(d time.Duration)
+
+func testStore(t *testing.T, s Store, advance clock) {
+	ctx := context.Background()
+
+	n, err := s.Fail(ctx, "acct:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = s.Fail(ctx, "acct:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// keys are independent
+	n, err = s.Fail(ctx, "acct:b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	locked, err := s.LockedFor(ctx, "acct:a")
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+
+	require.NoError(t, s.Lock(ctx, "acct:a", 30*time.Second))
+	locked, err = s.LockedFor(ctx, "acct:a")
+	require.NoError(t, err)
+	assert.InDelta(t, 30*time.Second, locked, float64(time.Second))
+
+	advance(31 * time.Second)
+	locked, err = s.LockedFor(ctx, "acct:a")
+	require.NoError(t, err)
+	assert.Zero(t, locked, "lock expires")
+
+	// the counter survives the lock and is forgotten after the window
+	n, err = s.Fail(ctx, "acct:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	advance(61 * time.Second)
+	n, err = s.Fail(ctx, "acct:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	require.NoError(t, s.Lock(ctx, "acct:a", time.Minute))
+	prev, err := s.Reset(ctx, "acct:a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), prev)
+	locked, err = s.LockedFor(ctx, "acct:a")
+	require.NoError(t, err)
+	assert.Zero(t, locked, "reset removes the lock")
+
+	prev, err = s.Reset(ctx, "acct:unknown")
+	require.NoError(t, err)
+	assert.Zero(t, prev)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	testStore(t, s, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestMemoryStore_SweepsExpiredKeys(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = s.Fail(ctx, "old", time.Minute)
+	now = now.Add(2 * time.Minute)
+	_, _ = s.Fail(ctx, "new", time.Minute)
+
+	assert.NotContains(t, s.entries, "old")
+	assert.Contains(t, s.entries, "new")
+}
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := NewRedisStore(RedisConfig{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = s.Close() })
+
+	testStore(t, s, mr.FastForward)
+}
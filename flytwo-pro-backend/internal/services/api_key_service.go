@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+// API key scopes. A key reaches only the endpoints of its scopes, and only
+// when its owner's roles allow them too.
+const (
+	ScopeCatalogRead   = "catalog:read"
+	ScopeCatalogImport = "catalog:import"
+)
+
+// ValidScopes lists every API key scope.
+var ValidScopes = []string{ScopeCatalogRead, ScopeCatalogImport}
+
+// ApiKeyPrefix starts every API key, so leaked keys are easy to spot in
+// logs and secret scanners.
+const ApiKeyPrefix = "gbk_"
+
+// MaxApiKeysPerUser bounds the active (not revoked, not expired) keys of a
+// user.
+const MaxApiKeysPerUser = 25
+
+var (
+	ErrInvalidApiKey  = errors.New("invalid, revoked or expired API key")
+	ErrApiKeyNotFound = errors.New("API key not found")
+	ErrApiKeyLimit    = errors.New("API key limit reached")
+	ErrInvalidScope   = errors.New("invalid scope")
+)
+
+// CreatedApiKey is a new key together with its secret, which is not stored
+// and cannot be shown again.
+type CreatedApiKey struct {
+	ApiKey pgstore.ApiKey
+	Secret string
+}
+
+// ApiKeyService manages the API keys users create for scripts. Keys are
+// stored as SHA-256 hashes.
+type ApiKeyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+}
+
+func NewApiKeyService(pool *pgxpool.Pool) ApiKeyService {
+	return ApiKeyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// CreateApiKey creates a key for userID. expiresAt may be nil for a key
+// that does not expire.
+func (s *ApiKeyService) CreateApiKey(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*CreatedApiKey, error) {
+	scopes, err := cleanScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.queries.CountActiveApiKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+	if active >= MaxApiKeysPerUser {
+		return nil, ErrApiKeyLimit
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret := ApiKeyPrefix + token
+
+	expires := pgtype.Timestamptz{}
+	if expiresAt != nil {
+		expires = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	key, err := s.queries.CreateApiKey(ctx, pgstore.CreateApiKeyParams{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    secret[:len(ApiKeyPrefix)+8],
+		KeyHash:   hashToken(secret),
+		Scopes:    scopes,
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.log.Info("API key created",
+		zap.String("user_id", userID.String()),
+		zap.String("key_id", key.ID.String()),
+		zap.Strings("scopes", scopes))
+
+	return &CreatedApiKey{ApiKey: key, Secret: secret}, nil
+}
+
+// ListApiKeys returns the keys of a user, revoked ones included, newest
+// first.
+func (s *ApiKeyService) ListApiKeys(ctx context.Context, userID uuid.UUID) ([]pgstore.ApiKey, error) {
+	keys, err := s.queries.ListApiKeysByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	if keys == nil {
+		keys = []pgstore.ApiKey{}
+	}
+	return keys, nil
+}
+
+// RevokeApiKey revokes a key of userID. Keys of other users and keys
+// already revoked return ErrApiKeyNotFound.
+func (s *ApiKeyService) RevokeApiKey(ctx context.Context, userID, id uuid.UUID) error {
+	rows, err := s.queries.RevokeApiKey(ctx, pgstore.RevokeApiKeyParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if rows == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	s.log.Info("API key revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("key_id", id.String()))
+	return nil
+}
+
+// AuthenticateApiKey returns the key matching secret if it is still valid
+// and records its use. last_used_at is written at most once a minute per
+// key, so busy scripts do not turn every request into an UPDATE.
+func (s *ApiKeyService) AuthenticateApiKey(ctx context.Context, secret string) (*pgstore.ApiKey, error) {
+	if !strings.HasPrefix(secret, ApiKeyPrefix) {
+		return nil, ErrInvalidApiKey
+	}
+
+	key, err := s.queries.GetApiKeyByHash(ctx, hashToken(secret))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidApiKey
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if !apiKeyUsable(key, time.Now()) {
+		return nil, ErrInvalidApiKey
+	}
+
+	if err := s.queries.TouchApiKey(ctx, key.ID); err != nil {
+		s.log.Warn("failed to record API key use",
+			zap.String("key_id", key.ID.String()),
+			zap.Error(err))
+	}
+	return &key, nil
+}
+
+// apiKeyUsable reports whether key is neither revoked nor expired at now.
+func apiKeyUsable(key pgstore.ApiKey, now time.Time) bool {
+	if key.RevokedAt.Valid {
+		return false
+	}
+	return !key.ExpiresAt.Valid || key.ExpiresAt.Time.After(now)
+}
+
+// cleanScopes validates scopes and returns them sorted without duplicates.
+func cleanScopes(scopes []string) ([]string, error) {
+	cleaned := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(ValidScopes, scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+		cleaned = append(cleaned, scope)
+	}
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	slices.Sort(cleaned)
+	return slices.Compact(cleaned), nil
+}
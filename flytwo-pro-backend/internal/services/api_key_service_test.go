@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gobid/internal/store/pgstore"
+)
+
+func TestCleanScopes(t *testing.T) {
+	scopes, err := cleanScopes([]string{" Catalog:Read", "catalog:import", "catalog:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"catalog:import", "catalog:read"}, scopes)
+
+	_, err = cleanScopes(nil)
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = cleanScopes([]string{"catalog:write"})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+}
+
+func TestApiKeyUsable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, apiKeyUsable(pgstore.ApiKey{}, now))
+	assert.True(t, apiKeyUsable(pgstore.ApiKey{
+		ExpiresAt: pgtype.Timestamptz{Time: now.Add(time.Minute), Valid: true},
+	}, now))
+	assert.False(t, apiKeyUsable(pgstore.ApiKey{
+		ExpiresAt: pgtype.Timestamptz{Time: now, Valid: true},
+	}, now))
+	assert.False(t, apiKeyUsable(pgstore.ApiKey{
+		RevokedAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+	}, now))
+}
+
+func TestAuthenticateApiKey_RejectsForeignTokens(t *testing.T) {
+	s := NewApiKeyService(nil)
+
+	// refresh tokens and other secrets never reach the database
+	_, err := s.AuthenticateApiKey(context.Background(), "not-an-api-key")
+
+	assert.ErrorIs(t, err, ErrInvalidApiKey)
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+)
+
+// WarmupQuery is a search pre-loaded into the cache.
+type WarmupQuery struct {
+	Catalog string // "catmat" or "catser"
+	Query   string
+}
+
+// CacheWarmupConfig selects the searches to warm and how hard to push.
+type CacheWarmupConfig struct {
+	// Queries are always warmed, before the popular ones.
+	Queries []WarmupQuery
+	// TopQueries is how many of the most frequent searches of the last
+	// Window are warmed (default window 7 days). Zero disables it.
+	TopQueries int32
+	Window     time.Duration
+	// Concurrency bounds the searches running at once (default 2), so a
+	// warm-up does not starve live traffic.
+	Concurrency int
+	// Timeout bounds a whole run (default 5m).
+	Timeout time.Duration
+}
+
+// CatalogWarmer is the part of the catalog service a warm-up drives. Its
+// search and stats methods fill the cache on a miss.
+type CatalogWarmer interface {
+	SearchCatmat(ctx context.Context, params CatmatSearchParams) (*SearchResult[CatmatSearchItem], error)
+	SearchCatser(ctx context.Context, params CatserSearchParams) (*SearchResult[CatserSearchItem], error)
+	GetCatalogStats(ctx context.Context) (*dto.CatalogStatsResponse, error)
+}
+
+// PopularQuerySource reports the most frequent searches.
+type PopularQuerySource interface {
+	TopQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error)
+}
+
+// WarmupReport describes one warm-up run.
+type WarmupReport struct {
+	Reason     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Queries    int
+	Warmed     int
+	Failed     int
+	Stats      bool // GetCatalogStats was warmed
+}
+
+// CacheWarmupService pre-populates the first page of popular searches and
+// the catalog stats, after startup and after imports bump the generation of
+// a catalog. Requests arriving while a run is pending are coalesced.
+type CacheWarmupService struct {
+	catalog   CatalogWarmer
+	analytics PopularQuerySource
+	log       *zap.Logger
+	cfg       CacheWarmupConfig
+
+	requests chan string
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	running bool
+	last    *WarmupReport
+}
+
+// NewCacheWarmupService builds the service; analytics may be nil when only
+// configured queries are warmed.
+func NewCacheWarmupService(catalog CatalogWarmer, analytics PopularQuerySource, cfg CacheWarmupConfig) CacheWarmupService {
+	if cfg.Window <= 0 {
+		cfg.Window = 7 * 24 * time.Hour
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 2
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+
+	return CacheWarmupService{
+		catalog:   catalog,
+		analytics: analytics,
+		log:       logger.Log,
+		cfg:       cfg,
+		requests:  make(chan string, 1),
+	}
+}
+
+// Start launches the loop that runs requested warm-ups.
+func (s *CacheWarmupService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.loop(ctx)
+}
+
+// Close stops the loop, cancelling a run in progress.
+func (s *CacheWarmupService) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Trigger requests a warm-up without waiting for it. It returns false when
+// one is already pending, in which case that run covers this request.
+func (s *CacheWarmupService) Trigger(reason string) bool {
+	select {
+	case s.requests <- reason:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status reports whether a run is in progress and the last finished one.
+func (s *CacheWarmupService) Status() (running bool, last *WarmupReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running, s.last
+}
+
+func (s *CacheWarmupService) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case reason := <-s.requests:
+			s.Run(ctx, reason)
+		}
+	}
+}
+
+// Run warms the cache once and records the report.
+func (s *CacheWarmupService) Run(ctx context.Context, reason string) WarmupReport {
+	s.mu.Lock()
+	s.running = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	report := WarmupReport{Reason: reason, StartedAt: time.Now()}
+	queries := s.queries(ctx)
+	report.Queries = len(queries)
+
+	var warmed, failed atomic.Int64
+	var g errgroup.Group
+	g.SetLimit(s.cfg.Concurrency)
+
+	g.Go(func() error {
+		if _, err := s.catalog.GetCatalogStats(ctx); err != nil {
+			s.log.Warn("cache warm-up: failed to load catalog stats", zap.Error(err))
+			return nil
+		}
+		report.Stats = true
+		return nil
+	})
+	for _, q := range queries {
+		g.Go(func() error {
+			if err := s.warm(ctx, q); err != nil {
+				failed.Add(1)
+				s.log.Warn("cache warm-up: search failed",
+					zap.String("catalog", q.Catalog),
+					zap.String("query", q.Query),
+					zap.Error(err))
+				return nil
+			}
+			warmed.Add(1)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	report.Warmed = int(warmed.Load())
+	report.Failed = int(failed.Load())
+	report.FinishedAt = time.Now()
+
+	s.mu.Lock()
+	s.running = false
+	s.last = &report
+	s.mu.Unlock()
+
+	s.log.Info("cache warm-up finished",
+		zap.String("reason", reason),
+		zap.Int("queries", report.Queries),
+		zap.Int("warmed", report.Warmed),
+		zap.Int("failed", report.Failed),
+		zap.Duration("duration", report.FinishedAt.Sub(report.StartedAt)))
+	return report
+}
+
+// queries returns the configured searches followed by the popular ones,
+// without duplicates. The text is kept as given because it is part of the
+// cache key; the search log already stores it normalized.
+func (s *CacheWarmupService) queries(ctx context.Context) []WarmupQuery {
+	seen := make(map[WarmupQuery]bool)
+	var queries []WarmupQuery
+	add := func(q WarmupQuery) {
+		q.Query = strings.TrimSpace(q.Query)
+		key := WarmupQuery{Catalog: q.Catalog, Query: normalizeSearchText(q.Query)}
+		if key.Query == "" || seen[key] || (q.Catalog != "catmat" && q.Catalog != "catser") {
+			return
+		}
+		seen[key] = true
+		queries = append(queries, q)
+	}
+
+	for _, q := range s.cfg.Queries {
+		add(q)
+	}
+
+	if s.analytics != nil && s.cfg.TopQueries > 0 {
+		now := time.Now()
+		top, err := s.analytics.TopQueries(ctx, SearchReportParams{
+			From:  now.Add(-s.cfg.Window),
+			To:    now,
+			Limit: s.cfg.TopQueries,
+		})
+		if err != nil {
+			s.log.Warn("cache warm-up: failed to load popular searches", zap.Error(err))
+		}
+		for _, stat := range top {
+			add(WarmupQuery{Catalog: stat.Catalog, Query: stat.Query})
+		}
+	}
+	return queries
+}
+
+// warm runs the first page of a search with the default limit, the request
+// most users make.
+func (s *CacheWarmupService) warm(ctx context.Context, q WarmupQuery) error {
+	var err error
+	if q.Catalog == "catmat" {
+		_, err = s.catalog.SearchCatmat(ctx, CatmatSearchParams{Query: q.Query})
+	} else {
+		_, err = s.catalog.SearchCatser(ctx, CatserSearchParams{Query: q.Query})
+	}
+	return err
+}
+
+// ParseWarmupQueries parses "catalog:query" entries separated by semicolons,
+// e.g. "catmat:papel a4;catser:limpeza".
+func ParseWarmupQueries(s string) ([]WarmupQuery, error) {
+	var queries []WarmupQuery
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		catalog, query, ok := strings.Cut(entry, ":")
+		catalog = strings.ToLower(strings.TrimSpace(catalog))
+		query = strings.TrimSpace(query)
+		if !ok || query == "" || (catalog != "catmat" && catalog != "catser") {
+			return nil, fmt.Errorf("invalid warm-up query %q", entry)
+		}
+		queries = append(queries, WarmupQuery{Catalog: catalog, Query: query})
+	}
+	return queries, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"gobid/internal/dto"
+)
+
+// fakeWarmer records the searches run by a warm-up and how many overlapped.
+type fakeWarmer struct {
+	mu       sync.Mutex
+	searches []WarmupQuery
+	stats    int
+	fail     map[string]bool
+
+	active  atomic.Int32
+	maxSeen atomic.Int32
+	delay   time.Duration
+}
+
+func (f *fakeWarmer) run(q WarmupQuery) error {
+	n := f.active.Add(1)
+	defer f.active.Add(-1)
+	for {
+		seen := f.maxSeen.Load()
+		if n <= seen || f.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.searches = append(f.searches, q)
+	if f.fail[q.Query] {
+		return errors.New("search failed")
+	}
+	return nil
+}
+
+func (f *fakeWarmer) SearchCatmat(ctx context.Context, params CatmatSearchParams) (*SearchResult[CatmatSearchItem], error) {
+	if params.Limit != 0 || params.Offset != 0 {
+		return nil, errors.New("warm-up must use the default page")
+	}
+	return &SearchResult[CatmatSearchItem]{}, f.run(WarmupQuery{Catalog: "catmat", Query: params.Query})
+}
+
+func (f *fakeWarmer) SearchCatser(ctx context.Context, params CatserSearchParams) (*SearchResult[CatserSearchItem], error) {
+	return &SearchResult[CatserSearchItem]{}, f.run(WarmupQuery{Catalog: "catser", Query: params.Query})
+}
+
+func (f *fakeWarmer) GetCatalogStats(ctx context.Context) (*dto.CatalogStatsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats++
+	return &dto.CatalogStatsResponse{}, nil
+}
+
+type fakePopularQueries struct {
+	params SearchReportParams
+	stats  []dto.SearchQueryStat
+}
+
+func (f *fakePopularQueries) TopQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error) {
+	f.params = params
+	return f.stats, nil
+}
+
+func newTestWarmupService(warmer *fakeWarmer, popular PopularQuerySource, cfg CacheWarmupConfig) *CacheWarmupService {
+	s := NewCacheWarmupService(warmer, popular, cfg)
+	s.log = zap.NewNop()
+	return &s
+}
+
+func TestCacheWarmup_ConfiguredThenPopular(t *testing.T) {
+	warmer := &fakeWarmer{fail: map[string]bool{"cadeira": true}}
+	popular := &fakePopularQueries{stats: []dto.SearchQueryStat{
+		{Catalog: "catmat", Query: "papel a4"}, // already configured
+		{Catalog: "catser", Query: "limpeza"},
+		{Catalog: "catmat", Query: "cadeira"},
+	}}
+	s := newTestWarmupService(warmer, popular, CacheWarmupConfig{
+		Queries:     []WarmupQuery{{Catalog: "catmat", Query: "Papel A4"}},
+		TopQueries:  10,
+		Concurrency: 1,
+	})
+
+	report := s.Run(context.Background(), "startup")
+
+	assert.Equal(t, []WarmupQuery{
+		{Catalog: "catmat", Query: "Papel A4"},
+		{Catalog: "catser", Query: "limpeza"},
+		{Catalog: "catmat", Query: "cadeira"},
+	}, warmer.searches)
+	assert.Equal(t, 1, warmer.stats)
+	assert.Equal(t, int32(10), popular.params.Limit)
+	assert.Equal(t, 7*24*time.Hour, popular.params.To.Sub(popular.params.From))
+
+	assert.Equal(t, "startup", report.Reason)
+	assert.Equal(t, 3, report.Queries)
+	assert.Equal(t, 2, report.Warmed)
+	assert.Equal(t, 1, report.Failed)
+	assert.True(t, report.Stats)
+
+	running, last := s.Status()
+	assert.False(t, running)
+	require.NotNil(t, last)
+	assert.Equal(t, report, *last)
+}
+
+func TestCacheWarmup_BoundedConcurrency(t *testing.T) {
+	warmer := &fakeWarmer{delay: 20 * time.Millisecond}
+	var queries []WarmupQuery
+	for _, q := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		queries = append(queries, WarmupQuery{Catalog: "catser", Query: q})
+	}
+	s := newTestWarmupService(warmer, nil, CacheWarmupConfig{Queries: queries, Concurrency: 3})
+
+	report := s.Run(context.Background(), "test")
+
+	assert.Equal(t, 8, report.Warmed)
+	assert.LessOrEqual(t, warmer.maxSeen.Load(), int32(3))
+	assert.Greater(t, warmer.maxSeen.Load(), int32(1))
+}
+
+func TestCacheWarmup_TriggerCoalesces(t *testing.T) {
+	warmer := &fakeWarmer{}
+	s := newTestWarmupService(warmer, nil, CacheWarmupConfig{
+		Queries: []WarmupQuery{{Catalog: "catmat", Query: "papel"}},
+	})
+
+	assert.True(t, s.Trigger("import catmat"))
+	assert.False(t, s.Trigger("import catser"))
+
+	s.Start(context.Background())
+	defer s.Close()
+
+	require.Eventually(t, func() bool {
+		_, last := s.Status()
+		return last != nil
+	}, time.Second, 10*time.Millisecond)
+	_, last := s.Status()
+	assert.Equal(t, "import catmat", last.Reason)
+	assert.Equal(t, 1, warmer.stats)
+}
+
+func TestParseWarmupQueries(t *testing.T) {
+	queries, err := ParseWarmupQueries(" catmat:papel a4 ; CATSER:limpeza predial;; ")
+	require.NoError(t, err)
+	assert.Equal(t, []WarmupQuery{
+		{Catalog: "catmat", Query: "papel a4"},
+		{Catalog: "catser", Query: "limpeza predial"},
+	}, queries)
+
+	queries, err = ParseWarmupQueries("")
+	require.NoError(t, err)
+	assert.Empty(t, queries)
+
+	_, err = ParseWarmupQueries("papel a4")
+	assert.Error(t, err)
+	_, err = ParseWarmupQueries("catalogo:papel")
+	assert.Error(t, err)
+}
@@ -7,12 +7,14 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/xuri/excelize/v2"
 	"go.uber.org/zap"
 
+	"gobid/internal/cache"
 	"gobid/internal/dto"
 	"gobid/internal/logger"
 	"gobid/internal/store/pgstore"
@@ -40,6 +42,12 @@ type SearchResult[T any] struct {
 	Offset int32 `json:"offset"`
 }
 
+// IsEmpty marks pages without items so the cache keeps them for the shorter
+// negative TTL.
+func (r *SearchResult[T]) IsEmpty() bool {
+	return len(r.Data) == 0
+}
+
 // CatmatSearchParams holds parameters for CATMAT FTS search.
 type CatmatSearchParams struct {
 	Query     string  `json:"q"`
@@ -93,28 +101,84 @@ type CatserSearchItem struct {
 
 // CatalogImportService handles bulk imports for CATMAT and CATSER.
 type CatalogImportService struct {
-	pool    *pgxpool.Pool
-	queries *pgstore.Queries
-	log     *zap.Logger
-	cache   SearchCache
+	pool     *pgxpool.Pool
+	queries  *pgstore.Queries
+	log      *zap.Logger
+	cache    SearchCache
+	synonyms QueryExpander
 }
 
 // SearchCache defines the minimal cache interface used by the catalog service.
+// Keys carry the generation of the catalogs they depend on; Invalidate bumps a
+// generation so that older entries are no longer reachable.
 type SearchCache interface {
-	Get(ctx context.Context, key string, dest any) (bool, error)
-	Set(ctx context.Context, key string, value any) error
+	GetOrLoad(ctx context.Context, key string, dest any, loader cache.Loader) error
+	Generation(ctx context.Context, namespace string) int64
+	Invalidate(ctx context.Context, namespace string) error
+}
+
+// loadCached returns the cached value of key, running load on a miss. Without
+// a cache it always loads.
+func loadCached[T any](ctx context.Context, c SearchCache, key string, load func(context.Context) (*T, error)) (*T, error) {
+	if c == nil {
+		return load(ctx)
+	}
+	var v T
+	err := c.GetOrLoad(ctx, key, &v, func(ctx context.Context) (any, error) {
+		return load(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
 }
 
-func NewCatalogImportService(pool *pgxpool.Pool, cache SearchCache) CatalogImportService {
+func NewCatalogImportService(pool *pgxpool.Pool, cache SearchCache, synonyms QueryExpander) CatalogImportService {
 	return CatalogImportService{
-		pool:    pool,
-		queries: pgstore.New(pool),
-		log:     logger.Log,
-		cache:   cache,
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		log:      logger.Log,
+		cache:    cache,
+		synonyms: synonyms,
+	}
+}
+
+// expandQuery applies the synonym dictionary to a search term. The expanded
+// text feeds both the FTS function and the count query, and is part of the
+// cache key.
+func (s *CatalogImportService) expandQuery(ctx context.Context, query string) string {
+	if s.synonyms == nil || query == "" {
+		return query
+	}
+	return s.synonyms.ExpandQuery(ctx, query)
+}
+
+// versionedKey appends the current generation of each namespace to key.
+func (s *CatalogImportService) versionedKey(ctx context.Context, key string, namespaces ...string) string {
+	if s.cache == nil {
+		return key
+	}
+	versions := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		versions[i] = fmt.Sprintf("%s.%d", ns, s.cache.Generation(ctx, ns))
+	}
+	return key + "|v=" + strings.Join(versions, ",")
+}
+
+// invalidateCatalogCache drops every cached search and stats entry of a
+// catalog after its items changed.
+func (s *CatalogImportService) invalidateCatalogCache(ctx context.Context, catalog string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Invalidate(ctx, catalog); err != nil {
+		s.log.Warn("failed to invalidate catalog cache", zap.String("catalog", catalog), zap.Error(err))
 	}
 }
 
 func (s *CatalogImportService) ImportCatmat(ctx context.Context, reader io.Reader) (*ImportResult, error) {
+	startedAt := time.Now()
+
 	f, err := openExcelFromReader(reader)
 	if err != nil {
 		return nil, err
@@ -187,6 +251,10 @@ func (s *CatalogImportService) ImportCatmat(ctx context.Context, reader io.Reade
 		result.RowsSaved++
 	}
 
+	if result.RowsSaved > 0 {
+		s.invalidateCatalogCache(ctx, "catmat")
+	}
+
 	if err := rows.Error(); err != nil {
 		return result, err
 	}
@@ -196,10 +264,14 @@ func (s *CatalogImportService) ImportCatmat(ctx context.Context, reader io.Reade
 		return result, fmt.Errorf("cabeçalho CATMAT não encontrado")
 	}
 
+	s.recordImport(ctx, "catmat", startedAt, result)
+
 	return result, nil
 }
 
 func (s *CatalogImportService) ImportCatser(ctx context.Context, reader io.Reader) (*ImportResult, error) {
+	startedAt := time.Now()
+
 	f, err := openExcelFromReader(reader)
 	if err != nil {
 		return nil, err
@@ -272,6 +344,10 @@ func (s *CatalogImportService) ImportCatser(ctx context.Context, reader io.Reade
 		result.RowsSaved++
 	}
 
+	if result.RowsSaved > 0 {
+		s.invalidateCatalogCache(ctx, "catser")
+	}
+
 	if err := rows.Error(); err != nil {
 		return result, err
 	}
@@ -281,6 +357,8 @@ func (s *CatalogImportService) ImportCatser(ctx context.Context, reader io.Reade
 		return result, fmt.Errorf("cabeçalho CATSER não encontrado")
 	}
 
+	s.recordImport(ctx, "catser", startedAt, result)
+
 	return result, nil
 }
 
@@ -527,8 +605,9 @@ func (s *CatalogImportService) SearchCatmat(ctx context.Context, params CatmatSe
 	var groupCodeParam *int16
 	var classCodeParam, pdmCodeParam *int32
 
-	if params.Query != "" {
-		queryParam = &params.Query
+	query := s.expandQuery(ctx, params.Query)
+	if query != "" {
+		queryParam = &query
 	}
 	if params.GroupCode != nil {
 		groupCodeParam = params.GroupCode
@@ -543,22 +622,16 @@ func (s *CatalogImportService) SearchCatmat(ctx context.Context, params CatmatSe
 		ncmCodeParam = params.NcmCode
 	}
 
-	cacheKey := fmt.Sprintf("catmat:q=%s|g=%v|c=%v|p=%v|n=%v|l=%d|o=%d",
-		params.Query, params.GroupCode, params.ClassCode, params.PdmCode, params.NcmCode, limit, offset)
+	cacheKey := s.versionedKey(ctx, fmt.Sprintf("catmat:q=%s|g=%v|c=%v|p=%v|n=%v|l=%d|o=%d",
+		query, params.GroupCode, params.ClassCode, params.PdmCode, params.NcmCode, limit, offset), "catmat")
 
-	if s.cache != nil {
-		var cached SearchResult[CatmatSearchItem]
-		ok, cacheErr := s.cache.Get(ctx, cacheKey, &cached)
-		if cacheErr == nil && ok {
-			return &cached, nil
-		}
-		if cacheErr != nil {
-			s.log.Debug("catmat cache get error", zap.Error(cacheErr))
-		} else {
-			s.log.Debug("catmat cache miss", zap.String("key", cacheKey))
-		}
-	}
+	return loadCached(ctx, s.cache, cacheKey, func(ctx context.Context) (*SearchResult[CatmatSearchItem], error) {
+		return s.queryCatmat(ctx, queryParam, groupCodeParam, classCodeParam, pdmCodeParam, ncmCodeParam, limit, offset)
+	})
+}
 
+// queryCatmat runs the CATMAT search and its total count on the database.
+func (s *CatalogImportService) queryCatmat(ctx context.Context, queryParam *string, groupCodeParam *int16, classCodeParam, pdmCodeParam *int32, ncmCodeParam *string, limit, offset int32) (*SearchResult[CatmatSearchItem], error) {
 	// Execute the search function
 	rows, err := s.pool.Query(ctx, `
 		SELECT id, group_code, group_name, class_code, class_name,
@@ -619,12 +692,6 @@ func (s *CatalogImportService) SearchCatmat(ctx context.Context, params CatmatSe
 		Offset: offset,
 	}
 
-	if s.cache != nil {
-		if setErr := s.cache.Set(ctx, cacheKey, result); setErr != nil {
-			s.log.Debug("catmat cache set error", zap.Error(setErr))
-		}
-	}
-
 	return result, nil
 }
 
@@ -649,8 +716,9 @@ func (s *CatalogImportService) SearchCatser(ctx context.Context, params CatserSe
 	var groupCodeParam *int16
 	var classCodeParam, serviceCodeParam *int32
 
-	if params.Query != "" {
-		queryParam = &params.Query
+	query := s.expandQuery(ctx, params.Query)
+	if query != "" {
+		queryParam = &query
 	}
 	if params.GroupCode != nil {
 		groupCodeParam = params.GroupCode
@@ -665,22 +733,16 @@ func (s *CatalogImportService) SearchCatser(ctx context.Context, params CatserSe
 		statusParam = params.Status
 	}
 
-	cacheKey := fmt.Sprintf("catser:q=%s|g=%v|c=%v|s=%v|st=%v|l=%d|o=%d",
-		params.Query, params.GroupCode, params.ClassCode, params.ServiceCode, params.Status, limit, offset)
+	cacheKey := s.versionedKey(ctx, fmt.Sprintf("catser:q=%s|g=%v|c=%v|s=%v|st=%v|l=%d|o=%d",
+		query, params.GroupCode, params.ClassCode, params.ServiceCode, params.Status, limit, offset), "catser")
 
-	if s.cache != nil {
-		var cached SearchResult[CatserSearchItem]
-		ok, cacheErr := s.cache.Get(ctx, cacheKey, &cached)
-		if cacheErr == nil && ok {
-			return &cached, nil
-		}
-		if cacheErr != nil {
-			s.log.Debug("catser cache get error", zap.Error(cacheErr))
-		} else {
-			s.log.Debug("catser cache miss", zap.String("key", cacheKey))
-		}
-	}
+	return loadCached(ctx, s.cache, cacheKey, func(ctx context.Context) (*SearchResult[CatserSearchItem], error) {
+		return s.queryCatser(ctx, queryParam, groupCodeParam, classCodeParam, serviceCodeParam, statusParam, limit, offset)
+	})
+}
 
+// queryCatser runs the CATSER search and its total count on the database.
+func (s *CatalogImportService) queryCatser(ctx context.Context, queryParam *string, groupCodeParam *int16, classCodeParam, serviceCodeParam *int32, statusParam *string, limit, offset int32) (*SearchResult[CatserSearchItem], error) {
 	// Execute the search function
 	rows, err := s.pool.Query(ctx, `
 		SELECT id, material_service_type, group_code, group_name, class_code,
@@ -738,27 +800,16 @@ func (s *CatalogImportService) SearchCatser(ctx context.Context, params CatserSe
 		Offset: offset,
 	}
 
-	if s.cache != nil {
-		if setErr := s.cache.Set(ctx, cacheKey, result); setErr != nil {
-			s.log.Debug("catser cache set error", zap.Error(setErr))
-		}
-	}
-
 	return result, nil
 }
 
 // GetCatalogStats returns statistics for both CATMAT and CATSER catalogs.
 func (s *CatalogImportService) GetCatalogStats(ctx context.Context) (*dto.CatalogStatsResponse, error) {
-	const statsCacheKey = "catalog:stats"
-
-	if s.cache != nil {
-		var cached dto.CatalogStatsResponse
-		if ok, err := s.cache.Get(ctx, statsCacheKey, &cached); err == nil && ok {
-			s.log.Debug("catalog stats cache hit", zap.String("key", statsCacheKey))
-			return &cached, nil
-		}
-	}
+	statsCacheKey := s.versionedKey(ctx, "catalog:stats", "catmat", "catser")
+	return loadCached(ctx, s.cache, statsCacheKey, s.queryCatalogStats)
+}
 
+func (s *CatalogImportService) queryCatalogStats(ctx context.Context) (*dto.CatalogStatsResponse, error) {
 	response := &dto.CatalogStatsResponse{
 		CatmatByGroup:  []dto.GroupCount{},
 		CatserByGroup:  []dto.GroupCount{},
@@ -842,13 +893,5 @@ func (s *CatalogImportService) GetCatalogStats(ctx context.Context) (*dto.Catalo
 		}
 	}
 
-	if s.cache != nil {
-		if err := s.cache.Set(ctx, statsCacheKey, response); err != nil {
-			s.log.Debug("catalog stats cache set error", zap.Error(err))
-		} else {
-			s.log.Debug("catalog stats cache set", zap.String("key", statsCacheKey))
-		}
-	}
-
 	return response, nil
 }
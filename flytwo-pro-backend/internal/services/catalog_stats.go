@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+)
+
+// recordImport stores a finished import in the import history. Failures are
+// logged only: the items were already saved.
+func (s *CatalogImportService) recordImport(ctx context.Context, catalog string, startedAt time.Time, result *ImportResult) {
+	if _, err := s.queries.InsertCatalogImport(ctx, pgstore.InsertCatalogImportParams{
+		Catalog:     catalog,
+		RowsRead:    int32(result.RowsRead),
+		RowsSaved:   int32(result.RowsSaved),
+		RowsSkipped: int32(result.RowsSkipped),
+		StartedAt:   startedAt,
+	}); err != nil {
+		s.log.Error("failed to record catalog import", zap.String("catalog", catalog), zap.Error(err))
+	}
+}
+
+// GetCatalogExtendedStats returns coverage, last import and the import history
+// of the last historyDays days. The queries run concurrently.
+func (s *CatalogImportService) GetCatalogExtendedStats(ctx context.Context, historyDays int) (*dto.CatalogExtendedStatsResponse, error) {
+	cacheKey := s.versionedKey(ctx, fmt.Sprintf("catalog:stats:extended:d=%d", historyDays), "catmat", "catser")
+	return loadCached(ctx, s.cache, cacheKey, func(ctx context.Context) (*dto.CatalogExtendedStatsResponse, error) {
+		return s.queryCatalogExtendedStats(ctx, historyDays)
+	})
+}
+
+func (s *CatalogImportService) queryCatalogExtendedStats(ctx context.Context, historyDays int) (*dto.CatalogExtendedStatsResponse, error) {
+	var (
+		catmat  pgstore.CatmatCoverageRow
+		catser  pgstore.CatserCoverageRow
+		last    []pgstore.CatalogImport
+		history []pgstore.CatalogImport
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		catmat, err = s.queries.CatmatCoverage(gctx)
+		return wrapErr(err, "failed to get catmat coverage")
+	})
+	g.Go(func() (err error) {
+		catser, err = s.queries.CatserCoverage(gctx)
+		return wrapErr(err, "failed to get catser coverage")
+	})
+	g.Go(func() (err error) {
+		last, err = s.queries.LastCatalogImports(gctx)
+		return wrapErr(err, "failed to get last catalog imports")
+	})
+	g.Go(func() (err error) {
+		history, err = s.queries.CatalogImportHistory(gctx, time.Now().AddDate(0, 0, -historyDays))
+		return wrapErr(err, "failed to get catalog import history")
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	withNcm := catmat.WithNcm
+	ncmShare := percentOf(catmat.WithNcm, catmat.Total)
+	response := &dto.CatalogExtendedStatsResponse{
+		Catmat: dto.CatalogCoverage{
+			Total:                catmat.Total,
+			Groups:               catmat.Groups,
+			Classes:              catmat.Classes,
+			WithNcm:              &withNcm,
+			NcmSharePct:          &ncmShare,
+			WithEmbedding:        catmat.WithEmbedding,
+			EmbeddingCoveragePct: percentOf(catmat.WithEmbedding, catmat.Total),
+		},
+		Catser: dto.CatalogCoverage{
+			Total:                catser.Total,
+			Groups:               catser.Groups,
+			Classes:              catser.Classes,
+			WithEmbedding:        catser.WithEmbedding,
+			EmbeddingCoveragePct: percentOf(catser.WithEmbedding, catser.Total),
+		},
+		History: make([]dto.CatalogImportSummary, len(history)),
+	}
+
+	for i := range last {
+		summary := toCatalogImportSummary(last[i])
+		switch last[i].Catalog {
+		case "catmat":
+			response.Catmat.LastImport = &summary
+		case "catser":
+			response.Catser.LastImport = &summary
+		}
+	}
+	for i := range history {
+		response.History[i] = toCatalogImportSummary(history[i])
+	}
+
+	return response, nil
+}
+
+// GetCatalogClassStats returns the item count per class of a group.
+func (s *CatalogImportService) GetCatalogClassStats(ctx context.Context, catalog string, groupCode int16) (*dto.CatalogClassStatsResponse, error) {
+	cacheKey := s.versionedKey(ctx, fmt.Sprintf("catalog:stats:classes:%s:g=%d", catalog, groupCode), catalog)
+	return loadCached(ctx, s.cache, cacheKey, func(ctx context.Context) (*dto.CatalogClassStatsResponse, error) {
+		return s.queryCatalogClassStats(ctx, catalog, groupCode)
+	})
+}
+
+func (s *CatalogImportService) queryCatalogClassStats(ctx context.Context, catalog string, groupCode int16) (*dto.CatalogClassStatsResponse, error) {
+	response := &dto.CatalogClassStatsResponse{
+		Catalog:   catalog,
+		GroupCode: groupCode,
+		Classes:   []dto.ClassCount{},
+	}
+
+	switch catalog {
+	case "catmat":
+		rows, err := s.queries.CatmatCountByClass(ctx, groupCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get catmat classes: %w", err)
+		}
+		for _, row := range rows {
+			response.Classes = append(response.Classes, dto.ClassCount{ClassCode: row.ClassCode, ClassName: row.ClassName, Count: row.Count})
+		}
+	case "catser":
+		rows, err := s.queries.CatserCountByClass(ctx, groupCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get catser classes: %w", err)
+		}
+		for _, row := range rows {
+			response.Classes = append(response.Classes, dto.ClassCount{ClassCode: row.ClassCode, ClassName: row.ClassName, Count: row.Count})
+		}
+	default:
+		return nil, fmt.Errorf("unknown catalog %q", catalog)
+	}
+
+	for _, class := range response.Classes {
+		response.Total += class.Count
+	}
+
+	return response, nil
+}
+
+func toCatalogImportSummary(imp pgstore.CatalogImport) dto.CatalogImportSummary {
+	return dto.CatalogImportSummary{
+		Catalog:     imp.Catalog,
+		RowsRead:    imp.RowsRead,
+		RowsSaved:   imp.RowsSaved,
+		RowsSkipped: imp.RowsSkipped,
+		ItemTotal:   imp.ItemTotal,
+		StartedAt:   imp.StartedAt.Format(time.RFC3339),
+		FinishedAt:  imp.FinishedAt.Format(time.RFC3339),
+		DurationMs:  imp.FinishedAt.Sub(imp.StartedAt).Milliseconds(),
+	}
+}
+
+// percentOf returns part/total as a percentage rounded to two decimals.
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+func wrapErr(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
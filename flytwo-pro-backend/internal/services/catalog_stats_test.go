@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"gobid/internal/cache"
+	"gobid/internal/dto"
+	"gobid/internal/store/pgstore"
+)
+
+func TestPercentOf(t *testing.T) {
+	assert.Equal(t, 0.0, percentOf(0, 0))
+	assert.Equal(t, 25.0, percentOf(1, 4))
+	assert.Equal(t, 33.33, percentOf(1, 3))
+	assert.Equal(t, 100.0, percentOf(7, 7))
+}
+
+func TestToCatalogImportSummary(t *testing.T) {
+	started := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	summary := toCatalogImportSummary(pgstore.CatalogImport{
+		Catalog:    "catser",
+		RowsRead:   10,
+		RowsSaved:  9,
+		ItemTotal:  500,
+		StartedAt:  started,
+		FinishedAt: started.Add(1500 * time.Millisecond),
+	})
+
+	assert.Equal(t, "catser", summary.Catalog)
+	assert.Equal(t, int64(1500), summary.DurationMs)
+	assert.Equal(t, "2025-03-01T10:00:00Z", summary.StartedAt)
+	assert.Equal(t, int64(500), summary.ItemTotal)
+}
+
+type generationCache struct {
+	gens    map[string]int64
+	entries map[string][]byte
+	loads   int
+}
+
+func (c *generationCache) GetOrLoad(ctx context.Context, key string, dest any, loader cache.Loader) error {
+	if b, ok := c.entries[key]; ok {
+		return json.Unmarshal(b, dest)
+	}
+	c.loads++
+	v, err := loader(ctx)
+	if err != nil {
+		return err
+	}
+	b, _ := json.Marshal(v)
+	c.entries[key] = b
+	return json.Unmarshal(b, dest)
+}
+
+func (c *generationCache) Generation(_ context.Context, ns string) int64 {
+	return c.gens[ns]
+}
+func (c *generationCache) Invalidate(_ context.Context, ns string) error {
+	c.gens[ns]++
+	return nil
+}
+
+func TestVersionedKey(t *testing.T) {
+	ctx := context.Background()
+	s := &CatalogImportService{cache: &generationCache{gens: map[string]int64{}, entries: map[string][]byte{}}, log: zap.NewNop()}
+
+	before := s.versionedKey(ctx, "catalog:stats", "catmat", "catser")
+	assert.Equal(t, "catalog:stats|v=catmat.0,catser.0", before)
+
+	s.invalidateCatalogCache(ctx, "catser")
+	assert.Equal(t, "catalog:stats|v=catmat.0,catser.1", s.versionedKey(ctx, "catalog:stats", "catmat", "catser"))
+	assert.Equal(t, "catmat:q=papel|v=catmat.0", s.versionedKey(ctx, "catmat:q=papel", "catmat"))
+
+	noCache := &CatalogImportService{}
+	assert.Equal(t, "catalog:stats", noCache.versionedKey(ctx, "catalog:stats", "catmat"))
+}
+
+func TestLoadCached(t *testing.T) {
+	ctx := context.Background()
+	c := &generationCache{gens: map[string]int64{}, entries: map[string][]byte{}}
+	load := func(context.Context) (*dto.ClassCount, error) {
+		return &dto.ClassCount{ClassCode: 7510, Count: 3}, nil
+	}
+
+	first, err := loadCached(ctx, c, "k", load)
+	assert.NoError(t, err)
+	second, err := loadCached(ctx, c, "k", load)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, c.loads)
+
+	failing := func(context.Context) (*dto.ClassCount, error) { return nil, errors.New("db down") }
+	_, err = loadCached(ctx, c, "other", failing)
+	assert.EqualError(t, err, "db down")
+
+	direct, err := loadCached[dto.ClassCount](ctx, nil, "k", load)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), direct.Count)
+}
+
+func TestSearchResult_IsEmpty(t *testing.T) {
+	var empty any = &SearchResult[CatmatSearchItem]{Data: nil, Limit: 50}
+	checker, ok := empty.(cache.EmptyChecker)
+	assert.True(t, ok)
+	assert.True(t, checker.IsEmpty())
+
+	assert.False(t, (&SearchResult[CatserSearchItem]{Data: []CatserSearchItem{{ServiceCode: 1}}}).IsEmpty())
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/mailer"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrVerificationThrottled    = errors.New("verification email sent too recently")
+)
+
+// EmailVerificationConfig sets the verification links and resend limits.
+type EmailVerificationConfig struct {
+	// TTL is how long a verification link works (default 48 hours).
+	TTL time.Duration
+	// VerifyURL is the frontend page that receives the token; "{token}" is
+	// replaced by it (default "http://localhost:4200/verify-email?token={token}").
+	VerifyURL string
+	// ResendInterval is the minimum time between two emails (default 1 minute).
+	ResendInterval time.Duration
+	// MaxPerHour caps the emails sent to one user in an hour (default 5).
+	MaxPerHour int64
+}
+
+// EmailVerificationService confirms that users own their email address.
+// Accounts start unverified; a single-use link, stored as a SHA-256 hash,
+// sets users.email_verified_at.
+type EmailVerificationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	mailer  mailer.Mailer
+	log     *zap.Logger
+	cfg     EmailVerificationConfig
+
+	wg sync.WaitGroup
+}
+
+func NewEmailVerificationService(pool *pgxpool.Pool, m mailer.Mailer, cfg EmailVerificationConfig) EmailVerificationService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 48 * time.Hour
+	}
+	if cfg.VerifyURL == "" {
+		cfg.VerifyURL = "http://localhost:4200/verify-email?token={token}"
+	}
+	if cfg.ResendInterval <= 0 {
+		cfg.ResendInterval = time.Minute
+	}
+	if cfg.MaxPerHour <= 0 {
+		cfg.MaxPerHour = 5
+	}
+
+	return EmailVerificationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		mailer:  m,
+		log:     logger.Log,
+		cfg:     cfg,
+	}
+}
+
+// SendVerification issues a new link, invalidating earlier ones, and emails
+// it in the background. When the user asked too often it returns
+// ErrVerificationThrottled and how long to wait.
+func (s *EmailVerificationService) SendVerification(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	token, err := generateToken()
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	// Locking the user serializes concurrent resends, so the limits hold.
+	user, err := qtx.GetUserForEmailVerification(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.EmailVerifiedAt.Valid {
+		return 0, ErrEmailAlreadyVerified
+	}
+
+	stats, err := qtx.GetEmailVerificationSendStats(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get verification stats: %w", err)
+	}
+	if wait := s.retryAfter(stats, time.Now()); wait > 0 {
+		return wait, ErrVerificationThrottled
+	}
+
+	if err := qtx.InvalidateEmailVerificationTokens(ctx, userID); err != nil {
+		return 0, fmt.Errorf("failed to invalidate previous verification tokens: %w", err)
+	}
+	if err := qtx.CreateEmailVerificationToken(ctx, pgstore.CreateEmailVerificationTokenParams{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.TTL),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to store verification token: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit verification token: %w", err)
+	}
+
+	msg := s.verificationMessage(user.Email, user.UserName, token)
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			s.log.Error("email verification: failed to send link",
+				zap.String("user_id", userID.String()),
+				zap.Error(err))
+			return
+		}
+		s.log.Info("email verification link sent", zap.String("user_id", userID.String()))
+	}()
+
+	return 0, nil
+}
+
+// Close waits for verification emails still being sent.
+func (s *EmailVerificationService) Close() {
+	s.wg.Wait()
+}
+
+// retryAfter returns how long the user must wait before another email, or
+// zero when one may be sent now.
+func (s *EmailVerificationService) retryAfter(stats pgstore.GetEmailVerificationSendStatsRow, now time.Time) time.Duration {
+	var wait time.Duration
+	if next := stats.LastSentAt.Add(s.cfg.ResendInterval); next.After(now) {
+		wait = next.Sub(now)
+	}
+	if stats.SentLastHour >= s.cfg.MaxPerHour {
+		if next := stats.FirstSentLastHour.Add(time.Hour); next.Sub(now) > wait {
+			wait = next.Sub(now)
+		}
+	}
+	return wait
+}
+
+func (s *EmailVerificationService) verificationMessage(email, userName, token string) mailer.Message {
+	link := strings.ReplaceAll(s.cfg.VerifyURL, "{token}", url.QueryEscape(token))
+	hours := int(s.cfg.TTL.Hours())
+
+	return mailer.Message{
+		To:      email,
+		Subject: "Confirme seu email - FlyTwo Pro",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Para ativar sua conta no FlyTwo Pro, confirme seu email acessando o link abaixo "+
+			"em até %d horas:\n\n"+
+			"%s\n\n"+
+			"Se você não criou esta conta, ignore este email.\n", userName, hours, link),
+	}
+}
+
+// VerifyEmail marks the email of the token owner as verified and returns
+// the user id.
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	verification, err := qtx.GetEmailVerificationTokenByHashForUpdate(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInvalidVerificationToken
+		}
+		return uuid.Nil, fmt.Errorf("failed to get verification token: %w", err)
+	}
+	if verification.UsedAt.Valid || !time.Now().Before(verification.ExpiresAt) {
+		return uuid.Nil, ErrInvalidVerificationToken
+	}
+
+	if _, err := qtx.SetUserEmailVerified(ctx, pgstore.SetUserEmailVerifiedParams{ID: verification.UserID, Verified: true}); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+	if err := qtx.InvalidateEmailVerificationTokens(ctx, verification.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit email verification: %w", err)
+	}
+
+	s.log.Info("email verified", zap.String("user_id", verification.UserID.String()))
+	return verification.UserID, nil
+}
+
+// IsEmailVerified reports whether the user confirmed their email.
+func (s *EmailVerificationService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	verifiedAt, err := s.queries.GetUserEmailVerifiedAt(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrUserNotFound
+		}
+		return false, fmt.Errorf("failed to get email verification: %w", err)
+	}
+	return verifiedAt.Valid, nil
+}
+
+// SetEmailVerified lets an admin verify or unverify an account by hand.
+func (s *EmailVerificationService) SetEmailVerified(ctx context.Context, userID uuid.UUID, verified bool) error {
+	affected, err := s.queries.SetUserEmailVerified(ctx, pgstore.SetUserEmailVerifiedParams{ID: userID, Verified: verified})
+	if err != nil {
+		return fmt.Errorf("failed to set email verification: %w", err)
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+
+	s.log.Info("email verification set by admin",
+		zap.String("user_id", userID.String()),
+		zap.Bool("verified", verified))
+	return nil
+}
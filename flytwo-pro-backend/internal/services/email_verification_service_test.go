@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gobid/internal/store/pgstore"
+)
+
+func TestEmailVerificationMessage(t *testing.T) {
+	s := NewEmailVerificationService(nil, nil, EmailVerificationConfig{
+		TTL:       24 * time.Hour,
+		VerifyURL: "https://app.example.com/confirmar?t={token}",
+	})
+
+	msg := s.verificationMessage("user@example.com", "maria", "abc-123_x")
+
+	assert.Equal(t, "user@example.com", msg.To)
+	assert.Contains(t, msg.Body, "Olá, maria.")
+	assert.Contains(t, msg.Body, "https://app.example.com/confirmar?t=abc-123_x\n")
+	assert.Contains(t, msg.Body, "24 horas")
+}
+
+func TestEmailVerificationRetryAfter(t *testing.T) {
+	s := NewEmailVerificationService(nil, nil, EmailVerificationConfig{
+		ResendInterval: time.Minute,
+		MaxPerHour:     3,
+	})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		stats pgstore.GetEmailVerificationSendStatsRow
+		want  time.Duration
+	}{
+		{
+			name:  "never sent",
+			stats: pgstore.GetEmailVerificationSendStatsRow{LastSentAt: time.Unix(0, 0), FirstSentLastHour: time.Unix(0, 0)},
+			want:  0,
+		},
+		{
+			name: "sent too recently",
+			stats: pgstore.GetEmailVerificationSendStatsRow{
+				SentLastHour:      1,
+				FirstSentLastHour: now.Add(-20 * time.Second),
+				LastSentAt:        now.Add(-20 * time.Second),
+			},
+			want: 40 * time.Second,
+		},
+		{
+			name: "hourly limit reached",
+			stats: pgstore.GetEmailVerificationSendStatsRow{
+				SentLastHour:      3,
+				FirstSentLastHour: now.Add(-50 * time.Minute),
+				LastSentAt:        now.Add(-5 * time.Minute),
+			},
+			want: 10 * time.Minute,
+		},
+		{
+			name: "below limit after interval",
+			stats: pgstore.GetEmailVerificationSendStatsRow{
+				SentLastHour:      2,
+				FirstSentLastHour: now.Add(-50 * time.Minute),
+				LastSentAt:        now.Add(-5 * time.Minute),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.retryAfter(tt.stats, now))
+		})
+	}
+}
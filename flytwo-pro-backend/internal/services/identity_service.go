@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"gobid/internal/logger"
+	"gobid/internal/oidc"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrIdentityEmailNotVerified = errors.New("identity provider did not verify the email")
+	// ErrIdentityAccountUnverified is returned when an account with the
+	// provider's email exists but never verified it: linking would hand the
+	// account, and whatever password its creator set, to the provider user.
+	ErrIdentityAccountUnverified = errors.New("account email is not verified")
+)
+
+const (
+	// identityUserNameMax leaves room for the suffix added on collisions.
+	identityUserNameMax = 40
+	// identityUserNameAttempts bounds the suffixes tried for a taken user name.
+	identityUserNameAttempts = 5
+)
+
+// IdentityLogin is the account an OpenID Connect login resolved to.
+type IdentityLogin struct {
+	UserID uuid.UUID
+	Email  string
+	// Linked is set when the identity was linked to an account in this login.
+	Linked bool
+	// Created is set when the account itself was created in this login.
+	Created bool
+}
+
+// IdentityService maps identity provider accounts to users.
+type IdentityService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+}
+
+func NewIdentityService(pool *pgxpool.Pool) IdentityService {
+	return IdentityService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// LoginWithIdentity returns the user of a provider account. An account seen
+// before is found by (provider, subject). Otherwise the verified email links
+// it to the user with that email, or a new user is created with the default
+// role, a verified email and an unusable password (a local password can be
+// set through the password reset).
+func (s *IdentityService) LoginWithIdentity(ctx context.Context, provider string, claims oidc.Claims) (*IdentityLogin, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	identity, err := qtx.GetUserIdentity(ctx, pgstore.GetUserIdentityParams{Provider: provider, Subject: claims.Subject})
+	if err == nil {
+		email := identity.Email
+		if claims.Email != "" {
+			email = claims.Email
+		}
+		if err := qtx.TouchUserIdentity(ctx, pgstore.TouchUserIdentityParams{ID: identity.ID, Email: email}); err != nil {
+			return nil, fmt.Errorf("failed to update identity: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return &IdentityLogin{UserID: identity.UserID, Email: email}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrIdentityEmailNotVerified
+	}
+
+	login := &IdentityLogin{Email: claims.Email, Linked: true}
+	user, err := qtx.FindUserByEmailInsensitive(ctx, claims.Email)
+	switch {
+	case err == nil:
+		if !user.EmailVerifiedAt.Valid {
+			return nil, ErrIdentityAccountUnverified
+		}
+		login.UserID = user.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		if login.UserID, err = s.createUser(ctx, tx, claims); err != nil {
+			return nil, err
+		}
+		login.Created = true
+	default:
+		return nil, fmt.Errorf("failed to find user by email: %w", err)
+	}
+
+	if _, err := qtx.CreateUserIdentity(ctx, pgstore.CreateUserIdentityParams{
+		UserID:   login.UserID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.log.Info("Identity linked",
+		zap.String("provider", provider),
+		zap.String("user_id", login.UserID.String()),
+		zap.Bool("created", login.Created))
+	return login, nil
+}
+
+// createUser creates the user of a first OIDC login. A taken user name is
+// retried with a numeric suffix, each attempt in a savepoint.
+func (s *IdentityService) createUser(ctx context.Context, tx pgx.Tx, claims oidc.Claims) (uuid.UUID, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return uuid.Nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword(secret, 12)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	base := identityUserName(claims)
+	for attempt := 0; attempt < identityUserNameAttempts; attempt++ {
+		userName := base
+		if attempt > 0 {
+			userName = fmt.Sprintf("%s_%04d", base, mathrand.IntN(10000))
+		}
+
+		id, err := s.insertUser(ctx, tx, pgstore.CreateUserParams{UserName: userName, Email: claims.Email, PasswordHash: hash})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_user_name_key" {
+			continue
+		}
+		if err != nil {
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return uuid.Nil, ErrDuplicatedEmailOrUsername
+			}
+			return uuid.Nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		return id, nil
+	}
+	return uuid.Nil, ErrDuplicatedEmailOrUsername
+}
+
+func (s *IdentityService) insertUser(ctx context.Context, tx pgx.Tx, args pgstore.CreateUserParams) (uuid.UUID, error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer sp.Rollback(ctx)
+	qsp := s.queries.WithTx(sp)
+
+	id, err := qsp.CreateUser(ctx, args)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := qsp.AddUserRole(ctx, pgstore.AddUserRoleParams{UserID: id, Role: DefaultRole}); err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := qsp.SetUserEmailVerified(ctx, pgstore.SetUserEmailVerifiedParams{ID: id, Verified: true}); err != nil {
+		return uuid.Nil, err
+	}
+	return id, sp.Commit(ctx)
+}
+
+// identityUserName derives a user name (letters, digits and underscores)
+// from the preferred username or the local part of the email.
+func identityUserName(claims oidc.Claims) string {
+	source := claims.PreferredUsername
+	if source == "" || strings.Contains(source, "@") {
+		source, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range source {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+		if b.Len() >= identityUserNameMax {
+			break
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	if len(name) < 3 {
+		name = "user_" + name
+	}
+	return strings.TrimSuffix(name, "_")
+}
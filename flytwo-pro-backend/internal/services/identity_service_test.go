@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gobid/internal/oidc"
+)
+
+func TestIdentityUserName(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims oidc.Claims
+		want   string
+	}{
+		{name: "preferred username", claims: oidc.Claims{PreferredUsername: "maria.silva", Email: "m@example.com"}, want: "maria_silva"},
+		{name: "preferred username is an email", claims: oidc.Claims{PreferredUsername: "maria@orgao.gov.br", Email: "maria@orgao.gov.br"}, want: "maria"},
+		{name: "email local part", claims: oidc.Claims{Email: "joão.souza+compras@orgao.gov.br"}, want: "jo_o_souza_compras"},
+		{name: "too short", claims: oidc.Claims{Email: "a@example.com"}, want: "user_a"},
+		{name: "nothing usable", claims: oidc.Claims{Email: "..@example.com"}, want: "user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, identityUserName(tt.claims))
+		})
+	}
+}
+
+func TestIdentityUserName_Truncated(t *testing.T) {
+	name := identityUserName(oidc.Claims{Email: strings.Repeat("a", 80) + "@example.com"})
+	assert.Len(t, name, identityUserNameMax)
+}
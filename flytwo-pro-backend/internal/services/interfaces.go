@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 
+	"gobid/internal/cache"
 	"gobid/internal/dto"
+	"gobid/internal/oidc"
 	"gobid/internal/store/pgstore"
 )
 
@@ -15,6 +18,8 @@ type UserServiceInterface interface {
 	CreateUser(ctx context.Context, userName, email, password string) (uuid.UUID, error)
 	AuthenticateUser(ctx context.Context, email, password string) (uuid.UUID, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*pgstore.User, error)
+	UpdateUser(ctx context.Context, userID uuid.UUID, userName, email *string) (*pgstore.User, bool, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error
 }
 
 // CatalogImportServiceInterface defines import, search and stats operations for CATMAT and CATSER.
@@ -24,4 +29,172 @@ type CatalogImportServiceInterface interface {
 	SearchCatmat(ctx context.Context, params CatmatSearchParams) (*SearchResult[CatmatSearchItem], error)
 	SearchCatser(ctx context.Context, params CatserSearchParams) (*SearchResult[CatserSearchItem], error)
 	GetCatalogStats(ctx context.Context) (*dto.CatalogStatsResponse, error)
+	GetCatalogExtendedStats(ctx context.Context, historyDays int) (*dto.CatalogExtendedStatsResponse, error)
+	GetCatalogClassStats(ctx context.Context, catalog string, groupCode int16) (*dto.CatalogClassStatsResponse, error)
+}
+
+// SearchSynonymServiceInterface defines management of the search synonym dictionary.
+type SearchSynonymServiceInterface interface {
+	ListSynonyms(ctx context.Context) ([]pgstore.SearchSynonym, error)
+	CreateSynonym(ctx context.Context, terms []string) (*pgstore.SearchSynonym, error)
+	UpdateSynonym(ctx context.Context, id int64, terms []string) (*pgstore.SearchSynonym, error)
+	DeleteSynonym(ctx context.Context, id int64) error
+	ExpandQuery(ctx context.Context, query string) string
+}
+
+// SearchAnalyticsServiceInterface defines search logging, click-through tracking and reports.
+type SearchAnalyticsServiceInterface interface {
+	RecordSearch(entry SearchLogEntry)
+	RecordClick(ctx context.Context, click SearchClick) error
+	TopQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error)
+	ZeroResultQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error)
+	SlowestQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error)
+}
+
+// SavedSearchServiceInterface defines saved searches and the notifications they generate.
+type SavedSearchServiceInterface interface {
+	ListSavedSearches(ctx context.Context, userID uuid.UUID) ([]pgstore.SavedSearch, error)
+	CreateSavedSearch(ctx context.Context, userID uuid.UUID, input SavedSearchInput) (*pgstore.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, userID, id uuid.UUID, input SavedSearchInput) (*pgstore.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error
+	EvaluateCatalog(ctx context.Context, catalog string) (int, error)
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]pgstore.Notification, int64, error)
+	MarkNotificationRead(ctx context.Context, userID uuid.UUID, id int64) error
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// ItemListServiceInterface defines the item lists, personal or shared with
+// the active organization, and their export.
+type ItemListServiceInterface interface {
+	ListItemLists(ctx context.Context, userID uuid.UUID) ([]pgstore.ListItemListsRow, error)
+	GetItemList(ctx context.Context, userID, id uuid.UUID) (*ItemListDetail, error)
+	CreateItemList(ctx context.Context, userID uuid.UUID, input ItemListInput) (*pgstore.ItemList, error)
+	UpdateItemList(ctx context.Context, userID, id uuid.UUID, input ItemListInput) (*pgstore.ItemList, error)
+	DeleteItemList(ctx context.Context, userID, id uuid.UUID) error
+	DuplicateItemList(ctx context.Context, userID, id uuid.UUID, name string) (*pgstore.ItemList, error)
+	AddItemListLine(ctx context.Context, userID, listID uuid.UUID, input ItemListLineInput) (*pgstore.ItemListLine, error)
+	UpdateItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64, input ItemListLineInput) (*pgstore.ItemListLine, error)
+	DeleteItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64) error
+	ExportItemList(ctx context.Context, userID, id uuid.UUID, w io.Writer) (*ItemListDetail, error)
+}
+
+// CacheInspectorInterface exposes the state of the search cache to the health
+// and admin endpoints. It is implemented by *cache.Cache.
+type CacheInspectorInterface interface {
+	Health() cache.Health
+	Stats() cache.Stats
+	Purge(ctx context.Context, prefix string) (int64, error)
+	Flush(ctx context.Context) (int64, error)
+}
+
+// CacheWarmupServiceInterface triggers cache warm-ups and reports on them.
+type CacheWarmupServiceInterface interface {
+	Trigger(reason string) bool
+	Status() (running bool, last *WarmupReport)
+}
+
+// TokenServiceInterface issues and validates bearer tokens.
+type TokenServiceInterface interface {
+	IssueTokens(ctx context.Context, userID uuid.UUID) (*TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Revoke(ctx context.Context, refreshToken string) error
+	ParseAccessToken(token string) (uuid.UUID, error)
+}
+
+// RoleServiceInterface manages the roles of users.
+type RoleServiceInterface interface {
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roles []string) ([]string, error)
+	ListUsers(ctx context.Context, limit, offset int32) ([]pgstore.ListUsersWithRolesRow, int64, error)
+	BootstrapAdmin(ctx context.Context) (bool, error)
+}
+
+// PasswordResetServiceInterface recovers accounts through emailed links.
+type PasswordResetServiceInterface interface {
+	RequestReset(ctx context.Context, ip, email string) time.Duration
+	AllowResetAttempt(ctx context.Context, ip string) time.Duration
+	ResetPassword(ctx context.Context, token, password string) (uuid.UUID, error)
+}
+
+// EmailVerificationServiceInterface confirms the email address of users.
+type EmailVerificationServiceInterface interface {
+	SendVerification(ctx context.Context, userID uuid.UUID) (time.Duration, error)
+	VerifyEmail(ctx context.Context, token string) (uuid.UUID, error)
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+	SetEmailVerified(ctx context.Context, userID uuid.UUID, verified bool) error
+}
+
+// LoginProtectionServiceInterface throttles failed logins and keeps the
+// security event log.
+type LoginProtectionServiceInterface interface {
+	Check(ctx context.Context, ip, email string) time.Duration
+	RecordFailure(ctx context.Context, ip, email string)
+	RecordSuccess(ctx context.Context, ip, email string, userID uuid.UUID)
+	Unlock(ctx context.Context, userID, adminID uuid.UUID, ip string) error
+	ListSecurityEvents(ctx context.Context, eventType string, userID uuid.UUID, limit, offset int32) ([]pgstore.SecurityEvent, int64, error)
+}
+
+// TwoFactorServiceInterface manages TOTP second factors.
+type TwoFactorServiceInterface interface {
+	Status(ctx context.Context, userID uuid.UUID) (TwoFactorStatus, error)
+	Enroll(ctx context.Context, userID uuid.UUID) (*TwoFactorEnrollment, error)
+	Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	Verify(ctx context.Context, userID uuid.UUID, code string) error
+	Disable(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	Reset(ctx context.Context, userID uuid.UUID) error
+	RequiredRoles(ctx context.Context) ([]string, error)
+	SetRequiredRoles(ctx context.Context, roles []string) ([]string, error)
+}
+
+// ApiKeyServiceInterface manages the API keys of users and authenticates
+// requests made with them.
+type ApiKeyServiceInterface interface {
+	CreateApiKey(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*CreatedApiKey, error)
+	ListApiKeys(ctx context.Context, userID uuid.UUID) ([]pgstore.ApiKey, error)
+	RevokeApiKey(ctx context.Context, userID, id uuid.UUID) error
+	AuthenticateApiKey(ctx context.Context, secret string) (*pgstore.ApiKey, error)
+}
+
+// UserSessionServiceInterface tracks web sessions so users and admins can
+// list and end them.
+type UserSessionServiceInterface interface {
+	RecordLogin(ctx context.Context, token string, userID uuid.UUID, ip, userAgent string) error
+	Touch(ctx context.Context, token string) error
+	EndSession(ctx context.Context, token string) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserSessionsRow, error)
+	RevokeSession(ctx context.Context, userID, id uuid.UUID) (string, error)
+	RevokeOtherSessions(ctx context.Context, userID uuid.UUID, keepToken string) (int64, error)
+	ForceLogout(ctx context.Context, userID, adminID uuid.UUID) (int64, error)
+}
+
+// PrivacyServiceInterface serves LGPD data subject requests: data exports
+// and account deletions.
+type PrivacyServiceInterface interface {
+	ExportUserData(ctx context.Context, userID uuid.UUID) (*UserData, error)
+	RequestDeletion(ctx context.Context, userID, requestedBy uuid.UUID, immediate bool) (*pgstore.AccountDeletion, error)
+	CancelDeletion(ctx context.Context, userID, cancelledBy uuid.UUID) (*pgstore.AccountDeletion, error)
+	GetPendingDeletion(ctx context.Context, userID uuid.UUID) (*pgstore.AccountDeletion, error)
+	ListDeletions(ctx context.Context, status string, limit, offset int32) ([]pgstore.ListAccountDeletionsRow, int64, error)
+}
+
+// IdentityServiceInterface maps OpenID Connect accounts to users.
+type IdentityServiceInterface interface {
+	LoginWithIdentity(ctx context.Context, provider string, claims oidc.Claims) (*IdentityLogin, error)
+}
+
+// OrganizationServiceInterface manages organizations (órgãos) and their
+// members.
+type OrganizationServiceInterface interface {
+	ListOrganizations(ctx context.Context, query string, limit, offset int32) ([]pgstore.ListOrganizationsRow, int64, error)
+	GetOrganization(ctx context.Context, id uuid.UUID) (*pgstore.Organization, error)
+	CreateOrganization(ctx context.Context, input OrganizationInput) (*pgstore.Organization, error)
+	UpdateOrganization(ctx context.Context, id uuid.UUID, input OrganizationInput) (*pgstore.Organization, error)
+	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserOrganizationsRow, error)
+	GetMembership(ctx context.Context, orgID, userID uuid.UUID) (*pgstore.GetOrganizationMembershipRow, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]pgstore.ListOrganizationMembersRow, error)
+	SetMember(ctx context.Context, orgID, userID uuid.UUID, role string) (*pgstore.OrganizationMember, error)
+	AddMemberByEmail(ctx context.Context, orgID uuid.UUID, email, role string) (*pgstore.OrganizationMember, error)
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error
 }
@@ -0,0 +1,472 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+	"gobid/internal/tenant"
+)
+
+var (
+	ErrItemListNotFound     = errors.New("item list not found")
+	ErrItemListLineNotFound = errors.New("item list line not found")
+	ErrItemListLineExists   = errors.New("item already in list")
+	ErrCatalogItemNotFound  = errors.New("catalog item not found")
+	ErrInvalidItemList      = errors.New("invalid item list")
+)
+
+// Line states relative to the current catalog.
+const (
+	ItemListLineOK      = "ok"
+	ItemListLineChanged = "changed" // an import changed the item after it was added
+	ItemListLineRemoved = "removed" // the item no longer exists in the catalog
+)
+
+// ItemListInput holds the editable fields of a list.
+type ItemListInput struct {
+	Name        string
+	Description string
+}
+
+// ItemListLineInput holds a line to add to a list. Catalog and ItemCode are
+// ignored on update.
+type ItemListLineInput struct {
+	Catalog  string // "catmat" or "catser"
+	ItemCode int32  // item_code (catmat) or service_code (catser)
+	Quantity float64
+	Unit     string
+	Notes    string
+}
+
+// ItemListLine is a list line checked against the current catalog.
+type ItemListLine struct {
+	pgstore.ListItemListLinesRow
+	Status string
+}
+
+// ItemListDetail is a list with its lines.
+type ItemListDetail struct {
+	pgstore.ItemList
+	Lines []ItemListLine
+}
+
+// ItemListService manages the item lists ("cestas"). With an active
+// organization in the context (see package tenant) it works on the lists
+// shared by the organization's members, which viewers can only read;
+// otherwise on the user's personal lists.
+type ItemListService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+}
+
+func NewItemListService(pool *pgxpool.Pool) ItemListService {
+	return ItemListService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// ListItemLists returns the lists in scope with their line count, most recently changed first.
+func (s *ItemListService) ListItemLists(ctx context.Context, userID uuid.UUID) ([]pgstore.ListItemListsRow, error) {
+	orgID, _ := itemListScope(ctx)
+	lists, err := s.queries.ListItemLists(ctx, pgstore.ListItemListsParams{OrganizationID: orgID, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list item lists: %w", err)
+	}
+	if lists == nil {
+		lists = []pgstore.ListItemListsRow{}
+	}
+	return lists, nil
+}
+
+// GetItemList returns a list in scope with its lines.
+func (s *ItemListService) GetItemList(ctx context.Context, userID, id uuid.UUID) (*ItemListDetail, error) {
+	list, err := s.getList(ctx, s.queries, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListItemListLines(ctx, list.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list item list lines: %w", err)
+	}
+
+	lines := make([]ItemListLine, len(rows))
+	for i, row := range rows {
+		lines[i] = ItemListLine{ListItemListLinesRow: row, Status: itemListLineStatus(row)}
+	}
+	return &ItemListDetail{ItemList: *list, Lines: lines}, nil
+}
+
+// CreateItemList creates an empty list, shared with the active organization
+// if there is one.
+func (s *ItemListService) CreateItemList(ctx context.Context, userID uuid.UUID, input ItemListInput) (*pgstore.ItemList, error) {
+	orgID, writable := itemListScope(ctx)
+	if !writable {
+		return nil, ErrOrganizationReadOnly
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrInvalidItemList
+	}
+
+	list, err := s.queries.CreateItemList(ctx, pgstore.CreateItemListParams{
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    strings.TrimSpace(input.Description),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item list: %w", err)
+	}
+	return &list, nil
+}
+
+// UpdateItemList renames a list or changes its description.
+func (s *ItemListService) UpdateItemList(ctx context.Context, userID, id uuid.UUID, input ItemListInput) (*pgstore.ItemList, error) {
+	orgID, writable := itemListScope(ctx)
+	if !writable {
+		return nil, ErrOrganizationReadOnly
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrInvalidItemList
+	}
+
+	list, err := s.queries.UpdateItemList(ctx, pgstore.UpdateItemListParams{
+		ID:             id,
+		OrganizationID: orgID,
+		UserID:         userID,
+		Name:           name,
+		Description:    strings.TrimSpace(input.Description),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemListNotFound
+		}
+		return nil, fmt.Errorf("failed to update item list: %w", err)
+	}
+	return &list, nil
+}
+
+// DeleteItemList removes a list and its lines.
+func (s *ItemListService) DeleteItemList(ctx context.Context, userID, id uuid.UUID) error {
+	orgID, writable := itemListScope(ctx)
+	if !writable {
+		return ErrOrganizationReadOnly
+	}
+	affected, err := s.queries.DeleteItemList(ctx, pgstore.DeleteItemListParams{ID: id, OrganizationID: orgID, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete item list: %w", err)
+	}
+	if affected == 0 {
+		return ErrItemListNotFound
+	}
+	return nil
+}
+
+// DuplicateItemList copies a list and its lines. The copy keeps the item
+// snapshots of the original, so changed and removed items stay flagged.
+// An empty name defaults to "<original> (cópia)". The copy stays in the
+// scope of the original.
+func (s *ItemListService) DuplicateItemList(ctx context.Context, userID, id uuid.UUID, name string) (*pgstore.ItemList, error) {
+	if _, writable := itemListScope(ctx); !writable {
+		return nil, ErrOrganizationReadOnly
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	original, err := s.getList(ctx, qtx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = original.Name + " (cópia)"
+	}
+
+	list, err := qtx.CreateItemList(ctx, pgstore.CreateItemListParams{
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+		OrganizationID: original.OrganizationID,
+		Name:           name,
+		Description:    original.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item list copy: %w", err)
+	}
+
+	if _, err := qtx.CopyItemListLines(ctx, pgstore.CopyItemListLinesParams{
+		ToListID:   list.ID,
+		FromListID: original.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to copy item list lines: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit item list copy: %w", err)
+	}
+	return &list, nil
+}
+
+// AddItemListLine adds a catalog item to a list, keeping a snapshot of the
+// item to detect later changes.
+func (s *ItemListService) AddItemListLine(ctx context.Context, userID, listID uuid.UUID, input ItemListLineInput) (*pgstore.ItemListLine, error) {
+	if _, writable := itemListScope(ctx); !writable {
+		return nil, ErrOrganizationReadOnly
+	}
+	if input.Quantity <= 0 {
+		return nil, ErrInvalidItemList
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := s.getList(ctx, qtx, userID, listID); err != nil {
+		return nil, err
+	}
+
+	var line pgstore.ItemListLine
+	switch input.Catalog {
+	case "catmat":
+		line, err = qtx.CreateCatmatListLine(ctx, pgstore.CreateCatmatListLineParams{
+			ListID:   listID,
+			ItemCode: input.ItemCode,
+			Quantity: input.Quantity,
+			Unit:     strings.TrimSpace(input.Unit),
+			Notes:    strings.TrimSpace(input.Notes),
+		})
+	case "catser":
+		line, err = qtx.CreateCatserListLine(ctx, pgstore.CreateCatserListLineParams{
+			ListID:   listID,
+			ItemCode: input.ItemCode,
+			Quantity: input.Quantity,
+			Unit:     strings.TrimSpace(input.Unit),
+			Notes:    strings.TrimSpace(input.Notes),
+		})
+	default:
+		return nil, ErrInvalidItemList
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCatalogItemNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrItemListLineExists
+		}
+		return nil, fmt.Errorf("failed to add item list line: %w", err)
+	}
+
+	if err := qtx.TouchItemList(ctx, listID); err != nil {
+		return nil, fmt.Errorf("failed to touch item list: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit item list line: %w", err)
+	}
+	return &line, nil
+}
+
+// UpdateItemListLine changes quantity, unit and notes of a line.
+func (s *ItemListService) UpdateItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64, input ItemListLineInput) (*pgstore.ItemListLine, error) {
+	if _, writable := itemListScope(ctx); !writable {
+		return nil, ErrOrganizationReadOnly
+	}
+	if input.Quantity <= 0 {
+		return nil, ErrInvalidItemList
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := s.getList(ctx, qtx, userID, listID); err != nil {
+		return nil, err
+	}
+
+	line, err := qtx.UpdateItemListLine(ctx, pgstore.UpdateItemListLineParams{
+		ID:       lineID,
+		ListID:   listID,
+		Quantity: input.Quantity,
+		Unit:     strings.TrimSpace(input.Unit),
+		Notes:    strings.TrimSpace(input.Notes),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemListLineNotFound
+		}
+		return nil, fmt.Errorf("failed to update item list line: %w", err)
+	}
+
+	if err := qtx.TouchItemList(ctx, listID); err != nil {
+		return nil, fmt.Errorf("failed to touch item list: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit item list line: %w", err)
+	}
+	return &line, nil
+}
+
+// DeleteItemListLine removes a line from a list.
+func (s *ItemListService) DeleteItemListLine(ctx context.Context, userID, listID uuid.UUID, lineID int64) error {
+	if _, writable := itemListScope(ctx); !writable {
+		return ErrOrganizationReadOnly
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := s.getList(ctx, qtx, userID, listID); err != nil {
+		return err
+	}
+
+	affected, err := qtx.DeleteItemListLine(ctx, pgstore.DeleteItemListLineParams{ID: lineID, ListID: listID})
+	if err != nil {
+		return fmt.Errorf("failed to delete item list line: %w", err)
+	}
+	if affected == 0 {
+		return ErrItemListLineNotFound
+	}
+
+	if err := qtx.TouchItemList(ctx, listID); err != nil {
+		return fmt.Errorf("failed to touch item list: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ExportItemList writes the list as an XLSX workbook to w and returns the list.
+func (s *ItemListService) ExportItemList(ctx context.Context, userID, id uuid.UUID, w io.Writer) (*ItemListDetail, error) {
+	detail, err := s.GetItemList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := buildItemListWorkbook(detail)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := f.Write(w); err != nil {
+		return nil, fmt.Errorf("failed to write item list workbook: %w", err)
+	}
+	return detail, nil
+}
+
+// getList returns a list in scope. Callers that change the list check
+// itemListScope first.
+func (s *ItemListService) getList(ctx context.Context, q *pgstore.Queries, userID, id uuid.UUID) (*pgstore.ItemList, error) {
+	orgID, _ := itemListScope(ctx)
+	list, err := q.GetItemList(ctx, pgstore.GetItemListParams{ID: id, OrganizationID: orgID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemListNotFound
+		}
+		return nil, fmt.Errorf("failed to get item list: %w", err)
+	}
+	return &list, nil
+}
+
+// itemListScope returns the organization whose lists the request works on
+// (invalid for the user's personal lists) and whether the user may change
+// them.
+func itemListScope(ctx context.Context) (pgtype.UUID, bool) {
+	org, ok := tenant.FromContext(ctx)
+	if !ok {
+		return pgtype.UUID{}, true
+	}
+	return pgtype.UUID{Bytes: org.ID, Valid: true}, org.Role != OrgRoleViewer
+}
+
+// itemListLineStatus compares the line snapshot with the current catalog item.
+// The fingerprint is empty when the item is gone.
+func itemListLineStatus(row pgstore.ListItemListLinesRow) string {
+	switch {
+	case row.CurrentFingerprint == "":
+		return ItemListLineRemoved
+	case row.CurrentFingerprint != row.ItemFingerprint:
+		return ItemListLineChanged
+	default:
+		return ItemListLineOK
+	}
+}
+
+var itemListLineStatusLabels = map[string]string{
+	ItemListLineOK:      "OK",
+	ItemListLineChanged: "Alterado no catálogo",
+	ItemListLineRemoved: "Removido do catálogo",
+}
+
+func buildItemListWorkbook(detail *ItemListDetail) (*excelize.File, error) {
+	f := excelize.NewFile()
+	sheet := "Cesta"
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to name sheet: %w", err)
+	}
+
+	header := []any{"Catálogo", "Código", "Descrição", "Quantidade", "Unidade", "Observações", "Situação"}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for i, line := range detail.Lines {
+		description := line.CurrentDescription
+		if line.Status == ItemListLineRemoved {
+			description = line.ItemDescription
+		}
+
+		row := []any{
+			strings.ToUpper(line.Catalog),
+			line.ItemCode,
+			description,
+			line.Quantity,
+			line.Unit,
+			line.Notes,
+			itemListLineStatusLabels[line.Status],
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to write line %d: %w", i+1, err)
+		}
+	}
+
+	_ = f.SetColWidth(sheet, "C", "C", 80)
+	_ = f.SetColWidth(sheet, "F", "G", 24)
+	return f, nil
+}
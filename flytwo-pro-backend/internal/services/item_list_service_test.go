@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+
+	"gobid/internal/store/pgstore"
+	"gobid/internal/tenant"
+)
+
+func TestItemListLineStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  string
+		current string
+		want    string
+	}{
+		{"unchanged", "abc", "abc", ItemListLineOK},
+		{"changed by import", "abc", "def", ItemListLineChanged},
+		{"removed from catalog", "abc", "", ItemListLineRemoved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := pgstore.ListItemListLinesRow{ItemFingerprint: tt.stored, CurrentFingerprint: tt.current}
+			assert.Equal(t, tt.want, itemListLineStatus(row))
+		})
+	}
+}
+
+func TestBuildItemListWorkbook(t *testing.T) {
+	detail := &ItemListDetail{
+		ItemList: pgstore.ItemList{Name: "Escritório"},
+		Lines: []ItemListLine{
+			{
+				ListItemListLinesRow: pgstore.ListItemListLinesRow{
+					Catalog:            "catmat",
+					ItemCode:           150001,
+					Quantity:           10,
+					Unit:               "CX",
+					ItemDescription:    "Papel A4",
+					CurrentDescription: "Papel A4, 75g",
+				},
+				Status: ItemListLineChanged,
+			},
+			{
+				ListItemListLinesRow: pgstore.ListItemListLinesRow{
+					Catalog:         "catser",
+					ItemCode:        2001,
+					Quantity:        1.5,
+					Notes:           "mensal",
+					ItemDescription: "Limpeza predial",
+				},
+				Status: ItemListLineRemoved,
+			},
+		},
+	}
+
+	f, err := buildItemListWorkbook(detail)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.Write(&buf))
+	f.Close()
+
+	read, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	defer read.Close()
+
+	rows, err := read.GetRows("Cesta")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "Situação", rows[0][6])
+	assert.Equal(t, []string{"CATMAT", "150001", "Papel A4, 75g", "10", "CX", "", "Alterado no catálogo"}, rows[1])
+	assert.Equal(t, []string{"CATSER", "2001", "Limpeza predial", "1.5", "", "mensal", "Removido do catálogo"}, rows[2])
+}
+
+func TestItemListScope(t *testing.T) {
+	orgID, writable := itemListScope(context.Background())
+	assert.False(t, orgID.Valid, "personal lists without an organization")
+	assert.True(t, writable)
+
+	id := uuid.New()
+	orgID, writable = itemListScope(tenant.WithOrganization(context.Background(), tenant.Organization{ID: id, Role: OrgRoleMember}))
+	assert.Equal(t, id, uuid.UUID(orgID.Bytes))
+	assert.True(t, orgID.Valid)
+	assert.True(t, writable)
+
+	_, writable = itemListScope(tenant.WithOrganization(context.Background(), tenant.Organization{ID: id, Role: OrgRoleViewer}))
+	assert.False(t, writable, "viewers only read the organization's lists")
+}
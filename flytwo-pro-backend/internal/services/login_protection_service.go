@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/ratelimit"
+	"gobid/internal/store/pgstore"
+)
+
+// Security event types recorded by LoginProtectionService.
+const (
+	SecurityEventLoginLockout = "login_lockout"
+	SecurityEventLoginUnlock  = "login_unlock"
+)
+
+// LoginLimit is the failed-login policy for one kind of key (account or IP).
+// The first FreeAttempts failures cost nothing; each later one blocks the key
+// for BaseDelay, doubling every failure, and MaxFailures failures lock it out
+// for Lockout.
+type LoginLimit struct {
+	FreeAttempts int64
+	BaseDelay    time.Duration
+	MaxFailures  int64
+	Lockout      time.Duration
+}
+
+// LoginProtectionConfig sets the limits per account and per client IP.
+type LoginProtectionConfig struct {
+	// Account limits failures per email (default 3 free, 1s base delay,
+	// lockout of 15 minutes after 10 failures).
+	Account LoginLimit
+	// IP limits failures per client address; it is looser because offices
+	// share addresses (default 20 free, 1s base delay, lockout of 15 minutes
+	// after 100 failures).
+	IP LoginLimit
+	// Window is how long failures are remembered after the last one
+	// (default 1 hour).
+	Window time.Duration
+}
+
+// LoginProtectionService slows down password guessing. Failed logins are
+// counted per account and per IP in a ratelimit.Store; while a key is
+// blocked, logins are refused before the password is checked, which also
+// spares the bcrypt work. Lockouts and unlocks go to the security_event
+// table.
+type LoginProtectionService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	store   ratelimit.Store
+	log     *zap.Logger
+	cfg     LoginProtectionConfig
+}
+
+func NewLoginProtectionService(pool *pgxpool.Pool, store ratelimit.Store, cfg LoginProtectionConfig) LoginProtectionService {
+	cfg.Account = cfg.Account.withDefaults(LoginLimit{FreeAttempts: 3, BaseDelay: time.Second, MaxFailures: 10, Lockout: 15 * time.Minute})
+	cfg.IP = cfg.IP.withDefaults(LoginLimit{FreeAttempts: 20, BaseDelay: time.Second, MaxFailures: 100, Lockout: 15 * time.Minute})
+	if cfg.Window <= 0 {
+		cfg.Window = time.Hour
+	}
+	if cfg.Window < cfg.Account.Lockout {
+		cfg.Window = cfg.Account.Lockout
+	}
+	if cfg.Window < cfg.IP.Lockout {
+		cfg.Window = cfg.IP.Lockout
+	}
+
+	return LoginProtectionService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		store:   store,
+		log:     logger.Log,
+		cfg:     cfg,
+	}
+}
+
+func (l LoginLimit) withDefaults(d LoginLimit) LoginLimit {
+	if l.FreeAttempts <= 0 {
+		l.FreeAttempts = d.FreeAttempts
+	}
+	if l.BaseDelay <= 0 {
+		l.BaseDelay = d.BaseDelay
+	}
+	if l.MaxFailures <= 0 {
+		l.MaxFailures = d.MaxFailures
+	}
+	if l.MaxFailures <= l.FreeAttempts {
+		l.MaxFailures = l.FreeAttempts + 1
+	}
+	if l.Lockout <= 0 {
+		l.Lockout = d.Lockout
+	}
+	return l
+}
+
+// delay returns how long a key is blocked after its failures-th failure.
+func (l LoginLimit) delay(failures int64) time.Duration {
+	if failures >= l.MaxFailures {
+		return l.Lockout
+	}
+	if failures <= l.FreeAttempts {
+		return 0
+	}
+	d := l.BaseDelay
+	for i := l.FreeAttempts + 1; i < failures && d < l.Lockout; i++ {
+		d *= 2
+	}
+	return min(d, l.Lockout)
+}
+
+func accountKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// Check returns how long a login for email from ip must wait, or zero when
+// it may proceed. Store errors let the login through, so an unreachable
+// Redis does not lock everyone out.
+func (s *LoginProtectionService) Check(ctx context.Context, ip, email string) time.Duration {
+	var wait time.Duration
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		d, err := s.store.LockedFor(ctx, key)
+		if err != nil {
+			s.log.Error("login protection: failed to read lock",
+				zap.String("store", s.store.Name()),
+				zap.Error(err))
+			continue
+		}
+		wait = max(wait, d)
+	}
+	return wait
+}
+
+// RecordFailure counts a failed login for email and ip and blocks them as
+// the policy says.
+func (s *LoginProtectionService) RecordFailure(ctx context.Context, ip, email string) {
+	s.fail(ctx, accountKey(email), s.cfg.Account, ip, email)
+	s.fail(ctx, ipKey(ip), s.cfg.IP, ip, "")
+}
+
+func (s *LoginProtectionService) fail(ctx context.Context, key string, limit LoginLimit, ip, email string) {
+	failures, err := s.store.Fail(ctx, key, s.cfg.Window)
+	if err != nil {
+		s.log.Error("login protection: failed to count failure",
+			zap.String("store", s.store.Name()),
+			zap.Error(err))
+		return
+	}
+
+	d := limit.delay(failures)
+	if d <= 0 {
+		return
+	}
+	if err := s.store.Lock(ctx, key, d); err != nil {
+		s.log.Error("login protection: failed to lock",
+			zap.String("store", s.store.Name()),
+			zap.Error(err))
+		return
+	}
+
+	if failures >= limit.MaxFailures {
+		scope := "ip"
+		if email != "" {
+			scope = "account"
+		}
+		s.log.Warn("login locked out",
+			zap.String("scope", scope),
+			zap.String("ip", ip),
+			zap.String("email", email),
+			zap.Int64("failures", failures))
+		s.recordEvent(ctx, SecurityEventLoginLockout, uuid.Nil, email, ip, map[string]any{
+			"scope":           scope,
+			"failures":        failures,
+			"lockout_seconds": int64(d.Seconds()),
+		})
+	}
+}
+
+// RecordSuccess clears the failures of the account. The IP counter is kept,
+// so logging into one account does not reset guessing against others.
+func (s *LoginProtectionService) RecordSuccess(ctx context.Context, ip, email string, userID uuid.UUID) {
+	failures, err := s.store.Reset(ctx, accountKey(email))
+	if err != nil {
+		s.log.Error("login protection: failed to reset account",
+			zap.String("store", s.store.Name()),
+			zap.Error(err))
+		return
+	}
+	if failures >= s.cfg.Account.MaxFailures {
+		s.recordEvent(ctx, SecurityEventLoginUnlock, userID, email, ip, map[string]any{
+			"reason":   "login_succeeded",
+			"failures": failures,
+		})
+	}
+}
+
+// Unlock clears the failures and the lockout of a user's account. Locks are
+// also kept per client IP, so a non-empty ip (e.g. taken from the user's
+// login_lockout event) clears that address as well; otherwise the user stays
+// blocked while the IP lock lasts.
+func (s *LoginProtectionService) Unlock(ctx context.Context, userID, adminID uuid.UUID, ip string) error {
+	user, err := s.queries.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	failures, err := s.store.Reset(ctx, accountKey(user.Email))
+	if err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	details := map[string]any{
+		"reason":   "admin",
+		"by":       adminID.String(),
+		"failures": failures,
+	}
+	if ip != "" {
+		ipFailures, err := s.store.Reset(ctx, ipKey(ip))
+		if err != nil {
+			return fmt.Errorf("failed to reset login failures of ip: %w", err)
+		}
+		details["ip_failures"] = ipFailures
+	}
+
+	s.log.Info("login unlocked by admin",
+		zap.String("user_id", userID.String()),
+		zap.String("ip", ip),
+		zap.String("by", adminID.String()))
+	s.recordEvent(ctx, SecurityEventLoginUnlock, userID, user.Email, ip, details)
+	return nil
+}
+
+// ListSecurityEvents returns a page of security events, newest first, and
+// the total count. Empty eventType and uuid.Nil userID match everything.
+func (s *LoginProtectionService) ListSecurityEvents(ctx context.Context, eventType string, userID uuid.UUID, limit, offset int32) ([]pgstore.SecurityEvent, int64, error) {
+	filterType := pgtype.Text{String: eventType, Valid: eventType != ""}
+	filterUser := pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil}
+
+	events, err := s.queries.ListSecurityEvents(ctx, pgstore.ListSecurityEventsParams{
+		Limit:     limit,
+		Offset:    offset,
+		EventType: filterType,
+		UserID:    filterUser,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list security events: %w", err)
+	}
+	total, err := s.queries.CountSecurityEvents(ctx, pgstore.CountSecurityEventsParams{
+		EventType: filterType,
+		UserID:    filterUser,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count security events: %w", err)
+	}
+	if events == nil {
+		events = []pgstore.SecurityEvent{}
+	}
+	return events, total, nil
+}
+
+// recordEvent writes a security event. Failing to write one is logged but
+// does not fail the login.
+func (s *LoginProtectionService) recordEvent(ctx context.Context, eventType string, userID uuid.UUID, email, ip string, details map[string]any) {
+	if userID == uuid.Nil && email != "" {
+		if user, err := s.queries.GetUserByEmail(ctx, email); err == nil {
+			userID = user.ID
+		}
+	}
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		payload = []byte("{}")
+	}
+
+	if err := s.queries.CreateSecurityEvent(ctx, pgstore.CreateSecurityEventParams{
+		EventType: eventType,
+		UserID:    pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil},
+		Email:     pgtype.Text{String: email, Valid: email != ""},
+		Ip:        pgtype.Text{String: ip, Valid: ip != ""},
+		Details:   payload,
+	}); err != nil {
+		s.log.Error("failed to record security event",
+			zap.String("event_type", eventType),
+			zap.Error(err))
+	}
+}
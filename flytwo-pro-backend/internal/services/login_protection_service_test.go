@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"gobid/internal/ratelimit"
+)
+
+func TestLoginLimitDelay(t *testing.T) {
+	limit := LoginLimit{FreeAttempts: 3, BaseDelay: time.Second, MaxFailures: 10, Lockout: 15 * time.Minute}
+
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{1, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{9, 32 * time.Second},
+		{10, 15 * time.Minute},
+		{25, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, limit.delay(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestLoginLimitDelay_CappedAtLockout(t *testing.T) {
+	limit := LoginLimit{FreeAttempts: 1, BaseDelay: time.Second, MaxFailures: 500, Lockout: time.Minute}
+
+	assert.Equal(t, time.Minute, limit.delay(499))
+}
+
+func TestLoginProtection_BacksOffPerAccount(t *testing.T) {
+	s := NewLoginProtectionService(nil, ratelimit.NewMemoryStore(), LoginProtectionConfig{
+		Account: LoginLimit{FreeAttempts: 2, BaseDelay: 10 * time.Second, MaxFailures: 5},
+	})
+	ctx := context.Background()
+
+	s.RecordFailure(ctx, "10.0.0.1", "user@example.com")
+	s.RecordFailure(ctx, "10.0.0.1", "User@Example.com ")
+	assert.Zero(t, s.Check(ctx, "10.0.0.1", "user@example.com"))
+
+	s.RecordFailure(ctx, "10.0.0.1", "user@example.com")
+	assert.InDelta(t, 10*time.Second, s.Check(ctx, "10.0.0.2", "user@example.com"), float64(time.Second),
+		"the account is blocked from any address")
+	assert.Zero(t, s.Check(ctx, "10.0.0.1", "other@example.com"), "other accounts are not")
+
+	s.RecordSuccess(ctx, "10.0.0.1", "user@example.com", uuid.New())
+	assert.Zero(t, s.Check(ctx, "10.0.0.1", "user@example.com"))
+}
+
+func TestLoginProtection_BacksOffPerIP(t *testing.T) {
+	s := NewLoginProtectionService(nil, ratelimit.NewMemoryStore(), LoginProtectionConfig{
+		IP: LoginLimit{FreeAttempts: 2, BaseDelay: 10 * time.Second, MaxFailures: 5},
+	})
+	ctx := context.Background()
+
+	s.RecordFailure(ctx, "10.0.0.1", "a@example.com")
+	s.RecordFailure(ctx, "10.0.0.1", "b@example.com")
+	s.RecordFailure(ctx, "10.0.0.1", "c@example.com")
+
+	assert.InDelta(t, 10*time.Second, s.Check(ctx, "10.0.0.1", "d@example.com"), float64(time.Second))
+	assert.Zero(t, s.Check(ctx, "10.0.0.2", "d@example.com"))
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+// Roles of a user inside an organization.
+const (
+	OrgRoleAdmin  = "admin"  // manages the members
+	OrgRoleMember = "member" // changes the shared data
+	OrgRoleViewer = "viewer" // reads the shared data
+)
+
+// ValidOrgRoles lists every organization role, most privileged first.
+var ValidOrgRoles = []string{OrgRoleAdmin, OrgRoleMember, OrgRoleViewer}
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationExists    = errors.New("organization with this CNPJ or UASG already exists")
+	ErrInvalidOrganization   = errors.New("invalid organization")
+	ErrInvalidOrgRole        = errors.New("invalid organization role")
+	ErrNotOrganizationMember = errors.New("user is not a member of the organization")
+	ErrLastOrganizationAdmin = errors.New("cannot remove the last admin of the organization")
+	ErrOrganizationReadOnly  = errors.New("organization role does not allow changes")
+)
+
+// OrganizationInput holds the editable fields of an organization. CNPJ and
+// UASG are optional and may be formatted ("00.394.460/0058-87").
+type OrganizationInput struct {
+	Name string
+	CNPJ string
+	UASG string
+}
+
+// OrganizationService manages the organizations (órgãos) users work on behalf
+// of and their members.
+type OrganizationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+}
+
+func NewOrganizationService(pool *pgxpool.Pool) OrganizationService {
+	return OrganizationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// ListOrganizations returns a page of organizations whose name contains query
+// or whose CNPJ or UASG equals it, with the total count.
+func (s *OrganizationService) ListOrganizations(ctx context.Context, query string, limit, offset int32) ([]pgstore.ListOrganizationsRow, int64, error) {
+	query = strings.TrimSpace(query)
+	if digits := documentSeparators.Replace(query); digits != "" && onlyDigits(digits) == digits {
+		query = digits
+	}
+
+	orgs, err := s.queries.ListOrganizations(ctx, pgstore.ListOrganizationsParams{Query: query, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	total, err := s.queries.CountOrganizations(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count organizations: %w", err)
+	}
+	if orgs == nil {
+		orgs = []pgstore.ListOrganizationsRow{}
+	}
+	return orgs, total, nil
+}
+
+// GetOrganization returns an organization by id.
+func (s *OrganizationService) GetOrganization(ctx context.Context, id uuid.UUID) (*pgstore.Organization, error) {
+	org, err := s.queries.GetOrganization(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &org, nil
+}
+
+// CreateOrganization registers an organization without members.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, input OrganizationInput) (*pgstore.Organization, error) {
+	params, err := cleanOrganizationInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.queries.CreateOrganization(ctx, pgstore.CreateOrganizationParams{
+		Name: params.Name,
+		Cnpj: params.Cnpj,
+		Uasg: params.Uasg,
+	})
+	if err != nil {
+		return nil, organizationWriteError("create", err)
+	}
+
+	s.log.Info("organization created",
+		zap.String("organization_id", org.ID.String()),
+		zap.String("name", org.Name))
+	return &org, nil
+}
+
+// UpdateOrganization replaces the name, CNPJ and UASG of an organization.
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, id uuid.UUID, input OrganizationInput) (*pgstore.Organization, error) {
+	params, err := cleanOrganizationInput(input)
+	if err != nil {
+		return nil, err
+	}
+	params.ID = id
+
+	org, err := s.queries.UpdateOrganization(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, organizationWriteError("update", err)
+	}
+	return &org, nil
+}
+
+// DeleteOrganization removes an organization with its memberships and its
+// shared data.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	affected, err := s.queries.DeleteOrganization(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	if affected == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	s.log.Info("organization deleted", zap.String("organization_id", id.String()))
+	return nil
+}
+
+// ListUserOrganizations returns the organizations a user is a member of,
+// with the user's role in each.
+func (s *OrganizationService) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserOrganizationsRow, error) {
+	orgs, err := s.queries.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user organizations: %w", err)
+	}
+	if orgs == nil {
+		orgs = []pgstore.ListUserOrganizationsRow{}
+	}
+	return orgs, nil
+}
+
+// GetMembership returns the membership of a user in an organization, or
+// ErrNotOrganizationMember.
+func (s *OrganizationService) GetMembership(ctx context.Context, orgID, userID uuid.UUID) (*pgstore.GetOrganizationMembershipRow, error) {
+	membership, err := s.queries.GetOrganizationMembership(ctx, pgstore.GetOrganizationMembershipParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotOrganizationMember
+		}
+		return nil, fmt.Errorf("failed to get organization membership: %w", err)
+	}
+	return &membership, nil
+}
+
+// ListMembers returns the members of an organization.
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]pgstore.ListOrganizationMembersRow, error) {
+	if _, err := s.GetOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.queries.ListOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	if members == nil {
+		members = []pgstore.ListOrganizationMembersRow{}
+	}
+	return members, nil
+}
+
+// SetMember adds a user to an organization or changes the user's role.
+// Demoting the only admin of the organization returns ErrLastOrganizationAdmin.
+func (s *OrganizationService) SetMember(ctx context.Context, orgID, userID uuid.UUID, role string) (*pgstore.OrganizationMember, error) {
+	return s.saveMember(ctx, orgID, role, func(q *pgstore.Queries) (uuid.UUID, error) {
+		if _, err := q.GetUserById(ctx, userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, ErrUserNotFound
+			}
+			return uuid.Nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return userID, nil
+	})
+}
+
+// AddMemberByEmail is SetMember for the account with the given email, for
+// organization admins who do not know user ids.
+func (s *OrganizationService) AddMemberByEmail(ctx context.Context, orgID uuid.UUID, email, role string) (*pgstore.OrganizationMember, error) {
+	return s.saveMember(ctx, orgID, role, func(q *pgstore.Queries) (uuid.UUID, error) {
+		user, err := q.FindUserByEmailInsensitive(ctx, strings.TrimSpace(email))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, ErrUserNotFound
+			}
+			return uuid.Nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		return user.ID, nil
+	})
+}
+
+// saveMember upserts the membership of the user findUser returns, with the
+// organization locked.
+func (s *OrganizationService) saveMember(ctx context.Context, orgID uuid.UUID, role string, findUser func(*pgstore.Queries) (uuid.UUID, error)) (*pgstore.OrganizationMember, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !slices.Contains(ValidOrgRoles, role) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrgRole, role)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := lockOrganization(ctx, qtx, orgID); err != nil {
+		return nil, err
+	}
+	userID, err := findUser(qtx)
+	if err != nil {
+		return nil, err
+	}
+	if role != OrgRoleAdmin {
+		if err := checkNotLastOrgAdmin(ctx, qtx, orgID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := qtx.UpsertOrganizationMember(ctx, pgstore.UpsertOrganizationMemberParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Role:           role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save organization member: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit organization member: %w", err)
+	}
+
+	s.log.Info("organization member saved",
+		zap.String("organization_id", orgID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("role", role))
+	return &member, nil
+}
+
+// RemoveMember removes a user from an organization. The only admin of the
+// organization cannot be removed (ErrLastOrganizationAdmin).
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := lockOrganization(ctx, qtx, orgID); err != nil {
+		return err
+	}
+	if err := checkNotLastOrgAdmin(ctx, qtx, orgID, userID); err != nil {
+		return err
+	}
+
+	affected, err := qtx.DeleteOrganizationMember(ctx, pgstore.DeleteOrganizationMemberParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotOrganizationMember
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit organization member removal: %w", err)
+	}
+
+	s.log.Info("organization member removed",
+		zap.String("organization_id", orgID.String()),
+		zap.String("user_id", userID.String()))
+	return nil
+}
+
+// lockOrganization locks the organization row, serializing changes to its
+// members.
+func lockOrganization(ctx context.Context, q *pgstore.Queries, orgID uuid.UUID) error {
+	if _, err := q.LockOrganization(ctx, orgID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrOrganizationNotFound
+		}
+		return fmt.Errorf("failed to lock organization: %w", err)
+	}
+	return nil
+}
+
+// checkNotLastOrgAdmin returns ErrLastOrganizationAdmin when userID is the
+// only admin of the organization. Call it with the organization locked.
+func checkNotLastOrgAdmin(ctx context.Context, q *pgstore.Queries, orgID, userID uuid.UUID) error {
+	membership, err := q.GetOrganizationMembership(ctx, pgstore.GetOrganizationMembershipParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get organization membership: %w", err)
+	}
+	if membership.Role != OrgRoleAdmin {
+		return nil
+	}
+
+	admins, err := q.CountOrganizationAdmins(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to count organization admins: %w", err)
+	}
+	if admins <= 1 {
+		return ErrLastOrganizationAdmin
+	}
+	return nil
+}
+
+// cleanOrganizationInput trims the name and reduces CNPJ and UASG to their
+// digits, checking the CNPJ check digits.
+func cleanOrganizationInput(input OrganizationInput) (pgstore.UpdateOrganizationParams, error) {
+	params := pgstore.UpdateOrganizationParams{Name: strings.TrimSpace(input.Name)}
+	if params.Name == "" {
+		return params, fmt.Errorf("%w: name is required", ErrInvalidOrganization)
+	}
+
+	if cnpj := documentSeparators.Replace(strings.TrimSpace(input.CNPJ)); cnpj != "" {
+		if !validCNPJ(cnpj) {
+			return params, fmt.Errorf("%w: invalid CNPJ", ErrInvalidOrganization)
+		}
+		params.Cnpj = pgtype.Text{String: cnpj, Valid: true}
+	}
+	if uasg := strings.TrimSpace(input.UASG); uasg != "" {
+		if len(uasg) != 6 || onlyDigits(uasg) != uasg {
+			return params, fmt.Errorf("%w: UASG must have 6 digits", ErrInvalidOrganization)
+		}
+		params.Uasg = pgtype.Text{String: uasg, Valid: true}
+	}
+	return params, nil
+}
+
+// documentSeparators strips the punctuation of a formatted CNPJ.
+var documentSeparators = strings.NewReplacer(".", "", "/", "", "-", "", " ", "")
+
+// validCNPJ checks that digits is a CNPJ of 14 digits with valid check
+// digits. Repeated digits ("00000000000000") pass the check but are not
+// valid.
+func validCNPJ(digits string) bool {
+	if len(digits) != 14 || onlyDigits(digits) != digits || strings.Count(digits, digits[:1]) == 14 {
+		return false
+	}
+
+	checkDigit := func(n int) byte {
+		sum, weight := 0, n-7
+		for i := 0; i < n; i++ {
+			sum += int(digits[i]-'0') * weight
+			weight--
+			if weight < 2 {
+				weight = 9
+			}
+		}
+		if rest := sum % 11; rest >= 2 {
+			return byte('0' + 11 - rest)
+		}
+		return '0'
+	}
+	return digits[12] == checkDigit(12) && digits[13] == checkDigit(13)
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func organizationWriteError(action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrOrganizationExists
+	}
+	return fmt.Errorf("failed to %s organization: %w", action, err)
+}
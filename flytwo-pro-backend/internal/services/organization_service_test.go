@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidCNPJ(t *testing.T) {
+	tests := []struct {
+		cnpj string
+		want bool
+	}{
+		{"00394460005887", true},
+		{"11222333000181", true},
+		{"11222333000182", false}, // wrong check digit
+		{"1122233300018", false},  // too short
+		{"11222333000181a", false},
+		{"1122233300018a", false},
+		{"00000000000000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cnpj, func(t *testing.T) {
+			assert.Equal(t, tt.want, validCNPJ(tt.cnpj))
+		})
+	}
+}
+
+func TestCleanOrganizationInput(t *testing.T) {
+	params, err := cleanOrganizationInput(OrganizationInput{
+		Name: "  Ministério da Economia ",
+		CNPJ: "00.394.460/0058-87",
+		UASG: "170001",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ministério da Economia", params.Name)
+	assert.Equal(t, "00394460005887", params.Cnpj.String)
+	assert.Equal(t, "170001", params.Uasg.String)
+
+	params, err = cleanOrganizationInput(OrganizationInput{Name: "Prefeitura"})
+	assert.NoError(t, err)
+	assert.False(t, params.Cnpj.Valid)
+	assert.False(t, params.Uasg.Valid)
+
+	for _, input := range []OrganizationInput{
+		{Name: " "},
+		{Name: "Prefeitura", CNPJ: "00.394.460/0058-88"},
+		{Name: "Prefeitura", UASG: "17000"},
+		{Name: "Prefeitura", UASG: "17000a"},
+	} {
+		_, err := cleanOrganizationInput(input)
+		assert.True(t, errors.Is(err, ErrInvalidOrganization), "%+v", input)
+	}
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"gobid/internal/logger"
+	"gobid/internal/mailer"
+	"gobid/internal/ratelimit"
+	"gobid/internal/store/pgstore"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// PasswordResetConfig sets how long reset links last, where they point and
+// how often they can be requested.
+type PasswordResetConfig struct {
+	// TTL is how long a reset token works (default 1 hour).
+	TTL time.Duration
+	// ResetURL is the frontend page that receives the token; "{token}" is
+	// replaced by it (default "http://localhost:4200/reset-password?token={token}").
+	ResetURL string
+	// MaxPerEmail and MaxPerIP cap the requests per email address (default
+	// 3) and per client IP (default 20) within Window (default 1 hour).
+	// Unknown addresses count too, so the limit tells nothing about them.
+	MaxPerEmail int64
+	MaxPerIP    int64
+	// MaxAttemptsPerIP caps the tokens a client IP may present to
+	// ResetPassword within Window (default 20).
+	MaxAttemptsPerIP int64
+	Window           time.Duration
+	// Workers send the emails (default 2) from a queue of QueueSize requests
+	// (default 100); requests arriving with the queue full are dropped.
+	Workers   int
+	QueueSize int
+}
+
+// PasswordResetService emails single-use reset links and changes the
+// password of whoever presents one. Tokens are stored as SHA-256 hashes.
+// Requests are throttled in a ratelimit.Store and handled by a fixed pool
+// of workers.
+type PasswordResetService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	mailer  mailer.Mailer
+	store   ratelimit.Store
+	log     *zap.Logger
+	cfg     PasswordResetConfig
+
+	requests chan string
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewPasswordResetService(pool *pgxpool.Pool, m mailer.Mailer, store ratelimit.Store, cfg PasswordResetConfig) PasswordResetService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Hour
+	}
+	if cfg.ResetURL == "" {
+		cfg.ResetURL = "http://localhost:4200/reset-password?token={token}"
+	}
+	if cfg.MaxPerEmail <= 0 {
+		cfg.MaxPerEmail = 3
+	}
+	if cfg.MaxPerIP <= 0 {
+		cfg.MaxPerIP = 20
+	}
+	if cfg.MaxAttemptsPerIP <= 0 {
+		cfg.MaxAttemptsPerIP = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Hour
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 2
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+
+	return PasswordResetService{
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		mailer:   m,
+		store:    store,
+		log:      logger.Log,
+		cfg:      cfg,
+		requests: make(chan string, cfg.QueueSize),
+	}
+}
+
+// Start launches the workers that send reset emails.
+func (s *PasswordResetService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for range s.cfg.Workers {
+		s.wg.Add(1)
+		go s.sendLoop(ctx)
+	}
+}
+
+// Close stops the workers after the emails being sent; queued requests
+// are dropped.
+func (s *PasswordResetService) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// RequestReset queues a reset link for email if ip and email are within
+// their limits, and otherwise returns how long to wait. The email is sent
+// in the background, so neither the response nor its timing tells whether
+// the account exists.
+func (s *PasswordResetService) RequestReset(ctx context.Context, ip, email string) time.Duration {
+	// Emails are matched case-insensitively, by the limit and by the lookup
+	// in sendResetLink alike.
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, limit := range []struct {
+		key string
+		max int64
+	}{
+		{"reset:ip:" + ip, s.cfg.MaxPerIP},
+		{"reset:email:" + email, s.cfg.MaxPerEmail},
+	} {
+		if wait := s.throttle(ctx, limit.key, limit.max); wait > 0 {
+			s.log.Warn("password reset throttled", zap.String("ip", ip))
+			return wait
+		}
+	}
+
+	select {
+	case s.requests <- email:
+	default:
+		s.log.Warn("password reset queue full, dropping request", zap.String("ip", ip))
+	}
+	return 0
+}
+
+// AllowResetAttempt counts a token presented from ip and returns how long
+// to wait once the IP is over its limit, or zero.
+func (s *PasswordResetService) AllowResetAttempt(ctx context.Context, ip string) time.Duration {
+	wait := s.throttle(ctx, "reset:attempt:ip:"+ip, s.cfg.MaxAttemptsPerIP)
+	if wait > 0 {
+		s.log.Warn("password reset attempts throttled", zap.String("ip", ip))
+	}
+	return wait
+}
+
+// throttle counts a request against key and locks it for the rest of the
+// window once max is exceeded. Store errors let the request through.
+func (s *PasswordResetService) throttle(ctx context.Context, key string, max int64) time.Duration {
+	if wait, err := s.store.LockedFor(ctx, key); err != nil {
+		s.log.Error("password reset: failed to read limit", zap.String("store", s.store.Name()), zap.Error(err))
+		return 0
+	} else if wait > 0 {
+		return wait
+	}
+
+	count, err := s.store.Fail(ctx, key, s.cfg.Window)
+	if err != nil {
+		s.log.Error("password reset: failed to count request", zap.String("store", s.store.Name()), zap.Error(err))
+		return 0
+	}
+	if count <= max {
+		return 0
+	}
+	if err := s.store.Lock(ctx, key, s.cfg.Window); err != nil {
+		s.log.Error("password reset: failed to lock", zap.String("store", s.store.Name()), zap.Error(err))
+	}
+	return s.cfg.Window
+}
+
+func (s *PasswordResetService) sendLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case email := <-s.requests:
+			// Not tied to ctx, so an email being sent at shutdown completes.
+			sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			if err := s.sendResetLink(sendCtx, email); err != nil {
+				s.log.Error("password reset: failed to send link", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+func (s *PasswordResetService) sendResetLink(ctx context.Context, email string) error {
+	user, err := s.queries.FindUserByEmailInsensitive(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.log.Info("password reset requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := qtx.InvalidatePasswordResetTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+	}
+	if err := qtx.CreatePasswordResetToken(ctx, pgstore.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.TTL),
+	}); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reset token: %w", err)
+	}
+
+	if err := s.mailer.Send(ctx, s.resetMessage(user.Email, user.UserName, token)); err != nil {
+		return err
+	}
+
+	s.log.Info("password reset link sent", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+func (s *PasswordResetService) resetMessage(email, userName, token string) mailer.Message {
+	link := strings.ReplaceAll(s.cfg.ResetURL, "{token}", url.QueryEscape(token))
+	minutes := int(s.cfg.TTL.Minutes())
+
+	return mailer.Message{
+		To:      email,
+		Subject: "Redefinição de senha - FlyTwo Pro",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Recebemos um pedido para redefinir a senha da sua conta no FlyTwo Pro. "+
+			"Para escolher uma nova senha, acesse o link abaixo em até %d minutos:\n\n"+
+			"%s\n\n"+
+			"O link só pode ser usado uma vez. Se você não fez este pedido, ignore este email; "+
+			"sua senha continua a mesma.\n", userName, minutes, link),
+	}
+}
+
+// ResetPassword sets a new password with a reset token and returns the user
+// id. The token and any other pending token of the user stop working, and
+// the same transaction revokes the web sessions, refresh tokens and API keys
+// of the user.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, password string) (uuid.UUID, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	reset, err := qtx.GetPasswordResetTokenByHashForUpdate(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInvalidResetToken
+		}
+		return uuid.Nil, fmt.Errorf("failed to get reset token: %w", err)
+	}
+	if reset.UsedAt.Valid || !time.Now().Before(reset.ExpiresAt) {
+		return uuid.Nil, ErrInvalidResetToken
+	}
+
+	// Hashed only for a valid token, so made-up tokens cost no bcrypt work.
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := qtx.UpdateUserPassword(ctx, pgstore.UpdateUserPasswordParams{ID: reset.UserID, PasswordHash: hash}); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := qtx.InvalidatePasswordResetTokens(ctx, reset.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+	if _, err := qtx.RevokeUserRefreshTokens(ctx, reset.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if _, err := revokeSessions(ctx, qtx, reset.UserID, ""); err != nil {
+		return uuid.Nil, err
+	}
+	// A reset is account recovery: whoever had the account may have left
+	// API keys behind.
+	if _, err := qtx.RevokeUserApiKeys(ctx, reset.UserID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to revoke api keys: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit password reset: %w", err)
+	}
+
+	s.log.Info("password reset", zap.String("user_id", reset.UserID.String()))
+	return reset.UserID, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"gobid/internal/ratelimit"
+)
+
+func TestPasswordResetMessage(t *testing.T) {
+	s := NewPasswordResetService(nil, nil, ratelimit.NewMemoryStore(), PasswordResetConfig{
+		TTL:      30 * time.Minute,
+		ResetURL: "https://app.example.com/senha/nova?t={token}",
+	})
+
+	msg := s.resetMessage("user@example.com", "maria", "abc-123_x")
+
+	assert.Equal(t, "user@example.com", msg.To)
+	assert.Contains(t, msg.Body, "Olá, maria.")
+	assert.Contains(t, msg.Body, "https://app.example.com/senha/nova?t=abc-123_x\n")
+	assert.Contains(t, msg.Body, "30 minutos")
+}
+
+func TestPasswordResetThrottle(t *testing.T) {
+	s := NewPasswordResetService(nil, nil, ratelimit.NewMemoryStore(), PasswordResetConfig{
+		MaxPerEmail: 2,
+		MaxPerIP:    3,
+		QueueSize:   10,
+	})
+	s.log = zap.NewNop()
+	ctx := context.Background()
+
+	assert.Zero(t, s.RequestReset(ctx, "192.0.2.1", "user@example.com"))
+	assert.Zero(t, s.RequestReset(ctx, "192.0.2.1", "User@Example.com "))
+	assert.Equal(t, time.Hour, s.RequestReset(ctx, "192.0.2.1", "user@example.com"))
+	// The IP budget was spent on the third request as well.
+	assert.Equal(t, time.Hour, s.RequestReset(ctx, "192.0.2.1", "other@example.com"))
+	assert.Zero(t, s.RequestReset(ctx, "198.51.100.7", "other@example.com"))
+
+	assert.Len(t, s.requests, 3)
+	// Both spellings reach the lookup as the same address the limit counted.
+	assert.Equal(t, "user@example.com", <-s.requests)
+	assert.Equal(t, "user@example.com", <-s.requests)
+}
+
+func TestPasswordResetAttemptsPerIP(t *testing.T) {
+	s := NewPasswordResetService(nil, nil, ratelimit.NewMemoryStore(), PasswordResetConfig{MaxAttemptsPerIP: 2})
+	s.log = zap.NewNop()
+	ctx := context.Background()
+
+	assert.Zero(t, s.AllowResetAttempt(ctx, "192.0.2.1"))
+	assert.Zero(t, s.AllowResetAttempt(ctx, "192.0.2.1"))
+	assert.Equal(t, time.Hour, s.AllowResetAttempt(ctx, "192.0.2.1"))
+	assert.Zero(t, s.AllowResetAttempt(ctx, "198.51.100.7"))
+}
+
+func TestPasswordResetQueueFull(t *testing.T) {
+	s := NewPasswordResetService(nil, nil, ratelimit.NewMemoryStore(), PasswordResetConfig{QueueSize: 1})
+	s.log = zap.NewNop()
+	ctx := context.Background()
+
+	assert.Zero(t, s.RequestReset(ctx, "192.0.2.1", "a@example.com"))
+	assert.Zero(t, s.RequestReset(ctx, "192.0.2.1", "b@example.com"))
+
+	assert.Len(t, s.requests, 1)
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, err := generateToken()
+	assert.NoError(t, err)
+	b, err := generateToken()
+	assert.NoError(t, err)
+
+	assert.Len(t, a, 43)
+	assert.NotEqual(t, a, b)
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+// Account deletion states, used to filter the admin list.
+const (
+	AccountDeletionPending   = "pending"
+	AccountDeletionCancelled = "cancelled"
+	AccountDeletionCompleted = "completed"
+)
+
+// AccountDeletionStatuses lists every account deletion state.
+var AccountDeletionStatuses = []string{AccountDeletionPending, AccountDeletionCancelled, AccountDeletionCompleted}
+
+var (
+	ErrDeletionAlreadyRequested = errors.New("account deletion already requested")
+	ErrDeletionNotFound         = errors.New("no pending account deletion")
+)
+
+// dueDeletionBatch bounds the accounts deleted per purge run.
+const dueDeletionBatch = 100
+
+// UserData is everything stored about a user, gathered for a data export.
+// Secrets (password hash, token and key hashes, TOTP secret) are left out.
+type UserData struct {
+	User            pgstore.User
+	Roles           []string
+	TwoFactor       TwoFactorStatus
+	Sessions        []pgstore.ListUserSessionsRow
+	RefreshTokens   []pgstore.RefreshToken
+	ApiKeys         []pgstore.ApiKey
+	Identities      []pgstore.UserIdentity
+	Organizations   []pgstore.ListUserOrganizationsRow
+	ItemLists       []ItemListDetail // personal lists; shared ones belong to the organization
+	SavedSearches   []pgstore.SavedSearch
+	Notifications   []pgstore.Notification
+	Searches        []pgstore.SearchLog
+	SearchClicks    []pgstore.SearchClick
+	SecurityEvents  []pgstore.SecurityEvent
+	PendingDeletion *pgstore.AccountDeletion
+	ExportedAt      time.Time
+}
+
+// PrivacyConfig holds the account deletion settings.
+type PrivacyConfig struct {
+	// GracePeriod is how long a requested deletion waits before the account
+	// is deleted; it can be cancelled meanwhile.
+	GracePeriod   time.Duration
+	PurgeInterval time.Duration
+}
+
+// PrivacyService serves LGPD data subject requests: exporting what is stored
+// about a user and deleting accounts after a grace period. Deleting removes
+// the users row; tables that belong to the user cascade, while search logs,
+// security events and item lists shared with an organization only lose the
+// link (and, for security events, the email and IP).
+type PrivacyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+	cfg     PrivacyConfig
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPrivacyService(pool *pgxpool.Pool, cfg PrivacyConfig) PrivacyService {
+	if cfg.GracePeriod < 0 {
+		cfg.GracePeriod = 0
+	}
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = time.Hour
+	}
+
+	return PrivacyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+		cfg:     cfg,
+	}
+}
+
+// Start launches the loop that deletes accounts whose grace period is over.
+func (s *PrivacyService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.purgeLoop(ctx)
+}
+
+// Close stops the purge loop.
+func (s *PrivacyService) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// ExportUserData gathers the data of a user from a single snapshot.
+func (s *PrivacyService) ExportUserData(ctx context.Context, userID uuid.UUID) (*UserData, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	row, err := qtx.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	data := &UserData{
+		User: pgstore.User{
+			ID:              row.ID,
+			UserName:        row.UserName,
+			Email:           row.Email,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+			EmailVerifiedAt: row.EmailVerifiedAt,
+		},
+		ExportedAt: time.Now(),
+	}
+
+	if data.Roles, err = qtx.ListUserRoles(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %w", err)
+	}
+	state, err := qtx.GetTwoFactorState(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor state: %w", err)
+	}
+	data.TwoFactor = TwoFactorStatus{Enabled: state.Enabled, Required: state.Required}
+
+	if data.Sessions, err = qtx.ListUserSessions(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if data.RefreshTokens, err = qtx.ListRefreshTokensByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+	if data.ApiKeys, err = qtx.ListApiKeysByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	if data.Identities, err = qtx.ListUserIdentitiesByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list linked identities: %w", err)
+	}
+
+	if data.Organizations, err = qtx.ListUserOrganizations(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	lists, err := qtx.ListItemLists(ctx, pgstore.ListItemListsParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list item lists: %w", err)
+	}
+	data.ItemLists = make([]ItemListDetail, len(lists))
+	for i, list := range lists {
+		rows, err := qtx.ListItemListLines(ctx, list.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list item list lines: %w", err)
+		}
+		lines := make([]ItemListLine, len(rows))
+		for j, row := range rows {
+			lines[j] = ItemListLine{ListItemListLinesRow: row, Status: itemListLineStatus(row)}
+		}
+		data.ItemLists[i] = ItemListDetail{
+			ItemList: pgstore.ItemList{
+				ID:          list.ID,
+				UserID:      list.UserID,
+				Name:        list.Name,
+				Description: list.Description,
+				CreatedAt:   list.CreatedAt,
+				UpdatedAt:   list.UpdatedAt,
+			},
+			Lines: lines,
+		}
+	}
+
+	if data.SavedSearches, err = qtx.ListSavedSearchesByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	if data.Notifications, err = qtx.ListAllNotificationsByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	pgUserID := pgtype.UUID{Bytes: userID, Valid: true}
+	if data.Searches, err = qtx.ListSearchLogsByUser(ctx, pgUserID); err != nil {
+		return nil, fmt.Errorf("failed to list searches: %w", err)
+	}
+	if data.SearchClicks, err = qtx.ListSearchClicksByUser(ctx, pgUserID); err != nil {
+		return nil, fmt.Errorf("failed to list search clicks: %w", err)
+	}
+	// Events about failed logins with the email carry no user_id.
+	if data.SecurityEvents, err = qtx.ListSecurityEventsForUser(ctx, pgstore.ListSecurityEventsForUserParams{
+		UserID: pgUserID,
+		Email:  row.Email,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+
+	pending, err := qtx.GetPendingAccountDeletion(ctx, userID)
+	switch {
+	case err == nil:
+		data.PendingDeletion = &pending
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("failed to get account deletion: %w", err)
+	}
+
+	s.log.Info("user data exported", zap.String("user_id", userID.String()))
+	return data, nil
+}
+
+// RequestDeletion schedules the deletion of an account after the grace
+// period, or right away when immediate is set. Sessions, refresh tokens and
+// API keys are revoked at once; logging in again stays possible so the user
+// can cancel. The only admin left cannot be deleted (ErrLastAdmin).
+func (s *PrivacyService) RequestDeletion(ctx context.Context, userID, requestedBy uuid.UUID, immediate bool) (*pgstore.AccountDeletion, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := qtx.GetUserById(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := checkNotLastAdmin(ctx, qtx, userID); err != nil {
+		return nil, err
+	}
+
+	scheduledFor := time.Now().Add(s.cfg.GracePeriod)
+	if immediate {
+		scheduledFor = time.Now()
+	}
+	deletion, err := qtx.CreateAccountDeletion(ctx, pgstore.CreateAccountDeletionParams{
+		UserID:       userID,
+		RequestedBy:  requestedBy,
+		ScheduledFor: scheduledFor,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDeletionAlreadyRequested
+		}
+		return nil, fmt.Errorf("failed to create account deletion: %w", err)
+	}
+
+	if _, err := revokeSessions(ctx, qtx, userID, ""); err != nil {
+		return nil, err
+	}
+	if _, err := qtx.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if _, err := qtx.RevokeUserApiKeys(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to revoke API keys: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit account deletion: %w", err)
+	}
+
+	s.log.Info("account deletion requested",
+		zap.String("user_id", userID.String()),
+		zap.String("by", requestedBy.String()),
+		zap.Time("scheduled_for", scheduledFor))
+
+	if immediate {
+		if err := s.deleteAccount(ctx, userID); err != nil {
+			return nil, err
+		}
+		deletion.CompletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+	return &deletion, nil
+}
+
+// CancelDeletion cancels the pending deletion of an account.
+func (s *PrivacyService) CancelDeletion(ctx context.Context, userID, cancelledBy uuid.UUID) (*pgstore.AccountDeletion, error) {
+	deletion, err := s.queries.CancelAccountDeletion(ctx, pgstore.CancelAccountDeletionParams{
+		UserID:      userID,
+		CancelledBy: pgtype.UUID{Bytes: cancelledBy, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeletionNotFound
+		}
+		return nil, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	s.log.Info("account deletion cancelled",
+		zap.String("user_id", userID.String()),
+		zap.String("by", cancelledBy.String()))
+	return &deletion, nil
+}
+
+// GetPendingDeletion returns the pending deletion of an account.
+func (s *PrivacyService) GetPendingDeletion(ctx context.Context, userID uuid.UUID) (*pgstore.AccountDeletion, error) {
+	deletion, err := s.queries.GetPendingAccountDeletion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeletionNotFound
+		}
+		return nil, fmt.Errorf("failed to get account deletion: %w", err)
+	}
+	return &deletion, nil
+}
+
+// ListDeletions returns a page of deletion requests, newest first, and the
+// total. An empty status lists all of them.
+func (s *PrivacyService) ListDeletions(ctx context.Context, status string, limit, offset int32) ([]pgstore.ListAccountDeletionsRow, int64, error) {
+	filter := pgtype.Text{}
+	if status != "" {
+		filter = pgtype.Text{String: status, Valid: true}
+	}
+
+	deletions, err := s.queries.ListAccountDeletions(ctx, pgstore.ListAccountDeletionsParams{
+		Limit:  limit,
+		Offset: offset,
+		Status: filter,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list account deletions: %w", err)
+	}
+	total, err := s.queries.CountAccountDeletions(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count account deletions: %w", err)
+	}
+	if deletions == nil {
+		deletions = []pgstore.ListAccountDeletionsRow{}
+	}
+	return deletions, total, nil
+}
+
+// ProcessDueDeletions deletes the accounts whose grace period is over and
+// returns how many were deleted. A failing account is logged and left
+// pending for the next run.
+func (s *PrivacyService) ProcessDueDeletions(ctx context.Context) (int, error) {
+	due, err := s.queries.ListDueAccountDeletions(ctx, dueDeletionBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due account deletions: %w", err)
+	}
+
+	deleted := 0
+	for _, userID := range due {
+		if err := s.deleteAccount(ctx, userID); err != nil {
+			s.log.Error("account deletion failed",
+				zap.String("user_id", userID.String()),
+				zap.Error(err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// deleteAccount removes a user with a pending deletion and marks the
+// request completed.
+func (s *PrivacyService) deleteAccount(ctx context.Context, userID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	deletion, err := qtx.GetPendingAccountDeletionForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeletionNotFound
+		}
+		return fmt.Errorf("failed to lock account deletion: %w", err)
+	}
+
+	user, err := qtx.GetUserById(ctx, userID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// Already gone; only the request is left to close.
+	case err != nil:
+		return fmt.Errorf("failed to get user: %w", err)
+	default:
+		if err := checkNotLastAdmin(ctx, qtx, userID); err != nil {
+			return err
+		}
+		if _, err := qtx.AnonymizeSecurityEvents(ctx, pgstore.AnonymizeSecurityEventsParams{
+			UserID: pgtype.UUID{Bytes: userID, Valid: true},
+			Email:  user.Email,
+		}); err != nil {
+			return fmt.Errorf("failed to anonymize security events: %w", err)
+		}
+		// Sessions opened since the request; the scs rows have no FK.
+		if _, err := revokeSessions(ctx, qtx, userID, ""); err != nil {
+			return err
+		}
+		// Lists shared with an organization stay with it and only lose the
+		// link to the user.
+		if _, err := qtx.DeletePersonalItemListsByUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete item lists: %w", err)
+		}
+		if _, err := qtx.DeleteUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+	}
+
+	if err := qtx.CompleteAccountDeletion(ctx, deletion.ID); err != nil {
+		return fmt.Errorf("failed to complete account deletion: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit account deletion: %w", err)
+	}
+
+	s.log.Info("account deleted",
+		zap.String("user_id", userID.String()),
+		zap.String("deletion_id", deletion.ID.String()))
+	return nil
+}
+
+func (s *PrivacyService) purgeLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.ProcessDueDeletions(ctx)
+		if err != nil {
+			s.log.Error("account deletion: purge failed", zap.Error(err))
+		} else if deleted > 0 {
+			s.log.Info("account deletion: purge finished", zap.Int("accounts", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkNotLastAdmin returns ErrLastAdmin when userID is the only admin. The
+// admin rows stay locked until the transaction ends.
+func checkNotLastAdmin(ctx context.Context, q *pgstore.Queries, userID uuid.UUID) error {
+	admins, err := q.LockAdminUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to lock admins: %w", err)
+	}
+	if slices.Equal(admins, []uuid.UUID{userID}) {
+		return ErrLastAdmin
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleBuyer   = "buyer"
+	RoleViewer  = "viewer"
+)
+
+// DefaultRole is granted to users who sign up.
+const DefaultRole = RoleBuyer
+
+// ValidRoles lists every role, most privileged first.
+var ValidRoles = []string{RoleAdmin, RoleManager, RoleBuyer, RoleViewer}
+
+var (
+	ErrInvalidRole = errors.New("invalid role")
+	ErrLastAdmin   = errors.New("cannot remove the last admin")
+)
+
+// HasAnyRole reports whether roles include one of want. Admins pass every
+// check.
+func HasAnyRole(roles []string, want ...string) bool {
+	for _, role := range roles {
+		if role == RoleAdmin || slices.Contains(want, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleService stores the roles of each user and bootstraps the first admin.
+type RoleService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+
+	// bootstrapAdminEmail is promoted to admin while no admin exists.
+	bootstrapAdminEmail string
+}
+
+func NewRoleService(pool *pgxpool.Pool, bootstrapAdminEmail string) RoleService {
+	return RoleService{
+		pool:                pool,
+		queries:             pgstore.New(pool),
+		log:                 logger.Log,
+		bootstrapAdminEmail: strings.TrimSpace(bootstrapAdminEmail),
+	}
+}
+
+// GetUserRoles returns the roles of a user, sorted.
+func (s *RoleService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	roles, err := s.queries.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %w", err)
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of a user. Removing admin from the only
+// admin left returns ErrLastAdmin.
+func (s *RoleService) SetUserRoles(ctx context.Context, userID uuid.UUID, roles []string) ([]string, error) {
+	clean, err := cleanRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := qtx.GetUserById(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Locking the admin rows serializes concurrent demotions.
+	admins, err := qtx.LockAdminUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock admins: %w", err)
+	}
+	if !slices.Contains(clean, RoleAdmin) && slices.Equal(admins, []uuid.UUID{userID}) {
+		return nil, ErrLastAdmin
+	}
+
+	if err := qtx.DeleteUserRoles(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to clear user roles: %w", err)
+	}
+	for _, role := range clean {
+		if err := qtx.AddUserRole(ctx, pgstore.AddUserRoleParams{UserID: userID, Role: role}); err != nil {
+			return nil, fmt.Errorf("failed to add user role: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit user roles: %w", err)
+	}
+
+	s.log.Info("user roles updated",
+		zap.String("user_id", userID.String()),
+		zap.Strings("roles", clean))
+	return clean, nil
+}
+
+// ListUsers returns a page of users with their roles and the total count.
+func (s *RoleService) ListUsers(ctx context.Context, limit, offset int32) ([]pgstore.ListUsersWithRolesRow, int64, error) {
+	users, err := s.queries.ListUsersWithRoles(ctx, pgstore.ListUsersWithRolesParams{Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	total, err := s.queries.CountUsers(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	if users == nil {
+		users = []pgstore.ListUsersWithRolesRow{}
+	}
+	return users, total, nil
+}
+
+// BootstrapAdmin makes the configured bootstrap user an admin when there is
+// no admin yet. It runs at startup and after each signup, so the account may
+// be created before or after the deploy.
+func (s *RoleService) BootstrapAdmin(ctx context.Context) (bool, error) {
+	if s.bootstrapAdminEmail == "" {
+		return false, nil
+	}
+
+	granted, err := s.queries.BootstrapAdmin(ctx, s.bootstrapAdminEmail)
+	if err != nil {
+		return false, fmt.Errorf("failed to bootstrap admin: %w", err)
+	}
+	if granted > 0 {
+		s.log.Info("bootstrap admin granted", zap.String("email", s.bootstrapAdminEmail))
+	}
+	return granted > 0, nil
+}
+
+// cleanRoles lowercases, validates, de-duplicates and sorts roles.
+func cleanRoles(roles []string) ([]string, error) {
+	seen := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if !slices.Contains(ValidRoles, role) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+		}
+		seen[role] = true
+	}
+
+	clean := make([]string, 0, len(seen))
+	for role := range seen {
+		clean = append(clean, role)
+	}
+	slices.Sort(clean)
+	return clean, nil
+}
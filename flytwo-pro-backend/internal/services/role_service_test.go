@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasAnyRole(t *testing.T) {
+	assert.True(t, HasAnyRole([]string{RoleBuyer}, RoleBuyer, RoleManager))
+	assert.True(t, HasAnyRole([]string{RoleAdmin}, RoleManager))
+	assert.False(t, HasAnyRole([]string{RoleViewer}, RoleManager))
+	assert.False(t, HasAnyRole(nil, RoleViewer))
+}
+
+func TestCleanRoles(t *testing.T) {
+	roles, err := cleanRoles([]string{" Manager", "buyer", "manager"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"buyer", "manager"}, roles)
+
+	roles, err = cleanRoles(nil)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	_, err = cleanRoles([]string{"superuser"})
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrInvalidSavedSearch   = errors.New("saved search needs a catalog and a query or at least one filter")
+	ErrSavedSearchLimit     = errors.New("saved search limit reached")
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// maxSavedSearchesPerUser bounds how many searches a single user re-evaluates on every import.
+const maxSavedSearchesPerUser = 50
+
+// SavedSearchInput holds the name and search of a saved search. Only the
+// params of the selected catalog are used; limit and offset are ignored.
+type SavedSearchInput struct {
+	Name    string
+	Catalog string // "catmat" or "catser"
+	Catmat  CatmatSearchParams
+	Catser  CatserSearchParams
+}
+
+// SavedSearchService stores user searches and notifies users when an import
+// adds items that match them.
+type SavedSearchService struct {
+	pool     *pgxpool.Pool
+	queries  *pgstore.Queries
+	log      *zap.Logger
+	synonyms QueryExpander
+}
+
+func NewSavedSearchService(pool *pgxpool.Pool, synonyms QueryExpander) SavedSearchService {
+	return SavedSearchService{
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		log:      logger.Log,
+		synonyms: synonyms,
+	}
+}
+
+// ListSavedSearches returns the user's saved searches, newest first.
+func (s *SavedSearchService) ListSavedSearches(ctx context.Context, userID uuid.UUID) ([]pgstore.SavedSearch, error) {
+	searches, err := s.queries.ListSavedSearchesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	if searches == nil {
+		searches = []pgstore.SavedSearch{}
+	}
+	return searches, nil
+}
+
+// CreateSavedSearch stores a search and records the items it already matches,
+// so only items added by later imports generate notifications.
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, userID uuid.UUID, input SavedSearchInput) (*pgstore.SavedSearch, error) {
+	params, err := savedSearchParams(input)
+	if err != nil {
+		return nil, err
+	}
+	params.UserID = userID
+
+	count, err := s.queries.CountSavedSearchesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+	if count >= maxSavedSearchesPerUser {
+		return nil, ErrSavedSearchLimit
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	search, err := qtx.CreateSavedSearch(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	if _, err := s.matchNewItems(ctx, qtx, search); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit saved search: %w", err)
+	}
+	return &search, nil
+}
+
+// UpdateSavedSearch replaces a saved search. Previous matches are discarded
+// and the new search starts from the items it matches now.
+func (s *SavedSearchService) UpdateSavedSearch(ctx context.Context, userID, id uuid.UUID, input SavedSearchInput) (*pgstore.SavedSearch, error) {
+	params, err := savedSearchParams(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	search, err := qtx.UpdateSavedSearch(ctx, pgstore.UpdateSavedSearchParams{
+		ID:          id,
+		UserID:      userID,
+		Name:        params.Name,
+		Catalog:     params.Catalog,
+		Query:       params.Query,
+		GroupCode:   params.GroupCode,
+		ClassCode:   params.ClassCode,
+		PdmCode:     params.PdmCode,
+		NcmCode:     params.NcmCode,
+		ServiceCode: params.ServiceCode,
+		Status:      params.Status,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSavedSearchNotFound
+		}
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+
+	if err := qtx.ClearSavedSearchMatches(ctx, search.ID); err != nil {
+		return nil, fmt.Errorf("failed to clear saved search matches: %w", err)
+	}
+	if _, err := s.matchNewItems(ctx, qtx, search); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit saved search: %w", err)
+	}
+	return &search, nil
+}
+
+// DeleteSavedSearch removes a saved search. Its notifications are kept.
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	affected, err := s.queries.DeleteSavedSearch(ctx, pgstore.DeleteSavedSearchParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if affected == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// EvaluateCatalog re-runs every saved search of a catalog and creates a
+// notification for each one that matches items it had not matched before.
+// A failing search is logged and skipped; the errors are returned joined.
+func (s *SavedSearchService) EvaluateCatalog(ctx context.Context, catalog string) (int, error) {
+	searches, err := s.queries.ListSavedSearchesByCatalog(ctx, catalog)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	notified := 0
+	var errs []error
+	for _, search := range searches {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		created, err := s.evaluate(ctx, search)
+		if err != nil {
+			s.log.Error("saved search: evaluation failed",
+				zap.String("saved_search_id", search.ID.String()),
+				zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		if created {
+			notified++
+		}
+	}
+
+	s.log.Info("saved searches evaluated",
+		zap.String("catalog", catalog),
+		zap.Int("searches", len(searches)),
+		zap.Int("notifications", notified))
+
+	return notified, errors.Join(errs...)
+}
+
+// ListNotifications returns a page of the user's notifications and the unread count.
+func (s *SavedSearchService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]pgstore.Notification, int64, error) {
+	notifications, err := s.queries.ListNotificationsByUser(ctx, pgstore.ListNotificationsByUserParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	if notifications == nil {
+		notifications = []pgstore.Notification{}
+	}
+
+	unread, err := s.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return notifications, unread, nil
+}
+
+// MarkNotificationRead marks one unread notification of the user as read.
+func (s *SavedSearchService) MarkNotificationRead(ctx context.Context, userID uuid.UUID, id int64) error {
+	affected, err := s.queries.MarkNotificationRead(ctx, pgstore.MarkNotificationReadParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user as read.
+func (s *SavedSearchService) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	affected, err := s.queries.MarkAllNotificationsRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return affected, nil
+}
+
+// evaluate records the new matches of one saved search and notifies its owner.
+func (s *SavedSearchService) evaluate(ctx context.Context, search pgstore.SavedSearch) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	codes, err := s.matchNewItems(ctx, qtx, search)
+	if err != nil {
+		return false, err
+	}
+
+	if len(codes) > 0 {
+		slices.Sort(codes)
+		if _, err := qtx.CreateNotification(ctx, pgstore.CreateNotificationParams{
+			UserID:          search.UserID,
+			SavedSearchID:   pgtype.UUID{Bytes: search.ID, Valid: true},
+			SavedSearchName: search.Name,
+			Catalog:         search.Catalog,
+			ItemCodes:       codes,
+		}); err != nil {
+			return false, fmt.Errorf("failed to create notification: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit saved search evaluation: %w", err)
+	}
+	return len(codes) > 0, nil
+}
+
+// matchNewItems stores the items the search matches that were not stored
+// before and returns their codes. The query goes through the synonym
+// dictionary, as in the regular search.
+func (s *SavedSearchService) matchNewItems(ctx context.Context, q *pgstore.Queries, search pgstore.SavedSearch) ([]int32, error) {
+	var query pgtype.Text
+	if search.Query != "" {
+		expanded := search.Query
+		if s.synonyms != nil {
+			expanded = s.synonyms.ExpandQuery(ctx, search.Query)
+		}
+		query = pgtype.Text{String: expanded, Valid: true}
+	}
+
+	var codes []int32
+	var err error
+	switch search.Catalog {
+	case "catmat":
+		codes, err = q.InsertCatmatSavedSearchMatches(ctx, pgstore.InsertCatmatSavedSearchMatchesParams{
+			SavedSearchID: search.ID,
+			Query:         query,
+			GroupCode:     search.GroupCode,
+			ClassCode:     search.ClassCode,
+			PdmCode:       search.PdmCode,
+			NcmCode:       search.NcmCode,
+		})
+	case "catser":
+		codes, err = q.InsertCatserSavedSearchMatches(ctx, pgstore.InsertCatserSavedSearchMatchesParams{
+			SavedSearchID: search.ID,
+			Query:         query,
+			GroupCode:     search.GroupCode,
+			ClassCode:     search.ClassCode,
+			ServiceCode:   search.ServiceCode,
+			Status:        search.Status,
+		})
+	default:
+		return nil, fmt.Errorf("unknown catalog %q", search.Catalog)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match saved search items: %w", err)
+	}
+
+	if err := q.MarkSavedSearchEvaluated(ctx, search.ID); err != nil {
+		return nil, fmt.Errorf("failed to mark saved search as evaluated: %w", err)
+	}
+	return codes, nil
+}
+
+// savedSearchParams validates the input and maps the filters of its catalog.
+// A search without query and filters would match the whole catalog, so it is rejected.
+func savedSearchParams(input SavedSearchInput) (pgstore.CreateSavedSearchParams, error) {
+	params := pgstore.CreateSavedSearchParams{
+		Name:    strings.TrimSpace(input.Name),
+		Catalog: input.Catalog,
+	}
+	if params.Name == "" {
+		return params, ErrInvalidSavedSearch
+	}
+
+	switch input.Catalog {
+	case "catmat":
+		p := input.Catmat
+		params.Query = strings.TrimSpace(p.Query)
+		params.GroupCode = toPgInt2(p.GroupCode)
+		params.ClassCode = toPgInt4(p.ClassCode)
+		params.PdmCode = toPgInt4(p.PdmCode)
+		params.NcmCode = toPgText(p.NcmCode)
+	case "catser":
+		p := input.Catser
+		params.Query = strings.TrimSpace(p.Query)
+		params.GroupCode = toPgInt2(p.GroupCode)
+		params.ClassCode = toPgInt4(p.ClassCode)
+		params.ServiceCode = toPgInt4(p.ServiceCode)
+		params.Status = toPgText(p.Status)
+	default:
+		return params, ErrInvalidSavedSearch
+	}
+
+	hasFilter := params.GroupCode.Valid || params.ClassCode.Valid || params.PdmCode.Valid ||
+		params.NcmCode.Valid || params.ServiceCode.Valid || params.Status.Valid
+	if params.Query == "" && !hasFilter {
+		return params, ErrInvalidSavedSearch
+	}
+
+	return params, nil
+}
+
+func toPgInt2(value *int16) pgtype.Int2 {
+	if value == nil {
+		return pgtype.Int2{}
+	}
+	return pgtype.Int2{Int16: *value, Valid: true}
+}
+
+func toPgInt4(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
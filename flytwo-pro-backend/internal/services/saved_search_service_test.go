@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedSearchParams_Catmat(t *testing.T) {
+	groupCode := int16(75)
+	ncm := "48025610"
+
+	params, err := savedSearchParams(SavedSearchInput{
+		Name:    "  Papel  ",
+		Catalog: "catmat",
+		Catmat:  CatmatSearchParams{Query: " papel a4 ", GroupCode: &groupCode, NcmCode: &ncm, Limit: 10},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Papel", params.Name)
+	assert.Equal(t, "papel a4", params.Query)
+	assert.True(t, params.GroupCode.Valid)
+	assert.Equal(t, int16(75), params.GroupCode.Int16)
+	assert.Equal(t, "48025610", params.NcmCode.String)
+	assert.False(t, params.ClassCode.Valid)
+	assert.False(t, params.ServiceCode.Valid)
+}
+
+func TestSavedSearchParams_CatserFilterOnly(t *testing.T) {
+	status := "Ativo"
+
+	params, err := savedSearchParams(SavedSearchInput{
+		Name:    "Serviços ativos",
+		Catalog: "catser",
+		Catser:  CatserSearchParams{Status: &status},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", params.Query)
+	assert.Equal(t, "Ativo", params.Status.String)
+}
+
+func TestSavedSearchParams_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input SavedSearchInput
+	}{
+		{"no query or filter", SavedSearchInput{Name: "Tudo", Catalog: "catmat"}},
+		{"blank query", SavedSearchInput{Name: "Tudo", Catalog: "catser", Catser: CatserSearchParams{Query: "   "}}},
+		{"blank name", SavedSearchInput{Name: " ", Catalog: "catmat", Catmat: CatmatSearchParams{Query: "papel"}}},
+		{"unknown catalog", SavedSearchInput{Name: "X", Catalog: "foo", Catmat: CatmatSearchParams{Query: "papel"}}},
+		{"filter of the other catalog", SavedSearchInput{Name: "X", Catalog: "catser", Catmat: CatmatSearchParams{Query: "papel"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := savedSearchParams(tt.input)
+			assert.True(t, errors.Is(err, ErrInvalidSavedSearch))
+		})
+	}
+}
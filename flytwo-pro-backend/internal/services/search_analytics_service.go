@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/dto"
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+// SearchLogEntry is a single catalog search recorded for analytics.
+type SearchLogEntry struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID // uuid.Nil when the search has no session user
+	Catalog     string    // "catmat" or "catser"
+	Query       string
+	Filters     map[string]any
+	ResultCount int64
+	Latency     time.Duration
+	CreatedAt   time.Time
+}
+
+// SearchClick records a click on a search result.
+type SearchClick struct {
+	SearchID uuid.UUID
+	UserID   uuid.UUID
+	ItemCode int32
+	Position *int32
+}
+
+// SearchReportParams filters the analytics reports.
+type SearchReportParams struct {
+	From    time.Time
+	To      time.Time
+	Catalog *string
+	Limit   int32
+}
+
+// SearchAnalyticsConfig holds buffering and retention settings for the search log.
+type SearchAnalyticsConfig struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	// Retention is how long search_log/search_click rows are kept; zero keeps them forever.
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// SearchAnalyticsService records searches asynchronously and builds reports on them.
+type SearchAnalyticsService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+	cfg     SearchAnalyticsConfig
+
+	entries chan SearchLogEntry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewSearchAnalyticsService(pool *pgxpool.Pool, cfg SearchAnalyticsConfig) SearchAnalyticsService {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 2 * time.Second
+	}
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = 24 * time.Hour
+	}
+
+	return SearchAnalyticsService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+		cfg:     cfg,
+		entries: make(chan SearchLogEntry, cfg.BufferSize),
+	}
+}
+
+// Start launches the background writer and, when retention is set, the purge loop.
+func (s *SearchAnalyticsService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.writeLoop(ctx)
+
+	if s.cfg.Retention > 0 {
+		s.wg.Add(1)
+		go s.purgeLoop(ctx)
+	}
+}
+
+// Close stops the background loops after flushing buffered entries.
+func (s *SearchAnalyticsService) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// RecordSearch queues a search for persistence without blocking the request.
+// Entries are dropped when the buffer is full.
+func (s *SearchAnalyticsService) RecordSearch(entry SearchLogEntry) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	entry.Query = normalizeSearchText(entry.Query)
+
+	select {
+	case s.entries <- entry:
+	default:
+		s.log.Warn("search log buffer full, dropping entry",
+			zap.String("catalog", entry.Catalog),
+			zap.String("query", entry.Query))
+	}
+}
+
+// RecordClick stores a click on a search result.
+func (s *SearchAnalyticsService) RecordClick(ctx context.Context, click SearchClick) error {
+	params := pgstore.InsertSearchClickParams{
+		SearchID: click.SearchID,
+		UserID:   toPgUUID(click.UserID),
+		ItemCode: click.ItemCode,
+	}
+	if click.Position != nil {
+		params.Position = pgtype.Int4{Int32: *click.Position, Valid: true}
+	}
+
+	if err := s.queries.InsertSearchClick(ctx, params); err != nil {
+		return fmt.Errorf("failed to record search click: %w", err)
+	}
+	return nil
+}
+
+// TopQueries returns the most frequent non-empty queries with their click-through rate.
+func (s *SearchAnalyticsService) TopQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error) {
+	rows, err := s.queries.TopSearchQueries(ctx, pgstore.TopSearchQueriesParams{
+		From:    params.From,
+		To:      params.To,
+		Catalog: toPgText(params.Catalog),
+		Limit:   params.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top search queries: %w", err)
+	}
+
+	stats := make([]dto.SearchQueryStat, len(rows))
+	for i, row := range rows {
+		stats[i] = dto.SearchQueryStat{
+			Catalog:           row.Catalog,
+			Query:             row.Query,
+			Searches:          row.Searches,
+			SearchesWithClick: row.SearchesWithClick,
+			ClickThroughRate:  float64(row.SearchesWithClick) / float64(row.Searches),
+			AvgResults:        row.AvgResults,
+			AvgLatencyMs:      row.AvgLatencyMs,
+			MaxLatencyMs:      row.MaxLatencyMs,
+			LastSearchedAt:    row.LastSearchedAt.Format(time.RFC3339),
+		}
+	}
+	return stats, nil
+}
+
+// ZeroResultQueries returns the queries that most often found nothing.
+func (s *SearchAnalyticsService) ZeroResultQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error) {
+	rows, err := s.queries.ZeroResultSearchQueries(ctx, pgstore.ZeroResultSearchQueriesParams{
+		From:    params.From,
+		To:      params.To,
+		Catalog: toPgText(params.Catalog),
+		Limit:   params.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zero-result search queries: %w", err)
+	}
+
+	stats := make([]dto.SearchQueryStat, len(rows))
+	for i, row := range rows {
+		stats[i] = dto.SearchQueryStat{
+			Catalog:        row.Catalog,
+			Query:          row.Query,
+			Searches:       row.Searches,
+			AvgLatencyMs:   row.AvgLatencyMs,
+			MaxLatencyMs:   row.MaxLatencyMs,
+			LastSearchedAt: row.LastSearchedAt.Format(time.RFC3339),
+		}
+	}
+	return stats, nil
+}
+
+// SlowestQueries returns the queries with the highest latency.
+func (s *SearchAnalyticsService) SlowestQueries(ctx context.Context, params SearchReportParams) ([]dto.SearchQueryStat, error) {
+	rows, err := s.queries.SlowestSearchQueries(ctx, pgstore.SlowestSearchQueriesParams{
+		From:    params.From,
+		To:      params.To,
+		Catalog: toPgText(params.Catalog),
+		Limit:   params.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slowest search queries: %w", err)
+	}
+
+	stats := make([]dto.SearchQueryStat, len(rows))
+	for i, row := range rows {
+		stats[i] = dto.SearchQueryStat{
+			Catalog:        row.Catalog,
+			Query:          row.Query,
+			Searches:       row.Searches,
+			AvgResults:     row.AvgResults,
+			AvgLatencyMs:   row.AvgLatencyMs,
+			MaxLatencyMs:   row.MaxLatencyMs,
+			LastSearchedAt: row.LastSearchedAt.Format(time.RFC3339),
+		}
+	}
+	return stats, nil
+}
+
+func (s *SearchAnalyticsService) writeLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SearchLogEntry, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.cfg.BatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-s.entries:
+					batch = append(batch, entry)
+				default:
+					if len(batch) > 0 {
+						s.flush(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch in one transaction. It uses its own context so a
+// shutdown still persists what was buffered.
+func (s *SearchAnalyticsService) flush(batch []SearchLogEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("search log: failed to begin transaction", zap.Error(err), zap.Int("entries", len(batch)))
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	for _, entry := range batch {
+		filters, err := json.Marshal(entry.Filters)
+		if err != nil || entry.Filters == nil {
+			filters = []byte("{}")
+		}
+
+		if err := qtx.InsertSearchLog(ctx, pgstore.InsertSearchLogParams{
+			ID:          entry.ID,
+			UserID:      toPgUUID(entry.UserID),
+			Catalog:     entry.Catalog,
+			Query:       entry.Query,
+			Filters:     filters,
+			ResultCount: entry.ResultCount,
+			LatencyMs:   int32(entry.Latency.Milliseconds()),
+			CreatedAt:   entry.CreatedAt,
+		}); err != nil {
+			s.log.Error("search log: failed to insert entry", zap.Error(err), zap.Int("entries", len(batch)))
+			return
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("search log: failed to commit", zap.Error(err), zap.Int("entries", len(batch)))
+		return
+	}
+
+	s.log.Debug("search log flushed", zap.Int("entries", len(batch)))
+}
+
+func (s *SearchAnalyticsService) purgeLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SearchAnalyticsService) purge(ctx context.Context) {
+	cutoff := time.Now().Add(-s.cfg.Retention)
+
+	logs, err := s.queries.DeleteSearchLogsBefore(ctx, cutoff)
+	if err != nil {
+		s.log.Error("search log: purge failed", zap.Error(err))
+		return
+	}
+	clicks, err := s.queries.DeleteSearchClicksBefore(ctx, cutoff)
+	if err != nil {
+		s.log.Error("search click: purge failed", zap.Error(err))
+		return
+	}
+
+	s.log.Info("search log purged",
+		zap.Time("before", cutoff),
+		zap.Int64("searches", logs),
+		zap.Int64("clicks", clicks))
+}
+
+func toPgUUID(id uuid.UUID) pgtype.UUID {
+	if id == uuid.Nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func toPgText(value *string) pgtype.Text {
+	if value == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *value, Valid: true}
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrSynonymNotFound     = errors.New("synonym group not found")
+	ErrInvalidSynonymGroup = errors.New("synonym group must have at least two distinct terms")
+)
+
+const (
+	// synonymRefreshInterval bounds how long a replica keeps serving a
+	// dictionary edited on another instance.
+	synonymRefreshInterval = time.Minute
+
+	// maxSynonymClauses caps the OR clauses produced by expansion; items past
+	// the cap are kept as typed.
+	maxSynonymClauses = 32
+
+	// maxSynonymTermWords is the longest multi-word term matched in unquoted input.
+	maxSynonymTermWords = 4
+)
+
+// QueryExpander rewrites a free-text query before it reaches websearch_to_tsquery.
+type QueryExpander interface {
+	ExpandQuery(ctx context.Context, query string) string
+}
+
+// SearchSynonymService manages the synonym dictionary and expands search queries with it.
+type SearchSynonymService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+
+	mu       sync.RWMutex
+	dict     synonymDictionary
+	loadedAt time.Time
+}
+
+func NewSearchSynonymService(pool *pgxpool.Pool) SearchSynonymService {
+	return SearchSynonymService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// ListSynonyms returns every synonym group ordered by id.
+func (s *SearchSynonymService) ListSynonyms(ctx context.Context) ([]pgstore.SearchSynonym, error) {
+	groups, err := s.queries.ListSearchSynonyms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list synonyms: %w", err)
+	}
+	if groups == nil {
+		groups = []pgstore.SearchSynonym{}
+	}
+	return groups, nil
+}
+
+// CreateSynonym stores a new group of equivalent terms.
+func (s *SearchSynonymService) CreateSynonym(ctx context.Context, terms []string) (*pgstore.SearchSynonym, error) {
+	clean, err := cleanSynonymTerms(terms)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.queries.CreateSearchSynonym(ctx, clean)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create synonym group: %w", err)
+	}
+
+	s.invalidate()
+	return &group, nil
+}
+
+// UpdateSynonym replaces the terms of an existing group.
+func (s *SearchSynonymService) UpdateSynonym(ctx context.Context, id int64, terms []string) (*pgstore.SearchSynonym, error) {
+	clean, err := cleanSynonymTerms(terms)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.queries.UpdateSearchSynonym(ctx, pgstore.UpdateSearchSynonymParams{ID: id, Terms: clean})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSynonymNotFound
+		}
+		return nil, fmt.Errorf("failed to update synonym group: %w", err)
+	}
+
+	s.invalidate()
+	return &group, nil
+}
+
+// DeleteSynonym removes a group.
+func (s *SearchSynonymService) DeleteSynonym(ctx context.Context, id int64) error {
+	affected, err := s.queries.DeleteSearchSynonym(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete synonym group: %w", err)
+	}
+	if affected == 0 {
+		return ErrSynonymNotFound
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// ExpandQuery rewrites query with OR groups of synonyms. Search results are
+// cached under the expanded query, so a dictionary change moves affected
+// searches to new cache keys.
+func (s *SearchSynonymService) ExpandQuery(ctx context.Context, query string) string {
+	if strings.TrimSpace(query) == "" {
+		return query
+	}
+	return s.dictionary(ctx).expand(query)
+}
+
+func (s *SearchSynonymService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *SearchSynonymService) dictionary(ctx context.Context) synonymDictionary {
+	s.mu.RLock()
+	dict, loadedAt := s.dict, s.loadedAt
+	s.mu.RUnlock()
+
+	if !loadedAt.IsZero() && time.Since(loadedAt) < synonymRefreshInterval {
+		return dict
+	}
+
+	groups, err := s.queries.ListSearchSynonyms(ctx)
+	if err != nil {
+		// Keep serving the previous dictionary; searches must not fail because of synonyms.
+		s.log.Warn("failed to load synonym dictionary", zap.Error(err))
+		return dict
+	}
+
+	terms := make([][]string, len(groups))
+	for i, g := range groups {
+		terms[i] = g.Terms
+	}
+	dict = newSynonymDictionary(terms)
+
+	s.mu.Lock()
+	s.dict = dict
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	s.log.Debug("synonym dictionary loaded", zap.Int("groups", len(groups)))
+	return dict
+}
+
+// cleanSynonymTerms trims, lowercases and de-duplicates terms. Quotes and a
+// leading "-" are stripped since they are websearch_to_tsquery operators.
+func cleanSynonymTerms(terms []string) ([]string, error) {
+	seen := make(map[string]bool, len(terms))
+	clean := make([]string, 0, len(terms))
+
+	for _, t := range terms {
+		t = strings.ReplaceAll(t, `"`, " ")
+		t = strings.TrimLeft(strings.TrimSpace(t), "-")
+		t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if t == "" || t == "or" {
+			continue
+		}
+
+		key := normalizeSearchText(t)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		clean = append(clean, t)
+	}
+
+	if len(clean) < 2 {
+		return nil, ErrInvalidSynonymGroup
+	}
+	return clean, nil
+}
+
+// normalizeSearchText lowercases, strips accents and collapses whitespace so
+// "Disco Rígido" and "disco rigido" match the same entry, as unaccent does in
+// portuguese_unaccent.
+func normalizeSearchText(term string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(term)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// synonymDictionary maps a normalized term to every term it is equivalent to.
+type synonymDictionary struct {
+	alternatives map[string][]string
+	maxWords     int
+}
+
+func newSynonymDictionary(groups [][]string) synonymDictionary {
+	dict := synonymDictionary{alternatives: make(map[string][]string)}
+
+	for _, terms := range groups {
+		for _, term := range terms {
+			key := normalizeSearchText(term)
+			if key == "" {
+				continue
+			}
+			for _, alt := range terms {
+				dict.alternatives[key] = appendUniqueTerm(dict.alternatives[key], alt)
+			}
+			if words := len(strings.Fields(key)); words > dict.maxWords {
+				dict.maxWords = words
+			}
+		}
+	}
+
+	if dict.maxWords > maxSynonymTermWords {
+		dict.maxWords = maxSynonymTermWords
+	}
+	return dict
+}
+
+func appendUniqueTerm(terms []string, term string) []string {
+	key := normalizeSearchText(term)
+	for _, t := range terms {
+		if normalizeSearchText(t) == key {
+			return terms
+		}
+	}
+	return append(terms, term)
+}
+
+// queryToken is a word, quoted phrase or operator of a websearch_to_tsquery input.
+type queryToken struct {
+	text    string
+	phrase  bool
+	negated bool
+	or      bool
+}
+
+// expand rewrites query so every item with synonyms becomes an OR group.
+// websearch_to_tsquery has no parentheses, so the rewrite is distributed:
+// "papel a4 branco" with {a4, papel sulfite} becomes
+// `papel a4 branco or papel "papel sulfite" branco`.
+// The query is returned untouched when nothing matches.
+func (d synonymDictionary) expand(query string) string {
+	if len(d.alternatives) == 0 {
+		return query
+	}
+
+	var clauses []string
+	expanded := false
+
+	for _, segment := range splitOrSegments(tokenizeQuery(query)) {
+		items, changed := d.expandSegment(segment)
+		expanded = expanded || changed
+		clauses = append(clauses, combineItems(items)...)
+	}
+
+	if !expanded {
+		return query
+	}
+	return strings.Join(clauses, " or ")
+}
+
+// expandSegment returns, for each item of an AND segment, the list of
+// alternatives it can be replaced with.
+func (d synonymDictionary) expandSegment(tokens []queryToken) ([][]string, bool) {
+	var items [][]string
+	clauses := 1
+	changed := false
+
+	for i := 0; i < len(tokens); {
+		tok := tokens[i]
+		if tok.negated {
+			items = append(items, []string{renderToken(tok)})
+			i++
+			continue
+		}
+
+		alts, consumed := d.lookup(tokens[i:])
+		if len(alts) > 1 && clauses*len(alts) <= maxSynonymClauses {
+			// The original keeps its typed form: unquoted words stay an AND
+			// of words instead of becoming a phrase.
+			rendered := make([]string, len(alts))
+			rendered[0] = alts[0]
+			if tok.phrase {
+				rendered[0] = renderToken(tok)
+			}
+			for j := 1; j < len(alts); j++ {
+				rendered[j] = renderTerm(alts[j])
+			}
+			items = append(items, rendered)
+			clauses *= len(alts)
+			changed = true
+			i += consumed
+			continue
+		}
+
+		items = append(items, []string{renderToken(tok)})
+		i++
+	}
+
+	return items, changed
+}
+
+// lookup finds the longest dictionary term starting at tokens[0]. Phrases
+// only match as a whole; unquoted words may combine into multi-word terms.
+func (d synonymDictionary) lookup(tokens []queryToken) ([]string, int) {
+	first := tokens[0]
+	if first.phrase {
+		return d.withOriginal(first.text, d.alternatives[normalizeSearchText(first.text)]), 1
+	}
+
+	for n := min(d.maxWords, len(tokens)); n >= 1; n-- {
+		words := make([]string, 0, n)
+		for _, tok := range tokens[:n] {
+			if tok.phrase || tok.negated {
+				break
+			}
+			words = append(words, tok.text)
+		}
+		if len(words) != n {
+			continue
+		}
+
+		original := strings.Join(words, " ")
+		if alts, ok := d.alternatives[normalizeSearchText(original)]; ok {
+			return d.withOriginal(original, alts), n
+		}
+	}
+
+	return nil, 1
+}
+
+// withOriginal puts the text the user typed first, followed by its synonyms.
+func (d synonymDictionary) withOriginal(original string, alts []string) []string {
+	if len(alts) == 0 {
+		return nil
+	}
+	key := normalizeSearchText(original)
+	out := []string{original}
+	for _, alt := range alts {
+		if normalizeSearchText(alt) != key {
+			out = append(out, alt)
+		}
+	}
+	return out
+}
+
+// combineItems distributes the alternatives into AND clauses.
+func combineItems(items [][]string) []string {
+	clauses := []string{""}
+	for _, alts := range items {
+		next := make([]string, 0, len(clauses)*len(alts))
+		for _, prefix := range clauses {
+			for _, alt := range alts {
+				if prefix == "" {
+					next = append(next, alt)
+				} else {
+					next = append(next, prefix+" "+alt)
+				}
+			}
+		}
+		clauses = next
+	}
+	return clauses
+}
+
+func renderTerm(term string) string {
+	if strings.ContainsAny(term, " \t") {
+		return `"` + term + `"`
+	}
+	return term
+}
+
+func renderToken(tok queryToken) string {
+	text := tok.text
+	if tok.phrase {
+		text = `"` + text + `"`
+	}
+	if tok.negated {
+		text = "-" + text
+	}
+	return text
+}
+
+// tokenizeQuery splits query into words, quoted phrases and "or" operators
+// following the websearch_to_tsquery syntax.
+func tokenizeQuery(query string) []queryToken {
+	var tokens []queryToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' {
+			negated = true
+			i++
+			if i >= len(runes) || unicode.IsSpace(runes[i]) {
+				continue
+			}
+		}
+
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			text := strings.Join(strings.Fields(string(runes[i+1:end])), " ")
+			if text != "" {
+				tokens = append(tokens, queryToken{text: text, phrase: true, negated: negated})
+			}
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+			end++
+		}
+		text := string(runes[i:end])
+		if !negated && strings.EqualFold(text, "or") {
+			tokens = append(tokens, queryToken{text: text, or: true})
+		} else {
+			tokens = append(tokens, queryToken{text: text, negated: negated})
+		}
+		i = end
+	}
+
+	return tokens
+}
+
+// splitOrSegments splits tokens on "or" operators into AND segments.
+func splitOrSegments(tokens []queryToken) [][]queryToken {
+	var segments [][]queryToken
+	var current []queryToken
+
+	for _, tok := range tokens {
+		if tok.or {
+			if len(current) > 0 {
+				segments = append(segments, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, tok)
+	}
+	if len(current) > 0 {
+		segments = append(segments, current)
+	}
+
+	return segments
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynonymDictionaryExpand(t *testing.T) {
+	dict := newSynonymDictionary([][]string{
+		{"a4", "papel sulfite"},
+		{"hd", "disco rígido"},
+		{"epi", "equipamento de proteção individual"},
+	})
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"no match", "caneta azul", "caneta azul"},
+		{"single word", "a4", `a4 or "papel sulfite"`},
+		{"word inside query", "resma a4 branco", `resma a4 branco or resma "papel sulfite" branco`},
+		{"multi-word term unquoted", "papel sulfite", `papel sulfite or a4`},
+		{"accents and case ignored", "Disco Rigido externo", `Disco Rigido externo or hd externo`},
+		{"quoted phrase", `"disco rígido"`, `"disco rígido" or hd`},
+		{"negated term kept", "cadeira -epi", "cadeira -epi"},
+		{"two groups", "a4 hd", `a4 hd or a4 "disco rígido" or "papel sulfite" hd or "papel sulfite" "disco rígido"`},
+		{"user or preserved", "caneta or a4", `caneta or a4 or "papel sulfite"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, dict.expand(tt.query))
+		})
+	}
+}
+
+func TestSynonymDictionaryExpand_EmptyDictionary(t *testing.T) {
+	dict := newSynonymDictionary(nil)
+	assert.Equal(t, "papel a4", dict.expand("papel a4"))
+}
+
+func TestSynonymDictionaryExpand_ClauseLimit(t *testing.T) {
+	dict := newSynonymDictionary([][]string{
+		{"a", "b", "c", "d", "e", "f"},
+		{"g", "h", "i", "j", "k", "l"},
+	})
+
+	// 6 x 6 clauses exceed the cap, so the second item stays as typed.
+	assert.Equal(t, "a g or b g or c g or d g or e g or f g", dict.expand("a g"))
+}
+
+func TestCleanSynonymTerms(t *testing.T) {
+	terms, err := cleanSynonymTerms([]string{"  A4 ", `"Papel  Sulfite"`, "a4", "-resma"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a4", "papel sulfite", "resma"}, terms)
+
+	_, err = cleanSynonymTerms([]string{"HD", "hd"})
+	assert.ErrorIs(t, err, ErrInvalidSynonymGroup)
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// TokenConfig holds the signing key and lifetimes of bearer tokens.
+type TokenConfig struct {
+	// Secret signs access tokens with HS256. Every instance must share it.
+	Secret     []byte
+	Issuer     string        // default "gobid"
+	AccessTTL  time.Duration // default 15 minutes
+	RefreshTTL time.Duration // default 30 days
+}
+
+// TokenPair is a signed access token and the refresh token that renews it.
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// TokenService issues short-lived JWT access tokens and rotating refresh
+// tokens. Refresh tokens are opaque random strings stored as SHA-256 hashes;
+// each refresh replaces the token with a new one of the same family, and
+// presenting a replaced token again revokes the whole family.
+type TokenService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+	cfg     TokenConfig
+}
+
+func NewTokenService(pool *pgxpool.Pool, cfg TokenConfig) TokenService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "gobid"
+	}
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = 30 * 24 * time.Hour
+	}
+
+	return TokenService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+		cfg:     cfg,
+	}
+}
+
+// IssueTokens starts a new refresh token family for a user who just proved
+// their credentials.
+func (s *TokenService) IssueTokens(ctx context.Context, userID uuid.UUID) (*TokenPair, error) {
+	created, refresh, err := s.createRefreshToken(ctx, s.queries, userID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	return s.pair(userID, refresh, created.ExpiresAt)
+}
+
+// Refresh exchanges a refresh token for a new pair. The old token stops
+// working; reusing it returns ErrRefreshTokenReused and revokes every token
+// of its family, logging out whoever holds the current one.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	current, err := qtx.GetRefreshTokenByHashForUpdate(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if current.RevokedAt.Valid || !time.Now().Before(current.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	if current.UsedAt.Valid {
+		if _, err := qtx.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit refresh token revocation: %w", err)
+		}
+		s.log.Warn("refresh token reuse detected, family revoked",
+			zap.String("user_id", current.UserID.String()),
+			zap.String("family_id", current.FamilyID.String()))
+		return nil, ErrRefreshTokenReused
+	}
+
+	created, refresh, err := s.createRefreshToken(ctx, qtx, current.UserID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.MarkRefreshTokenUsed(ctx, pgstore.MarkRefreshTokenUsedParams{
+		ID:         current.ID,
+		ReplacedBy: pgtype.UUID{Bytes: created.ID, Valid: true},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+
+	return s.pair(current.UserID, refresh, created.ExpiresAt)
+}
+
+// Revoke ends the family of a refresh token, e.g. on logout. Unknown tokens
+// are ignored.
+func (s *TokenService) Revoke(ctx context.Context, refreshToken string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	current, err := qtx.GetRefreshTokenByHashForUpdate(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if _, err := qtx.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit refresh token revocation: %w", err)
+	}
+	return nil
+}
+
+// ParseAccessToken validates an access token and returns its user id.
+func (s *TokenService) ParseAccessToken(token string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return s.cfg.Secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.cfg.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return userID, nil
+}
+
+func (s *TokenService) pair(userID uuid.UUID, refresh string, refreshExpiresAt time.Time) (*TokenPair, error) {
+	access, accessExpiresAt, err := s.signAccessToken(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (s *TokenService) signAccessToken(userID uuid.UUID, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.cfg.AccessTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    s.cfg.Issuer,
+		Subject:   userID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		ID:        uuid.NewString(),
+	})
+	signed, err := token.SignedString(s.cfg.Secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// createRefreshToken stores a new token of a family and returns its row and
+// the raw value, which is never stored.
+func (s *TokenService) createRefreshToken(ctx context.Context, q *pgstore.Queries, userID, familyID uuid.UUID) (pgstore.RefreshToken, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return pgstore.RefreshToken{}, "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	created, err := q.CreateRefreshToken(ctx, pgstore.CreateRefreshTokenParams{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	})
+	if err != nil {
+		return pgstore.RefreshToken{}, "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return created, token, nil
+}
+
+// generateToken returns 256 random bits, base64url encoded, for tokens that
+// are stored only as hashToken.
+func generateToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTokenService(secret string) *TokenService {
+	s := NewTokenService(nil, TokenConfig{Secret: []byte(secret)})
+	return &s
+}
+
+func TestTokenService_AccessTokenRoundTrip(t *testing.T) {
+	s := newTestTokenService("test-secret")
+	userID := uuid.New()
+
+	token, expiresAt, err := s.signAccessToken(userID, time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Second)
+
+	parsed, err := s.ParseAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, parsed)
+}
+
+func TestTokenService_RejectsInvalidAccessTokens(t *testing.T) {
+	s := newTestTokenService("test-secret")
+	userID := uuid.New()
+
+	expired, _, err := s.signAccessToken(userID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = s.ParseAccessToken(expired)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	foreign, _, err := newTestTokenService("other-secret").signAccessToken(userID, time.Now())
+	require.NoError(t, err)
+	_, err = s.ParseAccessToken(foreign)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "gobid",
+		Subject:   userID.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = s.ParseAccessToken(unsigned)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = s.ParseAccessToken("not-a-jwt")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Len(t, hashToken("refresh"), 32)
+	assert.Equal(t, hashToken("refresh"), hashToken("refresh"))
+	assert.NotEqual(t, hashToken("refresh"), hashToken("refresh2"))
+}
@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication not enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+const (
+	totpPeriod = 30
+	// totpSkew accepts the codes of the previous and next period, to allow
+	// for clock drift on the phone.
+	totpSkew = 1
+	// recoveryCodeAlphabet avoids letters that read like digits (I, L, O, U).
+	recoveryCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	recoveryCodeLength   = 10
+)
+
+// TwoFactorConfig sets how TOTP secrets are labelled and protected.
+type TwoFactorConfig struct {
+	// Issuer is the name shown by authenticator apps (default "FlyTwo Pro").
+	Issuer string
+	// EncryptionKey encrypts the TOTP secrets at rest; any length, it is
+	// hashed into an AES-256 key. Changing it disables every enrollment.
+	EncryptionKey []byte
+	// RecoveryCodes is how many recovery codes a user gets (default 10).
+	RecoveryCodes int
+}
+
+// TwoFactorEnrollment carries a new TOTP secret for the authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorStatus tells whether a user has 2FA and whether a role requires it.
+type TwoFactorStatus struct {
+	Enabled  bool
+	Required bool
+}
+
+// TwoFactorService manages TOTP second factors. A secret only protects the
+// account after the user confirms it with a first code; then logins need a
+// TOTP code or one of the single-use recovery codes, stored as SHA-256
+// hashes.
+type TwoFactorService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+	cfg     TwoFactorConfig
+	aead    cipher.AEAD
+	now     func() time.Time
+}
+
+func NewTwoFactorService(pool *pgxpool.Pool, cfg TwoFactorConfig) (TwoFactorService, error) {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "FlyTwo Pro"
+	}
+	if cfg.RecoveryCodes <= 0 {
+		cfg.RecoveryCodes = 10
+	}
+	if len(cfg.EncryptionKey) == 0 {
+		return TwoFactorService{}, errors.New("two-factor encryption key is empty")
+	}
+
+	key := sha256.Sum256(cfg.EncryptionKey)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return TwoFactorService{}, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return TwoFactorService{}, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return TwoFactorService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+		cfg:     cfg,
+		aead:    aead,
+		now:     time.Now,
+	}, nil
+}
+
+// Status reports whether the user enabled 2FA and whether one of their
+// roles requires it.
+func (s *TwoFactorService) Status(ctx context.Context, userID uuid.UUID) (TwoFactorStatus, error) {
+	state, err := s.queries.GetTwoFactorState(ctx, userID)
+	if err != nil {
+		return TwoFactorStatus{}, fmt.Errorf("failed to get two-factor state: %w", err)
+	}
+	return TwoFactorStatus{Enabled: state.Enabled, Required: state.Required}, nil
+}
+
+// Enroll creates a TOTP secret for the user, replacing an unconfirmed one.
+// It returns ErrTwoFactorAlreadyEnabled when 2FA is already on.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uuid.UUID) (*TwoFactorEnrollment, error) {
+	user, err := s.queries.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.cfg.Issuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	encrypted, err := s.encrypt(userID, key.Secret())
+	if err != nil {
+		return nil, err
+	}
+	affected, err := s.queries.UpsertPendingTOTP(ctx, pgstore.UpsertPendingTOTPParams{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store totp secret: %w", err)
+	}
+	if affected == 0 {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return &TwoFactorEnrollment{Secret: key.Secret(), URI: key.URL()}, nil
+}
+
+// Confirm enables 2FA with the first code from the authenticator app and
+// returns the recovery codes, which are not shown again.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	secret, err := qtx.GetUserTOTPForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+	if secret.ConfirmedAt.Valid {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok, err := s.matchTOTP(secret, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err := qtx.ConfirmUserTOTP(ctx, pgstore.ConfirmUserTOTPParams{UserID: userID, LastUsedStep: step}); err != nil {
+		return nil, fmt.Errorf("failed to confirm totp: %w", err)
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, qtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit two-factor enrollment: %w", err)
+	}
+
+	s.log.Info("two-factor authentication enabled", zap.String("user_id", userID.String()))
+	return codes, nil
+}
+
+// Verify checks a TOTP code or a recovery code of a user with 2FA enabled.
+// TOTP codes cannot be reused and recovery codes work once.
+func (s *TwoFactorService) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.verify(ctx, s.queries.WithTx(tx), userID, code); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit two-factor verification: %w", err)
+	}
+	return nil
+}
+
+// Disable turns 2FA off after checking a current code.
+func (s *TwoFactorService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := s.verify(ctx, qtx, userID, code); err != nil {
+		return err
+	}
+	if err := s.remove(ctx, qtx, userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit two-factor removal: %w", err)
+	}
+
+	s.log.Info("two-factor authentication disabled", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a
+// current code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := s.verify(ctx, qtx, userID, code); err != nil {
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, qtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+
+	s.log.Info("recovery codes regenerated", zap.String("user_id", userID.String()))
+	return codes, nil
+}
+
+// Reset removes the 2FA of a user who lost their device. It is meant for
+// admins and needs no code.
+func (s *TwoFactorService) Reset(ctx context.Context, userID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.remove(ctx, s.queries.WithTx(tx), userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit two-factor reset: %w", err)
+	}
+
+	s.log.Info("two-factor authentication reset", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// RequiredRoles returns the roles whose users must enable 2FA.
+func (s *TwoFactorService) RequiredRoles(ctx context.Context) ([]string, error) {
+	roles, err := s.queries.ListTwoFactorRequiredRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list two-factor roles: %w", err)
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	return roles, nil
+}
+
+// SetRequiredRoles replaces the roles whose users must enable 2FA.
+func (s *TwoFactorService) SetRequiredRoles(ctx context.Context, roles []string) ([]string, error) {
+	clean, err := cleanRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if err := qtx.DeleteTwoFactorRequiredRoles(ctx); err != nil {
+		return nil, fmt.Errorf("failed to clear two-factor roles: %w", err)
+	}
+	for _, role := range clean {
+		if err := qtx.AddTwoFactorRequiredRole(ctx, role); err != nil {
+			return nil, fmt.Errorf("failed to add two-factor role: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit two-factor roles: %w", err)
+	}
+
+	s.log.Info("two-factor required roles updated", zap.Strings("roles", clean))
+	return clean, nil
+}
+
+func (s *TwoFactorService) verify(ctx context.Context, qtx *pgstore.Queries, userID uuid.UUID, code string) error {
+	secret, err := qtx.GetUserTOTPForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnabled
+		}
+		return fmt.Errorf("failed to get totp secret: %w", err)
+	}
+	if !secret.ConfirmedAt.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == int(otp.DigitsSix) {
+		step, ok, err := s.matchTOTP(secret, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		if err := qtx.SetTOTPLastUsedStep(ctx, pgstore.SetTOTPLastUsedStepParams{UserID: userID, LastUsedStep: step}); err != nil {
+			return fmt.Errorf("failed to record totp use: %w", err)
+		}
+		return nil
+	}
+
+	used, err := qtx.UseRecoveryCode(ctx, pgstore.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashToken(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if used == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	s.log.Info("recovery code used", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// matchTOTP returns the time step of code, if it is valid now and newer than
+// the last code used.
+func (s *TwoFactorService) matchTOTP(secret pgstore.UserTotp, code string) (int64, bool, error) {
+	plain, err := s.decrypt(secret.UserID, secret.SecretEncrypted)
+	if err != nil {
+		return 0, false, err
+	}
+
+	current := s.now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= secret.LastUsedStep {
+			continue
+		}
+		want, err := totp.GenerateCodeCustom(plain, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to generate totp code: %w", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func (s *TwoFactorService) remove(ctx context.Context, qtx *pgstore.Queries, userID uuid.UUID) error {
+	affected, err := qtx.DeleteUserTOTP(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete totp secret: %w", err)
+	}
+	if affected == 0 {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, qtx *pgstore.Queries, userID uuid.UUID) ([]string, error) {
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	codes := make([]string, s.cfg.RecoveryCodes)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		if err := qtx.CreateRecoveryCode(ctx, pgstore.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashToken(normalizeRecoveryCode(code)),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to store recovery code: %w", err)
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+// encrypt seals a TOTP secret with AES-GCM, bound to the user id.
+func (s *TwoFactorService) encrypt(userID uuid.UUID, secret string) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, []byte(secret), userID[:]), nil
+}
+
+func (s *TwoFactorService) decrypt(userID uuid.UUID, sealed []byte) (string, error) {
+	size := s.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("totp secret is corrupted")
+	}
+	plain, err := s.aead.Open(nil, sealed[:size], sealed[size:], userID[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+	return string(plain), nil
+}
+
+// generateRecoveryCode returns a random code formatted as XXXXX-XXXXX.
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, v := range raw {
+		if i == recoveryCodeLength/2 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// normalizeRecoveryCode makes recovery codes case and dash insensitive.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
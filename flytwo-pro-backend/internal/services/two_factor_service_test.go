@@ -0,0 +1,107 @@
+package services
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gobid/internal/store/pgstore"
+)
+
+func newTestTwoFactorService(t *testing.T, now time.Time) TwoFactorService {
+	t.Helper()
+	s, err := NewTwoFactorService(nil, TwoFactorConfig{EncryptionKey: []byte("test-key")})
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	require.NoError(t, err)
+	return code
+}
+
+func TestNewTwoFactorService_RequiresKey(t *testing.T) {
+	_, err := NewTwoFactorService(nil, TwoFactorConfig{})
+	assert.Error(t, err)
+}
+
+func TestTwoFactorMatchTOTP(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 10, 0, time.UTC)
+	s := newTestTwoFactorService(t, now)
+	userID := uuid.New()
+	secret := "JBSWY3DPEHPK3PXP"
+	encrypted, err := s.encrypt(userID, secret)
+	require.NoError(t, err)
+	row := pgstore.UserTotp{UserID: userID, SecretEncrypted: encrypted}
+	currentStep := now.Unix() / totpPeriod
+
+	step, ok, err := s.matchTOTP(row, totpCode(t, secret, now))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, currentStep, step)
+
+	// one period of clock drift is tolerated, two are not
+	_, ok, _ = s.matchTOTP(row, totpCode(t, secret, now.Add(-30*time.Second)))
+	assert.True(t, ok)
+	_, ok, _ = s.matchTOTP(row, totpCode(t, secret, now.Add(-60*time.Second)))
+	assert.False(t, ok)
+
+	// a code already used cannot be replayed
+	row.LastUsedStep = currentStep
+	_, ok, _ = s.matchTOTP(row, totpCode(t, secret, now))
+	assert.False(t, ok)
+	_, ok, _ = s.matchTOTP(row, totpCode(t, secret, now.Add(30*time.Second)))
+	assert.True(t, ok)
+}
+
+func TestTwoFactorEncryption_BoundToUser(t *testing.T) {
+	s := newTestTwoFactorService(t, time.Now())
+	userID := uuid.New()
+
+	sealed, err := s.encrypt(userID, "JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "JBSWY3DPEHPK3PXP")
+
+	plain, err := s.decrypt(userID, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plain)
+
+	_, err = s.decrypt(uuid.New(), sealed)
+	assert.Error(t, err, "a secret copied to another user does not decrypt")
+
+	other, err := NewTwoFactorService(nil, TwoFactorConfig{EncryptionKey: []byte("other-key")})
+	require.NoError(t, err)
+	_, err = other.decrypt(userID, sealed)
+	assert.Error(t, err)
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{5}-[0-9A-HJKMNP-TV-Z]{5}$`)
+	seen := map[string]bool{}
+	for range 50 {
+		code, err := generateRecoveryCode()
+		require.NoError(t, err)
+		assert.Regexp(t, pattern, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	assert.Equal(t, "AB12CXY9Z0", normalizeRecoveryCode("ab12c-xy9z0"))
+	assert.Equal(t, "AB12CXY9Z0", normalizeRecoveryCode("AB12C XY9Z0"))
+	assert.Equal(t, normalizeRecoveryCode("AB12C-XY9Z0"), normalizeRecoveryCode("ab12cxy9z0"))
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"gobid/internal/logger"
+	"gobid/internal/store/pgstore"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// maxUserAgentLength bounds the User-Agent kept per session.
+const maxUserAgentLength = 512
+
+// UserSessionService keeps metadata about web sessions (owner, IP, user
+// agent, last seen) in user_session, next to the scs sessions table, and
+// ends sessions by deleting their scs rows.
+type UserSessionService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	log     *zap.Logger
+}
+
+func NewUserSessionService(pool *pgxpool.Pool) UserSessionService {
+	return UserSessionService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		log:     logger.Log,
+	}
+}
+
+// RecordLogin stores the metadata of a session that just logged in. It
+// also drops the user's rows whose session has expired or ended.
+func (s *UserSessionService) RecordLogin(ctx context.Context, token string, userID uuid.UUID, ip, userAgent string) error {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	// The scs row of a brand-new session only appears after the response,
+	// so rows younger than a minute are left alone.
+	if _, err := s.queries.DeleteStaleUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete stale sessions: %w", err)
+	}
+	if err := s.queries.CreateUserSession(ctx, pgstore.CreateUserSessionParams{
+		Token:     token,
+		UserID:    userID,
+		Ip:        ip,
+		UserAgent: userAgent,
+	}); err != nil {
+		return fmt.Errorf("failed to record session: %w", err)
+	}
+	return nil
+}
+
+// Touch marks a session as seen now. It writes at most once a minute per
+// session, so requests do not each turn into an UPDATE.
+func (s *UserSessionService) Touch(ctx context.Context, token string) error {
+	if err := s.queries.TouchUserSession(ctx, token); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// EndSession forgets the metadata of a session that logged out.
+func (s *UserSessionService) EndSession(ctx context.Context, token string) error {
+	if err := s.queries.DeleteUserSessionByToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	return nil
+}
+
+// ListSessions returns the live sessions of a user, most recently seen
+// first.
+func (s *UserSessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]pgstore.ListUserSessionsRow, error) {
+	sessions, err := s.queries.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if sessions == nil {
+		sessions = []pgstore.ListUserSessionsRow{}
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one session of a user and returns its token.
+func (s *UserSessionService) RevokeSession(ctx context.Context, userID, id uuid.UUID) (string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	token, err := qtx.DeleteUserSession(ctx, pgstore.DeleteUserSessionParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrSessionNotFound
+		}
+		return "", fmt.Errorf("failed to delete session: %w", err)
+	}
+	if _, err := qtx.DeleteSessions(ctx, []string{token}); err != nil {
+		return "", fmt.Errorf("failed to end session: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit session revocation: %w", err)
+	}
+
+	s.log.Info("session revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("session_id", id.String()))
+	return token, nil
+}
+
+// RevokeOtherSessions ends every session of a user except the one with
+// keepToken, and returns how many were live.
+func (s *UserSessionService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, keepToken string) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revoked, err := revokeSessions(ctx, s.queries.WithTx(tx), userID, keepToken)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit session revocation: %w", err)
+	}
+
+	s.log.Info("other sessions revoked",
+		zap.String("user_id", userID.String()),
+		zap.Int64("sessions", revoked))
+	return revoked, nil
+}
+
+// ForceLogout ends every web session of a user and revokes the refresh
+// tokens of the mobile app, and returns how many sessions were live.
+// Access tokens already issued stay valid until they expire.
+func (s *UserSessionService) ForceLogout(ctx context.Context, userID, adminID uuid.UUID) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+	if _, err := qtx.GetUserById(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	revoked, err := revokeSessions(ctx, qtx, userID, "")
+	if err != nil {
+		return 0, err
+	}
+	refreshTokens, err := qtx.RevokeUserRefreshTokens(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit forced logout: %w", err)
+	}
+
+	s.log.Info("user logged out by admin",
+		zap.String("user_id", userID.String()),
+		zap.String("by", adminID.String()),
+		zap.Int64("sessions", revoked),
+		zap.Int64("refresh_tokens", refreshTokens))
+	return revoked, nil
+}
+
+func revokeSessions(ctx context.Context, q *pgstore.Queries, userID uuid.UUID, keepToken string) (int64, error) {
+	tokens, err := q.DeleteOtherUserSessions(ctx, pgstore.DeleteOtherUserSessionsParams{
+		UserID: userID,
+		Token:  keepToken,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	revoked, err := q.DeleteSessions(ctx, tokens)
+	if err != nil {
+		return 0, fmt.Errorf("failed to end sessions: %w", err)
+	}
+	return revoked, nil
+}
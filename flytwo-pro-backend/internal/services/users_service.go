@@ -6,14 +6,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gobid/internal/store/pgstore"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
 var (
 	ErrDuplicatedEmailOrUsername = errors.New("username or email already exists")
 	ErrInvalidCredentials        = errors.New("invalid credentials")
+	ErrUserNotFound              = errors.New("user not found")
 )
 
 type UserService struct {
@@ -35,7 +38,14 @@ func (us *UserService) CreateUser(ctx context.Context, userName, email, password
 	}
 	args := pgstore.CreateUserParams{UserName: userName, Email: email, PasswordHash: hash}
 
-	id, err := us.queries.CreateUser(ctx, args)
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := us.queries.WithTx(tx)
+	id, err := qtx.CreateUser(ctx, args)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -44,6 +54,13 @@ func (us *UserService) CreateUser(ctx context.Context, userName, email, password
 		return uuid.UUID{}, err
 	}
 
+	if err := qtx.AddUserRole(ctx, pgstore.AddUserRoleParams{UserID: id, Role: DefaultRole}); err != nil {
+		return uuid.Nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+
 	return id, nil
 }
 
@@ -74,20 +91,115 @@ func (us *UserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*pgst
 	row, err := us.queries.GetUserById(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
 
 	// Convert GetUserByIdRow to User
 	user := &pgstore.User{
-		ID:           row.ID,
-		UserName:     row.UserName,
-		Email:        row.Email,
-		PasswordHash: row.PasswordHash,
-		CreatedAt:    row.CreatedAt,
-		UpdatedAt:    row.UpdatedAt,
+		ID:              row.ID,
+		UserName:        row.UserName,
+		Email:           row.Email,
+		PasswordHash:    row.PasswordHash,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		EmailVerifiedAt: row.EmailVerifiedAt,
 	}
 
 	return user, nil
 }
+
+// UpdateUser changes the user name and/or email of a user; nil leaves the
+// field as is. It reports whether the email changed: a new email is not
+// verified, and the verification and password reset links already sent to
+// the old address stop working.
+func (us *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, userName, email *string) (*pgstore.User, bool, error) {
+	args := pgstore.UpdateUserProfileParams{ID: userID}
+	if userName != nil {
+		args.UserName = pgtype.Text{String: strings.TrimSpace(*userName), Valid: true}
+	}
+	if email != nil {
+		args.Email = pgtype.Text{String: strings.TrimSpace(*email), Valid: true}
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := us.queries.WithTx(tx)
+	before, err := qtx.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, ErrUserNotFound
+		}
+		return nil, false, err
+	}
+
+	user, err := qtx.UpdateUserProfile(ctx, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, false, ErrDuplicatedEmailOrUsername
+		}
+		return nil, false, err
+	}
+
+	emailChanged := user.Email != before.Email
+	if emailChanged {
+		if err := qtx.InvalidateEmailVerificationTokens(ctx, userID); err != nil {
+			return nil, false, err
+		}
+		if err := qtx.InvalidatePasswordResetTokens(ctx, userID); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+
+	return &user, emailChanged, nil
+}
+
+// ChangePassword replaces the password of a user after checking the
+// current one, and revokes the refresh tokens of the mobile app. Ending
+// the web sessions is up to the caller.
+func (us *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	user, err := us.queries.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(currentPassword)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), 12)
+	if err != nil {
+		return err
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := us.queries.WithTx(tx)
+	if err := qtx.UpdateUserPassword(ctx, pgstore.UpdateUserPasswordParams{ID: userID, PasswordHash: hash}); err != nil {
+		return err
+	}
+	if _, err := qtx.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,233 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: account_deletion.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelAccountDeletion = `-- name: CancelAccountDeletion :one
+UPDATE account_deletion
+SET cancelled_at = now(),
+    cancelled_by = $2
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL
+RETURNING id, user_id, requested_by, requested_at, scheduled_for, cancelled_at, cancelled_by, completed_at
+`
+
+type CancelAccountDeletionParams struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	CancelledBy pgtype.UUID `json:"cancelled_by"`
+}
+
+func (q *Queries) CancelAccountDeletion(ctx context.Context, arg CancelAccountDeletionParams) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, cancelAccountDeletion, arg.UserID, arg.CancelledBy)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RequestedBy,
+		&i.RequestedAt,
+		&i.ScheduledFor,
+		&i.CancelledAt,
+		&i.CancelledBy,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const completeAccountDeletion = `-- name: CompleteAccountDeletion :exec
+UPDATE account_deletion
+SET completed_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) CompleteAccountDeletion(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, completeAccountDeletion, id)
+	return err
+}
+
+const countAccountDeletions = `-- name: CountAccountDeletions :one
+SELECT COUNT(*)
+FROM account_deletion d
+WHERE ($1::text IS NULL
+    OR ($1 = 'pending' AND d.cancelled_at IS NULL AND d.completed_at IS NULL)
+    OR ($1 = 'cancelled' AND d.cancelled_at IS NOT NULL)
+    OR ($1 = 'completed' AND d.completed_at IS NOT NULL))
+`
+
+func (q *Queries) CountAccountDeletions(ctx context.Context, status pgtype.Text) (int64, error) {
+	row := q.db.QueryRow(ctx, countAccountDeletions, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAccountDeletion = `-- name: CreateAccountDeletion :one
+INSERT INTO account_deletion (user_id, requested_by, scheduled_for)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, requested_by, requested_at, scheduled_for, cancelled_at, cancelled_by, completed_at
+`
+
+type CreateAccountDeletionParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	RequestedBy  uuid.UUID `json:"requested_by"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+func (q *Queries) CreateAccountDeletion(ctx context.Context, arg CreateAccountDeletionParams) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, createAccountDeletion, arg.UserID, arg.RequestedBy, arg.ScheduledFor)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RequestedBy,
+		&i.RequestedAt,
+		&i.ScheduledFor,
+		&i.CancelledAt,
+		&i.CancelledBy,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getPendingAccountDeletion = `-- name: GetPendingAccountDeletion :one
+SELECT id, user_id, requested_by, requested_at, scheduled_for, cancelled_at, cancelled_by, completed_at
+FROM account_deletion
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL
+`
+
+func (q *Queries) GetPendingAccountDeletion(ctx context.Context, userID uuid.UUID) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, getPendingAccountDeletion, userID)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RequestedBy,
+		&i.RequestedAt,
+		&i.ScheduledFor,
+		&i.CancelledAt,
+		&i.CancelledBy,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getPendingAccountDeletionForUpdate = `-- name: GetPendingAccountDeletionForUpdate :one
+SELECT id, user_id, requested_by, requested_at, scheduled_for, cancelled_at, cancelled_by, completed_at
+FROM account_deletion
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetPendingAccountDeletionForUpdate(ctx context.Context, userID uuid.UUID) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, getPendingAccountDeletionForUpdate, userID)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RequestedBy,
+		&i.RequestedAt,
+		&i.ScheduledFor,
+		&i.CancelledAt,
+		&i.CancelledBy,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const listAccountDeletions = `-- name: ListAccountDeletions :many
+SELECT
+    d.id, d.user_id, d.requested_by, d.requested_at, d.scheduled_for, d.cancelled_at, d.cancelled_by, d.completed_at,
+    COALESCE(u.email, '')::text AS email
+FROM account_deletion d
+LEFT JOIN users u ON u.id = d.user_id
+WHERE ($3::text IS NULL
+    OR ($3 = 'pending' AND d.cancelled_at IS NULL AND d.completed_at IS NULL)
+    OR ($3 = 'cancelled' AND d.cancelled_at IS NOT NULL)
+    OR ($3 = 'completed' AND d.completed_at IS NOT NULL))
+ORDER BY d.requested_at DESC, d.id
+LIMIT $1 OFFSET $2
+`
+
+type ListAccountDeletionsParams struct {
+	Limit  int32       `json:"limit"`
+	Offset int32       `json:"offset"`
+	Status pgtype.Text `json:"status"`
+}
+
+type ListAccountDeletionsRow struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	RequestedBy  uuid.UUID          `json:"requested_by"`
+	RequestedAt  time.Time          `json:"requested_at"`
+	ScheduledFor time.Time          `json:"scheduled_for"`
+	CancelledAt  pgtype.Timestamptz `json:"cancelled_at"`
+	CancelledBy  pgtype.UUID        `json:"cancelled_by"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	Email        string             `json:"email"`
+}
+
+func (q *Queries) ListAccountDeletions(ctx context.Context, arg ListAccountDeletionsParams) ([]ListAccountDeletionsRow, error) {
+	rows, err := q.db.Query(ctx, listAccountDeletions, arg.Limit, arg.Offset, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAccountDeletionsRow
+	for rows.Next() {
+		var i ListAccountDeletionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.RequestedBy,
+			&i.RequestedAt,
+			&i.ScheduledFor,
+			&i.CancelledAt,
+			&i.CancelledBy,
+			&i.CompletedAt,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueAccountDeletions = `-- name: ListDueAccountDeletions :many
+SELECT user_id
+FROM account_deletion
+WHERE cancelled_at IS NULL AND completed_at IS NULL AND scheduled_for <= now()
+ORDER BY scheduled_for
+LIMIT $1
+`
+
+func (q *Queries) ListDueAccountDeletions(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listDueAccountDeletions, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
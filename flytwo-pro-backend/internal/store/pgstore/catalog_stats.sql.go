@@ -0,0 +1,262 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: catalog_stats.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+)
+
+const catalogImportHistory = `-- name: CatalogImportHistory :many
+SELECT id, catalog, rows_read, rows_saved, rows_skipped, item_total, started_at, finished_at
+FROM catalog_import
+WHERE finished_at >= $1
+ORDER BY finished_at, id
+`
+
+func (q *Queries) CatalogImportHistory(ctx context.Context, since time.Time) ([]CatalogImport, error) {
+	rows, err := q.db.Query(ctx, catalogImportHistory, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CatalogImport
+	for rows.Next() {
+		var i CatalogImport
+		if err := rows.Scan(
+			&i.ID,
+			&i.Catalog,
+			&i.RowsRead,
+			&i.RowsSaved,
+			&i.RowsSkipped,
+			&i.ItemTotal,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const catmatCountByClass = `-- name: CatmatCountByClass :many
+SELECT class_code, class_name, COUNT(*) AS count
+FROM catmat_item
+WHERE group_code = $1
+GROUP BY class_code, class_name
+ORDER BY count DESC, class_code
+`
+
+type CatmatCountByClassRow struct {
+	ClassCode int32  `json:"class_code"`
+	ClassName string `json:"class_name"`
+	Count     int64  `json:"count"`
+}
+
+func (q *Queries) CatmatCountByClass(ctx context.Context, groupCode int16) ([]CatmatCountByClassRow, error) {
+	rows, err := q.db.Query(ctx, catmatCountByClass, groupCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CatmatCountByClassRow
+	for rows.Next() {
+		var i CatmatCountByClassRow
+		if err := rows.Scan(&i.ClassCode, &i.ClassName, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const catmatCoverage = `-- name: CatmatCoverage :one
+SELECT
+    COUNT(*) AS total,
+    COUNT(ncm_code) AS with_ncm,
+    COUNT(embedding) AS with_embedding,
+    COUNT(DISTINCT group_code) AS groups,
+    COUNT(DISTINCT class_code) AS classes
+FROM catmat_item
+`
+
+type CatmatCoverageRow struct {
+	Total         int64 `json:"total"`
+	WithNcm       int64 `json:"with_ncm"`
+	WithEmbedding int64 `json:"with_embedding"`
+	Groups        int64 `json:"groups"`
+	Classes       int64 `json:"classes"`
+}
+
+func (q *Queries) CatmatCoverage(ctx context.Context) (CatmatCoverageRow, error) {
+	row := q.db.QueryRow(ctx, catmatCoverage)
+	var i CatmatCoverageRow
+	err := row.Scan(
+		&i.Total,
+		&i.WithNcm,
+		&i.WithEmbedding,
+		&i.Groups,
+		&i.Classes,
+	)
+	return i, err
+}
+
+const catserCountByClass = `-- name: CatserCountByClass :many
+SELECT class_code, class_name, COUNT(*) AS count
+FROM catser_item
+WHERE group_code = $1
+GROUP BY class_code, class_name
+ORDER BY count DESC, class_code
+`
+
+type CatserCountByClassRow struct {
+	ClassCode int32  `json:"class_code"`
+	ClassName string `json:"class_name"`
+	Count     int64  `json:"count"`
+}
+
+func (q *Queries) CatserCountByClass(ctx context.Context, groupCode int16) ([]CatserCountByClassRow, error) {
+	rows, err := q.db.Query(ctx, catserCountByClass, groupCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CatserCountByClassRow
+	for rows.Next() {
+		var i CatserCountByClassRow
+		if err := rows.Scan(&i.ClassCode, &i.ClassName, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const catserCoverage = `-- name: CatserCoverage :one
+SELECT
+    COUNT(*) AS total,
+    COUNT(embedding) AS with_embedding,
+    COUNT(DISTINCT group_code) AS groups,
+    COUNT(DISTINCT class_code) AS classes
+FROM catser_item
+`
+
+type CatserCoverageRow struct {
+	Total         int64 `json:"total"`
+	WithEmbedding int64 `json:"with_embedding"`
+	Groups        int64 `json:"groups"`
+	Classes       int64 `json:"classes"`
+}
+
+func (q *Queries) CatserCoverage(ctx context.Context) (CatserCoverageRow, error) {
+	row := q.db.QueryRow(ctx, catserCoverage)
+	var i CatserCoverageRow
+	err := row.Scan(
+		&i.Total,
+		&i.WithEmbedding,
+		&i.Groups,
+		&i.Classes,
+	)
+	return i, err
+}
+
+const insertCatalogImport = `-- name: InsertCatalogImport :one
+INSERT INTO catalog_import (
+    catalog,
+    rows_read,
+    rows_saved,
+    rows_skipped,
+    item_total,
+    started_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    (CASE $1::text
+        WHEN 'catmat' THEN (SELECT COUNT(*) FROM catmat_item)
+        ELSE (SELECT COUNT(*) FROM catser_item)
+    END),
+    $5
+)
+RETURNING id, catalog, rows_read, rows_saved, rows_skipped, item_total, started_at, finished_at
+`
+
+type InsertCatalogImportParams struct {
+	Catalog     string    `json:"catalog"`
+	RowsRead    int32     `json:"rows_read"`
+	RowsSaved   int32     `json:"rows_saved"`
+	RowsSkipped int32     `json:"rows_skipped"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+func (q *Queries) InsertCatalogImport(ctx context.Context, arg InsertCatalogImportParams) (CatalogImport, error) {
+	row := q.db.QueryRow(ctx, insertCatalogImport,
+		arg.Catalog,
+		arg.RowsRead,
+		arg.RowsSaved,
+		arg.RowsSkipped,
+		arg.StartedAt,
+	)
+	var i CatalogImport
+	err := row.Scan(
+		&i.ID,
+		&i.Catalog,
+		&i.RowsRead,
+		&i.RowsSaved,
+		&i.RowsSkipped,
+		&i.ItemTotal,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const lastCatalogImports = `-- name: LastCatalogImports :many
+SELECT DISTINCT ON (catalog) id, catalog, rows_read, rows_saved, rows_skipped, item_total, started_at, finished_at
+FROM catalog_import
+ORDER BY catalog, finished_at DESC
+`
+
+func (q *Queries) LastCatalogImports(ctx context.Context) ([]CatalogImport, error) {
+	rows, err := q.db.Query(ctx, lastCatalogImports)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CatalogImport
+	for rows.Next() {
+		var i CatalogImport
+		if err := rows.Scan(
+			&i.ID,
+			&i.Catalog,
+			&i.RowsRead,
+			&i.RowsSaved,
+			&i.RowsSkipped,
+			&i.ItemTotal,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,141 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: email_verification.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createEmailVerificationToken = `-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_token (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateEmailVerificationTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash []byte    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, createEmailVerificationToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	return err
+}
+
+const getEmailVerificationSendStats = `-- name: GetEmailVerificationSendStats :one
+SELECT
+    count(*) FILTER (WHERE created_at > now() - interval '1 hour') AS sent_last_hour,
+    coalesce(min(created_at) FILTER (WHERE created_at > now() - interval '1 hour'), 'epoch'::timestamptz)::timestamptz AS first_sent_last_hour,
+    coalesce(max(created_at), 'epoch'::timestamptz)::timestamptz AS last_sent_at
+FROM email_verification_token
+WHERE user_id = $1
+`
+
+type GetEmailVerificationSendStatsRow struct {
+	SentLastHour      int64     `json:"sent_last_hour"`
+	FirstSentLastHour time.Time `json:"first_sent_last_hour"`
+	LastSentAt        time.Time `json:"last_sent_at"`
+}
+
+func (q *Queries) GetEmailVerificationSendStats(ctx context.Context, userID uuid.UUID) (GetEmailVerificationSendStatsRow, error) {
+	row := q.db.QueryRow(ctx, getEmailVerificationSendStats, userID)
+	var i GetEmailVerificationSendStatsRow
+	err := row.Scan(&i.SentLastHour, &i.FirstSentLastHour, &i.LastSentAt)
+	return i, err
+}
+
+const getEmailVerificationTokenByHashForUpdate = `-- name: GetEmailVerificationTokenByHashForUpdate :one
+SELECT id, user_id, token_hash, created_at, expires_at, used_at FROM email_verification_token
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetEmailVerificationTokenByHashForUpdate(ctx context.Context, tokenHash []byte) (EmailVerificationToken, error) {
+	row := q.db.QueryRow(ctx, getEmailVerificationTokenByHashForUpdate, tokenHash)
+	var i EmailVerificationToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const getUserEmailVerifiedAt = `-- name: GetUserEmailVerifiedAt :one
+SELECT email_verified_at FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserEmailVerifiedAt(ctx context.Context, id uuid.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getUserEmailVerifiedAt, id)
+	var email_verified_at pgtype.Timestamptz
+	err := row.Scan(&email_verified_at)
+	return email_verified_at, err
+}
+
+const getUserForEmailVerification = `-- name: GetUserForEmailVerification :one
+SELECT id, user_name, email, email_verified_at
+FROM users
+WHERE id = $1
+FOR UPDATE
+`
+
+type GetUserForEmailVerificationRow struct {
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	Email           string             `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+func (q *Queries) GetUserForEmailVerification(ctx context.Context, id uuid.UUID) (GetUserForEmailVerificationRow, error) {
+	row := q.db.QueryRow(ctx, getUserForEmailVerification, id)
+	var i GetUserForEmailVerificationRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const invalidateEmailVerificationTokens = `-- name: InvalidateEmailVerificationTokens :exec
+UPDATE email_verification_token
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidateEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateEmailVerificationTokens, userID)
+	return err
+}
+
+const setUserEmailVerified = `-- name: SetUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = CASE WHEN $1::bool THEN coalesce(email_verified_at, now()) END,
+    updated_at = now()
+WHERE id = $2
+`
+
+type SetUserEmailVerifiedParams struct {
+	Verified bool      `json:"verified"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetUserEmailVerified(ctx context.Context, arg SetUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserEmailVerified, arg.Verified, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,483 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: item_list.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const copyItemListLines = `-- name: CopyItemListLines :execrows
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT $1::uuid, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint
+FROM item_list_line
+WHERE list_id = $2::uuid
+ORDER BY id
+`
+
+type CopyItemListLinesParams struct {
+	ToListID   uuid.UUID `json:"to_list_id"`
+	FromListID uuid.UUID `json:"from_list_id"`
+}
+
+func (q *Queries) CopyItemListLines(ctx context.Context, arg CopyItemListLinesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, copyItemListLines, arg.ToListID, arg.FromListID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createCatmatListLine = `-- name: CreateCatmatListLine :one
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT
+    $1::uuid,
+    'catmat',
+    i.item_code,
+    $2::float8,
+    $3::text,
+    $4::text,
+    i.item_description,
+    catmat_item_fingerprint(i)
+FROM catmat_item i
+WHERE i.item_code = $5::integer
+RETURNING id, list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint, created_at, updated_at
+`
+
+type CreateCatmatListLineParams struct {
+	ListID   uuid.UUID `json:"list_id"`
+	Quantity float64   `json:"quantity"`
+	Unit     string    `json:"unit"`
+	Notes    string    `json:"notes"`
+	ItemCode int32     `json:"item_code"`
+}
+
+func (q *Queries) CreateCatmatListLine(ctx context.Context, arg CreateCatmatListLineParams) (ItemListLine, error) {
+	row := q.db.QueryRow(ctx, createCatmatListLine,
+		arg.ListID,
+		arg.Quantity,
+		arg.Unit,
+		arg.Notes,
+		arg.ItemCode,
+	)
+	var i ItemListLine
+	err := row.Scan(
+		&i.ID,
+		&i.ListID,
+		&i.Catalog,
+		&i.ItemCode,
+		&i.Quantity,
+		&i.Unit,
+		&i.Notes,
+		&i.ItemDescription,
+		&i.ItemFingerprint,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createCatserListLine = `-- name: CreateCatserListLine :one
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT
+    $1::uuid,
+    'catser',
+    i.service_code,
+    $2::float8,
+    $3::text,
+    $4::text,
+    i.service_description,
+    catser_item_fingerprint(i)
+FROM catser_item i
+WHERE i.service_code = $5::integer
+RETURNING id, list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint, created_at, updated_at
+`
+
+type CreateCatserListLineParams struct {
+	ListID   uuid.UUID `json:"list_id"`
+	Quantity float64   `json:"quantity"`
+	Unit     string    `json:"unit"`
+	Notes    string    `json:"notes"`
+	ItemCode int32     `json:"item_code"`
+}
+
+func (q *Queries) CreateCatserListLine(ctx context.Context, arg CreateCatserListLineParams) (ItemListLine, error) {
+	row := q.db.QueryRow(ctx, createCatserListLine,
+		arg.ListID,
+		arg.Quantity,
+		arg.Unit,
+		arg.Notes,
+		arg.ItemCode,
+	)
+	var i ItemListLine
+	err := row.Scan(
+		&i.ID,
+		&i.ListID,
+		&i.Catalog,
+		&i.ItemCode,
+		&i.Quantity,
+		&i.Unit,
+		&i.Notes,
+		&i.ItemDescription,
+		&i.ItemFingerprint,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createItemList = `-- name: CreateItemList :one
+INSERT INTO item_list (user_id, organization_id, name, description)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, description, created_at, updated_at, organization_id
+`
+
+type CreateItemListParams struct {
+	UserID         pgtype.UUID `json:"user_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+}
+
+func (q *Queries) CreateItemList(ctx context.Context, arg CreateItemListParams) (ItemList, error) {
+	row := q.db.QueryRow(ctx, createItemList,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Description,
+	)
+	var i ItemList
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const deleteItemList = `-- name: DeleteItemList :execrows
+DELETE FROM item_list
+WHERE id = $1::uuid
+  AND (organization_id = $2::uuid
+    OR ($2::uuid IS NULL AND organization_id IS NULL AND user_id = $3::uuid))
+`
+
+type DeleteItemListParams struct {
+	ID             uuid.UUID   `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) DeleteItemList(ctx context.Context, arg DeleteItemListParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteItemList, arg.ID, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteItemListLine = `-- name: DeleteItemListLine :execrows
+DELETE FROM item_list_line
+WHERE id = $1 AND list_id = $2
+`
+
+type DeleteItemListLineParams struct {
+	ID     int64     `json:"id"`
+	ListID uuid.UUID `json:"list_id"`
+}
+
+func (q *Queries) DeleteItemListLine(ctx context.Context, arg DeleteItemListLineParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteItemListLine, arg.ID, arg.ListID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePersonalItemListsByUser = `-- name: DeletePersonalItemListsByUser :execrows
+DELETE FROM item_list
+WHERE user_id = $1::uuid AND organization_id IS NULL
+`
+
+func (q *Queries) DeletePersonalItemListsByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePersonalItemListsByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getItemList = `-- name: GetItemList :one
+SELECT id, user_id, name, description, created_at, updated_at, organization_id
+FROM item_list
+WHERE id = $1::uuid
+  AND (organization_id = $2::uuid
+    OR ($2::uuid IS NULL AND organization_id IS NULL AND user_id = $3::uuid))
+`
+
+type GetItemListParams struct {
+	ID             uuid.UUID   `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) GetItemList(ctx context.Context, arg GetItemListParams) (ItemList, error) {
+	row := q.db.QueryRow(ctx, getItemList, arg.ID, arg.OrganizationID, arg.UserID)
+	var i ItemList
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const listItemListLines = `-- name: ListItemListLines :many
+SELECT
+    li.id,
+    li.list_id,
+    li.catalog,
+    li.item_code,
+    li.quantity,
+    li.unit,
+    li.notes,
+    li.item_description,
+    li.item_fingerprint,
+    li.created_at,
+    li.updated_at,
+    -- Vazios quando o item não existe mais no catálogo
+    COALESCE(m.item_description, s.service_description, '')::text AS current_description,
+    COALESCE(
+        CASE
+            WHEN m.id IS NOT NULL THEN catmat_item_fingerprint(m)
+            WHEN s.id IS NOT NULL THEN catser_item_fingerprint(s)
+        END,
+        ''
+    )::text AS current_fingerprint
+FROM item_list_line li
+LEFT JOIN catmat_item m ON li.catalog = 'catmat' AND m.item_code = li.item_code
+LEFT JOIN catser_item s ON li.catalog = 'catser' AND s.service_code = li.item_code
+WHERE li.list_id = $1
+ORDER BY li.created_at, li.id
+`
+
+type ListItemListLinesRow struct {
+	ID                 int64     `json:"id"`
+	ListID             uuid.UUID `json:"list_id"`
+	Catalog            string    `json:"catalog"`
+	ItemCode           int32     `json:"item_code"`
+	Quantity           float64   `json:"quantity"`
+	Unit               string    `json:"unit"`
+	Notes              string    `json:"notes"`
+	ItemDescription    string    `json:"item_description"`
+	ItemFingerprint    string    `json:"item_fingerprint"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	CurrentDescription string    `json:"current_description"`
+	CurrentFingerprint string    `json:"current_fingerprint"`
+}
+
+func (q *Queries) ListItemListLines(ctx context.Context, listID uuid.UUID) ([]ListItemListLinesRow, error) {
+	rows, err := q.db.Query(ctx, listItemListLines, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemListLinesRow
+	for rows.Next() {
+		var i ListItemListLinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ListID,
+			&i.Catalog,
+			&i.ItemCode,
+			&i.Quantity,
+			&i.Unit,
+			&i.Notes,
+			&i.ItemDescription,
+			&i.ItemFingerprint,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CurrentDescription,
+			&i.CurrentFingerprint,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemLists = `-- name: ListItemLists :many
+SELECT
+    l.id,
+    l.user_id,
+    l.organization_id,
+    l.name,
+    l.description,
+    l.created_at,
+    l.updated_at,
+    COUNT(li.id) AS line_count
+FROM item_list l
+LEFT JOIN item_list_line li ON li.list_id = l.id
+WHERE (l.organization_id = $1::uuid
+    OR ($1::uuid IS NULL AND l.organization_id IS NULL AND l.user_id = $2::uuid))
+GROUP BY l.id
+ORDER BY l.updated_at DESC
+`
+
+type ListItemListsParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+}
+
+type ListItemListsRow struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         pgtype.UUID `json:"user_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	LineCount      int64       `json:"line_count"`
+}
+
+func (q *Queries) ListItemLists(ctx context.Context, arg ListItemListsParams) ([]ListItemListsRow, error) {
+	rows, err := q.db.Query(ctx, listItemLists, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemListsRow
+	for rows.Next() {
+		var i ListItemListsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LineCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchItemList = `-- name: TouchItemList :exec
+UPDATE item_list
+SET updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchItemList(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, touchItemList, id)
+	return err
+}
+
+const updateItemList = `-- name: UpdateItemList :one
+UPDATE item_list
+SET name = $1::text,
+    description = $2::text,
+    updated_at = now()
+WHERE id = $3::uuid
+  AND (organization_id = $4::uuid
+    OR ($4::uuid IS NULL AND organization_id IS NULL AND user_id = $5::uuid))
+RETURNING id, user_id, name, description, created_at, updated_at, organization_id
+`
+
+type UpdateItemListParams struct {
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	ID             uuid.UUID   `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) UpdateItemList(ctx context.Context, arg UpdateItemListParams) (ItemList, error) {
+	row := q.db.QueryRow(ctx, updateItemList,
+		arg.Name,
+		arg.Description,
+		arg.ID,
+		arg.OrganizationID,
+		arg.UserID,
+	)
+	var i ItemList
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const updateItemListLine = `-- name: UpdateItemListLine :one
+UPDATE item_list_line
+SET quantity = $3,
+    unit = $4,
+    notes = $5,
+    updated_at = now()
+WHERE id = $1 AND list_id = $2
+RETURNING id, list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint, created_at, updated_at
+`
+
+type UpdateItemListLineParams struct {
+	ID       int64     `json:"id"`
+	ListID   uuid.UUID `json:"list_id"`
+	Quantity float64   `json:"quantity"`
+	Unit     string    `json:"unit"`
+	Notes    string    `json:"notes"`
+}
+
+func (q *Queries) UpdateItemListLine(ctx context.Context, arg UpdateItemListLineParams) (ItemListLine, error) {
+	row := q.db.QueryRow(ctx, updateItemListLine,
+		arg.ID,
+		arg.ListID,
+		arg.Quantity,
+		arg.Unit,
+		arg.Notes,
+	)
+	var i ItemListLine
+	err := row.Scan(
+		&i.ID,
+		&i.ListID,
+		&i.Catalog,
+		&i.ItemCode,
+		&i.Quantity,
+		&i.Unit,
+		&i.Notes,
+		&i.ItemDescription,
+		&i.ItemFingerprint,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+
+-- Dicionário de sinônimos usado na expansão de consultas do FTS
+-- (portuguese_unaccent). Cada linha é um grupo de termos equivalentes,
+-- ex: {"a4", "papel sulfite"} ou {"epi", "equipamento de proteção individual"}.
+CREATE TABLE search_synonym (
+    id          bigserial   PRIMARY KEY,
+    terms       text[]      NOT NULL CHECK (cardinality(terms) >= 2),
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_search_synonym_terms
+    ON search_synonym
+    USING GIN (terms);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_search_synonym_terms;
+DROP TABLE IF EXISTS search_synonym;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+
+-- Registro das buscas CATMAT/CATSER (gravado de forma assíncrona pela API)
+CREATE TABLE search_log (
+    id              uuid        PRIMARY KEY,
+    user_id         uuid        REFERENCES users (id) ON DELETE SET NULL,
+    catalog         text        NOT NULL, -- catmat / catser
+    query           text        NOT NULL, -- termo normalizado (minúsculas, sem acento)
+    filters         jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    result_count    bigint      NOT NULL,
+    latency_ms      integer     NOT NULL,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_search_log_created_at    ON search_log (created_at);
+CREATE INDEX idx_search_log_catalog_query ON search_log (catalog, query);
+
+-- Cliques em resultados; sem FK para search_log pois os dois são gravados
+-- de forma independente
+CREATE TABLE search_click (
+    id          bigserial   PRIMARY KEY,
+    search_id   uuid        NOT NULL,
+    user_id     uuid        REFERENCES users (id) ON DELETE SET NULL,
+    item_code   integer     NOT NULL,
+    position    integer,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_search_click_search_id  ON search_click (search_id);
+CREATE INDEX idx_search_click_created_at ON search_click (created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_search_click_created_at;
+DROP INDEX IF EXISTS idx_search_click_search_id;
+DROP TABLE IF EXISTS search_click;
+
+DROP INDEX IF EXISTS idx_search_log_catalog_query;
+DROP INDEX IF EXISTS idx_search_log_created_at;
+DROP TABLE IF EXISTS search_log;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
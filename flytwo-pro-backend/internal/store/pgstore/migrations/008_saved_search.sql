@@ -0,0 +1,64 @@
+-- Write your migrate up statements here
+
+-- Buscas salvas pelos usuários; reavaliadas após cada importação
+CREATE TABLE saved_search (
+    id                  uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id             uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name                text        NOT NULL,
+    catalog             text        NOT NULL CHECK (catalog IN ('catmat', 'catser')),
+    query               text        NOT NULL DEFAULT '',
+
+    -- Filtros (mesmos de CatmatSearchParams / CatserSearchParams)
+    group_code          smallint,
+    class_code          integer,
+    pdm_code            integer,    -- apenas catmat
+    ncm_code            text,       -- apenas catmat
+    service_code        integer,    -- apenas catser
+    status              text,       -- apenas catser
+
+    last_evaluated_at   timestamptz,
+    created_at          timestamptz NOT NULL DEFAULT now(),
+    updated_at          timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_saved_search_user_id ON saved_search (user_id);
+CREATE INDEX idx_saved_search_catalog ON saved_search (catalog);
+
+-- Itens que já casaram com cada busca salva; um item só gera notificação
+-- na primeira vez que aparece aqui
+CREATE TABLE saved_search_match (
+    saved_search_id     uuid        NOT NULL REFERENCES saved_search (id) ON DELETE CASCADE,
+    item_code           integer     NOT NULL, -- item_code (catmat) ou service_code (catser)
+    matched_at          timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (saved_search_id, item_code)
+);
+
+-- Notificações por usuário
+CREATE TABLE notification (
+    id                  bigserial   PRIMARY KEY,
+    user_id             uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    saved_search_id     uuid        REFERENCES saved_search (id) ON DELETE SET NULL,
+    saved_search_name   text        NOT NULL, -- cópia do nome, mantida se a busca for removida
+    catalog             text        NOT NULL,
+    item_codes          integer[]   NOT NULL,
+    read_at             timestamptz,
+    created_at          timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_user_created ON notification (user_id, created_at DESC);
+CREATE INDEX idx_notification_user_unread  ON notification (user_id) WHERE read_at IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_notification_user_unread;
+DROP INDEX IF EXISTS idx_notification_user_created;
+DROP TABLE IF EXISTS notification;
+
+DROP TABLE IF EXISTS saved_search_match;
+
+DROP INDEX IF EXISTS idx_saved_search_catalog;
+DROP INDEX IF EXISTS idx_saved_search_user_id;
+DROP TABLE IF EXISTS saved_search;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,63 @@
+-- Write your migrate up statements here
+
+-- Impressão digital dos campos de um item que importam para uma cesta;
+-- muda quando uma importação altera o item
+CREATE OR REPLACE FUNCTION catmat_item_fingerprint(i catmat_item)
+RETURNS text
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT md5(concat_ws('|', i.group_code, i.class_code, i.pdm_code, i.item_description, i.ncm_code));
+$$;
+
+CREATE OR REPLACE FUNCTION catser_item_fingerprint(i catser_item)
+RETURNS text
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT md5(concat_ws('|', i.group_code, i.class_code, i.service_description, i.status));
+$$;
+
+-- Cestas (listas de compra) dos usuários
+CREATE TABLE item_list (
+    id              uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id         uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name            text        NOT NULL,
+    description     text        NOT NULL DEFAULT '',
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_item_list_user_id ON item_list (user_id);
+
+CREATE TABLE item_list_line (
+    id                  bigserial        PRIMARY KEY,
+    list_id             uuid             NOT NULL REFERENCES item_list (id) ON DELETE CASCADE,
+    catalog             text             NOT NULL CHECK (catalog IN ('catmat', 'catser')),
+    item_code           integer          NOT NULL, -- item_code (catmat) ou service_code (catser)
+    quantity            double precision NOT NULL CHECK (quantity > 0),
+    unit                text             NOT NULL DEFAULT '',
+    notes               text             NOT NULL DEFAULT '',
+
+    -- Cópia do item no momento em que entrou na cesta
+    item_description    text             NOT NULL,
+    item_fingerprint    text             NOT NULL,
+
+    created_at          timestamptz      NOT NULL DEFAULT now(),
+    updated_at          timestamptz      NOT NULL DEFAULT now(),
+
+    CONSTRAINT uq_item_list_line_item UNIQUE (list_id, catalog, item_code)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS item_list_line;
+
+DROP INDEX IF EXISTS idx_item_list_user_id;
+DROP TABLE IF EXISTS item_list;
+
+DROP FUNCTION IF EXISTS catser_item_fingerprint(catser_item);
+DROP FUNCTION IF EXISTS catmat_item_fingerprint(catmat_item);
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+
+-- Histórico de importações CATMAT/CATSER
+CREATE TABLE catalog_import (
+    id              bigserial   PRIMARY KEY,
+    catalog         text        NOT NULL CHECK (catalog IN ('catmat', 'catser')),
+    rows_read       integer     NOT NULL,
+    rows_saved      integer     NOT NULL,
+    rows_skipped    integer     NOT NULL,
+    item_total      bigint      NOT NULL, -- itens no catálogo ao fim da importação
+    started_at      timestamptz NOT NULL,
+    finished_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_catalog_import_catalog_finished ON catalog_import (catalog, finished_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_catalog_import_catalog_finished;
+DROP TABLE IF EXISTS catalog_import;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+
+-- Camada L2 do cache de busca quando GOBID_CACHE_L2=postgres.
+-- UNLOGGED: sem WAL, escrita mais rápida; o conteúdo é descartado após um
+-- crash do servidor, o que é aceitável para cache.
+CREATE UNLOGGED TABLE cache_entry (
+    key         text        PRIMARY KEY,
+    value       bytea       NOT NULL,
+    expires_at  timestamptz -- NULL = não expira (contadores de geração)
+);
+
+CREATE INDEX idx_cache_entry_expires_at ON cache_entry (expires_at) WHERE expires_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_cache_entry_expires_at;
+DROP TABLE IF EXISTS cache_entry;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
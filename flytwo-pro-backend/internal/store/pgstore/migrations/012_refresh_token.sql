@@ -0,0 +1,28 @@
+-- Write your migrate up statements here
+
+-- Refresh tokens da autenticação por token (app mobile). Só o hash SHA-256 é
+-- guardado. Cada refresh gera um token novo na mesma família e marca o antigo
+-- como usado; reapresentar um token usado revoga a família inteira.
+CREATE TABLE refresh_token (
+    id              uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id         uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    family_id       uuid        NOT NULL,
+    token_hash      bytea       NOT NULL UNIQUE,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    expires_at      timestamptz NOT NULL,
+    used_at         timestamptz,
+    replaced_by     uuid,
+    revoked_at      timestamptz
+);
+
+CREATE INDEX idx_refresh_token_family ON refresh_token (family_id);
+CREATE INDEX idx_refresh_token_user ON refresh_token (user_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_refresh_token_user;
+DROP INDEX IF EXISTS idx_refresh_token_family;
+DROP TABLE IF EXISTS refresh_token;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
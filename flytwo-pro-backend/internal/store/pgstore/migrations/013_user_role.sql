@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+
+-- Papéis dos usuários: admin (importação do catálogo, cache e usuários),
+-- manager (sinônimos e relatórios de busca), buyer (cestas e buscas salvas)
+-- e viewer (apenas consulta). Um usuário pode ter mais de um papel.
+CREATE TABLE user_role (
+    user_id     uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role        text        NOT NULL CHECK (role IN ('admin', 'manager', 'buyer', 'viewer')),
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX idx_user_role_role ON user_role (role);
+
+-- Usuários existentes continuam com o acesso de comprador.
+INSERT INTO user_role (user_id, role)
+SELECT id, 'buyer' FROM users;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_user_role_role;
+DROP TABLE IF EXISTS user_role;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
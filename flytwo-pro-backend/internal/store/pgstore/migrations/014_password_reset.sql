@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+
+-- Tokens de redefinição de senha. Só o hash SHA-256 é guardado; cada token
+-- vale uma vez e pedir um novo invalida os anteriores do mesmo usuário.
+CREATE TABLE password_reset_token (
+    id          uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id     uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash  bytea       NOT NULL UNIQUE,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    expires_at  timestamptz NOT NULL,
+    used_at     timestamptz
+);
+
+CREATE INDEX idx_password_reset_token_user ON password_reset_token (user_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_password_reset_token_user;
+DROP TABLE IF EXISTS password_reset_token;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,28 @@
+-- Write your migrate up statements here
+
+-- Verificação de email. Contas novas começam sem email_verified_at; contas
+-- criadas antes desta migration são consideradas verificadas.
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+
+UPDATE users SET email_verified_at = created_at;
+
+-- Tokens dos links de verificação, guardados apenas como hash SHA-256.
+CREATE TABLE email_verification_token (
+    id          uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id     uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash  bytea       NOT NULL UNIQUE,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    expires_at  timestamptz NOT NULL,
+    used_at     timestamptz
+);
+
+CREATE INDEX idx_email_verification_token_user ON email_verification_token (user_id, created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_email_verification_token_user;
+DROP TABLE IF EXISTS email_verification_token;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,28 @@
+-- Write your migrate up statements here
+
+-- Registro de eventos de segurança (bloqueios e desbloqueios de login). As
+-- linhas só são inseridas, nunca alteradas; user_id fica nulo quando o email
+-- não pertence a nenhuma conta ou a conta é apagada.
+CREATE TABLE security_event (
+    id          bigserial   PRIMARY KEY,
+    event_type  text        NOT NULL,
+    user_id     uuid        REFERENCES users (id) ON DELETE SET NULL,
+    email       text,
+    ip          text,
+    details     jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_security_event_created ON security_event (created_at DESC);
+CREATE INDEX idx_security_event_type ON security_event (event_type, created_at DESC);
+CREATE INDEX idx_security_event_user ON security_event (user_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_security_event_user;
+DROP INDEX IF EXISTS idx_security_event_type;
+DROP INDEX IF EXISTS idx_security_event_created;
+DROP TABLE IF EXISTS security_event;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,36 @@
+-- Write your migrate up statements here
+
+-- Segundo fator TOTP. O segredo fica cifrado (AES-GCM) e só vale depois de
+-- confirmado com o primeiro código; last_used_step impede reusar um código.
+CREATE TABLE user_totp (
+    user_id           uuid        PRIMARY KEY NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    secret_encrypted  bytea       NOT NULL,
+    confirmed_at      timestamptz,
+    last_used_step    bigint      NOT NULL DEFAULT 0,
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+-- Códigos de recuperação, guardados como hash SHA-256; cada um vale uma vez.
+CREATE TABLE totp_recovery_code (
+    id          uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id     uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash   bytea       NOT NULL,
+    used_at     timestamptz,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- Papéis cujos usuários precisam ativar o segundo fator.
+CREATE TABLE two_factor_required_role (
+    role        text        PRIMARY KEY NOT NULL CHECK (role IN ('admin', 'manager', 'buyer', 'viewer')),
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS two_factor_required_role;
+DROP TABLE IF EXISTS totp_recovery_code;
+DROP TABLE IF EXISTS user_totp;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
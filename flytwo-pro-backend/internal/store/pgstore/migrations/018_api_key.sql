@@ -0,0 +1,27 @@
+-- Write your migrate up statements here
+
+-- Chaves de API para scripts e integrações. Só o hash SHA-256 é guardado; a
+-- chave aparece uma única vez, na criação. prefix guarda o começo da chave
+-- para o usuário reconhecer qual é qual.
+CREATE TABLE api_key (
+    id            uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id       uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name          text        NOT NULL,
+    prefix        text        NOT NULL,
+    key_hash      bytea       NOT NULL UNIQUE,
+    scopes        text[]      NOT NULL,
+    expires_at    timestamptz,
+    last_used_at  timestamptz,
+    revoked_at    timestamptz,
+    created_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_key_user ON api_key (user_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_api_key_user;
+DROP TABLE IF EXISTS api_key;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
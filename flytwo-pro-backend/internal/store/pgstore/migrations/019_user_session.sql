@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+
+-- Metadados das sessões web, gravados no login. A tabela sessions do scs
+-- guarda o user id dentro do data (gob), então não dá para listar ou
+-- encerrar as sessões de um usuário por ela. token é o mesmo da tabela
+-- sessions; sem FK porque o scs só grava a sessão depois da resposta.
+CREATE TABLE user_session (
+    id            uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    token         text        NOT NULL UNIQUE,
+    user_id       uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ip            text        NOT NULL DEFAULT '',
+    user_agent    text        NOT NULL DEFAULT '',
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    last_seen_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_session_user ON user_session (user_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_user_session_user;
+DROP TABLE IF EXISTS user_session;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,39 @@
+-- Write your migrate up statements here
+
+-- Pedidos de exclusão de conta (LGPD). A conta é apagada quando scheduled_for
+-- passa; até lá o pedido pode ser cancelado. Sem FK para users de propósito:
+-- a linha fica como registro do atendimento depois que a conta some, guardando
+-- apenas os ids.
+CREATE TABLE account_deletion (
+    id             uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id        uuid        NOT NULL,
+    requested_by   uuid        NOT NULL, -- o próprio usuário ou um admin
+    requested_at   timestamptz NOT NULL DEFAULT now(),
+    scheduled_for  timestamptz NOT NULL,
+    cancelled_at   timestamptz,
+    cancelled_by   uuid,
+    completed_at   timestamptz
+);
+
+-- No máximo um pedido pendente por usuário
+CREATE UNIQUE INDEX uq_account_deletion_pending ON account_deletion (user_id)
+    WHERE cancelled_at IS NULL AND completed_at IS NULL;
+CREATE INDEX idx_account_deletion_due ON account_deletion (scheduled_for)
+    WHERE cancelled_at IS NULL AND completed_at IS NULL;
+CREATE INDEX idx_account_deletion_requested ON account_deletion (requested_at DESC);
+
+-- A exportação busca o histórico de buscas por usuário
+CREATE INDEX idx_search_log_user ON search_log (user_id, created_at DESC) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_search_click_user ON search_click (user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_search_click_user;
+DROP INDEX IF EXISTS idx_search_log_user;
+DROP INDEX IF EXISTS idx_account_deletion_requested;
+DROP INDEX IF EXISTS idx_account_deletion_due;
+DROP INDEX IF EXISTS uq_account_deletion_pending;
+DROP TABLE IF EXISTS account_deletion;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
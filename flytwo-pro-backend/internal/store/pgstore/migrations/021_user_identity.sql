@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+
+-- Contas de provedores OpenID Connect (Keycloak, Azure AD, gov.br) vinculadas
+-- aos usuários. O vínculo é pelo par (provider, subject), que o provedor
+-- garante estável; o email só serve para achar ou criar a conta no primeiro
+-- login e é atualizado a cada login.
+CREATE TABLE user_identity (
+    id             uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id        uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider       text        NOT NULL, -- nome configurado em GOBID_OIDC_PROVIDERS
+    subject        text        NOT NULL, -- claim sub do ID token
+    email          text        NOT NULL,
+    created_at     timestamptz NOT NULL DEFAULT now(),
+    last_login_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identity_user ON user_identity (user_id);
+
+-- O primeiro login OIDC procura a conta pelo email sem diferenciar maiúsculas
+CREATE INDEX idx_users_email_lower ON users (lower(email));
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_users_email_lower;
+DROP INDEX IF EXISTS idx_user_identity_user;
+DROP TABLE IF EXISTS user_identity;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,57 @@
+-- Write your migrate up statements here
+
+-- Órgãos públicos em nome dos quais os usuários trabalham. O CNPJ e a UASG
+-- (código da unidade no Compras.gov.br) são guardados só com os dígitos.
+CREATE TABLE organization (
+    id          uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    name        text        NOT NULL,
+    cnpj        text        UNIQUE CHECK (cnpj ~ '^[0-9]{14}$'),
+    uasg        text        UNIQUE CHECK (uasg ~ '^[0-9]{6}$'),
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now()
+);
+
+-- Membros de cada órgão com o papel dentro dele: admin gerencia os membros,
+-- member altera os dados compartilhados e viewer só consulta
+CREATE TABLE organization_member (
+    organization_id  uuid        NOT NULL REFERENCES organization (id) ON DELETE CASCADE,
+    user_id          uuid        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role             text        NOT NULL CHECK (role IN ('admin', 'member', 'viewer')),
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    updated_at       timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_member_user ON organization_member (user_id);
+
+-- Cestas de um órgão são compartilhadas entre os membros; sem organization_id
+-- a cesta é pessoal. Cestas de órgão sobrevivem à exclusão de quem as criou
+-- (user_id vira NULL); as pessoais são apagadas antes da conta.
+ALTER TABLE item_list ADD COLUMN organization_id uuid REFERENCES organization (id) ON DELETE CASCADE;
+ALTER TABLE item_list ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE item_list DROP CONSTRAINT item_list_user_id_fkey;
+ALTER TABLE item_list ADD CONSTRAINT item_list_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE item_list ADD CONSTRAINT chk_item_list_owner
+    CHECK (user_id IS NOT NULL OR organization_id IS NOT NULL);
+
+CREATE INDEX idx_item_list_organization_id ON item_list (organization_id) WHERE organization_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_item_list_organization_id;
+
+DELETE FROM item_list WHERE organization_id IS NOT NULL;
+ALTER TABLE item_list DROP CONSTRAINT IF EXISTS chk_item_list_owner;
+ALTER TABLE item_list DROP CONSTRAINT IF EXISTS item_list_user_id_fkey;
+ALTER TABLE item_list ADD CONSTRAINT item_list_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE item_list ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE item_list DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS idx_organization_member_user;
+DROP TABLE IF EXISTS organization_member;
+DROP TABLE IF EXISTS organization;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+
+-- Sessões abertas antes da migration 019 não têm linha em user_session e só
+-- poderiam ser encerradas varrendo a tabela sessions inteira. Elas são
+-- encerradas uma única vez aqui; a partir daqui toda sessão logada é
+-- registrada no login e revogada pelo token.
+DELETE FROM sessions s
+WHERE NOT EXISTS (
+    SELECT 1 FROM user_session us WHERE us.token = s.token
+);
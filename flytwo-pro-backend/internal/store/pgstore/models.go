@@ -12,6 +12,47 @@ import (
 	"github.com/pgvector/pgvector-go"
 )
 
+type AccountDeletion struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	RequestedBy  uuid.UUID          `json:"requested_by"`
+	RequestedAt  time.Time          `json:"requested_at"`
+	ScheduledFor time.Time          `json:"scheduled_for"`
+	CancelledAt  pgtype.Timestamptz `json:"cancelled_at"`
+	CancelledBy  pgtype.UUID        `json:"cancelled_by"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+}
+
+type ApiKey struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	KeyHash    []byte             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type CacheEntry struct {
+	Key       string             `json:"key"`
+	Value     []byte             `json:"value"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type CatalogImport struct {
+	ID          int64     `json:"id"`
+	Catalog     string    `json:"catalog"`
+	RowsRead    int32     `json:"rows_read"`
+	RowsSaved   int32     `json:"rows_saved"`
+	RowsSkipped int32     `json:"rows_skipped"`
+	ItemTotal   int64     `json:"item_total"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
 type CatmatItem struct {
 	ID              int64           `json:"id"`
 	GroupCode       int16           `json:"group_code"`
@@ -41,17 +82,207 @@ type CatserItem struct {
 	Embedding           pgvector.Vector `json:"embedding"`
 }
 
+type EmailVerificationToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash []byte             `json:"token_hash"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+}
+
+type ItemList struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         pgtype.UUID `json:"user_id"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+type ItemListLine struct {
+	ID              int64     `json:"id"`
+	ListID          uuid.UUID `json:"list_id"`
+	Catalog         string    `json:"catalog"`
+	ItemCode        int32     `json:"item_code"`
+	Quantity        float64   `json:"quantity"`
+	Unit            string    `json:"unit"`
+	Notes           string    `json:"notes"`
+	ItemDescription string    `json:"item_description"`
+	ItemFingerprint string    `json:"item_fingerprint"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type Notification struct {
+	ID              int64              `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	SavedSearchID   pgtype.UUID        `json:"saved_search_id"`
+	SavedSearchName string             `json:"saved_search_name"`
+	Catalog         string             `json:"catalog"`
+	ItemCodes       []int32            `json:"item_codes"`
+	ReadAt          pgtype.Timestamptz `json:"read_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type Organization struct {
+	ID        uuid.UUID   `json:"id"`
+	Name      string      `json:"name"`
+	Cnpj      pgtype.Text `json:"cnpj"`
+	Uasg      pgtype.Text `json:"uasg"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash []byte             `json:"token_hash"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+}
+
+type RefreshToken struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	FamilyID   uuid.UUID          `json:"family_id"`
+	TokenHash  []byte             `json:"token_hash"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	UsedAt     pgtype.Timestamptz `json:"used_at"`
+	ReplacedBy pgtype.UUID        `json:"replaced_by"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type SavedSearch struct {
+	ID              uuid.UUID          `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	Name            string             `json:"name"`
+	Catalog         string             `json:"catalog"`
+	Query           string             `json:"query"`
+	GroupCode       pgtype.Int2        `json:"group_code"`
+	ClassCode       pgtype.Int4        `json:"class_code"`
+	PdmCode         pgtype.Int4        `json:"pdm_code"`
+	NcmCode         pgtype.Text        `json:"ncm_code"`
+	ServiceCode     pgtype.Int4        `json:"service_code"`
+	Status          pgtype.Text        `json:"status"`
+	LastEvaluatedAt pgtype.Timestamptz `json:"last_evaluated_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type SavedSearchMatch struct {
+	SavedSearchID uuid.UUID `json:"saved_search_id"`
+	ItemCode      int32     `json:"item_code"`
+	MatchedAt     time.Time `json:"matched_at"`
+}
+
+type SearchClick struct {
+	ID        int64       `json:"id"`
+	SearchID  uuid.UUID   `json:"search_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+	ItemCode  int32       `json:"item_code"`
+	Position  pgtype.Int4 `json:"position"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type SearchLog struct {
+	ID          uuid.UUID   `json:"id"`
+	UserID      pgtype.UUID `json:"user_id"`
+	Catalog     string      `json:"catalog"`
+	Query       string      `json:"query"`
+	Filters     []byte      `json:"filters"`
+	ResultCount int64       `json:"result_count"`
+	LatencyMs   int32       `json:"latency_ms"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type SearchSynonym struct {
+	ID        int64     `json:"id"`
+	Terms     []string  `json:"terms"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SecurityEvent struct {
+	ID        int64       `json:"id"`
+	EventType string      `json:"event_type"`
+	UserID    pgtype.UUID `json:"user_id"`
+	Email     pgtype.Text `json:"email"`
+	Ip        pgtype.Text `json:"ip"`
+	Details   []byte      `json:"details"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
 type Session struct {
 	Token  string    `json:"token"`
 	Data   []byte    `json:"data"`
 	Expiry time.Time `json:"expiry"`
 }
 
+type TotpRecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  []byte             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type TwoFactorRequiredRole struct {
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	Email        string    `json:"email"`
-	PasswordHash []byte    `json:"password_hash"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	Email           string             `json:"email"`
+	PasswordHash    []byte             `json:"password_hash"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+type UserIdentity struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserSession struct {
+	ID         uuid.UUID `json:"id"`
+	Token      string    `json:"token"`
+	UserID     uuid.UUID `json:"user_id"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+type UserTotp struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	SecretEncrypted []byte             `json:"secret_encrypted"`
+	ConfirmedAt     pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep    int64              `json:"last_used_step"`
+	CreatedAt       time.Time          `json:"created_at"`
 }
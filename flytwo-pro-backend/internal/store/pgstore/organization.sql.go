@@ -0,0 +1,381 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: organization.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countOrganizationAdmins = `-- name: CountOrganizationAdmins :one
+SELECT count(*) FROM organization_member
+WHERE organization_id = $1 AND role = 'admin'
+`
+
+func (q *Queries) CountOrganizationAdmins(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizationAdmins, organizationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOrganizations = `-- name: CountOrganizations :one
+SELECT count(*) FROM organization o
+WHERE $1::text = ''
+   OR o.name ILIKE '%' || $1::text || '%'
+   OR o.cnpj = $1::text
+   OR o.uasg = $1::text
+`
+
+func (q *Queries) CountOrganizations(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizations, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createOrganization = `-- name: CreateOrganization :one
+INSERT INTO organization (name, cnpj, uasg)
+VALUES ($1, $2, $3)
+RETURNING id, name, cnpj, uasg, created_at, updated_at
+`
+
+type CreateOrganizationParams struct {
+	Name string      `json:"name"`
+	Cnpj pgtype.Text `json:"cnpj"`
+	Uasg pgtype.Text `json:"uasg"`
+}
+
+func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization, arg.Name, arg.Cnpj, arg.Uasg)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Cnpj,
+		&i.Uasg,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOrganization = `-- name: DeleteOrganization :execrows
+DELETE FROM organization
+WHERE id = $1
+`
+
+func (q *Queries) DeleteOrganization(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrganization, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_member
+WHERE organization_id = $1 AND user_id = $2
+`
+
+type DeleteOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrganizationMember, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getOrganization = `-- name: GetOrganization :one
+SELECT id, name, cnpj, uasg, created_at, updated_at FROM organization
+WHERE id = $1
+`
+
+func (q *Queries) GetOrganization(ctx context.Context, id uuid.UUID) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganization, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Cnpj,
+		&i.Uasg,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationMembership = `-- name: GetOrganizationMembership :one
+SELECT m.organization_id, m.user_id, m.role, o.name AS organization_name
+FROM organization_member m
+JOIN organization o ON o.id = m.organization_id
+WHERE m.organization_id = $1 AND m.user_id = $2
+`
+
+type GetOrganizationMembershipParams struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+type GetOrganizationMembershipRow struct {
+	OrganizationID   uuid.UUID `json:"organization_id"`
+	UserID           uuid.UUID `json:"user_id"`
+	Role             string    `json:"role"`
+	OrganizationName string    `json:"organization_name"`
+}
+
+func (q *Queries) GetOrganizationMembership(ctx context.Context, arg GetOrganizationMembershipParams) (GetOrganizationMembershipRow, error) {
+	row := q.db.QueryRow(ctx, getOrganizationMembership, arg.OrganizationID, arg.UserID)
+	var i GetOrganizationMembershipRow
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.UserID,
+		&i.Role,
+		&i.OrganizationName,
+	)
+	return i, err
+}
+
+const listOrganizationMembers = `-- name: ListOrganizationMembers :many
+SELECT
+    m.user_id,
+    u.user_name,
+    u.email,
+    m.role,
+    m.created_at,
+    m.updated_at
+FROM organization_member m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY u.user_name, m.user_id
+`
+
+type ListOrganizationMembersRow struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]ListOrganizationMembersRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizationMembers, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizationMembersRow
+	for rows.Next() {
+		var i ListOrganizationMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizations = `-- name: ListOrganizations :many
+SELECT
+    o.id, o.name, o.cnpj, o.uasg, o.created_at, o.updated_at,
+    (SELECT count(*) FROM organization_member m WHERE m.organization_id = o.id) AS member_count
+FROM organization o
+WHERE $1::text = ''
+   OR o.name ILIKE '%' || $1::text || '%'
+   OR o.cnpj = $1::text
+   OR o.uasg = $1::text
+ORDER BY o.name, o.id
+LIMIT $3::integer OFFSET $2::integer
+`
+
+type ListOrganizationsParams struct {
+	Query  string `json:"query"`
+	Offset int32  `json:"offset"`
+	Limit  int32  `json:"limit"`
+}
+
+type ListOrganizationsRow struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Cnpj        pgtype.Text `json:"cnpj"`
+	Uasg        pgtype.Text `json:"uasg"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	MemberCount int64       `json:"member_count"`
+}
+
+func (q *Queries) ListOrganizations(ctx context.Context, arg ListOrganizationsParams) ([]ListOrganizationsRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizations, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizationsRow
+	for rows.Next() {
+		var i ListOrganizationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Cnpj,
+			&i.Uasg,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MemberCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserOrganizations = `-- name: ListUserOrganizations :many
+SELECT
+    o.id,
+    o.name,
+    o.cnpj,
+    o.uasg,
+    m.role,
+    m.created_at AS joined_at
+FROM organization_member m
+JOIN organization o ON o.id = m.organization_id
+WHERE m.user_id = $1
+ORDER BY o.name, o.id
+`
+
+type ListUserOrganizationsRow struct {
+	ID       uuid.UUID   `json:"id"`
+	Name     string      `json:"name"`
+	Cnpj     pgtype.Text `json:"cnpj"`
+	Uasg     pgtype.Text `json:"uasg"`
+	Role     string      `json:"role"`
+	JoinedAt time.Time   `json:"joined_at"`
+}
+
+func (q *Queries) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]ListUserOrganizationsRow, error) {
+	rows, err := q.db.Query(ctx, listUserOrganizations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserOrganizationsRow
+	for rows.Next() {
+		var i ListUserOrganizationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Cnpj,
+			&i.Uasg,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrganization = `-- name: LockOrganization :one
+SELECT id FROM organization
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockOrganization(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, lockOrganization, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateOrganization = `-- name: UpdateOrganization :one
+UPDATE organization
+SET name = $2,
+    cnpj = $3,
+    uasg = $4,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, name, cnpj, uasg, created_at, updated_at
+`
+
+type UpdateOrganizationParams struct {
+	ID   uuid.UUID   `json:"id"`
+	Name string      `json:"name"`
+	Cnpj pgtype.Text `json:"cnpj"`
+	Uasg pgtype.Text `json:"uasg"`
+}
+
+func (q *Queries) UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganization,
+		arg.ID,
+		arg.Name,
+		arg.Cnpj,
+		arg.Uasg,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Cnpj,
+		&i.Uasg,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertOrganizationMember = `-- name: UpsertOrganizationMember :one
+INSERT INTO organization_member (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET role = EXCLUDED.role,
+    updated_at = now()
+RETURNING organization_id, user_id, role, created_at, updated_at
+`
+
+type UpsertOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           string    `json:"role"`
+}
+
+func (q *Queries) UpsertOrganizationMember(ctx context.Context, arg UpsertOrganizationMemberParams) (OrganizationMember, error) {
+	row := q.db.QueryRow(ctx, upsertOrganizationMember, arg.OrganizationID, arg.UserID, arg.Role)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,55 @@
+-- name: CreateAccountDeletion :one
+INSERT INTO account_deletion (user_id, requested_by, scheduled_for)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPendingAccountDeletion :one
+SELECT *
+FROM account_deletion
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL;
+
+-- name: GetPendingAccountDeletionForUpdate :one
+SELECT *
+FROM account_deletion
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL
+FOR UPDATE;
+
+-- name: CancelAccountDeletion :one
+UPDATE account_deletion
+SET cancelled_at = now(),
+    cancelled_by = $2
+WHERE user_id = $1 AND cancelled_at IS NULL AND completed_at IS NULL
+RETURNING *;
+
+-- name: CompleteAccountDeletion :exec
+UPDATE account_deletion
+SET completed_at = now()
+WHERE id = $1;
+
+-- name: ListDueAccountDeletions :many
+SELECT user_id
+FROM account_deletion
+WHERE cancelled_at IS NULL AND completed_at IS NULL AND scheduled_for <= now()
+ORDER BY scheduled_for
+LIMIT $1;
+
+-- name: ListAccountDeletions :many
+SELECT
+    d.*,
+    COALESCE(u.email, '')::text AS email
+FROM account_deletion d
+LEFT JOIN users u ON u.id = d.user_id
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'pending' AND d.cancelled_at IS NULL AND d.completed_at IS NULL)
+    OR (sqlc.narg('status') = 'cancelled' AND d.cancelled_at IS NOT NULL)
+    OR (sqlc.narg('status') = 'completed' AND d.completed_at IS NOT NULL))
+ORDER BY d.requested_at DESC, d.id
+LIMIT $1 OFFSET $2;
+
+-- name: CountAccountDeletions :one
+SELECT COUNT(*)
+FROM account_deletion d
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'pending' AND d.cancelled_at IS NULL AND d.completed_at IS NULL)
+    OR (sqlc.narg('status') = 'cancelled' AND d.cancelled_at IS NOT NULL)
+    OR (sqlc.narg('status') = 'completed' AND d.completed_at IS NOT NULL));
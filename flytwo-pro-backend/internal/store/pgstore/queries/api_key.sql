@@ -0,0 +1,35 @@
+-- name: CreateApiKey :one
+INSERT INTO api_key (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListApiKeysByUser :many
+SELECT * FROM api_key
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: CountActiveApiKeys :one
+SELECT COUNT(*) FROM api_key
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now());
+
+-- name: GetApiKeyByHash :one
+SELECT * FROM api_key
+WHERE key_hash = $1;
+
+-- name: TouchApiKey :exec
+UPDATE api_key
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: RevokeApiKey :execrows
+UPDATE api_key
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserApiKeys :execrows
+UPDATE api_key
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,63 @@
+-- name: InsertCatalogImport :one
+INSERT INTO catalog_import (
+    catalog,
+    rows_read,
+    rows_saved,
+    rows_skipped,
+    item_total,
+    started_at
+)
+VALUES (
+    sqlc.arg('catalog'),
+    sqlc.arg('rows_read'),
+    sqlc.arg('rows_saved'),
+    sqlc.arg('rows_skipped'),
+    (CASE sqlc.arg('catalog')::text
+        WHEN 'catmat' THEN (SELECT COUNT(*) FROM catmat_item)
+        ELSE (SELECT COUNT(*) FROM catser_item)
+    END),
+    sqlc.arg('started_at')
+)
+RETURNING *;
+
+-- name: LastCatalogImports :many
+SELECT DISTINCT ON (catalog) *
+FROM catalog_import
+ORDER BY catalog, finished_at DESC;
+
+-- name: CatalogImportHistory :many
+SELECT *
+FROM catalog_import
+WHERE finished_at >= sqlc.arg('since')
+ORDER BY finished_at, id;
+
+-- name: CatmatCoverage :one
+SELECT
+    COUNT(*) AS total,
+    COUNT(ncm_code) AS with_ncm,
+    COUNT(embedding) AS with_embedding,
+    COUNT(DISTINCT group_code) AS groups,
+    COUNT(DISTINCT class_code) AS classes
+FROM catmat_item;
+
+-- name: CatserCoverage :one
+SELECT
+    COUNT(*) AS total,
+    COUNT(embedding) AS with_embedding,
+    COUNT(DISTINCT group_code) AS groups,
+    COUNT(DISTINCT class_code) AS classes
+FROM catser_item;
+
+-- name: CatmatCountByClass :many
+SELECT class_code, class_name, COUNT(*) AS count
+FROM catmat_item
+WHERE group_code = $1
+GROUP BY class_code, class_name
+ORDER BY count DESC, class_code;
+
+-- name: CatserCountByClass :many
+SELECT class_code, class_name, COUNT(*) AS count
+FROM catser_item
+WHERE group_code = $1
+GROUP BY class_code, class_name
+ORDER BY count DESC, class_code;
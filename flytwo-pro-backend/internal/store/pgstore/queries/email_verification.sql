@@ -0,0 +1,37 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_token (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetEmailVerificationTokenByHashForUpdate :one
+SELECT * FROM email_verification_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: InvalidateEmailVerificationTokens :exec
+UPDATE email_verification_token
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: GetEmailVerificationSendStats :one
+SELECT
+    count(*) FILTER (WHERE created_at > now() - interval '1 hour') AS sent_last_hour,
+    coalesce(min(created_at) FILTER (WHERE created_at > now() - interval '1 hour'), 'epoch'::timestamptz)::timestamptz AS first_sent_last_hour,
+    coalesce(max(created_at), 'epoch'::timestamptz)::timestamptz AS last_sent_at
+FROM email_verification_token
+WHERE user_id = $1;
+
+-- name: GetUserForEmailVerification :one
+SELECT id, user_name, email, email_verified_at
+FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = CASE WHEN sqlc.arg(verified)::bool THEN coalesce(email_verified_at, now()) END,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: GetUserEmailVerifiedAt :one
+SELECT email_verified_at FROM users
+WHERE id = $1;
@@ -0,0 +1,131 @@
+-- name: ListItemLists :many
+SELECT
+    l.id,
+    l.user_id,
+    l.organization_id,
+    l.name,
+    l.description,
+    l.created_at,
+    l.updated_at,
+    COUNT(li.id) AS line_count
+FROM item_list l
+LEFT JOIN item_list_line li ON li.list_id = l.id
+WHERE (l.organization_id = sqlc.narg('organization_id')::uuid
+    OR (sqlc.narg('organization_id')::uuid IS NULL AND l.organization_id IS NULL AND l.user_id = sqlc.arg('user_id')::uuid))
+GROUP BY l.id
+ORDER BY l.updated_at DESC;
+
+-- name: GetItemList :one
+SELECT *
+FROM item_list
+WHERE id = sqlc.arg('id')::uuid
+  AND (organization_id = sqlc.narg('organization_id')::uuid
+    OR (sqlc.narg('organization_id')::uuid IS NULL AND organization_id IS NULL AND user_id = sqlc.arg('user_id')::uuid));
+
+-- name: CreateItemList :one
+INSERT INTO item_list (user_id, organization_id, name, description)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateItemList :one
+UPDATE item_list
+SET name = sqlc.arg('name')::text,
+    description = sqlc.arg('description')::text,
+    updated_at = now()
+WHERE id = sqlc.arg('id')::uuid
+  AND (organization_id = sqlc.narg('organization_id')::uuid
+    OR (sqlc.narg('organization_id')::uuid IS NULL AND organization_id IS NULL AND user_id = sqlc.arg('user_id')::uuid))
+RETURNING *;
+
+-- name: TouchItemList :exec
+UPDATE item_list
+SET updated_at = now()
+WHERE id = $1;
+
+-- name: DeleteItemList :execrows
+DELETE FROM item_list
+WHERE id = sqlc.arg('id')::uuid
+  AND (organization_id = sqlc.narg('organization_id')::uuid
+    OR (sqlc.narg('organization_id')::uuid IS NULL AND organization_id IS NULL AND user_id = sqlc.arg('user_id')::uuid));
+
+-- name: DeletePersonalItemListsByUser :execrows
+DELETE FROM item_list
+WHERE user_id = sqlc.arg('user_id')::uuid AND organization_id IS NULL;
+
+-- name: ListItemListLines :many
+SELECT
+    li.id,
+    li.list_id,
+    li.catalog,
+    li.item_code,
+    li.quantity,
+    li.unit,
+    li.notes,
+    li.item_description,
+    li.item_fingerprint,
+    li.created_at,
+    li.updated_at,
+    -- Vazios quando o item não existe mais no catálogo
+    COALESCE(m.item_description, s.service_description, '')::text AS current_description,
+    COALESCE(
+        CASE
+            WHEN m.id IS NOT NULL THEN catmat_item_fingerprint(m)
+            WHEN s.id IS NOT NULL THEN catser_item_fingerprint(s)
+        END,
+        ''
+    )::text AS current_fingerprint
+FROM item_list_line li
+LEFT JOIN catmat_item m ON li.catalog = 'catmat' AND m.item_code = li.item_code
+LEFT JOIN catser_item s ON li.catalog = 'catser' AND s.service_code = li.item_code
+WHERE li.list_id = $1
+ORDER BY li.created_at, li.id;
+
+-- name: CreateCatmatListLine :one
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT
+    sqlc.arg('list_id')::uuid,
+    'catmat',
+    i.item_code,
+    sqlc.arg('quantity')::float8,
+    sqlc.arg('unit')::text,
+    sqlc.arg('notes')::text,
+    i.item_description,
+    catmat_item_fingerprint(i)
+FROM catmat_item i
+WHERE i.item_code = sqlc.arg('item_code')::integer
+RETURNING *;
+
+-- name: CreateCatserListLine :one
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT
+    sqlc.arg('list_id')::uuid,
+    'catser',
+    i.service_code,
+    sqlc.arg('quantity')::float8,
+    sqlc.arg('unit')::text,
+    sqlc.arg('notes')::text,
+    i.service_description,
+    catser_item_fingerprint(i)
+FROM catser_item i
+WHERE i.service_code = sqlc.arg('item_code')::integer
+RETURNING *;
+
+-- name: UpdateItemListLine :one
+UPDATE item_list_line
+SET quantity = $3,
+    unit = $4,
+    notes = $5,
+    updated_at = now()
+WHERE id = $1 AND list_id = $2
+RETURNING *;
+
+-- name: DeleteItemListLine :execrows
+DELETE FROM item_list_line
+WHERE id = $1 AND list_id = $2;
+
+-- name: CopyItemListLines :execrows
+INSERT INTO item_list_line (list_id, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint)
+SELECT sqlc.arg('to_list_id')::uuid, catalog, item_code, quantity, unit, notes, item_description, item_fingerprint
+FROM item_list_line
+WHERE list_id = sqlc.arg('from_list_id')::uuid
+ORDER BY id;
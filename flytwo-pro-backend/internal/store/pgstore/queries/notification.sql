@@ -0,0 +1,39 @@
+-- name: CreateNotification :one
+INSERT INTO notification (
+    user_id,
+    saved_search_id,
+    saved_search_name,
+    catalog,
+    item_codes
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListNotificationsByUser :many
+SELECT *
+FROM notification
+WHERE user_id = sqlc.arg('user_id')
+  AND (NOT sqlc.arg('unread_only')::boolean OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notification
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notification
+SET read_at = now()
+WHERE id = $1 AND user_id = $2 AND read_at IS NULL;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notification
+SET read_at = now()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: ListAllNotificationsByUser :many
+SELECT *
+FROM notification
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
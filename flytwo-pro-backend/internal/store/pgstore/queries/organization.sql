@@ -0,0 +1,93 @@
+-- name: ListOrganizations :many
+SELECT
+    o.*,
+    (SELECT count(*) FROM organization_member m WHERE m.organization_id = o.id) AS member_count
+FROM organization o
+WHERE sqlc.arg('query')::text = ''
+   OR o.name ILIKE '%' || sqlc.arg('query')::text || '%'
+   OR o.cnpj = sqlc.arg('query')::text
+   OR o.uasg = sqlc.arg('query')::text
+ORDER BY o.name, o.id
+LIMIT sqlc.arg('limit')::integer OFFSET sqlc.arg('offset')::integer;
+
+-- name: CountOrganizations :one
+SELECT count(*) FROM organization o
+WHERE sqlc.arg('query')::text = ''
+   OR o.name ILIKE '%' || sqlc.arg('query')::text || '%'
+   OR o.cnpj = sqlc.arg('query')::text
+   OR o.uasg = sqlc.arg('query')::text;
+
+-- name: GetOrganization :one
+SELECT * FROM organization
+WHERE id = $1;
+
+-- name: LockOrganization :one
+SELECT id FROM organization
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CreateOrganization :one
+INSERT INTO organization (name, cnpj, uasg)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateOrganization :one
+UPDATE organization
+SET name = $2,
+    cnpj = $3,
+    uasg = $4,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteOrganization :execrows
+DELETE FROM organization
+WHERE id = $1;
+
+-- name: GetOrganizationMembership :one
+SELECT m.organization_id, m.user_id, m.role, o.name AS organization_name
+FROM organization_member m
+JOIN organization o ON o.id = m.organization_id
+WHERE m.organization_id = $1 AND m.user_id = $2;
+
+-- name: ListOrganizationMembers :many
+SELECT
+    m.user_id,
+    u.user_name,
+    u.email,
+    m.role,
+    m.created_at,
+    m.updated_at
+FROM organization_member m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY u.user_name, m.user_id;
+
+-- name: UpsertOrganizationMember :one
+INSERT INTO organization_member (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET role = EXCLUDED.role,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_member
+WHERE organization_id = $1 AND user_id = $2;
+
+-- name: CountOrganizationAdmins :one
+SELECT count(*) FROM organization_member
+WHERE organization_id = $1 AND role = 'admin';
+
+-- name: ListUserOrganizations :many
+SELECT
+    o.id,
+    o.name,
+    o.cnpj,
+    o.uasg,
+    m.role,
+    m.created_at AS joined_at
+FROM organization_member m
+JOIN organization o ON o.id = m.organization_id
+WHERE m.user_id = $1
+ORDER BY o.name, o.id;
@@ -0,0 +1,18 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_token (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetPasswordResetTokenByHashForUpdate :one
+SELECT * FROM password_reset_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: InvalidatePasswordResetTokens :exec
+UPDATE password_reset_token
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,33 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_token (user_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetRefreshTokenByHashForUpdate :one
+SELECT *
+FROM refresh_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_token
+SET used_at = now(),
+    replaced_by = $2
+WHERE id = $1;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_token
+SET revoked_at = now()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+
+-- name: RevokeUserRefreshTokens :execrows
+UPDATE refresh_token
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: ListRefreshTokensByUser :many
+SELECT *
+FROM refresh_token
+WHERE user_id = $1
+ORDER BY created_at DESC;
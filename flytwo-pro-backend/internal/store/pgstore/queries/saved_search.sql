@@ -0,0 +1,100 @@
+-- name: ListSavedSearchesByUser :many
+SELECT *
+FROM saved_search
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListSavedSearchesByCatalog :many
+SELECT *
+FROM saved_search
+WHERE catalog = $1
+ORDER BY created_at;
+
+-- name: GetSavedSearch :one
+SELECT *
+FROM saved_search
+WHERE id = $1 AND user_id = $2;
+
+-- name: CountSavedSearchesByUser :one
+SELECT COUNT(*)
+FROM saved_search
+WHERE user_id = $1;
+
+-- name: CreateSavedSearch :one
+INSERT INTO saved_search (
+    user_id,
+    name,
+    catalog,
+    query,
+    group_code,
+    class_code,
+    pdm_code,
+    ncm_code,
+    service_code,
+    status
+)
+VALUES (
+    sqlc.arg('user_id'),
+    sqlc.arg('name'),
+    sqlc.arg('catalog'),
+    sqlc.arg('query'),
+    sqlc.narg('group_code'),
+    sqlc.narg('class_code'),
+    sqlc.narg('pdm_code'),
+    sqlc.narg('ncm_code'),
+    sqlc.narg('service_code'),
+    sqlc.narg('status')
+)
+RETURNING *;
+
+-- name: UpdateSavedSearch :one
+UPDATE saved_search
+SET name = sqlc.arg('name'),
+    catalog = sqlc.arg('catalog'),
+    query = sqlc.arg('query'),
+    group_code = sqlc.narg('group_code'),
+    class_code = sqlc.narg('class_code'),
+    pdm_code = sqlc.narg('pdm_code'),
+    ncm_code = sqlc.narg('ncm_code'),
+    service_code = sqlc.narg('service_code'),
+    status = sqlc.narg('status'),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_search
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkSavedSearchEvaluated :exec
+UPDATE saved_search
+SET last_evaluated_at = now()
+WHERE id = $1;
+
+-- name: ClearSavedSearchMatches :exec
+DELETE FROM saved_search_match
+WHERE saved_search_id = $1;
+
+-- name: InsertCatmatSavedSearchMatches :many
+INSERT INTO saved_search_match (saved_search_id, item_code)
+SELECT sqlc.arg('saved_search_id')::uuid, i.item_code
+FROM catmat_item i
+WHERE (sqlc.narg('query')::text IS NULL OR i.search_document @@ websearch_to_tsquery('portuguese_unaccent', sqlc.narg('query')::text))
+  AND (sqlc.narg('group_code')::smallint IS NULL OR i.group_code = sqlc.narg('group_code')::smallint)
+  AND (sqlc.narg('class_code')::integer IS NULL OR i.class_code = sqlc.narg('class_code')::integer)
+  AND (sqlc.narg('pdm_code')::integer IS NULL OR i.pdm_code = sqlc.narg('pdm_code')::integer)
+  AND (sqlc.narg('ncm_code')::text IS NULL OR i.ncm_code = sqlc.narg('ncm_code')::text)
+ON CONFLICT DO NOTHING
+RETURNING item_code;
+
+-- name: InsertCatserSavedSearchMatches :many
+INSERT INTO saved_search_match (saved_search_id, item_code)
+SELECT sqlc.arg('saved_search_id')::uuid, i.service_code
+FROM catser_item i
+WHERE (sqlc.narg('query')::text IS NULL OR i.search_document @@ websearch_to_tsquery('portuguese_unaccent', sqlc.narg('query')::text))
+  AND (sqlc.narg('group_code')::smallint IS NULL OR i.group_code = sqlc.narg('group_code')::smallint)
+  AND (sqlc.narg('class_code')::integer IS NULL OR i.class_code = sqlc.narg('class_code')::integer)
+  AND (sqlc.narg('service_code')::integer IS NULL OR i.service_code = sqlc.narg('service_code')::integer)
+  AND (sqlc.narg('status')::text IS NULL OR i.status = sqlc.narg('status')::text)
+ON CONFLICT DO NOTHING
+RETURNING item_code;
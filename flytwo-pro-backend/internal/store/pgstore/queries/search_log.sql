@@ -0,0 +1,91 @@
+-- name: InsertSearchLog :exec
+INSERT INTO search_log (
+    id,
+    user_id,
+    catalog,
+    query,
+    filters,
+    result_count,
+    latency_ms,
+    created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: InsertSearchClick :exec
+INSERT INTO search_click (search_id, user_id, item_code, position)
+VALUES ($1, $2, $3, $4);
+
+-- name: TopSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    COUNT(*) FILTER (
+        WHERE EXISTS (SELECT 1 FROM search_click c WHERE c.search_id = l.id)
+    ) AS searches_with_click,
+    AVG(l.result_count)::float8 AS avg_results,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= sqlc.arg('from')
+  AND l.created_at < sqlc.arg('to')
+  AND (sqlc.narg('catalog')::text IS NULL OR l.catalog = sqlc.narg('catalog'))
+  AND l.query <> ''
+GROUP BY l.catalog, l.query
+ORDER BY searches DESC, l.query
+LIMIT sqlc.arg('limit');
+
+-- name: ZeroResultSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= sqlc.arg('from')
+  AND l.created_at < sqlc.arg('to')
+  AND (sqlc.narg('catalog')::text IS NULL OR l.catalog = sqlc.narg('catalog'))
+  AND l.result_count = 0
+GROUP BY l.catalog, l.query
+ORDER BY searches DESC, l.query
+LIMIT sqlc.arg('limit');
+
+-- name: SlowestSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    AVG(l.result_count)::float8 AS avg_results,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= sqlc.arg('from')
+  AND l.created_at < sqlc.arg('to')
+  AND (sqlc.narg('catalog')::text IS NULL OR l.catalog = sqlc.narg('catalog'))
+GROUP BY l.catalog, l.query
+ORDER BY max_latency_ms DESC, l.query
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteSearchLogsBefore :execrows
+DELETE FROM search_log
+WHERE created_at < $1;
+
+-- name: DeleteSearchClicksBefore :execrows
+DELETE FROM search_click
+WHERE created_at < $1;
+
+-- name: ListSearchLogsByUser :many
+SELECT *
+FROM search_log
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListSearchClicksByUser :many
+SELECT *
+FROM search_click
+WHERE user_id = $1
+ORDER BY created_at DESC;
@@ -0,0 +1,25 @@
+-- name: ListSearchSynonyms :many
+SELECT id, terms, created_at, updated_at
+FROM search_synonym
+ORDER BY id;
+
+-- name: GetSearchSynonym :one
+SELECT id, terms, created_at, updated_at
+FROM search_synonym
+WHERE id = $1;
+
+-- name: CreateSearchSynonym :one
+INSERT INTO search_synonym (terms)
+VALUES ($1)
+RETURNING id, terms, created_at, updated_at;
+
+-- name: UpdateSearchSynonym :one
+UPDATE search_synonym
+SET terms = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, terms, created_at, updated_at;
+
+-- name: DeleteSearchSynonym :execrows
+DELETE FROM search_synonym
+WHERE id = $1;
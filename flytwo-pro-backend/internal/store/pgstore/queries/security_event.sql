@@ -0,0 +1,30 @@
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_event (event_type, user_id, email, ip, details)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListSecurityEvents :many
+SELECT id, event_type, user_id, email, ip, details, created_at
+FROM security_event
+WHERE (sqlc.narg('event_type')::text IS NULL OR event_type = sqlc.narg('event_type'))
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountSecurityEvents :one
+SELECT COUNT(*)
+FROM security_event
+WHERE (sqlc.narg('event_type')::text IS NULL OR event_type = sqlc.narg('event_type'))
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'));
+
+-- name: ListSecurityEventsForUser :many
+SELECT id, event_type, user_id, email, ip, details, created_at
+FROM security_event
+WHERE user_id = sqlc.arg('user_id') OR lower(email) = lower(sqlc.arg('email'))
+ORDER BY created_at DESC, id DESC;
+
+-- name: AnonymizeSecurityEvents :execrows
+UPDATE security_event
+SET user_id = NULL,
+    email = NULL,
+    ip = NULL
+WHERE user_id = sqlc.arg('user_id') OR lower(email) = lower(sqlc.arg('email'));
@@ -0,0 +1,73 @@
+-- name: UpsertPendingTOTP :execrows
+INSERT INTO user_totp (user_id, secret_encrypted)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted,
+    confirmed_at = NULL,
+    last_used_step = 0,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL;
+
+-- name: GetUserTOTPForUpdate :one
+SELECT user_id, secret_encrypted, confirmed_at, last_used_step, created_at
+FROM user_totp
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed_at = now(),
+    last_used_step = $2
+WHERE user_id = $1;
+
+-- name: SetTOTPLastUsedStep :exec
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1;
+
+-- name: DeleteUserTOTP :execrows
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: GetTwoFactorState :one
+SELECT
+    EXISTS (
+        SELECT 1 FROM user_totp t
+        WHERE t.user_id = $1 AND t.confirmed_at IS NOT NULL
+    )::boolean AS enabled,
+    EXISTS (
+        SELECT 1 FROM user_role ur
+        JOIN two_factor_required_role r ON r.role = ur.role
+        WHERE ur.user_id = $1
+    )::boolean AS required;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_code (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_code
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_code
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM totp_recovery_code
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: ListTwoFactorRequiredRoles :many
+SELECT role FROM two_factor_required_role
+ORDER BY role;
+
+-- name: DeleteTwoFactorRequiredRoles :exec
+DELETE FROM two_factor_required_role;
+
+-- name: AddTwoFactorRequiredRole :exec
+INSERT INTO two_factor_required_role (role)
+VALUES ($1)
+ON CONFLICT DO NOTHING;
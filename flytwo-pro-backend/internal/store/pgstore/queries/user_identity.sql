@@ -0,0 +1,19 @@
+-- name: GetUserIdentity :one
+SELECT * FROM user_identity
+WHERE provider = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identity (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identity
+SET email = $2,
+    last_login_at = now()
+WHERE id = $1;
+
+-- name: ListUserIdentitiesByUser :many
+SELECT * FROM user_identity
+WHERE user_id = $1
+ORDER BY created_at;
@@ -0,0 +1,42 @@
+-- name: ListUserRoles :many
+SELECT role FROM user_role
+WHERE user_id = $1
+ORDER BY role;
+
+-- name: AddUserRole :exec
+INSERT INTO user_role (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteUserRoles :exec
+DELETE FROM user_role
+WHERE user_id = $1;
+
+-- name: LockAdminUsers :many
+SELECT user_id FROM user_role
+WHERE role = 'admin'
+FOR UPDATE;
+
+-- name: BootstrapAdmin :execrows
+INSERT INTO user_role (user_id, role)
+SELECT u.id, 'admin' FROM users u
+WHERE lower(u.email) = lower(sqlc.arg(email))
+  AND NOT EXISTS (SELECT 1 FROM user_role WHERE role = 'admin')
+ON CONFLICT DO NOTHING;
+
+-- name: ListUsersWithRoles :many
+SELECT
+    u.id,
+    u.user_name,
+    u.email,
+    u.created_at,
+    u.email_verified_at,
+    coalesce(array_agg(r.role ORDER BY r.role) FILTER (WHERE r.role IS NOT NULL), '{}')::text[] AS roles
+FROM users u
+LEFT JOIN user_role r ON r.user_id = u.id
+GROUP BY u.id
+ORDER BY u.created_at, u.id
+LIMIT $1 OFFSET $2;
+
+-- name: CountUsers :one
+SELECT count(*) FROM users;
@@ -0,0 +1,49 @@
+-- name: CreateUserSession :exec
+INSERT INTO user_session (token, user_id, ip, user_agent)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (token) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    ip = EXCLUDED.ip,
+    user_agent = EXCLUDED.user_agent,
+    created_at = now(),
+    last_seen_at = now();
+
+-- name: DeleteStaleUserSessions :execrows
+DELETE FROM user_session us
+WHERE us.user_id = $1
+  AND us.created_at < now() - interval '1 minute'
+  AND NOT EXISTS (
+      SELECT 1 FROM sessions s
+      WHERE s.token = us.token AND s.expiry > now()
+  );
+
+-- name: ListUserSessions :many
+SELECT us.id, us.token, us.ip, us.user_agent, us.created_at, us.last_seen_at, s.expiry
+FROM user_session us
+JOIN sessions s ON s.token = us.token
+WHERE us.user_id = $1 AND s.expiry > now()
+ORDER BY us.last_seen_at DESC;
+
+-- name: TouchUserSession :exec
+UPDATE user_session
+SET last_seen_at = now()
+WHERE token = $1
+  AND last_seen_at < now() - interval '1 minute';
+
+-- name: DeleteUserSessionByToken :exec
+DELETE FROM user_session
+WHERE token = $1;
+
+-- name: DeleteUserSession :one
+DELETE FROM user_session
+WHERE id = $1 AND user_id = $2
+RETURNING token;
+
+-- name: DeleteOtherUserSessions :many
+DELETE FROM user_session
+WHERE user_id = $1 AND token <> $2
+RETURNING token;
+
+-- name: DeleteSessions :execrows
+DELETE FROM sessions
+WHERE token = ANY(@tokens::text[]);
@@ -10,7 +10,8 @@ SELECT
   password_hash,
   email,
   created_at,
-  updated_at
+  updated_at,
+  email_verified_at
 FROM users
 WHERE id = $1;
 
@@ -23,4 +24,26 @@ SELECT
     created_at,
     updated_at
 FROM users
-WHERE email = $1;
\ No newline at end of file
+WHERE email = $1;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET user_name = COALESCE(sqlc.narg('user_name'), user_name),
+    email = COALESCE(sqlc.narg('email'), email),
+    email_verified_at = CASE
+        WHEN sqlc.narg('email')::text IS NULL OR sqlc.narg('email')::text = email THEN email_verified_at
+    END,
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: FindUserByEmailInsensitive :one
+SELECT id, user_name, email, email_verified_at
+FROM users
+WHERE lower(email) = lower($1)
+ORDER BY created_at
+LIMIT 1;
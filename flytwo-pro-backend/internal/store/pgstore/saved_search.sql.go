@@ -0,0 +1,418 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: saved_search.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearSavedSearchMatches = `-- name: ClearSavedSearchMatches :exec
+DELETE FROM saved_search_match
+WHERE saved_search_id = $1
+`
+
+func (q *Queries) ClearSavedSearchMatches(ctx context.Context, savedSearchID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, clearSavedSearchMatches, savedSearchID)
+	return err
+}
+
+const countSavedSearchesByUser = `-- name: CountSavedSearchesByUser :one
+SELECT COUNT(*)
+FROM saved_search
+WHERE user_id = $1
+`
+
+func (q *Queries) CountSavedSearchesByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countSavedSearchesByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createSavedSearch = `-- name: CreateSavedSearch :one
+INSERT INTO saved_search (
+    user_id,
+    name,
+    catalog,
+    query,
+    group_code,
+    class_code,
+    pdm_code,
+    ncm_code,
+    service_code,
+    status
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+RETURNING id, user_id, name, catalog, query, group_code, class_code, pdm_code, ncm_code, service_code, status, last_evaluated_at, created_at, updated_at
+`
+
+type CreateSavedSearchParams struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	Name        string      `json:"name"`
+	Catalog     string      `json:"catalog"`
+	Query       string      `json:"query"`
+	GroupCode   pgtype.Int2 `json:"group_code"`
+	ClassCode   pgtype.Int4 `json:"class_code"`
+	PdmCode     pgtype.Int4 `json:"pdm_code"`
+	NcmCode     pgtype.Text `json:"ncm_code"`
+	ServiceCode pgtype.Int4 `json:"service_code"`
+	Status      pgtype.Text `json:"status"`
+}
+
+func (q *Queries) CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error) {
+	row := q.db.QueryRow(ctx, createSavedSearch,
+		arg.UserID,
+		arg.Name,
+		arg.Catalog,
+		arg.Query,
+		arg.GroupCode,
+		arg.ClassCode,
+		arg.PdmCode,
+		arg.NcmCode,
+		arg.ServiceCode,
+		arg.Status,
+	)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Catalog,
+		&i.Query,
+		&i.GroupCode,
+		&i.ClassCode,
+		&i.PdmCode,
+		&i.NcmCode,
+		&i.ServiceCode,
+		&i.Status,
+		&i.LastEvaluatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteSavedSearch = `-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_search
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteSavedSearchParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSavedSearch, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getSavedSearch = `-- name: GetSavedSearch :one
+SELECT id, user_id, name, catalog, query, group_code, class_code, pdm_code, ncm_code, service_code, status, last_evaluated_at, created_at, updated_at
+FROM saved_search
+WHERE id = $1 AND user_id = $2
+`
+
+type GetSavedSearchParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetSavedSearch(ctx context.Context, arg GetSavedSearchParams) (SavedSearch, error) {
+	row := q.db.QueryRow(ctx, getSavedSearch, arg.ID, arg.UserID)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Catalog,
+		&i.Query,
+		&i.GroupCode,
+		&i.ClassCode,
+		&i.PdmCode,
+		&i.NcmCode,
+		&i.ServiceCode,
+		&i.Status,
+		&i.LastEvaluatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertCatmatSavedSearchMatches = `-- name: InsertCatmatSavedSearchMatches :many
+INSERT INTO saved_search_match (saved_search_id, item_code)
+SELECT $1::uuid, i.item_code
+FROM catmat_item i
+WHERE ($2::text IS NULL OR i.search_document @@ websearch_to_tsquery('portuguese_unaccent', $2::text))
+  AND ($3::smallint IS NULL OR i.group_code = $3::smallint)
+  AND ($4::integer IS NULL OR i.class_code = $4::integer)
+  AND ($5::integer IS NULL OR i.pdm_code = $5::integer)
+  AND ($6::text IS NULL OR i.ncm_code = $6::text)
+ON CONFLICT DO NOTHING
+RETURNING item_code
+`
+
+type InsertCatmatSavedSearchMatchesParams struct {
+	SavedSearchID uuid.UUID   `json:"saved_search_id"`
+	Query         pgtype.Text `json:"query"`
+	GroupCode     pgtype.Int2 `json:"group_code"`
+	ClassCode     pgtype.Int4 `json:"class_code"`
+	PdmCode       pgtype.Int4 `json:"pdm_code"`
+	NcmCode       pgtype.Text `json:"ncm_code"`
+}
+
+func (q *Queries) InsertCatmatSavedSearchMatches(ctx context.Context, arg InsertCatmatSavedSearchMatchesParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, insertCatmatSavedSearchMatches,
+		arg.SavedSearchID,
+		arg.Query,
+		arg.GroupCode,
+		arg.ClassCode,
+		arg.PdmCode,
+		arg.NcmCode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var item_code int32
+		if err := rows.Scan(&item_code); err != nil {
+			return nil, err
+		}
+		items = append(items, item_code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCatserSavedSearchMatches = `-- name: InsertCatserSavedSearchMatches :many
+INSERT INTO saved_search_match (saved_search_id, item_code)
+SELECT $1::uuid, i.service_code
+FROM catser_item i
+WHERE ($2::text IS NULL OR i.search_document @@ websearch_to_tsquery('portuguese_unaccent', $2::text))
+  AND ($3::smallint IS NULL OR i.group_code = $3::smallint)
+  AND ($4::integer IS NULL OR i.class_code = $4::integer)
+  AND ($5::integer IS NULL OR i.service_code = $5::integer)
+  AND ($6::text IS NULL OR i.status = $6::text)
+ON CONFLICT DO NOTHING
+RETURNING item_code
+`
+
+type InsertCatserSavedSearchMatchesParams struct {
+	SavedSearchID uuid.UUID   `json:"saved_search_id"`
+	Query         pgtype.Text `json:"query"`
+	GroupCode     pgtype.Int2 `json:"group_code"`
+	ClassCode     pgtype.Int4 `json:"class_code"`
+	ServiceCode   pgtype.Int4 `json:"service_code"`
+	Status        pgtype.Text `json:"status"`
+}
+
+func (q *Queries) InsertCatserSavedSearchMatches(ctx context.Context, arg InsertCatserSavedSearchMatchesParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, insertCatserSavedSearchMatches,
+		arg.SavedSearchID,
+		arg.Query,
+		arg.GroupCode,
+		arg.ClassCode,
+		arg.ServiceCode,
+		arg.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var item_code int32
+		if err := rows.Scan(&item_code); err != nil {
+			return nil, err
+		}
+		items = append(items, item_code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSavedSearchesByCatalog = `-- name: ListSavedSearchesByCatalog :many
+SELECT id, user_id, name, catalog, query, group_code, class_code, pdm_code, ncm_code, service_code, status, last_evaluated_at, created_at, updated_at
+FROM saved_search
+WHERE catalog = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListSavedSearchesByCatalog(ctx context.Context, catalog string) ([]SavedSearch, error) {
+	rows, err := q.db.Query(ctx, listSavedSearchesByCatalog, catalog)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SavedSearch
+	for rows.Next() {
+		var i SavedSearch
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Catalog,
+			&i.Query,
+			&i.GroupCode,
+			&i.ClassCode,
+			&i.PdmCode,
+			&i.NcmCode,
+			&i.ServiceCode,
+			&i.Status,
+			&i.LastEvaluatedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSavedSearchesByUser = `-- name: ListSavedSearchesByUser :many
+SELECT id, user_id, name, catalog, query, group_code, class_code, pdm_code, ncm_code, service_code, status, last_evaluated_at, created_at, updated_at
+FROM saved_search
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListSavedSearchesByUser(ctx context.Context, userID uuid.UUID) ([]SavedSearch, error) {
+	rows, err := q.db.Query(ctx, listSavedSearchesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SavedSearch
+	for rows.Next() {
+		var i SavedSearch
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Catalog,
+			&i.Query,
+			&i.GroupCode,
+			&i.ClassCode,
+			&i.PdmCode,
+			&i.NcmCode,
+			&i.ServiceCode,
+			&i.Status,
+			&i.LastEvaluatedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markSavedSearchEvaluated = `-- name: MarkSavedSearchEvaluated :exec
+UPDATE saved_search
+SET last_evaluated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkSavedSearchEvaluated(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markSavedSearchEvaluated, id)
+	return err
+}
+
+const updateSavedSearch = `-- name: UpdateSavedSearch :one
+UPDATE saved_search
+SET name = $1,
+    catalog = $2,
+    query = $3,
+    group_code = $4,
+    class_code = $5,
+    pdm_code = $6,
+    ncm_code = $7,
+    service_code = $8,
+    status = $9,
+    updated_at = now()
+WHERE id = $10 AND user_id = $11
+RETURNING id, user_id, name, catalog, query, group_code, class_code, pdm_code, ncm_code, service_code, status, last_evaluated_at, created_at, updated_at
+`
+
+type UpdateSavedSearchParams struct {
+	Name        string      `json:"name"`
+	Catalog     string      `json:"catalog"`
+	Query       string      `json:"query"`
+	GroupCode   pgtype.Int2 `json:"group_code"`
+	ClassCode   pgtype.Int4 `json:"class_code"`
+	PdmCode     pgtype.Int4 `json:"pdm_code"`
+	NcmCode     pgtype.Text `json:"ncm_code"`
+	ServiceCode pgtype.Int4 `json:"service_code"`
+	Status      pgtype.Text `json:"status"`
+	ID          uuid.UUID   `json:"id"`
+	UserID      uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) UpdateSavedSearch(ctx context.Context, arg UpdateSavedSearchParams) (SavedSearch, error) {
+	row := q.db.QueryRow(ctx, updateSavedSearch,
+		arg.Name,
+		arg.Catalog,
+		arg.Query,
+		arg.GroupCode,
+		arg.ClassCode,
+		arg.PdmCode,
+		arg.NcmCode,
+		arg.ServiceCode,
+		arg.Status,
+		arg.ID,
+		arg.UserID,
+	)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Catalog,
+		&i.Query,
+		&i.GroupCode,
+		&i.ClassCode,
+		&i.PdmCode,
+		&i.NcmCode,
+		&i.ServiceCode,
+		&i.Status,
+		&i.LastEvaluatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
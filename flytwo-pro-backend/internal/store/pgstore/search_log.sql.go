@@ -0,0 +1,379 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: search_log.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteSearchClicksBefore = `-- name: DeleteSearchClicksBefore :execrows
+DELETE FROM search_click
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteSearchClicksBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSearchClicksBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSearchLogsBefore = `-- name: DeleteSearchLogsBefore :execrows
+DELETE FROM search_log
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteSearchLogsBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSearchLogsBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertSearchClick = `-- name: InsertSearchClick :exec
+INSERT INTO search_click (search_id, user_id, item_code, position)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertSearchClickParams struct {
+	SearchID uuid.UUID   `json:"search_id"`
+	UserID   pgtype.UUID `json:"user_id"`
+	ItemCode int32       `json:"item_code"`
+	Position pgtype.Int4 `json:"position"`
+}
+
+func (q *Queries) InsertSearchClick(ctx context.Context, arg InsertSearchClickParams) error {
+	_, err := q.db.Exec(ctx, insertSearchClick,
+		arg.SearchID,
+		arg.UserID,
+		arg.ItemCode,
+		arg.Position,
+	)
+	return err
+}
+
+const insertSearchLog = `-- name: InsertSearchLog :exec
+INSERT INTO search_log (
+    id,
+    user_id,
+    catalog,
+    query,
+    filters,
+    result_count,
+    latency_ms,
+    created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertSearchLogParams struct {
+	ID          uuid.UUID   `json:"id"`
+	UserID      pgtype.UUID `json:"user_id"`
+	Catalog     string      `json:"catalog"`
+	Query       string      `json:"query"`
+	Filters     []byte      `json:"filters"`
+	ResultCount int64       `json:"result_count"`
+	LatencyMs   int32       `json:"latency_ms"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+func (q *Queries) InsertSearchLog(ctx context.Context, arg InsertSearchLogParams) error {
+	_, err := q.db.Exec(ctx, insertSearchLog,
+		arg.ID,
+		arg.UserID,
+		arg.Catalog,
+		arg.Query,
+		arg.Filters,
+		arg.ResultCount,
+		arg.LatencyMs,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const listSearchClicksByUser = `-- name: ListSearchClicksByUser :many
+SELECT id, search_id, user_id, item_code, position, created_at
+FROM search_click
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListSearchClicksByUser(ctx context.Context, userID pgtype.UUID) ([]SearchClick, error) {
+	rows, err := q.db.Query(ctx, listSearchClicksByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchClick
+	for rows.Next() {
+		var i SearchClick
+		if err := rows.Scan(
+			&i.ID,
+			&i.SearchID,
+			&i.UserID,
+			&i.ItemCode,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSearchLogsByUser = `-- name: ListSearchLogsByUser :many
+SELECT id, user_id, catalog, query, filters, result_count, latency_ms, created_at
+FROM search_log
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListSearchLogsByUser(ctx context.Context, userID pgtype.UUID) ([]SearchLog, error) {
+	rows, err := q.db.Query(ctx, listSearchLogsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchLog
+	for rows.Next() {
+		var i SearchLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Catalog,
+			&i.Query,
+			&i.Filters,
+			&i.ResultCount,
+			&i.LatencyMs,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const slowestSearchQueries = `-- name: SlowestSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    AVG(l.result_count)::float8 AS avg_results,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= $1
+  AND l.created_at < $2
+  AND ($3::text IS NULL OR l.catalog = $3)
+GROUP BY l.catalog, l.query
+ORDER BY max_latency_ms DESC, l.query
+LIMIT $4
+`
+
+type SlowestSearchQueriesParams struct {
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	Catalog pgtype.Text `json:"catalog"`
+	Limit   int32       `json:"limit"`
+}
+
+type SlowestSearchQueriesRow struct {
+	Catalog        string    `json:"catalog"`
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	AvgResults     float64   `json:"avg_results"`
+	AvgLatencyMs   float64   `json:"avg_latency_ms"`
+	MaxLatencyMs   int32     `json:"max_latency_ms"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+func (q *Queries) SlowestSearchQueries(ctx context.Context, arg SlowestSearchQueriesParams) ([]SlowestSearchQueriesRow, error) {
+	rows, err := q.db.Query(ctx, slowestSearchQueries,
+		arg.From,
+		arg.To,
+		arg.Catalog,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SlowestSearchQueriesRow
+	for rows.Next() {
+		var i SlowestSearchQueriesRow
+		if err := rows.Scan(
+			&i.Catalog,
+			&i.Query,
+			&i.Searches,
+			&i.AvgResults,
+			&i.AvgLatencyMs,
+			&i.MaxLatencyMs,
+			&i.LastSearchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const topSearchQueries = `-- name: TopSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    COUNT(*) FILTER (
+        WHERE EXISTS (SELECT 1 FROM search_click c WHERE c.search_id = l.id)
+    ) AS searches_with_click,
+    AVG(l.result_count)::float8 AS avg_results,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= $1
+  AND l.created_at < $2
+  AND ($3::text IS NULL OR l.catalog = $3)
+  AND l.query <> ''
+GROUP BY l.catalog, l.query
+ORDER BY searches DESC, l.query
+LIMIT $4
+`
+
+type TopSearchQueriesParams struct {
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	Catalog pgtype.Text `json:"catalog"`
+	Limit   int32       `json:"limit"`
+}
+
+type TopSearchQueriesRow struct {
+	Catalog           string    `json:"catalog"`
+	Query             string    `json:"query"`
+	Searches          int64     `json:"searches"`
+	SearchesWithClick int64     `json:"searches_with_click"`
+	AvgResults        float64   `json:"avg_results"`
+	AvgLatencyMs      float64   `json:"avg_latency_ms"`
+	MaxLatencyMs      int32     `json:"max_latency_ms"`
+	LastSearchedAt    time.Time `json:"last_searched_at"`
+}
+
+func (q *Queries) TopSearchQueries(ctx context.Context, arg TopSearchQueriesParams) ([]TopSearchQueriesRow, error) {
+	rows, err := q.db.Query(ctx, topSearchQueries,
+		arg.From,
+		arg.To,
+		arg.Catalog,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopSearchQueriesRow
+	for rows.Next() {
+		var i TopSearchQueriesRow
+		if err := rows.Scan(
+			&i.Catalog,
+			&i.Query,
+			&i.Searches,
+			&i.SearchesWithClick,
+			&i.AvgResults,
+			&i.AvgLatencyMs,
+			&i.MaxLatencyMs,
+			&i.LastSearchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const zeroResultSearchQueries = `-- name: ZeroResultSearchQueries :many
+SELECT
+    l.catalog,
+    l.query,
+    COUNT(*) AS searches,
+    AVG(l.latency_ms)::float8 AS avg_latency_ms,
+    MAX(l.latency_ms)::integer AS max_latency_ms,
+    MAX(l.created_at)::timestamptz AS last_searched_at
+FROM search_log l
+WHERE l.created_at >= $1
+  AND l.created_at < $2
+  AND ($3::text IS NULL OR l.catalog = $3)
+  AND l.result_count = 0
+GROUP BY l.catalog, l.query
+ORDER BY searches DESC, l.query
+LIMIT $4
+`
+
+type ZeroResultSearchQueriesParams struct {
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	Catalog pgtype.Text `json:"catalog"`
+	Limit   int32       `json:"limit"`
+}
+
+type ZeroResultSearchQueriesRow struct {
+	Catalog        string    `json:"catalog"`
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	AvgLatencyMs   float64   `json:"avg_latency_ms"`
+	MaxLatencyMs   int32     `json:"max_latency_ms"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+func (q *Queries) ZeroResultSearchQueries(ctx context.Context, arg ZeroResultSearchQueriesParams) ([]ZeroResultSearchQueriesRow, error) {
+	rows, err := q.db.Query(ctx, zeroResultSearchQueries,
+		arg.From,
+		arg.To,
+		arg.Catalog,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ZeroResultSearchQueriesRow
+	for rows.Next() {
+		var i ZeroResultSearchQueriesRow
+		if err := rows.Scan(
+			&i.Catalog,
+			&i.Query,
+			&i.Searches,
+			&i.AvgLatencyMs,
+			&i.MaxLatencyMs,
+			&i.LastSearchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
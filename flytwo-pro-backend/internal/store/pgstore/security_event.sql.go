@@ -0,0 +1,165 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: security_event.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const anonymizeSecurityEvents = `-- name: AnonymizeSecurityEvents :execrows
+UPDATE security_event
+SET user_id = NULL,
+    email = NULL,
+    ip = NULL
+WHERE user_id = $1 OR lower(email) = lower($2)
+`
+
+type AnonymizeSecurityEventsParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	Email  string      `json:"email"`
+}
+
+func (q *Queries) AnonymizeSecurityEvents(ctx context.Context, arg AnonymizeSecurityEventsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, anonymizeSecurityEvents, arg.UserID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countSecurityEvents = `-- name: CountSecurityEvents :one
+SELECT COUNT(*)
+FROM security_event
+WHERE ($1::text IS NULL OR event_type = $1)
+  AND ($2::uuid IS NULL OR user_id = $2)
+`
+
+type CountSecurityEventsParams struct {
+	EventType pgtype.Text `json:"event_type"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) CountSecurityEvents(ctx context.Context, arg CountSecurityEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSecurityEvents, arg.EventType, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createSecurityEvent = `-- name: CreateSecurityEvent :exec
+INSERT INTO security_event (event_type, user_id, email, ip, details)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateSecurityEventParams struct {
+	EventType string      `json:"event_type"`
+	UserID    pgtype.UUID `json:"user_id"`
+	Email     pgtype.Text `json:"email"`
+	Ip        pgtype.Text `json:"ip"`
+	Details   []byte      `json:"details"`
+}
+
+func (q *Queries) CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error {
+	_, err := q.db.Exec(ctx, createSecurityEvent,
+		arg.EventType,
+		arg.UserID,
+		arg.Email,
+		arg.Ip,
+		arg.Details,
+	)
+	return err
+}
+
+const listSecurityEvents = `-- name: ListSecurityEvents :many
+SELECT id, event_type, user_id, email, ip, details, created_at
+FROM security_event
+WHERE ($3::text IS NULL OR event_type = $3)
+  AND ($4::uuid IS NULL OR user_id = $4)
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListSecurityEventsParams struct {
+	Limit     int32       `json:"limit"`
+	Offset    int32       `json:"offset"`
+	EventType pgtype.Text `json:"event_type"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) ListSecurityEvents(ctx context.Context, arg ListSecurityEventsParams) ([]SecurityEvent, error) {
+	rows, err := q.db.Query(ctx, listSecurityEvents,
+		arg.Limit,
+		arg.Offset,
+		arg.EventType,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SecurityEvent
+	for rows.Next() {
+		var i SecurityEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.UserID,
+			&i.Email,
+			&i.Ip,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecurityEventsForUser = `-- name: ListSecurityEventsForUser :many
+SELECT id, event_type, user_id, email, ip, details, created_at
+FROM security_event
+WHERE user_id = $1 OR lower(email) = lower($2)
+ORDER BY created_at DESC, id DESC
+`
+
+type ListSecurityEventsForUserParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	Email  string      `json:"email"`
+}
+
+func (q *Queries) ListSecurityEventsForUser(ctx context.Context, arg ListSecurityEventsForUserParams) ([]SecurityEvent, error) {
+	rows, err := q.db.Query(ctx, listSecurityEventsForUser, arg.UserID, arg.Email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SecurityEvent
+	for rows.Next() {
+		var i SecurityEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.UserID,
+			&i.Email,
+			&i.Ip,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
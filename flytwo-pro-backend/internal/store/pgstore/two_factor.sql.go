@@ -0,0 +1,230 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: two_factor.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addTwoFactorRequiredRole = `-- name: AddTwoFactorRequiredRole :exec
+INSERT INTO two_factor_required_role (role)
+VALUES ($1)
+ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) AddTwoFactorRequiredRole(ctx context.Context, role string) error {
+	_, err := q.db.Exec(ctx, addTwoFactorRequiredRole, role)
+	return err
+}
+
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed_at = now(),
+    last_used_step = $2
+WHERE user_id = $1
+`
+
+type ConfirmUserTOTPParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	LastUsedStep int64     `json:"last_used_step"`
+}
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) error {
+	_, err := q.db.Exec(ctx, confirmUserTOTP, arg.UserID, arg.LastUsedStep)
+	return err
+}
+
+const countUnusedRecoveryCodes = `-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM totp_recovery_code
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnusedRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_code (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash []byte    `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_code
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteTwoFactorRequiredRoles = `-- name: DeleteTwoFactorRequiredRoles :exec
+DELETE FROM two_factor_required_role
+`
+
+func (q *Queries) DeleteTwoFactorRequiredRoles(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteTwoFactorRequiredRoles)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :execrows
+DELETE FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserTOTP, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getTwoFactorState = `-- name: GetTwoFactorState :one
+SELECT
+    EXISTS (
+        SELECT 1 FROM user_totp t
+        WHERE t.user_id = $1 AND t.confirmed_at IS NOT NULL
+    )::boolean AS enabled,
+    EXISTS (
+        SELECT 1 FROM user_role ur
+        JOIN two_factor_required_role r ON r.role = ur.role
+        WHERE ur.user_id = $1
+    )::boolean AS required
+`
+
+type GetTwoFactorStateRow struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
+
+func (q *Queries) GetTwoFactorState(ctx context.Context, userID uuid.UUID) (GetTwoFactorStateRow, error) {
+	row := q.db.QueryRow(ctx, getTwoFactorState, userID)
+	var i GetTwoFactorStateRow
+	err := row.Scan(&i.Enabled, &i.Required)
+	return i, err
+}
+
+const getUserTOTPForUpdate = `-- name: GetUserTOTPForUpdate :one
+SELECT user_id, secret_encrypted, confirmed_at, last_used_step, created_at
+FROM user_totp
+WHERE user_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetUserTOTPForUpdate(ctx context.Context, userID uuid.UUID) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, getUserTOTPForUpdate, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.SecretEncrypted,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listTwoFactorRequiredRoles = `-- name: ListTwoFactorRequiredRoles :many
+SELECT role FROM two_factor_required_role
+ORDER BY role
+`
+
+func (q *Queries) ListTwoFactorRequiredRoles(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listTwoFactorRequiredRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		items = append(items, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTOTPLastUsedStep = `-- name: SetTOTPLastUsedStep :exec
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1
+`
+
+type SetTOTPLastUsedStepParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	LastUsedStep int64     `json:"last_used_step"`
+}
+
+func (q *Queries) SetTOTPLastUsedStep(ctx context.Context, arg SetTOTPLastUsedStepParams) error {
+	_, err := q.db.Exec(ctx, setTOTPLastUsedStep, arg.UserID, arg.LastUsedStep)
+	return err
+}
+
+const upsertPendingTOTP = `-- name: UpsertPendingTOTP :execrows
+INSERT INTO user_totp (user_id, secret_encrypted)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted,
+    confirmed_at = NULL,
+    last_used_step = 0,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL
+`
+
+type UpsertPendingTOTPParams struct {
+	UserID          uuid.UUID `json:"user_id"`
+	SecretEncrypted []byte    `json:"secret_encrypted"`
+}
+
+func (q *Queries) UpsertPendingTOTP(ctx context.Context, arg UpsertPendingTOTPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertPendingTOTP, arg.UserID, arg.SecretEncrypted)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_code
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash []byte    `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
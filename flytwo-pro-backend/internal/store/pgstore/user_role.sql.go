@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_role.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addUserRole = `-- name: AddUserRole :exec
+INSERT INTO user_role (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddUserRoleParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Role   string    `json:"role"`
+}
+
+func (q *Queries) AddUserRole(ctx context.Context, arg AddUserRoleParams) error {
+	_, err := q.db.Exec(ctx, addUserRole, arg.UserID, arg.Role)
+	return err
+}
+
+const bootstrapAdmin = `-- name: BootstrapAdmin :execrows
+INSERT INTO user_role (user_id, role)
+SELECT u.id, 'admin' FROM users u
+WHERE lower(u.email) = lower($1)
+  AND NOT EXISTS (SELECT 1 FROM user_role WHERE role = 'admin')
+ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) BootstrapAdmin(ctx context.Context, email string) (int64, error) {
+	result, err := q.db.Exec(ctx, bootstrapAdmin, email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT count(*) FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteUserRoles = `-- name: DeleteUserRoles :exec
+DELETE FROM user_role
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserRoles(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserRoles, userID)
+	return err
+}
+
+const listUserRoles = `-- name: ListUserRoles :many
+SELECT role FROM user_role
+WHERE user_id = $1
+ORDER BY role
+`
+
+func (q *Queries) ListUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUserRoles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		items = append(items, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithRoles = `-- name: ListUsersWithRoles :many
+SELECT
+    u.id,
+    u.user_name,
+    u.email,
+    u.created_at,
+    u.email_verified_at,
+    coalesce(array_agg(r.role ORDER BY r.role) FILTER (WHERE r.role IS NOT NULL), '{}')::text[] AS roles
+FROM users u
+LEFT JOIN user_role r ON r.user_id = u.id
+GROUP BY u.id
+ORDER BY u.created_at, u.id
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersWithRolesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListUsersWithRolesRow struct {
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	Email           string             `json:"email"`
+	CreatedAt       time.Time          `json:"created_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	Roles           []string           `json:"roles"`
+}
+
+func (q *Queries) ListUsersWithRoles(ctx context.Context, arg ListUsersWithRolesParams) ([]ListUsersWithRolesRow, error) {
+	rows, err := q.db.Query(ctx, listUsersWithRoles, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersWithRolesRow
+	for rows.Next() {
+		var i ListUsersWithRolesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.EmailVerifiedAt,
+			&i.Roles,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAdminUsers = `-- name: LockAdminUsers :many
+SELECT user_id FROM user_role
+WHERE role = 'admin'
+FOR UPDATE
+`
+
+func (q *Queries) LockAdminUsers(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, lockAdminUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
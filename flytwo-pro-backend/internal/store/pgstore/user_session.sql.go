@@ -0,0 +1,189 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_session.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createUserSession = `-- name: CreateUserSession :exec
+INSERT INTO user_session (token, user_id, ip, user_agent)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (token) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    ip = EXCLUDED.ip,
+    user_agent = EXCLUDED.user_agent,
+    created_at = now(),
+    last_seen_at = now()
+`
+
+type CreateUserSessionParams struct {
+	Token     string    `json:"token"`
+	UserID    uuid.UUID `json:"user_id"`
+	Ip        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+}
+
+func (q *Queries) CreateUserSession(ctx context.Context, arg CreateUserSessionParams) error {
+	_, err := q.db.Exec(ctx, createUserSession,
+		arg.Token,
+		arg.UserID,
+		arg.Ip,
+		arg.UserAgent,
+	)
+	return err
+}
+
+const deleteOtherUserSessions = `-- name: DeleteOtherUserSessions :many
+DELETE FROM user_session
+WHERE user_id = $1 AND token <> $2
+RETURNING token
+`
+
+type DeleteOtherUserSessionsParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Token  string    `json:"token"`
+}
+
+func (q *Queries) DeleteOtherUserSessions(ctx context.Context, arg DeleteOtherUserSessionsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, deleteOtherUserSessions, arg.UserID, arg.Token)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		items = append(items, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSessions = `-- name: DeleteSessions :execrows
+DELETE FROM sessions
+WHERE token = ANY($1::text[])
+`
+
+func (q *Queries) DeleteSessions(ctx context.Context, tokens []string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSessions, tokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteStaleUserSessions = `-- name: DeleteStaleUserSessions :execrows
+DELETE FROM user_session us
+WHERE us.user_id = $1
+  AND us.created_at < now() - interval '1 minute'
+  AND NOT EXISTS (
+      SELECT 1 FROM sessions s
+      WHERE s.token = us.token AND s.expiry > now()
+  )
+`
+
+func (q *Queries) DeleteStaleUserSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStaleUserSessions, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserSession = `-- name: DeleteUserSession :one
+DELETE FROM user_session
+WHERE id = $1 AND user_id = $2
+RETURNING token
+`
+
+type DeleteUserSessionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (string, error) {
+	row := q.db.QueryRow(ctx, deleteUserSession, arg.ID, arg.UserID)
+	var token string
+	err := row.Scan(&token)
+	return token, err
+}
+
+const deleteUserSessionByToken = `-- name: DeleteUserSessionByToken :exec
+DELETE FROM user_session
+WHERE token = $1
+`
+
+func (q *Queries) DeleteUserSessionByToken(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, deleteUserSessionByToken, token)
+	return err
+}
+
+const listUserSessions = `-- name: ListUserSessions :many
+SELECT us.id, us.token, us.ip, us.user_agent, us.created_at, us.last_seen_at, s.expiry
+FROM user_session us
+JOIN sessions s ON s.token = us.token
+WHERE us.user_id = $1 AND s.expiry > now()
+ORDER BY us.last_seen_at DESC
+`
+
+type ListUserSessionsRow struct {
+	ID         uuid.UUID `json:"id"`
+	Token      string    `json:"token"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Expiry     time.Time `json:"expiry"`
+}
+
+func (q *Queries) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]ListUserSessionsRow, error) {
+	rows, err := q.db.Query(ctx, listUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserSessionsRow
+	for rows.Next() {
+		var i ListUserSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Token,
+			&i.Ip,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.Expiry,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchUserSession = `-- name: TouchUserSession :exec
+UPDATE user_session
+SET last_seen_at = now()
+WHERE token = $1
+  AND last_seen_at < now() - interval '1 minute'
+`
+
+func (q *Queries) TouchUserSession(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, touchUserSession, token)
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -31,6 +32,46 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (uuid.UU
 	return id, err
 }
 
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findUserByEmailInsensitive = `-- name: FindUserByEmailInsensitive :one
+SELECT id, user_name, email, email_verified_at
+FROM users
+WHERE lower(email) = lower($1)
+ORDER BY created_at
+LIMIT 1
+`
+
+type FindUserByEmailInsensitiveRow struct {
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	Email           string             `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+func (q *Queries) FindUserByEmailInsensitive(ctx context.Context, lower string) (FindUserByEmailInsensitiveRow, error) {
+	row := q.db.QueryRow(ctx, findUserByEmailInsensitive, lower)
+	var i FindUserByEmailInsensitiveRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT
     id,
@@ -73,18 +114,20 @@ SELECT
   password_hash,
   email,
   created_at,
-  updated_at
+  updated_at,
+  email_verified_at
 FROM users
 WHERE id = $1
 `
 
 type GetUserByIdRow struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	PasswordHash []byte    `json:"password_hash"`
-	Email        string    `json:"email"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	PasswordHash    []byte             `json:"password_hash"`
+	Email           string             `json:"email"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
 }
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow, error) {
@@ -97,6 +140,40 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET user_name = COALESCE($1, user_name),
+    email = COALESCE($2, email),
+    email_verified_at = CASE
+        WHEN $2::text IS NULL OR $2::text = email THEN email_verified_at
+    END,
+    updated_at = now()
+WHERE id = $3
+RETURNING id, user_name, email, password_hash, created_at, updated_at, email_verified_at
+`
+
+type UpdateUserProfileParams struct {
+	UserName pgtype.Text `json:"user_name"`
+	Email    pgtype.Text `json:"email"`
+	ID       uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.UserName, arg.Email, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,30 @@
+// Package tenant carries the organization (órgão) a request acts on behalf
+// of, so services can scope shared data by it without taking it as a
+// parameter. Requests without one work on the user's personal data.
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Organization is the active organization of a request and the role of the
+// user in it.
+type Organization struct {
+	ID   uuid.UUID
+	Role string
+}
+
+type contextKey struct{}
+
+// WithOrganization returns a copy of ctx acting on behalf of org.
+func WithOrganization(ctx context.Context, org Organization) context.Context {
+	return context.WithValue(ctx, contextKey{}, org)
+}
+
+// FromContext returns the active organization of ctx, if any.
+func FromContext(ctx context.Context) (Organization, bool) {
+	org, ok := ctx.Value(contextKey{}).(Organization)
+	return org, ok
+}
@@ -60,6 +60,8 @@ func (v *Validator) Validate(data interface{}) map[string]string {
 			}
 		case "username":
 			errors[field] = "must contain only letters, numbers, and underscores"
+		case "nefield":
+			errors[field] = fmt.Sprintf("must be different from %s", toSnakeCase(param))
 		default:
 			errors[field] = fmt.Sprintf("failed validation: %s", tag)
 		}
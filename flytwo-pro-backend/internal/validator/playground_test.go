@@ -184,4 +184,19 @@ func TestToSnakeCase(t *testing.T) {
 			assert.Equal(t, tt.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestValidator_ValidateNeField(t *testing.T) {
+	v := NewValidator()
+
+	type passwordChange struct {
+		CurrentPassword string `validate:"required"`
+		NewPassword     string `validate:"required,nefield=CurrentPassword"`
+	}
+
+	errors := v.Validate(passwordChange{CurrentPassword: "Secret123", NewPassword: "Secret123"})
+	require.NotNil(t, errors)
+	assert.Equal(t, "must be different from current_password", errors["newpassword"])
+
+	assert.Nil(t, v.Validate(passwordChange{CurrentPassword: "Secret123", NewPassword: "Secret456"}))
+}